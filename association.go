@@ -86,11 +86,16 @@ func (association *Association) Replace(values ...interface{}) error {
 					association.Error = rel.Field.Set(reflectValue, reflect.Zero(rel.Field.FieldType).Interface())
 				}
 
+				// foreign keys that are part of primary keys can't be cleared
 				for _, ref := range rel.References {
-					updateMap[ref.ForeignKey.DBName] = nil
+					if !ref.ForeignKey.PrimaryKey {
+						updateMap[ref.ForeignKey.DBName] = nil
+					}
 				}
 
-				association.Error = association.DB.UpdateColumns(updateMap).Error
+				if len(updateMap) > 0 {
+					association.Error = association.DB.UpdateColumns(updateMap).Error
+				}
 			}
 		case schema.HasOne, schema.HasMany:
 			var (
@@ -112,13 +117,15 @@ func (association *Association) Replace(values ...interface{}) error {
 				if ref.OwnPrimaryKey {
 					primaryFields = append(primaryFields, ref.PrimaryKey)
 					foreignKeys = append(foreignKeys, ref.ForeignKey.DBName)
-					updateMap[ref.ForeignKey.DBName] = nil
+					if !ref.ForeignKey.PrimaryKey {
+						updateMap[ref.ForeignKey.DBName] = nil
+					}
 				} else if ref.PrimaryValue != "" {
 					tx.Where(clause.Eq{Column: ref.ForeignKey.DBName, Value: ref.PrimaryValue})
 				}
 			}
 
-			if _, pvs := schema.GetIdentityFieldValuesMap(reflectValue, primaryFields); len(pvs) > 0 {
+			if _, pvs := schema.GetIdentityFieldValuesMap(reflectValue, primaryFields); len(pvs) > 0 && len(updateMap) > 0 {
 				column, values := schema.ToQueryValues(rel.FieldSchema.Table, foreignKeys, pvs)
 				association.Error = tx.Where(clause.IN{Column: column, Values: values}).UpdateColumns(updateMap).Error
 			}
@@ -177,7 +184,9 @@ func (association *Association) Delete(values ...interface{}) error {
 			if ref.PrimaryValue == "" {
 				primaryFields = append(primaryFields, ref.PrimaryKey)
 				foreignKeys = append(foreignKeys, ref.ForeignKey.DBName)
-				updateAttrs[ref.ForeignKey.DBName] = nil
+				if !ref.ForeignKey.PrimaryKey {
+					updateAttrs[ref.ForeignKey.DBName] = nil
+				}
 			} else {
 				conds = append(conds, clause.Eq{Column: ref.ForeignKey.DBName, Value: ref.PrimaryValue})
 			}
@@ -195,7 +204,9 @@ func (association *Association) Delete(values ...interface{}) error {
 			relColumn, relValues := schema.ToQueryValues(rel.Schema.Table, foreignKeys, rvs)
 			conds = append(conds, clause.IN{Column: relColumn, Values: relValues})
 
-			association.Error = tx.Clauses(conds...).UpdateColumns(updateAttrs).Error
+			if len(updateAttrs) > 0 {
+				association.Error = tx.Clauses(conds...).UpdateColumns(updateAttrs).Error
+			}
 		case schema.HasOne, schema.HasMany:
 			tx := association.DB.Model(reflect.New(rel.FieldSchema.ModelType).Interface())
 
@@ -207,7 +218,9 @@ func (association *Association) Delete(values ...interface{}) error {
 			relColumn, relValues := schema.ToQueryValues(rel.FieldSchema.Table, rel.FieldSchema.PrimaryFieldDBNames, rvs)
 			conds = append(conds, clause.IN{Column: relColumn, Values: relValues})
 
-			association.Error = tx.Clauses(conds...).UpdateColumns(updateAttrs).Error
+			if len(updateAttrs) > 0 {
+				association.Error = tx.Clauses(conds...).UpdateColumns(updateAttrs).Error
+			}
 		case schema.Many2Many:
 			var (
 				primaryFields, relPrimaryFields     []*schema.Field
@@ -275,7 +288,9 @@ func (association *Association) Delete(values ...interface{}) error {
 
 							if rel.JoinTable == nil {
 								for _, ref := range rel.References {
-									if ref.OwnPrimaryKey || ref.PrimaryValue != "" {
+									if ref.ForeignKey.PrimaryKey {
+										continue
+									} else if ref.OwnPrimaryKey || ref.PrimaryValue != "" {
 										association.Error = ref.ForeignKey.Set(fieldValue, reflect.Zero(ref.ForeignKey.FieldType).Interface())
 									} else {
 										association.Error = ref.ForeignKey.Set(data, reflect.Zero(ref.ForeignKey.FieldType).Interface())
@@ -408,7 +423,7 @@ func (association *Association) saveAssociation(clear bool, values ...interface{
 	}
 
 	associationDB := association.DB.Session(&Session{}).Model(nil)
-	if !association.DB.FullSaveAssociations {
+	if !association.DB.Statement.FullSaveAssociation(association.Relationship.Name) {
 		associationDB.Select(selectedSaveColumns)
 	}
 	if len(omitColumns) > 0 {
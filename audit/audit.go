@@ -0,0 +1,224 @@
+// Package audit writes audit trail of models implementing Auditable, each created, updated and deleted row is recorded
+// in the audit table of the model with actor of context, time and changed fields, in the transaction of the operation,
+// actors are resolved with gorm.Config.ActorResolver
+//    db, err := gorm.Open(sqlite.Open("gorm.db"), &gorm.Config{ActorResolver: currentUser})
+//    db.Use(audit.New(audit.Config{}))
+//    audit.AutoMigrate(db, &User{})
+//    db.WithContext(ctx).Model(&user).Update("name", "hello")
+//    records, err := audit.History(db, &user)
+package audit
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+	"gorm.io/gorm/utils"
+	"gorm.io/gorm/utils/snapshot"
+)
+
+// PluginName name of the plugin, its callbacks are registered as `audit:<stage>_<operation>`
+const PluginName = "gorm:audit"
+
+// Auditable model with audit trail, AuditTable returns name of its audit table, e.g: `user_audits`
+type Auditable interface {
+	AuditTable() string
+}
+
+// Change old and new values of changed field, Old is nil for created rows, New is nil for deleted rows
+type Change struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+// Record audit record of a row, EntityID is primary key of the row, changes are keyed by column names
+type Record struct {
+	ID        uint64
+	EntityID  string            `gorm:"size:255;index"`
+	Operation string            `gorm:"size:16"`
+	Actor     string            `gorm:"size:255"`
+	Changes   map[string]Change `gorm:"serializer:json"`
+	CreatedAt time.Time
+}
+
+// Config config of the plugin
+type Config struct{}
+
+// Plugin audit plugin, implements gorm.Plugin
+type Plugin struct {
+	Config
+}
+
+// New returns audit plugin with config
+func New(config Config) *Plugin {
+	return &Plugin{Config: config}
+}
+
+// Name returns name of the plugin
+func (p *Plugin) Name() string {
+	return PluginName
+}
+
+// AutoMigrate migrates audit tables of models
+func AutoMigrate(db *gorm.DB, models ...Auditable) error {
+	for _, model := range models {
+		if err := db.Table(model.AuditTable()).AutoMigrate(&Record{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// History returns audit records of value in order, value should be Auditable model with primary key
+func History(db *gorm.DB, value Auditable) (records []Record, err error) {
+	stmt := &gorm.Statement{DB: db}
+	if err = stmt.Parse(value); err != nil {
+		return nil, err
+	}
+
+	_, values := schema.GetIdentityFieldValuesMap(reflect.Indirect(reflect.ValueOf(value)), stmt.Schema.PrimaryFields)
+	if len(values) != 1 {
+		return nil, fmt.Errorf("%w: audit history requires primary key of %v", gorm.ErrPrimaryKeyRequired, stmt.Schema.Name)
+	}
+
+	err = db.Table(value.AuditTable()).Where("entity_id = ?", utils.ToStringKey(values[0]...)).Order("id").Find(&records).Error
+	return records, err
+}
+
+// Initialize registers callbacks writing audit records
+func (p *Plugin) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	for _, err := range []error{
+		callbacks.Create().After("gorm:create").Before("gorm:commit_or_rollback_transaction").Register("audit:after_create", p.after("create")),
+		callbacks.Update().After("gorm:scope_tenant").Before("gorm:update").Register("audit:before_update", p.before),
+		callbacks.Update().After("gorm:update").Before("gorm:commit_or_rollback_transaction").Register("audit:after_update", p.after("update")),
+		callbacks.Delete().After("gorm:scope_tenant").Before("gorm:delete").Register("audit:before_delete", p.before),
+		callbacks.Delete().After("gorm:delete").Before("gorm:commit_or_rollback_transaction").Register("audit:after_delete", p.after("delete")),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// auditTable returns audit table of model of statement, empty if it isn't audited
+func auditTable(db *gorm.DB) string {
+	if db.Error != nil || db.DryRun || db.Statement.Schema == nil || len(db.Statement.Schema.PrimaryFields) == 0 {
+		return ""
+	}
+
+	if auditable, ok := reflect.New(db.Statement.Schema.ModelType).Interface().(Auditable); ok {
+		return auditable.AuditTable()
+	}
+	return ""
+}
+
+// before reads rows going to be updated or deleted
+func (p *Plugin) before(db *gorm.DB) {
+	if auditTable(db) == "" {
+		return
+	}
+
+	if rows, ok, err := snapshot.Before(db); err != nil {
+		db.AddError(err)
+	} else if ok {
+		db.InstanceSet(PluginName, rows)
+	}
+}
+
+// after writes audit records of changed rows
+func (p *Plugin) after(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		table := auditTable(db)
+		if table == "" || db.RowsAffected == 0 {
+			return
+		}
+
+		var (
+			stmt      = db.Statement
+			primaries = stmt.Schema.PrimaryFieldDBNames
+			actor     string
+			now       = db.NowFunc()
+			records   []Record
+		)
+
+		if value, ok := stmt.Actor(); ok && value != nil {
+			actor = fmt.Sprint(value)
+		}
+
+		if operation == "create" {
+			values := func(rv reflect.Value) {
+				changes := map[string]Change{}
+				for _, field := range stmt.Schema.Fields {
+					if field.DBName != "" {
+						value, _ := field.ValueOf(rv)
+						changes[field.DBName] = Change{New: value}
+					}
+				}
+
+				key := make([]interface{}, len(stmt.Schema.PrimaryFields))
+				for idx, field := range stmt.Schema.PrimaryFields {
+					key[idx], _ = field.ValueOf(rv)
+				}
+				records = append(records, Record{EntityID: utils.ToStringKey(key...), Operation: operation, Actor: actor, Changes: changes, CreatedAt: now})
+			}
+
+			switch stmt.ReflectValue.Kind() {
+			case reflect.Struct:
+				values(stmt.ReflectValue)
+			case reflect.Slice, reflect.Array:
+				for i := 0; i < stmt.ReflectValue.Len(); i++ {
+					values(reflect.Indirect(stmt.ReflectValue.Index(i)))
+				}
+			}
+		} else if value, ok := db.InstanceGet(PluginName); ok {
+			var (
+				olds = value.([]map[string]interface{})
+				news = map[string]map[string]interface{}{}
+			)
+
+			if operation == "update" && len(olds) > 0 {
+				keys := make([][]interface{}, 0, len(olds))
+				for _, old := range olds {
+					keys = append(keys, snapshot.PrimaryValues(old, primaries))
+				}
+
+				rows, err := snapshot.FindByPrimaryValues(db, keys)
+				if err != nil {
+					db.AddError(err)
+					return
+				}
+
+				for _, row := range rows {
+					news[snapshot.Key(row, primaries)] = row
+				}
+			}
+
+			for _, old := range olds {
+				var (
+					key     = snapshot.Key(old, primaries)
+					changes = map[string]Change{}
+				)
+
+				for name, oldValue := range old {
+					if operation == "delete" {
+						changes[name] = Change{Old: oldValue}
+					} else if newValue := news[key][name]; !reflect.DeepEqual(oldValue, newValue) {
+						changes[name] = Change{Old: oldValue, New: newValue}
+					}
+				}
+
+				if len(changes) > 0 {
+					records = append(records, Record{EntityID: key, Operation: operation, Actor: actor, Changes: changes, CreatedAt: now})
+				}
+			}
+		}
+
+		if len(records) > 0 {
+			db.AddError(db.Session(&gorm.Session{NewDB: true}).Table(table).Create(&records).Error)
+		}
+	}
+}
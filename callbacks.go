@@ -8,19 +8,30 @@ import (
 	"sort"
 	"time"
 
+	"gorm.io/gorm/logger"
 	"gorm.io/gorm/schema"
 	"gorm.io/gorm/utils"
 )
 
+// QueryMetrics metrics of executed statement passed to MetricsCallback, Fingerprint is empty unless QueryFingerprint is enabled
+type QueryMetrics struct {
+	SQL          string
+	Fingerprint  string
+	RowsAffected int64
+	Elapsed      time.Duration
+	Error        error
+}
+
 func initializeCallbacks(db *DB) *callbacks {
+	hooks := executeHooks()
 	return &callbacks{
 		processors: map[string]*processor{
-			"create": {db: db},
-			"query":  {db: db},
-			"update": {db: db},
-			"delete": {db: db},
-			"row":    {db: db},
-			"raw":    {db: db},
+			"create": {db: db, name: "create", hooks: hooks},
+			"query":  {db: db, name: "query", hooks: hooks},
+			"update": {db: db, name: "update", hooks: hooks},
+			"delete": {db: db, name: "delete", hooks: hooks},
+			"row":    {db: db, name: "row", hooks: hooks},
+			"raw":    {db: db, name: "raw", hooks: hooks},
 		},
 	}
 }
@@ -32,8 +43,10 @@ type callbacks struct {
 
 type processor struct {
 	db        *DB
+	name      string
 	fns       []func(*DB)
 	callbacks []*callback
+	hooks     []executeHook
 }
 
 type callback struct {
@@ -71,8 +84,78 @@ func (cs *callbacks) Raw() *processor {
 	return cs.processors["raw"]
 }
 
+// execution statement executed by processor, it is passed through enabled hooks of Execute in order, each hook calls next
+// to run the rest of hooks and callbacks of the processor
+type execution struct {
+	*processor
+	db      *DB
+	started time.Time
+	ctx     context.Context // context passed to Logger and MetricsCallback, with fingerprint if QueryFingerprint is enabled
+	hooks   []executeHook
+	done    bool
+}
+
+// executeHook hook of Execute for a feature of statements, e.g: timeout, run is called around inner hooks and callbacks,
+// which are run with next of execution, hooks not enabled for the statement are skipped, nil enabled is always enabled
+type executeHook struct {
+	name    string
+	enabled func(e *execution) bool
+	run     func(e *execution)
+}
+
+// executeHooks returns hooks of Execute from the outermost one, e.g: vars are released after statements are traced, which
+// is after errors of callbacks are wrapped
+func executeHooks() []executeHook {
+	return []executeHook{
+		{name: "gorm:release_vars", enabled: func(e *execution) bool { return !e.db.DryRun }, run: releaseVarsHook},
+		{name: "gorm:count_statements", enabled: countStatementsEnabled, run: countStatementsHook},
+		{name: "gorm:metrics", enabled: func(e *execution) bool { return e.db.Statement.DB.MetricsCallback != nil }, run: metricsHook},
+		{name: "gorm:trace", run: traceHook},
+		{name: "gorm:fingerprint", enabled: func(e *execution) bool { return e.db.Statement.DB.QueryFingerprint }, run: fingerprintHook},
+		{name: "gorm:wrap_query_errors", enabled: func(e *execution) bool { return e.db.Statement.DB.WrapQueryErrors }, run: wrapQueryErrorsHook},
+		{name: "gorm:strict_columns", enabled: func(e *execution) bool { return e.db.Statement.DB.StrictColumns }, run: strictColumnsHook},
+		{name: "gorm:interceptors", enabled: func(e *execution) bool { return len(e.db.Statement.DB.Interceptors) > 0 }, run: interceptorsHook},
+		{name: "gorm:timeout", enabled: timeoutEnabled, run: timeoutHook},
+		{name: "gorm:tenant", enabled: tenantEnabled, run: tenantHook},
+		{name: "gorm:replicas", enabled: func(e *execution) bool { return e.db.Statement.DB.Replicas != nil }, run: replicasHook},
+		{name: "gorm:reject_writes", enabled: func(e *execution) bool { return e.db.Statement.DB.RejectWrites }, run: rejectWritesHook},
+		{name: "gorm:session_variables", enabled: sessionVariablesEnabled, run: sessionVariablesHook},
+	}
+}
+
+// next runs the next enabled hook, callbacks of processor are run after all hooks, next is no-op after the rest of hooks
+// and callbacks returned, e.g: inner hook skipped callbacks
+func (e *execution) next() {
+	if e.done {
+		return
+	}
+
+	for len(e.hooks) > 0 {
+		hook := e.hooks[0]
+		e.hooks = e.hooks[1:]
+		if hook.enabled == nil || hook.enabled(e) {
+			hook.run(e)
+			e.done = true
+			return
+		}
+	}
+
+	e.done = true
+	for _, f := range e.fns {
+		f(e.db)
+	}
+}
+
+// context returns context passed to Logger and MetricsCallback
+func (e *execution) context() context.Context {
+	if e.ctx != nil {
+		return e.ctx
+	}
+	return e.db.Statement.Context
+}
+
 func (p *processor) Execute(db *DB) {
-	curTime := time.Now()
+	e := &execution{processor: p, db: db, started: time.Now(), hooks: p.hooks}
 	stmt := db.Statement
 
 	if stmt.Model == nil {
@@ -106,17 +189,73 @@ func (p *processor) Execute(db *DB) {
 		}
 	}
 
-	for _, f := range p.fns {
-		f(db)
-	}
+	e.next()
+}
+
+// traceHook logs statement with Logger after executing
+func traceHook(e *execution) {
+	e.next()
 
-	db.Logger.Trace(stmt.Context, curTime, func() (string, int64) {
-		return db.Dialector.Explain(stmt.SQL.String(), stmt.Vars...), db.RowsAffected
+	db, stmt := e.db, e.db.Statement
+	db.Logger.Trace(e.context(), e.started, func() (string, int64) {
+		return db.Dialector.Explain(stmt.SQL.String(), stmt.redactedVars()...), db.RowsAffected
 	}, db.Error)
+}
+
+// fingerprintHook adds fingerprint of executed statement to context of Logger and MetricsCallback
+func fingerprintHook(e *execution) {
+	e.next()
+
+	if stmt := e.db.Statement; stmt.SQL.Len() > 0 && stmt.Context != nil {
+		e.ctx = logger.WithFingerprint(stmt.Context, logger.Fingerprint(stmt.SQL.String()))
+	}
+}
+
+// metricsHook calls MetricsCallback with metrics of executed statement
+func metricsHook(e *execution) {
+	e.next()
+
+	if db, stmt := e.db, e.db.Statement; stmt.SQL.Len() > 0 {
+		ctx := e.context()
+		stmt.DB.MetricsCallback(ctx, QueryMetrics{
+			SQL: stmt.SQL.String(), Fingerprint: logger.FingerprintFromContext(ctx), RowsAffected: db.RowsAffected,
+			Elapsed: time.Since(e.started), Error: db.Error,
+		})
+	}
+}
+
+// timeoutEnabled returns true if statement has timeout or there is StatementTimeout
+func timeoutEnabled(e *execution) bool {
+	return e.db.Statement.Timeout != 0 || e.db.Statement.DB.StatementTimeout != 0
+}
+
+// timeoutHook executes statement with context canceled after its timeout, Row and Rows ignore timeout as their contexts
+// can't be released when rows are closed, rows are read after executing
+func timeoutHook(e *execution) {
+	stmt := e.db.Statement
+	if stmt.Timeout == 0 {
+		stmt.Timeout = stmt.DB.StatementTimeout
+	}
+
+	if e.name != "row" {
+		defer stmt.withTimeout()()
+	}
+	e.next()
+}
+
+// withTimeout replaces context of statement with context canceled after timeout of statement, restore releases the
+// context and restores the original one
+func (stmt *Statement) withTimeout() (restore func()) {
+	parentCtx := stmt.Context
+	if stmt.Timeout <= 0 || parentCtx == nil {
+		return func() {}
+	}
 
-	if !stmt.DB.DryRun {
-		stmt.SQL.Reset()
-		stmt.Vars = nil
+	ctx, cancel := context.WithTimeout(parentCtx, stmt.Timeout)
+	stmt.Context = ctx
+	return func() {
+		cancel()
+		stmt.Context = parentCtx
 	}
 }
 
@@ -1,6 +1,7 @@
 package callbacks
 
 import (
+	"fmt"
 	"reflect"
 	"strings"
 
@@ -305,8 +306,13 @@ func SaveAfterAssociations(db *gorm.DB) {
 	}
 }
 
-func onConflictOption(stmt *gorm.Statement, s *schema.Schema, selectColumns map[string]bool, restricted bool, defaultUpdatingColumns []string) clause.OnConflict {
-	if stmt.DB.FullSaveAssociations {
+// fullSaveAssociations returns whether to upsert the relation's records, check Statement.FullSaveAssociation
+func fullSaveAssociations(db *gorm.DB, rel *schema.Relationship) bool {
+	return db.Statement.FullSaveAssociation(rel.Name)
+}
+
+func onConflictOption(fullSave bool, s *schema.Schema, selectColumns map[string]bool, restricted bool, defaultUpdatingColumns []string) clause.OnConflict {
+	if fullSave {
 		defaultUpdatingColumns = make([]string, 0, len(s.DBNames))
 		for _, dbName := range s.DBNames {
 			if v, ok := selectColumns[dbName]; (ok && !v) || (!ok && restricted) {
@@ -317,6 +323,15 @@ func onConflictOption(stmt *gorm.Statement, s *schema.Schema, selectColumns map[
 				defaultUpdatingColumns = append(defaultUpdatingColumns, dbName)
 			}
 		}
+	} else {
+		// foreign keys could be part of composite primary keys, which are not updated
+		updatingColumns := make([]string, 0, len(defaultUpdatingColumns))
+		for _, dbName := range defaultUpdatingColumns {
+			if field := s.LookUpField(dbName); field == nil || !field.PrimaryKey {
+				updatingColumns = append(updatingColumns, dbName)
+			}
+		}
+		defaultUpdatingColumns = updatingColumns
 	}
 
 	if len(defaultUpdatingColumns) > 0 {
@@ -334,10 +349,81 @@ func onConflictOption(stmt *gorm.Statement, s *schema.Schema, selectColumns map[
 	return clause.OnConflict{DoNothing: true}
 }
 
+// savingRecords addresses of records being saved in the path of saving nested associations, with names of their models
+type savingRecords map[uintptr]string
+
+// planSavingRecords returns records of values to be saved, records appear more than once are saved once, records being saved
+// in the path are skipped if they have primary keys, e.g: pets referencing the user having them, otherwise their foreign keys
+// can't be assigned, ErrCyclicAssociation is returned
+//
+// values are records of the relationship of all records of the level, they are saved with one statement, belongs-to
+// associations are saved before their owners and others after them, so levels are saved in order of dependencies
+func planSavingRecords(db *gorm.DB, rel *schema.Relationship, values interface{}) (interface{}, savingRecords, error) {
+	records := savingRecords{}
+	if v, ok := db.Get("gorm:saving_records"); ok {
+		for addr, name := range v.(savingRecords) {
+			records[addr] = name
+		}
+	}
+
+	addRecord := func(rv reflect.Value) {
+		if rv = reflect.Indirect(rv); rv.Kind() == reflect.Struct && rv.CanAddr() {
+			records[rv.UnsafeAddr()] = db.Statement.Schema.Name
+		}
+	}
+
+	switch db.Statement.ReflectValue.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < db.Statement.ReflectValue.Len(); i++ {
+			addRecord(db.Statement.ReflectValue.Index(i))
+		}
+	case reflect.Struct:
+		addRecord(db.Statement.ReflectValue)
+	}
+
+	var (
+		rv      = reflect.ValueOf(values)
+		elems   = rv
+		planned reflect.Value
+		saved   = map[uintptr]bool{}
+	)
+
+	if rv.Kind() == reflect.Ptr {
+		elems = reflect.Append(reflect.MakeSlice(reflect.SliceOf(rv.Type()), 0, 1), rv)
+	}
+	planned = reflect.MakeSlice(elems.Type(), 0, elems.Len())
+
+	for i := 0; i < elems.Len(); i++ {
+		elem := elems.Index(i)
+		if saved[elem.Pointer()] {
+			continue
+		}
+		saved[elem.Pointer()] = true
+
+		if name, ok := records[elem.Pointer()]; ok {
+			for _, field := range rel.FieldSchema.PrimaryFields {
+				if _, isZero := field.ValueOf(elem); isZero {
+					return nil, nil, fmt.Errorf("%w: %v of %v references %v being saved", gorm.ErrCyclicAssociation, rel.Name, rel.Schema.Name, name)
+				}
+			}
+			continue
+		}
+		planned = reflect.Append(planned, elem)
+	}
+
+	if planned.Len() == 0 {
+		return nil, records, nil
+	} else if rv.Kind() == reflect.Ptr {
+		return planned.Index(0).Interface(), records, nil
+	}
+	return planned.Interface(), records, nil
+}
+
 func saveAssociations(db *gorm.DB, rel *schema.Relationship, values interface{}, selectColumns map[string]bool, restricted bool, defaultUpdatingColumns []string) error {
 	var (
 		selects, omits []string
-		onConflict     = onConflictOption(db.Statement, rel.FieldSchema, selectColumns, restricted, defaultUpdatingColumns)
+		fullSave       = fullSaveAssociations(db, rel)
+		onConflict     = onConflictOption(fullSave, rel.FieldSchema, selectColumns, restricted, defaultUpdatingColumns)
 		refName        = rel.Name + "."
 	)
 
@@ -358,6 +444,11 @@ func saveAssociations(db *gorm.DB, rel *schema.Relationship, values interface{},
 		}
 	}
 
+	values, records, err := planSavingRecords(db, rel, values)
+	if err != nil || values == nil {
+		return db.AddError(err)
+	}
+
 	tx := db.Session(&gorm.Session{NewDB: true}).Clauses(onConflict).Session(&gorm.Session{
 		SkipHooks:                db.Statement.SkipHooks,
 		DisableNestedTransaction: true,
@@ -367,6 +458,12 @@ func saveAssociations(db *gorm.DB, rel *schema.Relationship, values interface{},
 		tx.Statement.Settings.Store(k, v)
 		return true
 	})
+	tx.Statement.Settings.Store("gorm:saving_records", records)
+
+	// nested associations' settings are prefixed with the relation name, e.g: "Pets.Toy"
+	if nested, ok := db.Statement.NestedFullSaveAssociations(rel.Name); ok {
+		tx.Statement.Settings.Store("gorm:full_save_associations", nested)
+	}
 
 	if len(selects) > 0 {
 		tx = tx.Select(selects)
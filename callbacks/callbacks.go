@@ -18,12 +18,18 @@ func RegisterDefaultCallbacks(db *gorm.DB, config *Config) {
 	createCallback.Match(enableTransaction).Register("gorm:begin_transaction", BeginTransaction)
 	createCallback.Register("gorm:before_create", BeforeCreate)
 	createCallback.Register("gorm:save_before_associations", SaveBeforeAssociations)
+	createCallback.Register("gorm:assign_tenant", AssignTenant)
+	createCallback.Register("gorm:route_partition", RoutePartition)
 	createCallback.Register("gorm:create", Create(config))
 	createCallback.Register("gorm:save_after_associations", SaveAfterAssociations)
 	createCallback.Register("gorm:after_create", AfterCreate)
+	createCallback.Register("gorm:transaction_hooks", RegisterTransactionHooks)
 	createCallback.Match(enableTransaction).Register("gorm:commit_or_rollback_transaction", CommitOrRollbackTransaction)
 
 	queryCallback := db.Callback().Query()
+	queryCallback.Register("gorm:scope_tenant", ScopeTenant)
+	queryCallback.Register("gorm:route_partition", RoutePartition)
+	queryCallback.Register("gorm:as_of", QueryAsOf)
 	queryCallback.Register("gorm:query", Query)
 	queryCallback.Register("gorm:preload", Preload)
 	queryCallback.Register("gorm:after_query", AfterQuery)
@@ -32,8 +38,11 @@ func RegisterDefaultCallbacks(db *gorm.DB, config *Config) {
 	deleteCallback.Match(enableTransaction).Register("gorm:begin_transaction", BeginTransaction)
 	deleteCallback.Register("gorm:before_delete", BeforeDelete)
 	deleteCallback.Register("gorm:delete_before_associations", DeleteBeforeAssociations)
+	deleteCallback.Register("gorm:scope_tenant", ScopeTenant)
+	deleteCallback.Register("gorm:route_partition", RoutePartition)
 	deleteCallback.Register("gorm:delete", Delete)
 	deleteCallback.Register("gorm:after_delete", AfterDelete)
+	deleteCallback.Register("gorm:transaction_hooks", RegisterTransactionHooks)
 	deleteCallback.Match(enableTransaction).Register("gorm:commit_or_rollback_transaction", CommitOrRollbackTransaction)
 
 	updateCallback := db.Callback().Update()
@@ -41,11 +50,15 @@ func RegisterDefaultCallbacks(db *gorm.DB, config *Config) {
 	updateCallback.Register("gorm:setup_reflect_value", SetupUpdateReflectValue)
 	updateCallback.Register("gorm:before_update", BeforeUpdate)
 	updateCallback.Register("gorm:save_before_associations", SaveBeforeAssociations)
+	updateCallback.Register("gorm:scope_tenant", ScopeTenant)
+	updateCallback.Register("gorm:route_partition", RoutePartition)
 	updateCallback.Register("gorm:update", Update)
 	updateCallback.Register("gorm:save_after_associations", SaveAfterAssociations)
 	updateCallback.Register("gorm:after_update", AfterUpdate)
+	updateCallback.Register("gorm:transaction_hooks", RegisterTransactionHooks)
 	updateCallback.Match(enableTransaction).Register("gorm:commit_or_rollback_transaction", CommitOrRollbackTransaction)
 
+	db.Callback().Row().Register("gorm:scope_tenant", ScopeTenant)
 	db.Callback().Row().Register("gorm:row", RowQuery)
 	db.Callback().Raw().Register("gorm:raw", RawExec)
 }
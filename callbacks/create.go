@@ -7,6 +7,7 @@ import (
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 	"gorm.io/gorm/schema"
+	"gorm.io/gorm/utils"
 )
 
 func BeforeCreate(db *gorm.DB) {
@@ -36,6 +37,10 @@ func Create(config *Config) func(db *gorm.DB) {
 	} else {
 		return func(db *gorm.DB) {
 			if db.Error == nil {
+				if db.AddError(checkReadOnly(db.Statement)) != nil {
+					return
+				}
+
 				if db.Statement.Schema != nil && !db.Statement.Unscoped {
 					for _, c := range db.Statement.Schema.CreateClauses {
 						db.Statement.AddClause(c)
@@ -45,9 +50,22 @@ func Create(config *Config) func(db *gorm.DB) {
 				if db.Statement.SQL.String() == "" {
 					db.Statement.SQL.Grow(180)
 					db.Statement.AddClauseIfNotExists(clause.Insert{})
-					db.Statement.AddClause(ConvertToCreateValues(db.Statement))
+					values := ConvertToCreateValues(db.Statement)
+					if db.AddError(checkCreateEnumValues(db.Statement, values)) == nil && db.AddError(validateCreateValues(db, values)) == nil {
+						db.AddError(serializeCreateValues(db.Statement, values))
+					}
+					db.Statement.AddClause(values)
 
-					db.Statement.Build("INSERT", "VALUES", "ON CONFLICT")
+					if hasReturning(db) {
+						db.Statement.Build("INSERT", "VALUES", "ON CONFLICT", "RETURNING")
+					} else {
+						db.Statement.Build("INSERT", "VALUES", "ON CONFLICT")
+					}
+				}
+
+				if hasReturning(db) {
+					createReturning(db)
+					return
 				}
 
 				if !db.DryRun && db.Error == nil {
@@ -96,6 +114,10 @@ func Create(config *Config) func(db *gorm.DB) {
 									db.AddError(err)
 								}
 							}
+
+							if db.Statement.Schema != nil && len(db.Statement.Schema.FieldsWithReturning) > 0 {
+								fetchReturningFields(db)
+							}
 						}
 					} else {
 						db.AddError(err)
@@ -106,8 +128,100 @@ func Create(config *Config) func(db *gorm.DB) {
 	}
 }
 
+// hasReturning returns true if statement has RETURNING clause and the database supports it, the clause is ignored otherwise
+func hasReturning(db *gorm.DB) bool {
+	_, ok := db.Statement.Clauses["RETURNING"]
+	return ok && db.Capabilities().Returning
+}
+
+// createReturning executes INSERT with RETURNING clause, returned rows are scanned into values being created by index,
+// records conflicted with `ON CONFLICT DO NOTHING` aren't returned, so records with primary key are skipped for it
+func createReturning(db *gorm.DB) {
+	if db.DryRun || db.Error != nil {
+		return
+	}
+
+	rows, err := db.Statement.ConnPool.QueryContext(db.Statement.Context, db.Statement.SQL.String(), db.Statement.Vars...)
+	if err != nil {
+		db.AddError(err)
+		return
+	}
+	defer rows.Close()
+
+	var reflectValues []reflect.Value
+	switch db.Statement.ReflectValue.Kind() {
+	case reflect.Slice, reflect.Array:
+		c := db.Statement.Clauses["ON CONFLICT"]
+		onConflict, _ := c.Expression.(clause.OnConflict)
+
+		for i := 0; i < db.Statement.ReflectValue.Len(); i++ {
+			reflectValue := reflect.Indirect(db.Statement.ReflectValue.Index(i))
+			if reflectValue.Kind() != reflect.Struct {
+				continue
+			}
+
+			if sch := db.Statement.Schema; onConflict.DoNothing && sch != nil && sch.PrioritizedPrimaryField != nil {
+				if _, isZero := sch.PrioritizedPrimaryField.ValueOf(reflectValue); !isZero {
+					continue
+				}
+			}
+			reflectValues = append(reflectValues, reflectValue)
+		}
+	case reflect.Struct:
+		reflectValues = append(reflectValues, db.Statement.ReflectValue)
+	}
+
+	gorm.ScanInto(rows, db, reflectValues...)
+}
+
+// fetchReturningFields reload fields tagged with `returning` by primary keys, for dialects don't support RETURNING
+func fetchReturningFields(db *gorm.DB) {
+	var (
+		sch                   = db.Statement.Schema
+		columns               = make([]string, 0, len(sch.PrimaryFields)+len(sch.FieldsWithReturning))
+		identityMap, pkValues = schema.GetIdentityFieldValuesMap(db.Statement.ReflectValue, sch.PrimaryFields)
+	)
+
+	if len(pkValues) == 0 {
+		return
+	}
+
+	columns = append(columns, sch.PrimaryFieldDBNames...)
+	for _, field := range sch.FieldsWithReturning {
+		if !field.PrimaryKey {
+			columns = append(columns, field.DBName)
+		}
+	}
+
+	results := sch.MakeSlice().Elem()
+	column, values := schema.ToQueryValues(clause.CurrentTable, sch.PrimaryFieldDBNames, pkValues)
+	tx := db.Session(&gorm.Session{NewDB: true}).Table(db.Statement.Table).Unscoped()
+	if db.AddError(tx.Select(columns).Where(clause.IN{Column: column, Values: values}).Find(results.Addr().Interface()).Error) != nil {
+		return
+	}
+
+	fieldValues := make([]interface{}, len(sch.PrimaryFields))
+	for i := 0; i < results.Len(); i++ {
+		elem := results.Index(i)
+		for idx, field := range sch.PrimaryFields {
+			fieldValues[idx], _ = field.ValueOf(elem)
+		}
+
+		for _, data := range identityMap[utils.ToStringKey(fieldValues...)] {
+			for _, field := range sch.FieldsWithReturning {
+				fieldValue, _ := field.ValueOf(elem)
+				db.AddError(field.Set(data, fieldValue))
+			}
+		}
+	}
+}
+
 func CreateWithReturning(db *gorm.DB) {
 	if db.Error == nil {
+		if db.AddError(checkReadOnly(db.Statement)) != nil {
+			return
+		}
+
 		if db.Statement.Schema != nil && !db.Statement.Unscoped {
 			for _, c := range db.Statement.Schema.CreateClauses {
 				db.Statement.AddClause(c)
@@ -116,9 +230,22 @@ func CreateWithReturning(db *gorm.DB) {
 
 		if db.Statement.SQL.String() == "" {
 			db.Statement.AddClauseIfNotExists(clause.Insert{})
-			db.Statement.AddClause(ConvertToCreateValues(db.Statement))
+			values := ConvertToCreateValues(db.Statement)
+			if db.AddError(checkCreateEnumValues(db.Statement, values)) == nil && db.AddError(validateCreateValues(db, values)) == nil {
+				db.AddError(serializeCreateValues(db.Statement, values))
+			}
+			db.Statement.AddClause(values)
 
-			db.Statement.Build("INSERT", "VALUES", "ON CONFLICT")
+			if hasReturning(db) {
+				db.Statement.Build("INSERT", "VALUES", "ON CONFLICT", "RETURNING")
+			} else {
+				db.Statement.Build("INSERT", "VALUES", "ON CONFLICT")
+			}
+		}
+
+		if hasReturning(db) {
+			createReturning(db)
+			return
 		}
 
 		if sch := db.Statement.Schema; sch != nil && len(sch.FieldsWithDefaultDBValue) > 0 {
@@ -238,13 +365,14 @@ func ConvertToCreateValues(stmt *gorm.Statement) (values clause.Values) {
 		var (
 			selectColumns, restricted = stmt.SelectAndOmitColumns(true, false)
 			curTime                   = stmt.DB.NowFunc()
+			actor, hasActor           = stmt.Actor()
 			isZero                    bool
 		)
 		values = clause.Values{Columns: make([]clause.Column, 0, len(stmt.Schema.DBNames))}
 
 		for _, db := range stmt.Schema.DBNames {
 			if field := stmt.Schema.FieldsByDBName[db]; !field.HasDefaultValue || field.DefaultValueInterface != nil {
-				if v, ok := selectColumns[db]; (ok && v) || (!ok && (!restricted || field.AutoCreateTime > 0 || field.AutoUpdateTime > 0)) {
+				if v, ok := selectColumns[db]; (ok && v) || (!ok && (!restricted || field.AutoCreateTime > 0 || field.AutoUpdateTime > 0 || (hasActor && (field.AutoCreatedBy || field.AutoUpdatedBy)))) {
 					values.Columns = append(values.Columns, clause.Column{Name: db})
 				}
 			}
@@ -277,6 +405,9 @@ func ConvertToCreateValues(stmt *gorm.Statement) (values clause.Values) {
 						} else if field.AutoCreateTime > 0 || field.AutoUpdateTime > 0 {
 							field.Set(rv, curTime)
 							values.Values[i][idx], _ = field.ValueOf(rv)
+						} else if hasActor && (field.AutoCreatedBy || field.AutoUpdatedBy) {
+							stmt.AddError(field.Set(rv, actor))
+							values.Values[i][idx], _ = field.ValueOf(rv)
 						}
 					} else if field.AutoUpdateTime > 0 {
 						if _, ok := stmt.DB.InstanceGet("gorm:update_track_time"); ok {
@@ -319,6 +450,9 @@ func ConvertToCreateValues(stmt *gorm.Statement) (values clause.Values) {
 					} else if field.AutoCreateTime > 0 || field.AutoUpdateTime > 0 {
 						field.Set(stmt.ReflectValue, curTime)
 						values.Values[0][idx], _ = field.ValueOf(stmt.ReflectValue)
+					} else if hasActor && (field.AutoCreatedBy || field.AutoUpdatedBy) {
+						stmt.AddError(field.Set(stmt.ReflectValue, actor))
+						values.Values[0][idx], _ = field.ValueOf(stmt.ReflectValue)
 					}
 				}
 			}
@@ -348,13 +482,13 @@ func ConvertToCreateValues(stmt *gorm.Statement) (values clause.Values) {
 					}
 				}
 
-				onConflict := clause.OnConflict{
-					Columns:   make([]clause.Column, len(stmt.Schema.PrimaryFieldDBNames)),
-					DoUpdates: clause.AssignmentColumns(columns),
-				}
-
-				for idx, field := range stmt.Schema.PrimaryFields {
-					onConflict.Columns[idx] = clause.Column{Name: field.DBName}
+				// conflict target defaults to all primary keys, including composite primary keys
+				onConflict.UpdateAll = false
+				onConflict.DoUpdates = clause.AssignmentColumns(columns)
+				if len(onConflict.Columns) == 0 && onConflict.OnConstraint == "" {
+					for _, field := range stmt.Schema.PrimaryFields {
+						onConflict.Columns = append(onConflict.Columns, clause.Column{Name: field.DBName})
+					}
 				}
 
 				stmt.AddClause(onConflict)
@@ -103,6 +103,10 @@ func DeleteBeforeAssociations(db *gorm.DB) {
 
 func Delete(db *gorm.DB) {
 	if db.Error == nil {
+		if db.AddError(checkReadOnly(db.Statement)) != nil {
+			return
+		}
+
 		if db.Statement.Schema != nil && !db.Statement.Unscoped {
 			for _, c := range db.Statement.Schema.DeleteClauses {
 				db.Statement.AddClause(c)
@@ -1,10 +1,16 @@
 package callbacks
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
 	"sort"
+	"strings"
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
 )
 
 // ConvertMapToValuesForCreate convert map to values
@@ -88,3 +94,264 @@ func ConvertSliceOfMapToValuesForCreate(stmt *gorm.Statement, mapValues []map[st
 	}
 	return
 }
+
+// checkEnumValues validates values of enum fields in columns
+func checkEnumValues(stmt *gorm.Statement, column string, values ...interface{}) error {
+	if stmt.Schema == nil {
+		return nil
+	}
+
+	field := stmt.Schema.LookUpField(column)
+	if field == nil || len(field.EnumValues) == 0 {
+		return nil
+	}
+
+	for _, value := range values {
+		if valuer, ok := value.(driver.Valuer); ok {
+			value, _ = valuer.Value()
+		}
+
+		reflectValue := reflect.Indirect(reflect.ValueOf(value))
+		if reflectValue.Kind() != reflect.String {
+			continue
+		}
+
+		valid := false
+		for _, enum := range field.EnumValues {
+			if enum == reflectValue.String() {
+				valid = true
+				break
+			}
+		}
+
+		if !valid {
+			return fmt.Errorf("%w: %q for field %s, allowed values: %s", gorm.ErrInvalidEnumValue, reflectValue.String(), field.Name, strings.Join(field.EnumValues, ","))
+		}
+	}
+	return nil
+}
+
+// checkCreateEnumValues validates values of enum fields for creating
+func checkCreateEnumValues(stmt *gorm.Statement, values clause.Values) error {
+	for idx, column := range values.Columns {
+		columnValues := make([]interface{}, 0, len(values.Values))
+		for _, v := range values.Values {
+			if idx < len(v) {
+				columnValues = append(columnValues, v[idx])
+			}
+		}
+
+		if err := checkEnumValues(stmt, column.Name, columnValues...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// serializeValue serializes value of column with Statement.SerializeValue, expressions are kept as is
+func serializeValue(stmt *gorm.Statement, column string, dst reflect.Value, value interface{}) (interface{}, error) {
+	if stmt.Schema == nil {
+		return value, nil
+	}
+
+	field := stmt.Schema.LookUpField(column)
+	if _, ok := value.(clause.Expression); ok || field == nil {
+		return value, nil
+	}
+	return stmt.SerializeValue(field, dst, value)
+}
+
+// serializeCreateValues serializes values of fields having serializer, array, interval and time fields for creating
+func serializeCreateValues(stmt *gorm.Statement, values clause.Values) error {
+	if stmt.Schema == nil {
+		return nil
+	}
+
+	for idx, column := range values.Columns {
+		field := stmt.Schema.LookUpField(column.Name)
+		if field == nil || !stmt.SerializesField(field) {
+			continue
+		}
+
+		for i, v := range values.Values {
+			if idx >= len(v) {
+				continue
+			}
+
+			dst := reflect.Indirect(stmt.ReflectValue)
+			if dst.Kind() == reflect.Slice || dst.Kind() == reflect.Array {
+				dst = reflect.Indirect(dst.Index(i))
+			}
+
+			var err error
+			if v[idx], err = serializeValue(stmt, column.Name, dst, v[idx]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// jsonPatchOriginals returns loaded values of fields with jsonPatch, used to build patches of changed keys when updating
+func jsonPatchOriginals(stmt *gorm.Statement) map[string]map[string]interface{} {
+	if stmt.Schema == nil || stmt.ReflectValue.Kind() != reflect.Struct {
+		return nil
+	}
+
+	updatingValue := reflect.ValueOf(stmt.Dest)
+	for updatingValue.Kind() == reflect.Ptr {
+		updatingValue = updatingValue.Elem()
+	}
+
+	// the loaded value is unknown if model is updated with itself
+	if updatingValue.CanAddr() && stmt.Dest == stmt.Model {
+		return nil
+	}
+
+	if stmt.DB.Capabilities().JSONPatch == "" {
+		return nil
+	}
+
+	var originals map[string]map[string]interface{}
+	for _, field := range stmt.Schema.Fields {
+		if !field.JSONPatch || field.DBName == "" {
+			continue
+		}
+
+		if value, isZero := field.ValueOf(stmt.ReflectValue); !isZero {
+			if original, ok := jsonObjectOf(value); ok {
+				if originals == nil {
+					originals = map[string]map[string]interface{}{}
+				}
+				originals[field.DBName] = original
+			}
+		}
+	}
+	return originals
+}
+
+// jsonObjectOf converts value to json object, returns false if it is not an object
+func jsonObjectOf(value interface{}) (map[string]interface{}, bool) {
+	bytes, err := json.Marshal(value)
+	if err != nil {
+		return nil, false
+	}
+
+	var object map[string]interface{}
+	if err := json.Unmarshal(bytes, &object); err != nil || object == nil {
+		return nil, false
+	}
+	return object, true
+}
+
+// jsonPatchValue builds expression updating changed keys of json column only, e.g: jsonb_set/JSON_SET,
+// returns false if the value can't be patched and should be saved as a whole
+func jsonPatchValue(stmt *gorm.Statement, column string, original map[string]interface{}, value interface{}) (interface{}, bool) {
+	if _, ok := value.(clause.Expression); ok {
+		return nil, false
+	}
+
+	updated, ok := jsonObjectOf(value)
+	if !ok {
+		return nil, false
+	}
+
+	var (
+		syntax = stmt.DB.Capabilities().JSONPatch
+		expr   = clause.Expr{SQL: "?", Vars: []interface{}{clause.Column{Name: column}}}
+		diff   func(path []string, original, updated map[string]interface{}) error
+	)
+
+	if syntax == gorm.SyntaxPostgres {
+		expr.SQL = "CAST(? AS jsonb)"
+	}
+
+	diff = func(path []string, original, updated map[string]interface{}) error {
+		keys := make([]string, 0, len(original)+len(updated))
+		for k := range updated {
+			keys = append(keys, k)
+		}
+		for k := range original {
+			if _, ok := updated[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			keyPath := append(path[:len(path):len(path)], k)
+			ov, inOriginal := original[k]
+			uv, inUpdated := updated[k]
+
+			if !inUpdated {
+				switch syntax {
+				case gorm.SyntaxPostgres:
+					expr = clause.Expr{SQL: "? #- CAST(? AS text[])", Vars: []interface{}{expr, jsonPatchPath(syntax, keyPath)}}
+				case gorm.SyntaxMySQL:
+					expr = clause.Expr{SQL: "JSON_REMOVE(?,?)", Vars: []interface{}{expr, jsonPatchPath(syntax, keyPath)}}
+				default:
+					expr = clause.Expr{SQL: "json_remove(?,?)", Vars: []interface{}{expr, jsonPatchPath(syntax, keyPath)}}
+				}
+				continue
+			}
+
+			if inOriginal && reflect.DeepEqual(ov, uv) {
+				continue
+			}
+
+			om, ok1 := ov.(map[string]interface{})
+			um, ok2 := uv.(map[string]interface{})
+			if ok1 && ok2 {
+				if err := diff(keyPath, om, um); err != nil {
+					return err
+				}
+				continue
+			}
+
+			bytes, err := json.Marshal(uv)
+			if err != nil {
+				return err
+			}
+
+			switch syntax {
+			case gorm.SyntaxPostgres:
+				expr = clause.Expr{SQL: "jsonb_set(?,CAST(? AS text[]),CAST(? AS jsonb))", Vars: []interface{}{expr, jsonPatchPath(syntax, keyPath), string(bytes)}}
+			case gorm.SyntaxMySQL:
+				expr = clause.Expr{SQL: "JSON_SET(?,?,CAST(? AS JSON))", Vars: []interface{}{expr, jsonPatchPath(syntax, keyPath), string(bytes)}}
+			default:
+				expr = clause.Expr{SQL: "json_set(?,?,json(?))", Vars: []interface{}{expr, jsonPatchPath(syntax, keyPath), string(bytes)}}
+			}
+		}
+		return nil
+	}
+
+	if err := diff(nil, original, updated); err != nil {
+		return nil, false
+	}
+	return expr, true
+}
+
+// jsonPatchPath returns path of json key, `{"a","b"}` for postgres, `$."a"."b"` for others
+func jsonPatchPath(syntax gorm.Syntax, path []string) string {
+	if syntax == gorm.SyntaxPostgres {
+		result, _ := schema.ArrayValue(path, true)
+		return result.(string)
+	}
+
+	var builder strings.Builder
+	builder.WriteByte('$')
+	for _, key := range path {
+		builder.WriteString(".\"")
+		builder.WriteString(strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(key))
+		builder.WriteByte('"')
+	}
+	return builder.String()
+}
+
+// checkReadOnly returns ErrReadOnly if model of statement is read-only, e.g: model backed by materialized view
+func checkReadOnly(stmt *gorm.Statement) error {
+	if stmt.Schema != nil && stmt.Schema.MaterializedView {
+		return fmt.Errorf("%w: %v", gorm.ErrReadOnly, stmt.Schema)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package callbacks
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// sqliteTimeFormat format of times written by history triggers of sqlite, times are compared as strings
+const sqliteTimeFormat = "2006-01-02 15:04:05.000"
+
+// QueryAsOf reads historical state of temporal models set with db.AsOf, table is replaced with union of row versions
+// in history table valid at the time, and current rows unchanged since then, tables set with Table are not replaced
+func QueryAsOf(db *gorm.DB) {
+	if db.Error != nil || db.Statement.Schema == nil || db.Statement.Schema.HistoryTable == "" {
+		return
+	}
+
+	value, ok := db.Get("gorm:as_of")
+	if !ok || (db.Statement.TableExpr != nil && len(db.Statement.TableExpr.Vars) > 0) {
+		return
+	}
+
+	at, ok := value.(time.Time)
+	if !ok {
+		db.AddError(fmt.Errorf("%w: gorm:as_of of %v should be time.Time, got %T", gorm.ErrInvalidData, db.Statement.Schema, value))
+		return
+	}
+
+	var (
+		stmt      = db.Statement
+		table     = stmt.Quote(clause.Table{Name: clause.CurrentTable})
+		history   = stmt.Quote(stmt.Schema.HistoryTable)
+		createdAt string
+		columns   = make([]string, 0, len(stmt.Schema.DBNames))
+		primaries = make([]string, 0, len(stmt.Schema.PrimaryFieldDBNames))
+	)
+
+	if stmt.Namespace != "" && !strings.Contains(stmt.Schema.HistoryTable, ".") {
+		history = stmt.Quote(stmt.Namespace + "." + stmt.Schema.HistoryTable)
+	}
+
+	for _, dbName := range stmt.Schema.DBNames {
+		columns = append(columns, stmt.Quote(dbName))
+	}
+
+	for _, dbName := range stmt.Schema.PrimaryFieldDBNames {
+		primaries = append(primaries, history+"."+stmt.Quote(dbName)+" = "+table+"."+stmt.Quote(dbName))
+	}
+
+	if field := stmt.Schema.CreatedTimeField(); field != nil {
+		createdAt = table + "." + stmt.Quote(field.DBName)
+		if stmt.DB.Capabilities().TextTime {
+			createdAt = "strftime('%Y-%m-%d %H:%M:%f', " + createdAt + ")"
+		}
+	}
+
+	// sqlite triggers write times in UTC
+	var asOf interface{} = at
+	if stmt.DB.Capabilities().TextTime {
+		asOf = at.UTC().Format(sqliteTimeFormat)
+	}
+
+	var (
+		validFrom = stmt.Quote(schema.HistoryValidFrom)
+		validTo   = stmt.Quote(schema.HistoryValidTo)
+		sql       = "(SELECT " + strings.Join(columns, ",") + " FROM " + history +
+			" WHERE (" + validFrom + " IS NULL OR " + validFrom + " <= ?) AND " + validTo + " > ?" +
+			" UNION ALL SELECT " + strings.Join(columns, ",") + " FROM " + table +
+			" WHERE NOT EXISTS (SELECT 1 FROM " + history + " WHERE " + strings.Join(primaries, " AND ") + " AND " + history + "." + validTo + " > ?)"
+		vars = []interface{}{asOf, asOf, asOf}
+	)
+
+	if createdAt != "" {
+		sql += " AND " + createdAt + " <= ?"
+		vars = append(vars, asOf)
+	}
+
+	stmt.TableExpr = &clause.Expr{SQL: sql + ") " + stmt.Quote(stmt.Table), Vars: vars}
+}
@@ -1,6 +1,10 @@
 package callbacks
 
-import "gorm.io/gorm"
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
 
 type BeforeCreateInterface interface {
 	BeforeCreate(*gorm.DB) error
@@ -37,3 +41,23 @@ type AfterDeleteInterface interface {
 type AfterFindInterface interface {
 	AfterFind(*gorm.DB) error
 }
+
+type ValidateInterface interface {
+	Validate(context.Context) error
+}
+
+type AfterCommitInterface interface {
+	AfterCommit(context.Context)
+}
+
+type AfterRollbackInterface interface {
+	AfterRollback(context.Context)
+}
+
+type BeforeTransitionInterface interface {
+	BeforeTransition(tx *gorm.DB, field, from, to string) error
+}
+
+type AfterTransitionInterface interface {
+	AfterTransition(tx *gorm.DB, field, from, to string) error
+}
@@ -0,0 +1,17 @@
+package callbacks
+
+import (
+	"gorm.io/gorm"
+)
+
+// RoutePartition routes statements of partitioned models to partitions with PartitionRouter, tables set with Table are not routed
+func RoutePartition(db *gorm.DB) {
+	if db.Error == nil && db.PartitionRouter != nil && db.Statement.Schema != nil && db.Statement.Schema.Partition != nil &&
+		db.Statement.TableExpr == nil && db.Statement.Table == db.Statement.Schema.Table {
+		if partition, err := db.PartitionRouter.Route(db.Statement); err != nil {
+			db.AddError(err)
+		} else if partition != "" {
+			db.Statement.Table = partition
+		}
+	}
+}
@@ -160,8 +160,16 @@ func BuildQuerySQL(db *gorm.DB) {
 		}
 
 		db.Statement.AddClauseIfNotExists(clauseSelect)
+		applyDefaultOrder(db)
 
 		db.Statement.Build("SELECT", "FROM", "WHERE", "GROUP BY", "ORDER BY", "LIMIT", "FOR")
+
+		if sql := db.Statement.SQL.String(); db.Statement.Timeout > 0 && db.Capabilities().ExecutionTimeHint && strings.HasPrefix(sql, "SELECT ") {
+			// abort query in server after the timeout, even if the client is gone
+			db.Statement.SQL.Reset()
+			db.Statement.SQL.WriteString(fmt.Sprintf("SELECT /*+ MAX_EXECUTION_TIME(%d) */ ", db.Statement.Timeout.Milliseconds()))
+			db.Statement.SQL.WriteString(sql[len("SELECT "):])
+		}
 	}
 }
 
@@ -214,3 +222,41 @@ func AfterQuery(db *gorm.DB) {
 		})
 	}
 }
+
+// primaryKeyOrder order of First
+var primaryKeyOrder = clause.OrderByColumn{Column: clause.Column{Table: clause.CurrentTable, Name: clause.PrimaryKey}}
+
+// applyDefaultOrder orders queries of models without order by DefaultOrder of schema, First orders by it before primary key,
+// sub queries, grouped or distinct queries and queries scanned into other types are not ordered
+func applyDefaultOrder(db *gorm.DB) {
+	stmt := db.Statement
+	if stmt.Schema == nil || len(stmt.Schema.DefaultOrder) == 0 || stmt.Distinct || !stmt.ReflectValue.IsValid() {
+		return
+	}
+
+	if _, ok := stmt.Clauses["GROUP BY"]; ok {
+		return
+	}
+
+	if _, ok := db.InstanceGet("gorm:sub_query"); ok {
+		return
+	}
+
+	modelType := stmt.ReflectValue.Type()
+	for modelType.Kind() == reflect.Slice || modelType.Kind() == reflect.Array || modelType.Kind() == reflect.Ptr {
+		modelType = modelType.Elem()
+	}
+
+	if modelType != stmt.Schema.ModelType {
+		return
+	}
+
+	c, ok := stmt.Clauses["ORDER BY"]
+	if !ok || c.Expression == nil {
+		stmt.AddClause(clause.OrderBy{Columns: stmt.Schema.DefaultOrder})
+	} else if orderBy, ok := c.Expression.(clause.OrderBy); ok && len(orderBy.Columns) == 1 && orderBy.Columns[0] == primaryKeyOrder {
+		columns := make([]clause.OrderByColumn, 0, len(stmt.Schema.DefaultOrder)+1)
+		c.Expression = clause.OrderBy{Columns: append(append(columns, stmt.Schema.DefaultOrder...), primaryKeyOrder)}
+		stmt.Clauses["ORDER BY"] = c
+	}
+}
@@ -0,0 +1,160 @@
+package callbacks
+
+import (
+	"database/sql/driver"
+	"reflect"
+	"sort"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// transition transition of state machine field of updating record
+type transition struct {
+	field    *schema.Field
+	from, to string
+}
+
+// stateOf returns state of value of state machine field
+func stateOf(value interface{}) (string, bool) {
+	if valuer, ok := value.(driver.Valuer); ok {
+		value, _ = valuer.Value()
+	}
+
+	if rv := reflect.Indirect(reflect.ValueOf(value)); rv.Kind() == reflect.String {
+		return rv.String(), true
+	}
+	return "", false
+}
+
+// stateOriginals returns current states of state machine fields of updating record, before they are assigned with updating values
+func stateOriginals(stmt *gorm.Statement) map[string]string {
+	if stmt.Schema == nil || stmt.ReflectValue.Kind() != reflect.Struct {
+		return nil
+	}
+
+	updatingValue := reflect.ValueOf(stmt.Dest)
+	for updatingValue.Kind() == reflect.Ptr {
+		updatingValue = updatingValue.Elem()
+	}
+
+	// the loaded value is unknown if model is updated with itself
+	if updatingValue.CanAddr() && stmt.Dest == stmt.Model {
+		return nil
+	}
+
+	var originals map[string]string
+	for _, field := range stmt.Schema.Fields {
+		if len(field.StateTransitions) == 0 || field.DBName == "" {
+			continue
+		}
+
+		if value, isZero := field.ValueOf(stmt.ReflectValue); !isZero {
+			if state, ok := stateOf(value); ok {
+				if originals == nil {
+					originals = map[string]string{}
+				}
+				originals[field.DBName] = state
+			}
+		}
+	}
+	return originals
+}
+
+// restoreStates restores states of updating record assigned with rejected updating values
+func restoreStates(stmt *gorm.Statement, originals map[string]string) {
+	for dbName, state := range originals {
+		if field := stmt.Schema.LookUpField(dbName); field != nil && stmt.ReflectValue.CanAddr() {
+			field.Set(stmt.ReflectValue, state)
+		}
+	}
+}
+
+// checkTransitions checks transitions of state machine fields in set, transitions from current states of updating record
+// are validated, rows are updated only if they are in states allowed to transition to updating states
+func checkTransitions(db *gorm.DB, originals map[string]string, set clause.Set) ([]transition, error) {
+	if db.Statement.Schema == nil {
+		return nil, nil
+	}
+
+	var transitions []transition
+	for _, assignment := range set {
+		field := db.Statement.Schema.LookUpField(assignment.Column.Name)
+		if field == nil || len(field.StateTransitions) == 0 {
+			continue
+		}
+
+		to, ok := stateOf(assignment.Value)
+		if !ok {
+			continue
+		}
+
+		var (
+			from, loaded = originals[field.DBName]
+			states       []string
+			allowed      bool
+		)
+
+		for state, tos := range field.StateTransitions {
+			for _, s := range tos {
+				if s == to {
+					states = append(states, state)
+					allowed = allowed || state == from
+				}
+			}
+		}
+
+		// states are sorted to generate the same SQL for statement caches
+		sort.Strings(states)
+		froms := []interface{}{to}
+		for _, state := range states {
+			froms = append(froms, state)
+		}
+
+		if loaded && from != to {
+			if !allowed {
+				return nil, &gorm.TransitionError{Field: field.Name, From: from, To: to}
+			}
+			transitions = append(transitions, transition{field: field, from: from, to: to})
+		} else if !loaded && len(froms) == 1 {
+			return nil, &gorm.TransitionError{Field: field.Name, To: to}
+		}
+
+		// rows changed to other states concurrently are not updated, conditions are required to update rows
+		if _, ok := db.Statement.Clauses["WHERE"]; ok || db.AllowGlobalUpdate {
+			db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+				clause.IN{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Values: froms},
+			}})
+		}
+	}
+	return transitions, nil
+}
+
+// checkTransitioned returns error if record of transitions is not updated, e.g: it was changed to other states concurrently
+func checkTransitioned(db *gorm.DB, transitions []transition) error {
+	if len(transitions) == 0 || db.RowsAffected > 0 {
+		return nil
+	}
+	return &gorm.TransitionError{Field: transitions[0].field.Name, From: transitions[0].from, To: transitions[0].to}
+}
+
+// callTransitionHooks calls BeforeTransition or AfterTransition methods of updating record for transitions
+func callTransitionHooks(db *gorm.DB, transitions []transition, before bool) {
+	if len(transitions) == 0 || db.Statement.SkipHooks {
+		return
+	}
+
+	callMethod(db, func(value interface{}, tx *gorm.DB) (called bool) {
+		for _, t := range transitions {
+			if i, ok := value.(BeforeTransitionInterface); ok && before {
+				called = true
+				db.AddError(i.BeforeTransition(tx, t.field.Name, t.from, t.to))
+			} else if i, ok := value.(AfterTransitionInterface); ok && !before {
+				called = true
+				db.AddError(i.AfterTransition(tx, t.field.Name, t.from, t.to))
+			}
+		}
+		return called
+	})
+}
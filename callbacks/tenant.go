@@ -0,0 +1,80 @@
+package callbacks
+
+import (
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// tenantScope returns tenant field of statement and tenant id of its context with TenantScope, ok is false if it is not scoped
+func tenantScope(db *gorm.DB) (field *schema.Field, tenantID interface{}, ok bool) {
+	scope := db.TenantScope
+	if db.Error != nil || scope == nil || scope.TenantID == nil || db.Statement.Schema == nil {
+		return nil, nil, false
+	}
+
+	if skip, ok := db.Statement.Settings.Load("gorm:skip_tenant_scope"); ok && skip.(bool) {
+		return nil, nil, false
+	}
+
+	if field = db.Statement.Schema.LookUpField(scope.Column); field == nil {
+		return nil, nil, false
+	}
+
+	if tenantID, ok = scope.TenantID(db.Statement.Context); !ok {
+		db.AddError(fmt.Errorf("%w: %v of %v", gorm.ErrMissingTenant, field.DBName, db.Statement.Schema.Table))
+	}
+	return field, tenantID, ok
+}
+
+// ScopeTenant conditions queries, updates and deletes with tenant of context, raw SQL is not changed
+func ScopeTenant(db *gorm.DB) {
+	if field, tenantID, ok := tenantScope(db); ok && db.Statement.SQL.Len() == 0 {
+		db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+			clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Value: tenantID},
+		}})
+	}
+}
+
+// AssignTenant assigns tenant of context to created records, returns ErrTenantMismatch if records are of other tenants
+func AssignTenant(db *gorm.DB) {
+	field, tenantID, ok := tenantScope(db)
+	if !ok {
+		return
+	}
+
+	assign := func(rv reflect.Value) {
+		if value, zero := field.ValueOf(rv); !zero && fmt.Sprint(value) != fmt.Sprint(tenantID) {
+			db.AddError(fmt.Errorf("%w: %v of %v is %v", gorm.ErrTenantMismatch, field.DBName, db.Statement.Schema.Table, value))
+		} else {
+			db.AddError(field.Set(rv, tenantID))
+		}
+	}
+
+	switch dest := db.Statement.Dest.(type) {
+	case map[string]interface{}:
+		dest[field.DBName] = tenantID
+	case *map[string]interface{}:
+		(*dest)[field.DBName] = tenantID
+	case []map[string]interface{}:
+		for _, m := range dest {
+			m[field.DBName] = tenantID
+		}
+	case *[]map[string]interface{}:
+		for _, m := range *dest {
+			m[field.DBName] = tenantID
+		}
+	default:
+		switch db.Statement.ReflectValue.Kind() {
+		case reflect.Slice, reflect.Array:
+			for i := 0; i < db.Statement.ReflectValue.Len(); i++ {
+				assign(reflect.Indirect(db.Statement.ReflectValue.Index(i)))
+			}
+		case reflect.Struct:
+			assign(db.Statement.ReflectValue)
+		}
+	}
+}
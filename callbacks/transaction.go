@@ -7,8 +7,11 @@ import (
 func BeginTransaction(db *gorm.DB) {
 	if !db.Config.SkipDefaultTransaction {
 		if tx := db.Begin(); tx.Error == nil {
-			db.Statement.ConnPool = tx.Statement.ConnPool
+			db.Statement.ConnPool, db.Statement.InTransaction = tx.Statement.ConnPool, true
 			db.InstanceSet("gorm:started_transaction", true)
+			if hooks, ok := tx.Statement.Settings.Load("gorm:tx_hooks"); ok {
+				db.Statement.Settings.Store("gorm:tx_hooks", hooks)
+			}
 		} else if tx.Error == gorm.ErrInvalidTransaction {
 			tx.Error = nil
 		}
@@ -23,7 +26,29 @@ func CommitOrRollbackTransaction(db *gorm.DB) {
 			} else {
 				db.Rollback()
 			}
-			db.Statement.ConnPool = db.ConnPool
+			db.Statement.ConnPool, db.Statement.InTransaction = db.ConnPool, false
+			db.Statement.Settings.Delete("gorm:tx_hooks")
 		}
 	}
 }
+
+// RegisterTransactionHooks registers AfterCommit and AfterRollback methods of models to current transaction,
+// AfterCommit methods are registered only if there is no error
+func RegisterTransactionHooks(db *gorm.DB) {
+	if db.Statement.Schema != nil && !db.Statement.SkipHooks && (db.Statement.Schema.AfterCommit || db.Statement.Schema.AfterRollback) {
+		callMethod(db, func(value interface{}, tx *gorm.DB) (called bool) {
+			if i, ok := value.(AfterCommitInterface); ok && db.Statement.Schema.AfterCommit {
+				called = true
+				if db.Error == nil {
+					db.AfterCommit(i.AfterCommit)
+				}
+			}
+
+			if i, ok := value.(AfterRollbackInterface); ok && db.Statement.Schema.AfterRollback {
+				called = true
+				db.AfterRollback(i.AfterRollback)
+			}
+			return called
+		})
+	}
+}
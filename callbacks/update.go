@@ -52,6 +52,16 @@ func BeforeUpdate(db *gorm.DB) {
 
 func Update(db *gorm.DB) {
 	if db.Error == nil {
+		var (
+			transitions []transition
+			states      map[string]string
+			err         error
+		)
+
+		if db.AddError(checkReadOnly(db.Statement)) != nil {
+			return
+		}
+
 		if db.Statement.Schema != nil && !db.Statement.Unscoped {
 			for _, c := range db.Statement.Schema.UpdateClauses {
 				db.Statement.AddClause(c)
@@ -61,7 +71,41 @@ func Update(db *gorm.DB) {
 		if db.Statement.SQL.String() == "" {
 			db.Statement.SQL.Grow(180)
 			db.Statement.AddClauseIfNotExists(clause.Update{})
+			jsonOriginals := jsonPatchOriginals(db.Statement)
+			states = stateOriginals(db.Statement)
 			if set := ConvertToAssignments(db.Statement); len(set) != 0 {
+				if db.AddError(validateAssignments(db, set)) != nil {
+					return
+				}
+
+				if transitions, err = checkTransitions(db, states, set); db.AddError(err) != nil {
+					restoreStates(db.Statement, states)
+					return
+				}
+
+				if callTransitionHooks(db, transitions, true); db.Error != nil {
+					restoreStates(db.Statement, states)
+					return
+				}
+
+				for idx, assignment := range set {
+					if db.AddError(checkEnumValues(db.Statement, assignment.Column.Name, assignment.Value)) != nil {
+						return
+					}
+
+					if original, ok := jsonOriginals[assignment.Column.Name]; ok {
+						if value, ok := jsonPatchValue(db.Statement, assignment.Column.Name, original, assignment.Value); ok {
+							set[idx].Value = value
+							continue
+						}
+					}
+
+					value, err := serializeValue(db.Statement, assignment.Column.Name, db.Statement.ReflectValue, assignment.Value)
+					if db.AddError(err) != nil {
+						return
+					}
+					set[idx].Value = value
+				}
 				db.Statement.AddClause(set)
 			} else {
 				return
@@ -79,6 +123,11 @@ func Update(db *gorm.DB) {
 
 			if err == nil {
 				db.RowsAffected, _ = result.RowsAffected()
+				if db.AddError(checkTransitioned(db, transitions)) != nil {
+					restoreStates(db.Statement, states)
+				} else if db.RowsAffected > 0 {
+					callTransitionHooks(db, transitions, false)
+				}
 			} else {
 				db.AddError(err)
 			}
@@ -217,16 +266,26 @@ func ConvertToAssignments(stmt *gorm.Statement) (set clause.Set) {
 						}
 					}
 				}
+
+				if field.AutoUpdatedBy && value[field.Name] == nil && value[field.DBName] == nil {
+					if v, ok := selectColumns[field.DBName]; (ok && v) || !ok {
+						if actor, ok := stmt.Actor(); ok {
+							assignValue(field, actor)
+							set = append(set, clause.Assignment{Column: clause.Column{Name: field.DBName}, Value: actor})
+						}
+					}
+				}
 			}
 		}
 	default:
 		switch updatingValue.Kind() {
 		case reflect.Struct:
+			actor, hasActor := stmt.Actor()
 			set = make([]clause.Assignment, 0, len(stmt.Schema.FieldsByDBName))
 			for _, dbName := range stmt.Schema.DBNames {
 				field := stmt.Schema.LookUpField(dbName)
 				if !field.PrimaryKey || (!updatingValue.CanAddr() || stmt.Dest != stmt.Model) {
-					if v, ok := selectColumns[field.DBName]; (ok && v) || (!ok && (!restricted || (!stmt.SkipHooks && field.AutoUpdateTime > 0))) {
+					if v, ok := selectColumns[field.DBName]; (ok && v) || (!ok && (!restricted || (!stmt.SkipHooks && (field.AutoUpdateTime > 0 || (hasActor && field.AutoUpdatedBy))))) {
 						value, isZero := field.ValueOf(updatingValue)
 						if !stmt.SkipHooks && field.AutoUpdateTime > 0 {
 							if field.AutoUpdateTime == schema.UnixNanosecond {
@@ -239,6 +298,8 @@ func ConvertToAssignments(stmt *gorm.Statement) (set clause.Set) {
 								value = stmt.DB.NowFunc().Unix()
 							}
 							isZero = false
+						} else if !stmt.SkipHooks && hasActor && field.AutoUpdatedBy {
+							value, isZero = actor, false
 						}
 
 						if ok || !isZero {
@@ -0,0 +1,90 @@
+package callbacks
+
+import (
+	"database/sql/driver"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// validateValue validates value of column with validation rules of its field
+func validateValue(stmt *gorm.Statement, column string, value interface{}) (errs gorm.ValidationErrors) {
+	if stmt.Schema == nil {
+		return nil
+	}
+
+	field := stmt.Schema.LookUpField(column)
+	if field == nil || len(field.ValidationRules) == 0 {
+		return nil
+	}
+
+	if _, ok := value.(clause.Expression); ok {
+		return nil
+	} else if valuer, ok := value.(driver.Valuer); ok {
+		value, _ = valuer.Value()
+	}
+
+	for _, rule := range field.ValidationRules {
+		if !rule.Validate(value) {
+			errs = append(errs, &gorm.FieldError{Field: field.Name, Rule: rule.String(), Value: value})
+		}
+	}
+	return errs
+}
+
+// validateModels calls Validate method of models, returned ValidationErrors are merged into errs,
+// other errors are returned directly
+func validateModels(db *gorm.DB, errs gorm.ValidationErrors) error {
+	if db.Statement.Schema == nil || !db.Statement.Schema.Validate || !db.Statement.ReflectValue.IsValid() {
+		if len(errs) > 0 {
+			return errs
+		}
+		return nil
+	}
+
+	var modelErr error
+	callMethod(db, func(value interface{}, tx *gorm.DB) bool {
+		if i, ok := value.(ValidateInterface); ok {
+			if err := i.Validate(db.Statement.Context); err != nil {
+				var fieldErrs gorm.ValidationErrors
+				if errors.As(err, &fieldErrs) {
+					errs = append(errs, fieldErrs...)
+				} else if modelErr == nil {
+					modelErr = err
+				}
+			}
+			return true
+		}
+		return false
+	})
+
+	if modelErr != nil {
+		return modelErr
+	} else if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// validateCreateValues validates values for creating
+func validateCreateValues(db *gorm.DB, values clause.Values) error {
+	var errs gorm.ValidationErrors
+	for idx, column := range values.Columns {
+		for _, v := range values.Values {
+			if idx < len(v) {
+				errs = append(errs, validateValue(db.Statement, column.Name, v[idx])...)
+			}
+		}
+	}
+	return validateModels(db, errs)
+}
+
+// validateAssignments validates assignments for updating
+func validateAssignments(db *gorm.DB, set clause.Set) error {
+	var errs gorm.ValidationErrors
+	for _, assignment := range set {
+		errs = append(errs, validateValue(db.Statement, assignment.Column.Name, assignment.Value)...)
+	}
+	return validateModels(db, errs)
+}
@@ -0,0 +1,109 @@
+package gorm
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func newHookExecution(name string, hooks []executeHook, fns ...func(*DB)) *execution {
+	db := &DB{Config: &Config{}, Statement: &Statement{}}
+	db.Statement.DB = db
+	return &execution{processor: &processor{db: db, name: name, fns: fns, hooks: hooks}, db: db, hooks: hooks}
+}
+
+func TestExecuteHooks(t *testing.T) {
+	var called []string
+	record := func(name string, next bool) func(*execution) {
+		return func(e *execution) {
+			called = append(called, name)
+			if next {
+				e.next()
+				e.next()
+			}
+		}
+	}
+
+	hooks := []executeHook{
+		{name: "outer", run: record("outer", true)},
+		{name: "disabled", enabled: func(*execution) bool { return false }, run: record("disabled", true)},
+		{name: "inner", enabled: func(*execution) bool { return true }, run: record("inner", true)},
+	}
+	newHookExecution("query", hooks, func(*DB) { called = append(called, "callback") }).next()
+	if expects := []string{"outer", "inner", "callback"}; !reflect.DeepEqual(called, expects) {
+		t.Errorf("enabled hooks should be called in order before callbacks once, expects %v, got %v", expects, called)
+	}
+
+	called = nil
+	hooks[2].run = record("inner", false)
+	newHookExecution("query", hooks, func(*DB) { called = append(called, "callback") }).next()
+	if expects := []string{"outer", "inner"}; !reflect.DeepEqual(called, expects) {
+		t.Errorf("callbacks should be skipped if hook doesn't call next, expects %v, got %v", expects, called)
+	}
+}
+
+func TestWrapQueryErrorsHook(t *testing.T) {
+	errFailed := errors.New("failed")
+	e := newHookExecution("update", nil, func(db *DB) {
+		db.Statement.Table = "users"
+		db.Statement.SQL.WriteString("UPDATE users SET name = ?")
+		db.AddError(errFailed)
+	})
+	wrapQueryErrorsHook(e)
+
+	var queryErr *QueryError
+	if !errors.As(e.db.Error, &queryErr) || !errors.Is(e.db.Error, errFailed) || queryErr.Operation != "update" ||
+		queryErr.Table != "users" || queryErr.SQL != "UPDATE users SET name = ?" {
+		t.Errorf("error of executing should be wrapped with QueryError, got %#v", e.db.Error)
+	}
+
+	e = newHookExecution("query", nil, func(db *DB) {
+		db.Statement.SQL.WriteString("SELECT * FROM users")
+	})
+	e.db.Error = errFailed
+	wrapQueryErrorsHook(e)
+	if e.db.Error != errFailed {
+		t.Errorf("error before executing shouldn't be wrapped, got %#v", e.db.Error)
+	}
+}
+
+func TestRejectWritesHook(t *testing.T) {
+	var executed bool
+	e := newHookExecution("delete", nil, func(*DB) { executed = true })
+	rejectWritesHook(e)
+	if !errors.Is(e.db.Error, ErrReadOnlySession) || executed {
+		t.Errorf("writes should be rejected, got error %v, executed %v", e.db.Error, executed)
+	}
+
+	e = newHookExecution("query", nil, func(*DB) { executed = true })
+	rejectWritesHook(e)
+	if e.db.Error != nil || !executed {
+		t.Errorf("reads should be executed, got error %v, executed %v", e.db.Error, executed)
+	}
+}
+
+type vetoInterceptor struct {
+	err    error
+	called *[]string
+}
+
+func (i vetoInterceptor) BeforeQuery(db *DB, operation string) error {
+	*i.called = append(*i.called, "before")
+	return i.err
+}
+
+func (i vetoInterceptor) AfterQuery(db *DB, operation string) {
+	*i.called = append(*i.called, "after")
+}
+
+func TestInterceptorsHook(t *testing.T) {
+	var called []string
+	errVetoed := errors.New("vetoed")
+	e := newHookExecution("create", nil, func(*DB) { called = append(called, "callback") })
+	e.db.Interceptors = []Interceptor{vetoInterceptor{called: &called}, vetoInterceptor{err: errVetoed, called: &called}}
+	interceptorsHook(e)
+
+	if expects := []string{"before", "before", "after"}; !reflect.DeepEqual(called, expects) || !errors.Is(e.db.Error, errVetoed) {
+		t.Errorf("statement should be vetoed, expects %v, got %v, error %v", expects, called, e.db.Error)
+	}
+}
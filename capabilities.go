@@ -0,0 +1,116 @@
+package gorm
+
+// Capabilities features supported by database of dialector, callbacks and plugins branch on them instead of names of dialectors
+type Capabilities struct {
+	Returning         bool // INSERT/UPDATE/DELETE ... RETURNING or equivalent
+	OnConflict        bool // upserting with ON CONFLICT or equivalent, e.g: ON DUPLICATE KEY UPDATE, MERGE
+	SavePoint         bool
+	CTE               bool // WITH common table expressions
+	Lateral           bool // LATERAL joins
+	NestedTransaction bool // nested transactions backed by savepoints
+	MaxPlaceholders   int  // max number of vars of statement
+
+	RowValues             bool // IN with lists of row values, e.g: (a, b) IN ((1, 2)), subqueries are used if not supported
+	ValuesSubquery        bool // VALUES lists as subqueries, e.g: (a, b) IN (VALUES (1, 2)), IN of row values is expanded to ORs if neither is supported
+	ReleaseSavePoint      bool // savepoints could be released before the transaction is finished
+	ReadOnlyTransaction   bool
+	DeferrableTransaction bool
+	TwoPhaseCommit        bool // e.g: PREPARE TRANSACTION, XA
+	SessionVariables      bool // variables set locally in transaction, e.g: set_config
+	ExecutionTimeHint     bool // optimizer hint aborts SELECT after timeout, e.g: MAX_EXECUTION_TIME
+	StatementTimeout      bool // timeout of statements set locally in transaction, e.g: statement_timeout
+	TableComment          bool
+	MaterializedView      bool
+	ConcurrentIndex       bool // indexes created and dropped without locking out writes
+
+	// syntaxes of dialect-specific SQL built by gorm, features are disabled or fall back to portable SQL if empty,
+	// dialectors of compatible databases declare syntaxes of them, e.g: SyntaxPostgres for JSONPatch of cockroachdb
+	AdvisoryLock   Syntax // advisory locks of Lock, e.g: pg_advisory_lock, GET_LOCK, locks are inserted into LocksTable without them
+	JSONPatch      Syntax // changed keys of json columns are updated in place, e.g: jsonb_set, JSON_SET, json_set
+	DateAdd        Syntax // times are added with intervals, e.g: + interval, DATE_ADD, strftime is used without it
+	EstimatedCount Syntax // counts are estimated with statistics and plans, e.g: pg_class, information_schema.tables
+	ErrorCodes     Syntax // errors are translated by codes and messages of database, check ErrorTranslator
+	Collation      Syntax // collations of columns are migrated, e.g: COLLATE, CHARACTER SET of mysql
+	Comment        Syntax // comments of tables, columns and indexes are migrated, e.g: COMMENT ON, ALTER TABLE ... COMMENT
+	Trigger        Syntax // triggers of tables, e.g: functions of postgres triggers, CREATE TRIGGER ... FOR EACH ROW is used without it
+	Partition      Syntax // partitions of tables, e.g: PARTITION BY of postgres, CREATE TABLE ... LIKE of mysql
+	NativeArray    bool   // arrays and intervals are stored as native types, e.g: postgres, they are stored as JSON and microseconds otherwise
+	TextTime       bool   // times are stored as text, e.g: sqlite, they are compared as UTC text
+	XATransaction  bool   // two-phase commit with XA statements instead of PREPARE TRANSACTION, e.g: mysql
+}
+
+// Syntax syntax of dialect-specific SQL of capabilities, named after the database using it
+type Syntax string
+
+const (
+	SyntaxPostgres  Syntax = "postgres"
+	SyntaxMySQL     Syntax = "mysql"
+	SyntaxSQLite    Syntax = "sqlite"
+	SyntaxSQLServer Syntax = "sqlserver"
+)
+
+// CapabilitiesInterface dialector reports capabilities of its database, capabilities of known databases are assumed
+// for dialectors don't implement it
+type CapabilitiesInterface interface {
+	Capabilities() Capabilities
+}
+
+// defaultMaxPlaceholders max number of vars of statement of unknown databases
+const defaultMaxPlaceholders = 999
+
+// knownCapabilities capabilities of known databases of their recent versions, RETURNING of sqlite requires 3.35,
+// dialectors of it declare it with CapabilitiesInterface
+var knownCapabilities = map[string]Capabilities{
+	"postgres": {
+		Returning: true, OnConflict: true, SavePoint: true, CTE: true, Lateral: true, NestedTransaction: true, MaxPlaceholders: 65535,
+		RowValues: true, ValuesSubquery: true, ReleaseSavePoint: true, ReadOnlyTransaction: true, DeferrableTransaction: true, TwoPhaseCommit: true,
+		SessionVariables: true, StatementTimeout: true, TableComment: true, MaterializedView: true, ConcurrentIndex: true,
+		AdvisoryLock: SyntaxPostgres, JSONPatch: SyntaxPostgres, DateAdd: SyntaxPostgres, EstimatedCount: SyntaxPostgres,
+		ErrorCodes: SyntaxPostgres, Collation: SyntaxPostgres, Comment: SyntaxPostgres, Trigger: SyntaxPostgres,
+		Partition: SyntaxPostgres, NativeArray: true,
+	},
+	"mysql": {
+		OnConflict: true, SavePoint: true, CTE: true, Lateral: true, NestedTransaction: true, MaxPlaceholders: 65535,
+		RowValues: true, ReleaseSavePoint: true, ReadOnlyTransaction: true, TwoPhaseCommit: true, ExecutionTimeHint: true,
+		TableComment: true, ConcurrentIndex: true,
+		AdvisoryLock: SyntaxMySQL, JSONPatch: SyntaxMySQL, DateAdd: SyntaxMySQL, EstimatedCount: SyntaxMySQL,
+		ErrorCodes: SyntaxMySQL, Collation: SyntaxMySQL, Comment: SyntaxMySQL, Trigger: SyntaxMySQL, Partition: SyntaxMySQL,
+		XATransaction: true,
+	},
+	"sqlite": {
+		OnConflict: true, SavePoint: true, CTE: true, NestedTransaction: true, MaxPlaceholders: 32766,
+		ValuesSubquery: true, ReleaseSavePoint: true, ReadOnlyTransaction: true,
+		JSONPatch: SyntaxSQLite, ErrorCodes: SyntaxSQLite, Collation: SyntaxSQLite, Trigger: SyntaxSQLite, Partition: SyntaxSQLite,
+		TextTime: true,
+	},
+	// sqlserver returns values with OUTPUT instead of RETURNING, ON CONFLICT is built as MERGE by its dialector
+	"sqlserver": {
+		OnConflict: true, SavePoint: true, CTE: true, NestedTransaction: true, MaxPlaceholders: 2100,
+		ErrorCodes: SyntaxSQLServer, Trigger: SyntaxSQLServer,
+	},
+}
+
+// Capabilities returns capabilities of database of dialector, check Capabilities for details
+//    if db.Capabilities().Returning {
+//      db.Clauses(clause.Returning{}).Create(&users)
+//    }
+func (db *DB) Capabilities() Capabilities {
+	if db.Dialector == nil {
+		return Capabilities{MaxPlaceholders: defaultMaxPlaceholders}
+	} else if c, ok := db.Dialector.(CapabilitiesInterface); ok {
+		capabilities := c.Capabilities()
+		if capabilities.MaxPlaceholders <= 0 {
+			capabilities.MaxPlaceholders = defaultMaxPlaceholders
+		}
+		return capabilities
+	} else if capabilities, ok := knownCapabilities[db.Dialector.Name()]; ok {
+		return capabilities
+	}
+
+	// unknown databases are assumed to work as before capabilities are introduced
+	_, savePoint := db.Dialector.(SavePointerDialectorInterface)
+	return Capabilities{
+		OnConflict: true, SavePoint: savePoint, NestedTransaction: savePoint, MaxPlaceholders: defaultMaxPlaceholders,
+		RowValues: true, ReleaseSavePoint: true, ReadOnlyTransaction: true,
+	}
+}
@@ -0,0 +1,175 @@
+// Package cdc captures row level changes made through gorm, changes are delivered to subscribers after the transaction
+// made them is committed, they are discarded if it is rolled back, e.g: for cache busting or publishing events
+//    capture := cdc.New().Subscribe(func(ctx context.Context, change cdc.Change) {
+//      cache.Delete(change.Table, change.PrimaryKey)
+//    })
+//    db.Use(capture)
+package cdc
+
+import (
+	"context"
+	"sync"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+	"gorm.io/gorm/utils/snapshot"
+)
+
+// PluginName name of the plugin, its callbacks are registered as `cdc:<stage>_<operation>`
+const PluginName = "gorm:cdc"
+
+// Operation operation of change
+type Operation string
+
+const (
+	Create Operation = "create"
+	Update Operation = "update"
+	Delete Operation = "delete"
+)
+
+// Change change of a row, Old is nil for created rows, New is nil for deleted rows, values are keyed by column names
+type Change struct {
+	Operation  Operation
+	Table      string
+	PrimaryKey map[string]interface{}
+	Old        map[string]interface{}
+	New        map[string]interface{}
+}
+
+// Subscriber receives changes after they are committed
+type Subscriber func(ctx context.Context, change Change)
+
+// Capture captures changes of models with primary keys, implements gorm.Plugin, rows are read before and after
+// updating and deleting them to capture old and new values, changes made with Table without model or created with maps are not captured
+type Capture struct {
+	mu          sync.RWMutex
+	subscribers []Subscriber
+}
+
+// New returns change capture without subscribers
+func New() *Capture {
+	return &Capture{}
+}
+
+// Name returns name of the plugin
+func (c *Capture) Name() string {
+	return PluginName
+}
+
+// Subscribe registers subscriber receiving committed changes
+func (c *Capture) Subscribe(subscriber Subscriber) *Capture {
+	c.mu.Lock()
+	c.subscribers = append(c.subscribers, subscriber)
+	c.mu.Unlock()
+	return c
+}
+
+// Initialize registers callbacks capturing changes, they run in the transaction of the operation
+func (c *Capture) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	for _, err := range []error{
+		callbacks.Create().After("gorm:create").Before("gorm:commit_or_rollback_transaction").Register("cdc:after_create", c.after(Create)),
+		callbacks.Update().After("gorm:scope_tenant").Before("gorm:update").Register("cdc:before_update", c.before),
+		callbacks.Update().After("gorm:update").Before("gorm:commit_or_rollback_transaction").Register("cdc:after_update", c.after(Update)),
+		callbacks.Delete().After("gorm:scope_tenant").Before("gorm:delete").Register("cdc:before_delete", c.before),
+		callbacks.Delete().After("gorm:delete").Before("gorm:commit_or_rollback_transaction").Register("cdc:after_delete", c.after(Delete)),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// capturing returns true if changes of statement should be captured
+func capturing(db *gorm.DB) bool {
+	return db.Error == nil && !db.DryRun && db.Statement.Schema != nil && len(db.Statement.Schema.PrimaryFields) > 0
+}
+
+// before reads rows going to be updated or deleted
+func (c *Capture) before(db *gorm.DB) {
+	if !capturing(db) {
+		return
+	}
+
+	if rows, ok, err := snapshot.Before(db); err != nil {
+		db.AddError(err)
+	} else if ok {
+		db.InstanceSet(PluginName, rows)
+	}
+}
+
+// after builds changes of rows, and delivers them to subscribers after the transaction is committed
+func (c *Capture) after(operation Operation) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		if !capturing(db) || db.RowsAffected == 0 {
+			return
+		}
+
+		var (
+			stmt      = db.Statement
+			primaries = stmt.Schema.PrimaryFieldDBNames
+			olds      []map[string]interface{}
+			changes   []Change
+		)
+
+		if operation != Create {
+			value, ok := db.InstanceGet(PluginName)
+			if !ok {
+				return
+			}
+			olds = value.([]map[string]interface{})
+		}
+
+		news := map[string]map[string]interface{}{}
+		if operation != Delete {
+			var values [][]interface{}
+			if operation == Create {
+				_, values = schema.GetIdentityFieldValuesMap(stmt.ReflectValue, stmt.Schema.PrimaryFields)
+			} else {
+				for _, old := range olds {
+					values = append(values, snapshot.PrimaryValues(old, primaries))
+				}
+			}
+
+			if len(values) == 0 {
+				return
+			}
+
+			rows, err := snapshot.FindByPrimaryValues(db, values)
+			if err != nil {
+				db.AddError(err)
+				return
+			}
+
+			for _, row := range rows {
+				news[snapshot.Key(row, primaries)] = row
+				if operation == Create {
+					changes = append(changes, Change{Operation: operation, Table: stmt.Table, PrimaryKey: snapshot.PrimaryKey(row, primaries), New: row})
+				}
+			}
+		}
+
+		for _, old := range olds {
+			change := Change{Operation: operation, Table: stmt.Table, PrimaryKey: snapshot.PrimaryKey(old, primaries), Old: old}
+			if operation == Update {
+				change.New = news[snapshot.Key(old, primaries)]
+			}
+			changes = append(changes, change)
+		}
+
+		if len(changes) > 0 {
+			db.AfterCommit(func(ctx context.Context) {
+				c.mu.RLock()
+				subscribers := c.subscribers
+				c.mu.RUnlock()
+
+				for _, change := range changes {
+					for _, subscriber := range subscribers {
+						subscriber(ctx, change)
+					}
+				}
+			})
+		}
+	}
+}
@@ -1,9 +1,11 @@
 package gorm
 
 import (
+	"database/sql"
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
 
 	"gorm.io/gorm/clause"
 	"gorm.io/gorm/utils"
@@ -54,8 +56,11 @@ func (db *DB) Table(name string, args ...interface{}) (tx *DB) {
 		}
 	} else if tables := strings.Split(name, "."); len(tables) == 2 {
 		tx.Statement.TableExpr = &clause.Expr{SQL: tx.Statement.Quote(name)}
-		tx.Statement.Table = tables[1]
+		tx.Statement.Table, tx.Statement.Namespace = tables[1], tables[0]
 		return
+	} else if tx.Namespace != "" {
+		tx.Statement.TableExpr = &clause.Expr{SQL: tx.Statement.Quote(tx.Namespace + "." + name)}
+		tx.Statement.Namespace = tx.Namespace
 	}
 
 	tx.Statement.Table = name
@@ -276,6 +281,54 @@ func (db *DB) Unscoped() (tx *DB) {
 	return
 }
 
+// AsOf reads historical state at time t of temporal models, which declare history tables with schema.HistoryTabler,
+// models without history tables are read in current state
+//    db.AsOf(time.Now().Add(-24 * time.Hour)).First(&user, 1)
+func (db *DB) AsOf(t time.Time) (tx *DB) {
+	tx = db.getInstance()
+	tx.Statement.Settings.Store("gorm:as_of", t)
+	return
+}
+
+// WithTimeout sets timeout of statement, the context of statement is canceled after the timeout, queries of mysql
+// are also hinted with MAX_EXECUTION_TIME, transactions of postgres are started with statement_timeout, so their statements
+// are aborted by server, it overwrites StatementTimeout of config, a negative timeout turns it off, Row and Rows ignore it
+// as rows are read after returning
+//    db.WithTimeout(200 * time.Millisecond).Find(&users)
+func (db *DB) WithTimeout(timeout time.Duration) (tx *DB) {
+	tx = db.getInstance()
+	tx.Statement.Timeout = timeout
+	return
+}
+
+// Prepared uses prepared statements for the statement or skips them, regardless of PrepareStmt of config, e.g: preparing
+// hot queries only, or skipping the prepare round trip of one-off dynamic queries
+//    db.Prepared(true).First(&user, id)
+//    db.Prepared(false).Where("id IN ?", ids).Find(&users)
+func (db *DB) Prepared(enable bool) (tx *DB) {
+	tx = db.getInstance()
+	switch conn := tx.Statement.ConnPool.(type) {
+	case *PreparedStmtDB:
+		if !enable {
+			tx.Statement.ConnPool = conn.ConnPool
+		}
+	case *PreparedStmtTX:
+		if !enable {
+			tx.Statement.ConnPool = conn.Tx
+		}
+	default:
+		if v, ok := tx.cacheStore.Load("preparedStmt"); ok && enable {
+			preparedStmt := v.(*PreparedStmtDB)
+			if sqlTx, ok := conn.(*sql.Tx); ok {
+				tx.Statement.ConnPool = &PreparedStmtTX{Tx: sqlTx, PreparedStmtDB: preparedStmt}
+			} else if conn != nil {
+				tx.Statement.ConnPool = &PreparedStmtDB{ConnPool: conn, Mux: preparedStmt.Mux, Stmts: preparedStmt.Stmts}
+			}
+		}
+	}
+	return
+}
+
 func (db *DB) Raw(sql string, values ...interface{}) (tx *DB) {
 	tx = db.getInstance()
 	tx.Statement.SQL = strings.Builder{}
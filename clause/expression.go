@@ -153,7 +153,24 @@ type IN struct {
 	Values []interface{}
 }
 
+// RowValuesSubqueryBuilder builder requires subquery for IN of row values, e.g: sqlite,
+// `(a,b) IN ((1,2),(3,4))` is built as `(a,b) IN (VALUES (1,2),(3,4))` for it
+type RowValuesSubqueryBuilder interface {
+	RowValuesSubquery() bool
+}
+
+// RowValuesExpansionBuilder builder requires IN of row values of columns to be expanded to ORs, e.g: sqlserver,
+// `(a,b) IN ((1,2),(3,4))` is built as `((a = 1 AND b = 2) OR (a = 3 AND b = 4))` for it
+type RowValuesExpansionBuilder interface {
+	RowValuesExpansion() bool
+}
+
 func (in IN) Build(builder Builder) {
+	if columns, ok := in.expandedColumns(builder); ok {
+		in.writeExpanded(builder, columns)
+		return
+	}
+
 	builder.WriteQuoted(in.Column)
 
 	switch len(in.Values) {
@@ -169,12 +186,70 @@ func (in IN) Build(builder Builder) {
 		fallthrough
 	default:
 		builder.WriteString(" IN (")
-		builder.AddVar(builder, in.Values...)
+		in.writeValues(builder)
 		builder.WriteByte(')')
 	}
 }
 
+func (in IN) writeValues(builder Builder) {
+	if _, ok := in.Values[0].([]interface{}); ok {
+		if b, ok := builder.(RowValuesSubqueryBuilder); ok && b.RowValuesSubquery() {
+			builder.WriteString("VALUES ")
+		}
+	}
+	builder.AddVar(builder, in.Values...)
+}
+
+// expandedColumns returns columns if IN of row values should be expanded to ORs for the builder
+func (in IN) expandedColumns(builder Builder) ([]Column, bool) {
+	columns, ok := in.Column.([]Column)
+	if !ok || len(in.Values) == 0 {
+		return nil, false
+	}
+
+	if _, ok := in.Values[0].([]interface{}); !ok {
+		return nil, false
+	}
+
+	if b, ok := builder.(RowValuesExpansionBuilder); ok && b.RowValuesExpansion() {
+		return columns, true
+	}
+	return nil, false
+}
+
+func (in IN) writeExpanded(builder Builder, columns []Column) {
+	builder.WriteByte('(')
+	for idx, value := range in.Values {
+		if idx > 0 {
+			builder.WriteString(" OR ")
+		}
+
+		values, _ := value.([]interface{})
+		builder.WriteByte('(')
+		for i, column := range columns {
+			if i > 0 {
+				builder.WriteString(" AND ")
+			}
+			builder.WriteQuoted(column)
+			builder.WriteString(" = ")
+			if i < len(values) {
+				builder.AddVar(builder, values[i])
+			} else {
+				builder.WriteString("NULL")
+			}
+		}
+		builder.WriteByte(')')
+	}
+	builder.WriteByte(')')
+}
+
 func (in IN) NegationBuild(builder Builder) {
+	if columns, ok := in.expandedColumns(builder); ok {
+		builder.WriteString("NOT ")
+		in.writeExpanded(builder, columns)
+		return
+	}
+
 	switch len(in.Values) {
 	case 0:
 	case 1:
@@ -189,7 +264,7 @@ func (in IN) NegationBuild(builder Builder) {
 	default:
 		builder.WriteQuoted(in.Column)
 		builder.WriteString(" NOT IN (")
-		builder.AddVar(builder, in.Values...)
+		in.writeValues(builder)
 		builder.WriteByte(')')
 	}
 }
@@ -312,3 +387,55 @@ func eqNilReflect(value interface{}) bool {
 	reflectValue := reflect.ValueOf(value)
 	return reflectValue.Kind() == reflect.Ptr && reflectValue.IsNil()
 }
+
+// ArrayAny whether value equals any element of array column, e.g: ? = ANY(column)
+type ArrayAny Eq
+
+func (arrayAny ArrayAny) Build(builder Builder) {
+	builder.AddVar(builder, arrayAny.Value)
+	builder.WriteString(" = ANY(")
+	builder.WriteQuoted(arrayAny.Column)
+	builder.WriteByte(')')
+}
+
+func (arrayAny ArrayAny) NegationBuild(builder Builder) {
+	builder.AddVar(builder, arrayAny.Value)
+	builder.WriteString(" <> ALL(")
+	builder.WriteQuoted(arrayAny.Column)
+	builder.WriteByte(')')
+}
+
+// ArrayContains whether array column contains all values, e.g: column @> ARRAY[?,?]
+type ArrayContains struct {
+	Column interface{}
+	Values []interface{}
+}
+
+func (contains ArrayContains) Build(builder Builder) {
+	builder.WriteQuoted(contains.Column)
+	builder.WriteString(" @> ")
+	contains.buildValues(builder)
+}
+
+func (contains ArrayContains) NegationBuild(builder Builder) {
+	builder.WriteString("NOT ")
+	builder.WriteQuoted(contains.Column)
+	builder.WriteString(" @> ")
+	contains.buildValues(builder)
+}
+
+func (contains ArrayContains) buildValues(builder Builder) {
+	if len(contains.Values) == 0 {
+		builder.WriteString("'{}'")
+		return
+	}
+
+	builder.WriteString("ARRAY[")
+	for idx, value := range contains.Values {
+		if idx > 0 {
+			builder.WriteByte(',')
+		}
+		builder.AddVar(builder, value)
+	}
+	builder.WriteByte(']')
+}
@@ -136,6 +136,26 @@ func TestExpression(t *testing.T) {
 			clause.Neq{Column: column, Value: (interface{})(nil)},
 		},
 		Result: "`column-name` IS NOT NULL",
+	}, {
+		Expressions: []clause.Expression{
+			clause.ArrayAny{Column: column, Value: "go"},
+		},
+		Result: "? = ANY(`column-name`)",
+	}, {
+		Expressions: []clause.Expression{
+			clause.Not(clause.ArrayAny{Column: column, Value: "go"}),
+		},
+		Result: "? <> ALL(`column-name`)",
+	}, {
+		Expressions: []clause.Expression{
+			clause.ArrayContains{Column: column, Values: []interface{}{"go", "sql"}},
+		},
+		Result: "`column-name` @> ARRAY[?,?]",
+	}, {
+		Expressions: []clause.Expression{
+			clause.Not(clause.ArrayContains{Column: column, Values: []interface{}{"go"}}),
+		},
+		Result: "NOT `column-name` @> ARRAY[?]",
 	}}
 
 	for idx, result := range results {
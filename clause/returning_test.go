@@ -25,6 +25,9 @@ func TestReturning(t *testing.T) {
 				[]clause.Column{{Name: "name"}, {Name: "age"}},
 			}},
 			"SELECT * FROM `users` RETURNING `users`.`id`,`name`,`age`", nil,
+		}, {
+			[]clause.Interface{clause.Select{}, clause.From{}, clause.Returning{}},
+			"SELECT * FROM `users` RETURNING *", nil,
 		},
 	}
 
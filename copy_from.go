@@ -0,0 +1,121 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm/clause"
+)
+
+// BulkLoader dialector loads rows into table with bulk loading protocol of its database, e.g: COPY of postgres,
+// LOAD DATA LOCAL INFILE of mysql, it is used by CopyFrom if the dialector implements it
+type BulkLoader interface {
+	BulkLoad(ctx context.Context, conn ConnPool, table string, columns []string, rows [][]interface{}) (rowsAffected int64, err error)
+}
+
+// CopyFrom ingests records of slice value in bulk, rows are loaded with BulkLoader of dialector if it implements it, otherwise
+// they are created in batches as large as vars limit of database allows, hooks and associations are skipped, primary keys of
+// records loaded with BulkLoader are not assigned back
+//    db.CopyFrom(&orders)
+func (db *DB) CopyFrom(value interface{}) (tx *DB) {
+	tx = db.getInstance()
+	reflectValue := reflect.Indirect(reflect.ValueOf(value))
+	if reflectValue.Kind() != reflect.Slice && reflectValue.Kind() != reflect.Array {
+		tx.AddError(fmt.Errorf("%w: copy from requires slice, got %T", ErrInvalidData, value))
+		return
+	} else if reflectValue.Len() == 0 {
+		return
+	}
+
+	if err := tx.Statement.Parse(value); err != nil {
+		tx.AddError(err)
+		return
+	}
+
+	loader, ok := tx.Dialector.(BulkLoader)
+	if !ok {
+		batchSize := tx.Capabilities().MaxPlaceholders
+		if batchSize /= len(tx.Statement.Schema.DBNames) + 1; batchSize < 1 {
+			batchSize = 1
+		}
+		return tx.Session(&Session{SkipHooks: true}).Omit(clause.Associations).CreateInBatches(value, batchSize)
+	}
+
+	var (
+		s               = tx.Statement.Schema
+		records         = make([]reflect.Value, 0, reflectValue.Len())
+		curTime         = tx.NowFunc()
+		actor, hasActor = tx.Statement.Actor()
+		columns         []string
+		rows            = make([][]interface{}, reflectValue.Len())
+	)
+
+	for i := 0; i < reflectValue.Len(); i++ {
+		record := reflect.Indirect(reflectValue.Index(i))
+		if record.Kind() != reflect.Struct {
+			tx.AddError(fmt.Errorf("slice data #%v is invalid: %w", i, ErrInvalidData))
+			return
+		}
+		records = append(records, record)
+	}
+
+	// columns having default values in database are omitted if they are zero in all records
+	for _, dbName := range s.DBNames {
+		field := s.FieldsByDBName[dbName]
+		if !field.Creatable {
+			continue
+		}
+
+		include := !field.HasDefaultValue || field.DefaultValueInterface != nil
+		for _, record := range records {
+			if _, isZero := field.ValueOf(record); !isZero {
+				include = true
+				break
+			}
+		}
+
+		if include {
+			columns = append(columns, dbName)
+		}
+	}
+
+	// values are converted as values of created records, e.g: serializers, arrays, intervals and time zones
+	for idx, record := range records {
+		rows[idx] = make([]interface{}, len(columns))
+		for i, dbName := range columns {
+			field := s.FieldsByDBName[dbName]
+			value, isZero := field.ValueOf(record)
+			if isZero {
+				if field.DefaultValueInterface != nil {
+					value = field.DefaultValueInterface
+				} else if (field.AutoCreateTime > 0 || field.AutoUpdateTime > 0) && record.CanAddr() {
+					field.Set(record, curTime)
+					value, _ = field.ValueOf(record)
+				} else if hasActor && (field.AutoCreatedBy || field.AutoUpdatedBy) && record.CanAddr() {
+					if err := field.Set(record, actor); err != nil {
+						tx.AddError(err)
+						return
+					}
+					value, _ = field.ValueOf(record)
+				}
+			}
+
+			if tx.Statement.SerializesField(field) {
+				var err error
+				if value, err = tx.Statement.SerializeValue(field, record, value); err != nil {
+					tx.AddError(err)
+					return
+				}
+			}
+			rows[idx][i] = value
+		}
+	}
+
+	if !tx.DryRun {
+		rowsAffected, err := loader.BulkLoad(tx.Statement.Context, tx.Statement.ConnPool, tx.Statement.Table, columns, rows)
+		tx.RowsAffected = rowsAffected
+		tx.AddError(err)
+	}
+	return
+}
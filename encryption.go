@@ -0,0 +1,46 @@
+package gorm
+
+import (
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm/schema"
+)
+
+// ReencryptInBatches re-encrypt fields with encrypted serializer in batches after rotating keys, records are decrypted
+// with the key of their key id and saved with current key of the KeyProvider
+//     db.Model(&User{}).ReencryptInBatches(&[]User{}, 100)
+func (db *DB) ReencryptInBatches(dest interface{}, batchSize int) (tx *DB) {
+	var fields []string
+	return db.FindInBatches(dest, batchSize, func(tx *DB, batch int) error {
+		if fields == nil {
+			for _, field := range tx.Statement.Schema.Fields {
+				switch field.Serializer.(type) {
+				case schema.EncryptedSerializer, *schema.EncryptedSerializer:
+					if field.DBName != "" {
+						fields = append(fields, field.Name)
+					}
+				}
+			}
+
+			if len(fields) == 0 {
+				return fmt.Errorf("%w: no encrypted fields found for %v", ErrInvalidField, tx.Statement.Schema)
+			}
+		}
+
+		return tx.Session(&Session{NewDB: true}).Transaction(func(tx *DB) error {
+			results := reflect.Indirect(reflect.ValueOf(dest))
+			for i := 0; i < results.Len(); i++ {
+				record := results.Index(i)
+				if record.Kind() != reflect.Ptr {
+					record = record.Addr()
+				}
+
+				if err := tx.Model(record.Interface()).Select(fields).Updates(record.Interface()).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+}
@@ -0,0 +1,144 @@
+package gorm
+
+import (
+	"errors"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// ErrorTranslator dialector translates errors of its database, errors are translated by gorm with Capabilities.ErrorCodes
+// if dialector doesn't implement it, check TranslateError
+type ErrorTranslator interface {
+	Translate(err error) error
+}
+
+// TranslatedError error of database translated to gorm error Kind, e.g: ErrDuplicatedKey, with names of violated constraint,
+// table and column if they are reported by database, the error of database driver is unwrapped with errors.As
+//    var translated *gorm.TranslatedError
+//    if errors.Is(err, gorm.ErrDuplicatedKey) && errors.As(err, &translated) {
+//      fmt.Println(translated.Constraint)
+//    }
+type TranslatedError struct {
+	Kind       error
+	Constraint string
+	Table      string
+	Column     string
+	Err        error
+}
+
+// Error returns message of the error of database
+func (e *TranslatedError) Error() string {
+	return e.Err.Error()
+}
+
+// Is returns true if target is Kind of the error
+func (e *TranslatedError) Is(target error) bool {
+	return target == e.Kind
+}
+
+// Unwrap returns the error of database
+func (e *TranslatedError) Unwrap() error {
+	return e.Err
+}
+
+var (
+	sqliteErrorRegexp        = regexp.MustCompile(`^(UNIQUE|FOREIGN KEY|NOT NULL|CHECK) constraint failed(?:: (.+))?`)
+	mysqlKeyRegexp           = regexp.MustCompile("(?:for key|CONSTRAINT|[Cc]olumn|[Cc]heck constraint) [`']([^`']+)[`']")
+	sqlserverConstraintRegex = regexp.MustCompile(`(?:constraint|index|column) ["']([^"']+)["']`)
+)
+
+// translateError translates err of database with ErrorTranslator of dialector, or by codes and messages of its ErrorCodes
+func (db *DB) translateError(err error) error {
+	var translated *TranslatedError
+	if errors.As(err, &translated) {
+		return err
+	}
+
+	if translator, ok := db.Dialector.(ErrorTranslator); ok {
+		return translator.Translate(err)
+	}
+
+	result := &TranslatedError{Err: err}
+	switch syntax := db.Capabilities().ErrorCodes; syntax {
+	case SyntaxSQLite:
+		matches := sqliteErrorRegexp.FindStringSubmatch(err.Error())
+		if len(matches) == 0 {
+			return err
+		}
+
+		result.Kind = map[string]error{
+			"UNIQUE": ErrDuplicatedKey, "FOREIGN KEY": ErrForeignKeyViolated, "NOT NULL": ErrNotNullViolated, "CHECK": ErrCheckConstraintViolated,
+		}[matches[1]]
+
+		// columns of UNIQUE and NOT NULL are reported as `table.column`, name of CHECK constraint is reported
+		if names := strings.Split(strings.Split(matches[2], ",")[0], "."); result.Kind == ErrCheckConstraintViolated {
+			result.Constraint = matches[2]
+		} else if len(names) == 2 {
+			result.Table, result.Column = names[0], names[1]
+		}
+	case SyntaxPostgres:
+		code, ok := errorField(err, "Code").(string)
+		if !ok {
+			return err
+		}
+
+		if result.Kind = map[string]error{
+			"23505": ErrDuplicatedKey, "23503": ErrForeignKeyViolated, "23502": ErrNotNullViolated, "23514": ErrCheckConstraintViolated,
+			"40001": ErrSerializationFailure, "40P01": ErrDeadlock,
+		}[code]; result.Kind == nil {
+			return err
+		}
+
+		result.Constraint, _ = errorField(err, "ConstraintName").(string)
+		result.Table, _ = errorField(err, "TableName").(string)
+		result.Column, _ = errorField(err, "ColumnName").(string)
+	case SyntaxMySQL, SyntaxSQLServer:
+		number := reflect.ValueOf(errorField(err, "Number"))
+		if !number.IsValid() || !number.CanConvert(reflect.TypeOf(int64(0))) {
+			return err
+		}
+
+		var (
+			message = err.Error()
+			kinds   = map[int64]error{1062: ErrDuplicatedKey, 1451: ErrForeignKeyViolated, 1452: ErrForeignKeyViolated, 1048: ErrNotNullViolated, 3819: ErrCheckConstraintViolated, 1213: ErrDeadlock}
+			re      = mysqlKeyRegexp
+		)
+
+		if syntax == SyntaxSQLServer {
+			kinds, re = map[int64]error{2627: ErrDuplicatedKey, 2601: ErrDuplicatedKey, 547: ErrForeignKeyViolated, 515: ErrNotNullViolated, 1205: ErrDeadlock, 3960: ErrSerializationFailure}, sqlserverConstraintRegex
+			if strings.Contains(message, "CHECK constraint") {
+				kinds[547] = ErrCheckConstraintViolated
+			}
+		}
+
+		if result.Kind = kinds[number.Convert(reflect.TypeOf(int64(0))).Int()]; result.Kind == nil {
+			return err
+		}
+
+		if matches := re.FindStringSubmatch(message); len(matches) > 1 {
+			if result.Kind == ErrNotNullViolated {
+				result.Column = matches[1]
+			} else {
+				result.Constraint = matches[1]
+			}
+		}
+	default:
+		return err
+	}
+
+	return result
+}
+
+// errorField returns value of field name of error of database driver in err chain, e.g: `Code` of *pgconn.PgError
+func errorField(err error, name string) interface{} {
+	for ; err != nil; err = errors.Unwrap(err) {
+		value := reflect.Indirect(reflect.ValueOf(err))
+		if value.Kind() == reflect.Struct {
+			if field := value.FieldByName(name); field.IsValid() && field.CanInterface() {
+				return field.Interface()
+			}
+		}
+	}
+	return nil
+}
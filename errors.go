@@ -2,6 +2,7 @@ package gorm
 
 import (
 	"errors"
+	"fmt"
 )
 
 var (
@@ -17,6 +18,8 @@ var (
 	ErrUnsupportedRelation = errors.New("unsupported relations")
 	// ErrPrimaryKeyRequired primary keys required
 	ErrPrimaryKeyRequired = errors.New("primary key required")
+	// ErrUniqueKeyRequired columns of conditions aren't the primary keys or an unique key, e.g: conflict target of FirstOrCreateAtomic
+	ErrUniqueKeyRequired = errors.New("unique key required")
 	// ErrModelValueRequired model value required
 	ErrModelValueRequired = errors.New("model value required")
 	// ErrInvalidData unsupported data
@@ -31,4 +34,111 @@ var (
 	ErrEmptySlice = errors.New("empty slice found")
 	// ErrDryRunModeUnsupported dry run mode unsupported
 	ErrDryRunModeUnsupported = errors.New("dry run mode unsupported")
+	// ErrNullValue NULL value scanned into non-pointer field with NullPolicy NullAsError
+	ErrNullValue = errors.New("NULL value for non-pointer field")
+	// ErrMissingSoftDeleteTime missing soft delete time field
+	ErrMissingSoftDeleteTime = errors.New("missing soft delete time field")
+	// ErrInvalidPage invalid page
+	ErrInvalidPage = errors.New("invalid page")
+	// ErrInvalidEnumValue value not in allowed values of enum field
+	ErrInvalidEnumValue = errors.New("invalid enum value")
+	// ErrInvalidGeometry invalid geometry value
+	ErrInvalidGeometry = errors.New("invalid geometry")
+	// ErrValidationFailed values failed validation before creating or updating, returned as ValidationErrors
+	ErrValidationFailed = errors.New("validation failed")
+	// ErrMigrationNotFound migration not registered
+	ErrMigrationNotFound = errors.New("migration not found")
+	// ErrIrreversibleMigration migration without Down or DownSQL can't be rolled back
+	ErrIrreversibleMigration = errors.New("irreversible migration")
+	// ErrSeedNotFound seed not registered
+	ErrSeedNotFound = errors.New("seed not found")
+	// ErrNamedQueryNotFound named query not loaded
+	ErrNamedQueryNotFound = errors.New("named query not found")
+	// ErrScopeNotFound scope not registered
+	ErrScopeNotFound = errors.New("scope not found")
+	// ErrReadOnly creating, updating or deleting read-only model, e.g: model backed by materialized view
+	ErrReadOnly = errors.New("read-only model")
+	// ErrReadOnlySession creating, updating, deleting or executing statements with session of ReadOnly
+	ErrReadOnlySession = errors.New("read-only session")
+	// ErrSubQueryRequired sub query required
+	ErrSubQueryRequired = errors.New("sub query required")
+	// ErrDuplicatedKey unique constraint violated, translated from errors of database with TranslateError
+	ErrDuplicatedKey = errors.New("duplicated key not allowed")
+	// ErrForeignKeyViolated foreign key constraint violated, translated from errors of database with TranslateError
+	ErrForeignKeyViolated = errors.New("violates foreign key constraint")
+	// ErrNotNullViolated NULL value for NOT NULL column, translated from errors of database with TranslateError
+	ErrNotNullViolated = errors.New("violates not-null constraint")
+	// ErrCheckConstraintViolated check constraint violated, translated from errors of database with TranslateError
+	ErrCheckConstraintViolated = errors.New("violates check constraint")
+	// ErrSerializationFailure transaction can't be serialized, it could be retried, translated from errors of database with TranslateError
+	ErrSerializationFailure = errors.New("serialization failure")
+	// ErrDeadlock transaction aborted by deadlock, it could be retried, translated from errors of database with TranslateError
+	ErrDeadlock = errors.New("deadlock detected")
+	// ErrColumnConversionNotConfirmed converting type of column with `using` expression, which might lose data, is not confirmed
+	ErrColumnConversionNotConfirmed = errors.New("column conversion not confirmed")
+	// ErrMissingTenant statement of model scoped with TenantScope without tenant in context
+	ErrMissingTenant = errors.New("missing tenant")
+	// ErrTenantMismatch creating record of other tenant with TenantScope
+	ErrTenantMismatch = errors.New("tenant mismatch")
+	// ErrTransactionInDoubt some prepared transactions of two-phase commit failed to commit, they need to be resolved manually
+	ErrTransactionInDoubt = errors.New("transaction in doubt")
+	// ErrCyclicAssociation records belong to each other in cycle, their foreign keys can't be assigned when saving them
+	ErrCyclicAssociation = errors.New("cyclic association")
+	// ErrInvalidTransition updating state machine field to state not allowed from its current state, check TransitionError
+	ErrInvalidTransition = errors.New("invalid state transition")
 )
+
+// QueryError error of executing statement with its SQL, table and operation, e.g: `create`, `query`, `update`, `delete`, `row`, `raw`,
+// errors are wrapped with it when WrapQueryErrors is enabled, SQL is parameterized without values of vars
+//    var queryErr *gorm.QueryError
+//    if errors.As(err, &queryErr) {
+//      log.Printf("failed to %v %v with SQL %v", queryErr.Operation, queryErr.Table, queryErr.SQL)
+//    }
+type QueryError struct {
+	SQL       string
+	Table     string
+	Operation string
+	Err       error
+}
+
+// Error returns message of the error
+func (e *QueryError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the error
+func (e *QueryError) Unwrap() error {
+	return e.Err
+}
+
+// wrapQueryErrorsHook wraps error of executing statement with QueryError, errors returned before executing aren't wrapped
+func wrapQueryErrorsHook(e *execution) {
+	db, errBefore := e.db, e.db.Error
+	e.next()
+
+	if stmt := db.Statement; db.Error != nil && db.Error != errBefore && stmt.SQL.Len() > 0 && !errors.Is(db.Error, ErrRecordNotFound) {
+		db.Error = &QueryError{SQL: stmt.SQL.String(), Table: stmt.Table, Operation: e.name, Err: db.Error}
+	}
+}
+
+// TransitionError invalid transition of state machine field declared with schema.StateTransitionsInterface, From is empty
+// if current state is unknown, e.g: to state isn't allowed from any state
+//    var transitionErr *gorm.TransitionError
+//    if errors.As(err, &transitionErr) {
+//      log.Printf("can't change %v from %v to %v", transitionErr.Field, transitionErr.From, transitionErr.To)
+//    }
+type TransitionError struct {
+	Field string
+	From  string
+	To    string
+}
+
+// Error returns message of the error
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("%v: %v from %q to %q", ErrInvalidTransition, e.Field, e.From, e.To)
+}
+
+// Is returns true if target is ErrInvalidTransition
+func (e *TransitionError) Is(target error) bool {
+	return target == ErrInvalidTransition
+}
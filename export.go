@@ -0,0 +1,373 @@
+package gorm
+
+import (
+	"database/sql/driver"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"time"
+
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// ExportFormat format of exported rows
+type ExportFormat string
+
+const (
+	// FormatCSV rows are exported as CSV with header of columns
+	FormatCSV ExportFormat = "csv"
+	// FormatJSONLines rows are exported as JSON lines, with header `{"table": "orders", "columns": ["id", ...]}` as the first line
+	FormatJSONLines ExportFormat = "jsonl"
+)
+
+// exportBatchSize number of rows queried in each page when exporting
+const exportBatchSize = 1000
+
+// WriteCSV write query results to w as CSV with column headers, rows are streamed without loading into memory
+//     db.Model(&Order{}).Where("amount > ?", 100).WriteCSV(w)
+func (db *DB) WriteCSV(w io.Writer) (tx *DB) {
+	writer := csv.NewWriter(w)
+	tx = db.exportRows(func(columns []string) error {
+		return writer.Write(columns)
+	}, func(columns []string, values []interface{}) error {
+		return writer.Write(csvRecord(values))
+	})
+
+	writer.Flush()
+	tx.AddError(writer.Error())
+	return
+}
+
+// WriteJSONLines write query results to w as JSON lines, one object keyed by column for each row
+//     db.Model(&Order{}).Where("amount > ?", 100).WriteJSONLines(w)
+func (db *DB) WriteJSONLines(w io.Writer) (tx *DB) {
+	return db.exportRows(nil, func(columns []string, values []interface{}) error {
+		line, err := jsonLine(columns, values)
+		if err == nil {
+			_, err = w.Write(line)
+		}
+		return err
+	})
+}
+
+// csvRecord returns CSV record of values of row
+func csvRecord(values []interface{}) []string {
+	record := make([]string, len(values))
+	for idx, value := range values {
+		switch v := value.(type) {
+		case nil:
+		case time.Time:
+			record[idx] = v.Format(time.RFC3339Nano)
+		case []byte:
+			record[idx] = string(v)
+		default:
+			record[idx] = fmt.Sprint(v)
+		}
+	}
+	return record
+}
+
+// jsonLine returns JSON line of row, values are keyed by columns in order
+func jsonLine(columns []string, values []interface{}) ([]byte, error) {
+	line := []byte{'{'}
+	for idx, value := range values {
+		if idx > 0 {
+			line = append(line, ',')
+		}
+
+		if b, ok := value.([]byte); ok {
+			value = string(b)
+		}
+
+		key, _ := json.Marshal(columns[idx])
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+
+		line = append(append(append(line, key...), ':'), data...)
+	}
+	return append(line, '}', '\n'), nil
+}
+
+// exportHeader header of rows exported as JSON lines
+type exportHeader struct {
+	Table   string   `json:"table"`
+	Columns []string `json:"columns"`
+}
+
+// Export streams rows of model to w in format, rows are queried in pages ordered by primary key with keyset pagination,
+// conditions of db are applied, rows are exported in one query if model doesn't have single primary key, check Import
+//    db.Export(&Order{}, w, gorm.FormatJSONLines)
+//    db.Where("created_at > ?", since).Export(&Order{}, w, gorm.FormatCSV)
+func (db *DB) Export(model interface{}, w io.Writer, format ExportFormat) (tx *DB) {
+	tx = db.getInstance()
+	stmt := &Statement{DB: tx}
+	if err := stmt.Parse(model); err != nil {
+		tx.AddError(err)
+		return
+	}
+
+	var (
+		writer = csv.NewWriter(w)
+		header func(columns []string) error
+		row    func(columns []string, values []interface{}) error
+	)
+
+	switch format {
+	case FormatCSV:
+		header = writer.Write
+		row = func(columns []string, values []interface{}) error {
+			return writer.Write(csvRecord(values))
+		}
+	case FormatJSONLines:
+		header = func(columns []string) error {
+			data, err := json.Marshal(exportHeader{Table: stmt.Table, Columns: columns})
+			if err == nil {
+				_, err = w.Write(append(data, '\n'))
+			}
+			return err
+		}
+		row = func(columns []string, values []interface{}) error {
+			line, err := jsonLine(columns, values)
+			if err == nil {
+				_, err = w.Write(line)
+			}
+			return err
+		}
+	default:
+		tx.AddError(fmt.Errorf("%w: unknown export format %q", ErrInvalidData, format))
+		return
+	}
+
+	defer func() {
+		writer.Flush()
+		tx.AddError(writer.Error())
+	}()
+
+	base := tx.Model(model).Session(&Session{})
+	if len(stmt.Schema.PrimaryFields) != 1 {
+		result := base.exportRows(header, row)
+		tx.RowsAffected = result.RowsAffected
+		tx.AddError(result.Error)
+		return
+	}
+
+	var (
+		primary = stmt.Schema.PrimaryFields[0].DBName
+		last    interface{}
+	)
+
+	for {
+		query := base.Order(clause.OrderByColumn{Column: clause.Column{Table: clause.CurrentTable, Name: primary}}).Limit(exportBatchSize)
+		if last != nil {
+			query = query.Where(clause.Gt{Column: clause.Column{Table: clause.CurrentTable, Name: primary}, Value: last})
+		}
+
+		result := query.exportRows(header, func(columns []string, values []interface{}) error {
+			for idx, column := range columns {
+				if column == primary {
+					last = values[idx]
+				}
+			}
+			return row(columns, values)
+		})
+
+		tx.RowsAffected += result.RowsAffected
+		if tx.AddError(result.Error) != nil || result.RowsAffected < exportBatchSize || last == nil {
+			return
+		}
+		header = nil
+	}
+}
+
+// Import imports rows exported with Export from r in format into table of model, rows are decoded into records of model and
+// loaded with CopyFrom in batches in one transaction, columns not found in model are ignored
+//    db.Import(&Order{}, r, gorm.FormatJSONLines)
+func (db *DB) Import(model interface{}, r io.Reader, format ExportFormat) (tx *DB) {
+	tx = db.getInstance()
+	stmt := &Statement{DB: tx}
+	if err := stmt.Parse(model); err != nil {
+		tx.AddError(err)
+		return
+	}
+
+	var next func() (map[string]interface{}, error)
+	switch format {
+	case FormatCSV:
+		reader := csv.NewReader(r)
+		columns, err := reader.Read()
+		if err != nil {
+			if err != io.EOF {
+				tx.AddError(err)
+			}
+			return
+		}
+
+		next = func() (map[string]interface{}, error) {
+			record, err := reader.Read()
+			if err != nil {
+				return nil, err
+			}
+
+			values := make(map[string]interface{}, len(columns))
+			for idx, column := range columns {
+				// empty values of CSV are NULL except for non-pointer strings
+				if field := stmt.Schema.LookUpField(column); field != nil && idx < len(record) && (record[idx] != "" || field.FieldType.Kind() == reflect.String) {
+					values[column] = record[idx]
+				}
+			}
+			return values, nil
+		}
+	case FormatJSONLines:
+		decoder := json.NewDecoder(r)
+		decoder.UseNumber()
+		next = func() (map[string]interface{}, error) {
+			for {
+				var values map[string]interface{}
+				if err := decoder.Decode(&values); err != nil {
+					return nil, err
+				}
+
+				// skip header
+				if _, ok := values["columns"].([]interface{}); ok && len(values) == 2 && values["table"] != nil {
+					continue
+				}
+
+				for column, value := range values {
+					if number, ok := value.(json.Number); ok {
+						values[column] = number.String()
+					}
+				}
+				return values, nil
+			}
+		}
+	default:
+		tx.AddError(fmt.Errorf("%w: unknown export format %q", ErrInvalidData, format))
+		return
+	}
+
+	var rowsAffected int64
+	tx.AddError(tx.Transaction(func(tx *DB) error {
+		batch := reflect.MakeSlice(reflect.SliceOf(stmt.Schema.ModelType), 0, exportBatchSize)
+		flush := func() error {
+			if batch.Len() == 0 {
+				return nil
+			}
+
+			records := reflect.New(batch.Type())
+			records.Elem().Set(batch)
+			result := tx.CopyFrom(records.Interface())
+			rowsAffected += result.RowsAffected
+			batch = batch.Slice(0, 0)
+			return result.Error
+		}
+
+		for {
+			values, err := next()
+			if err == io.EOF {
+				return flush()
+			} else if err != nil {
+				return err
+			}
+
+			record := reflect.New(stmt.Schema.ModelType).Elem()
+			for column, value := range values {
+				if field := stmt.Schema.LookUpField(column); field != nil && value != nil {
+					if err := setImportedField(tx, field, record, value); err != nil {
+						return err
+					}
+				}
+			}
+
+			if batch = reflect.Append(batch, record); batch.Len() >= exportBatchSize {
+				if err := flush(); err != nil {
+					return err
+				}
+			}
+		}
+	}))
+	tx.RowsAffected = rowsAffected
+	return
+}
+
+// setImportedField set imported value to field of record, values are exported as raw database values, so values of fields
+// with serializer, arrays and intervals are decoded as scanned values, times are parsed in the exported format
+func setImportedField(db *DB, field *schema.Field, record reflect.Value, value interface{}) error {
+	if isDecodedField(field) {
+		return decodeField(db.Statement.Context, field, record, value)
+	}
+
+	if str, ok := value.(string); ok && field.GORMDataType == schema.Time {
+		if t, err := time.Parse(time.RFC3339Nano, str); err == nil {
+			value = t
+		}
+	}
+	return field.Set(record, value)
+}
+
+func (db *DB) exportRows(header func(columns []string) error, fc func(columns []string, values []interface{}) error) (tx *DB) {
+	if db.requiresSessionTransaction() {
+		// rows are streamed in transaction with session variables
+		tx = db.getInstance()
+		tx.AddError(db.Transaction(func(stx *DB) error {
+			result := stx.exportRows(header, fc)
+			tx.RowsAffected = result.RowsAffected
+			return result.Error
+		}))
+		return
+	}
+
+	tx = db.getInstance()
+	rows, err := tx.Rows()
+	if err != nil {
+		tx.AddError(err)
+		return
+	}
+	defer rows.Close()
+
+	tx.RowsAffected = 0
+	columns, _ := rows.Columns()
+	columnTypes, _ := rows.ColumnTypes()
+	values := make([]interface{}, len(columns))
+	results := make([]interface{}, len(columns))
+
+	if header != nil {
+		if tx.AddError(header(columns)) != nil {
+			return
+		}
+	}
+
+	for rows.Next() {
+		prepareValues(values, tx, columnTypes, columns)
+		if tx.AddError(rows.Scan(values...)) != nil {
+			return
+		}
+
+		for idx, value := range values {
+			results[idx] = nil
+			reflectValue := reflect.ValueOf(value)
+			for reflectValue.Kind() == reflect.Ptr && !reflectValue.IsNil() {
+				reflectValue = reflectValue.Elem()
+			}
+
+			if reflectValue.Kind() != reflect.Ptr && reflectValue.IsValid() {
+				results[idx] = reflectValue.Interface()
+				if valuer, ok := results[idx].(driver.Valuer); ok {
+					results[idx], _ = valuer.Value()
+				}
+			}
+		}
+
+		tx.RowsAffected++
+		if tx.AddError(fc(columns, results)) != nil {
+			return
+		}
+	}
+
+	tx.AddError(rows.Err())
+	return
+}
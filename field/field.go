@@ -0,0 +1,157 @@
+package field
+
+import (
+	"time"
+
+	"gorm.io/gorm/clause"
+)
+
+// Numeric number types could be compared
+type Numeric interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 | ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~float32 | ~float64
+}
+
+// Field typed column reference, used to build conditions
+//     db.Where(query.User.ID.Eq(1)).First(&user)
+type Field[T any] struct {
+	column clause.Column
+}
+
+// New returns a typed column reference of table
+func New[T any](table, name string) Field[T] {
+	return Field[T]{column: clause.Column{Table: table, Name: name}}
+}
+
+// Column returns the column of field
+func (field Field[T]) Column() clause.Column {
+	return field.column
+}
+
+// Eq column = value
+func (field Field[T]) Eq(value T) clause.Expression {
+	return clause.Eq{Column: field.column, Value: value}
+}
+
+// Neq column <> value
+func (field Field[T]) Neq(value T) clause.Expression {
+	return clause.Neq{Column: field.column, Value: value}
+}
+
+// In column IN (values)
+func (field Field[T]) In(values ...T) clause.Expression {
+	inValues := make([]interface{}, len(values))
+	for idx, value := range values {
+		inValues[idx] = value
+	}
+	return clause.IN{Column: field.column, Values: inValues}
+}
+
+// NotIn column NOT IN (values)
+func (field Field[T]) NotIn(values ...T) clause.Expression {
+	return clause.Not(field.In(values...))
+}
+
+// IsNull column IS NULL
+func (field Field[T]) IsNull() clause.Expression {
+	return clause.Eq{Column: field.column, Value: nil}
+}
+
+// IsNotNull column IS NOT NULL
+func (field Field[T]) IsNotNull() clause.Expression {
+	return clause.Neq{Column: field.column, Value: nil}
+}
+
+// Asc order by column ASC
+func (field Field[T]) Asc() clause.OrderByColumn {
+	return clause.OrderByColumn{Column: field.column}
+}
+
+// Desc order by column DESC
+func (field Field[T]) Desc() clause.OrderByColumn {
+	return clause.OrderByColumn{Column: field.column, Desc: true}
+}
+
+// Set assign value to column, used for updates
+func (field Field[T]) Set(value T) clause.Assignment {
+	return clause.Assignment{Column: field.column, Value: value}
+}
+
+// ordered column could be compared with >, >=, <, <=
+type ordered[T any] struct {
+	Field[T]
+}
+
+// Gt column > value
+func (field ordered[T]) Gt(value T) clause.Expression {
+	return clause.Gt{Column: field.column, Value: value}
+}
+
+// Gte column >= value
+func (field ordered[T]) Gte(value T) clause.Expression {
+	return clause.Gte{Column: field.column, Value: value}
+}
+
+// Lt column < value
+func (field ordered[T]) Lt(value T) clause.Expression {
+	return clause.Lt{Column: field.column, Value: value}
+}
+
+// Lte column <= value
+func (field ordered[T]) Lte(value T) clause.Expression {
+	return clause.Lte{Column: field.column, Value: value}
+}
+
+// Between column >= min AND column <= max
+func (field ordered[T]) Between(min, max T) clause.Expression {
+	return clause.And(field.Gte(min), field.Lte(max))
+}
+
+// Number typed numeric column reference
+type Number[T Numeric] struct {
+	ordered[T]
+}
+
+// NewNumber returns a typed numeric column reference of table
+func NewNumber[T Numeric](table, name string) Number[T] {
+	return Number[T]{ordered[T]{New[T](table, name)}}
+}
+
+// String typed string column reference
+type String struct {
+	ordered[string]
+}
+
+// NewString returns a typed string column reference of table
+func NewString(table, name string) String {
+	return String{ordered[string]{New[string](table, name)}}
+}
+
+// Like column LIKE pattern
+func (field String) Like(pattern string) clause.Expression {
+	return clause.Like{Column: field.column, Value: pattern}
+}
+
+// NotLike column NOT LIKE pattern
+func (field String) NotLike(pattern string) clause.Expression {
+	return clause.Not(field.Like(pattern))
+}
+
+// Time typed time column reference
+type Time struct {
+	ordered[time.Time]
+}
+
+// NewTime returns a typed time column reference of table
+func NewTime(table, name string) Time {
+	return Time{ordered[time.Time]{New[time.Time](table, name)}}
+}
+
+// Bool typed bool column reference
+type Bool struct {
+	Field[bool]
+}
+
+// NewBool returns a typed bool column reference of table
+func NewBool(table, name string) Bool {
+	return Bool{New[bool](table, name)}
+}
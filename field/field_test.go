@@ -0,0 +1,76 @@
+package field_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/field"
+	"gorm.io/gorm/utils/tests"
+)
+
+var db, _ = gorm.Open(tests.DummyDialector{}, nil)
+
+func TestField(t *testing.T) {
+	var (
+		now      = time.Now()
+		id       = field.NewNumber[uint]("users", "id")
+		name     = field.NewString("users", "name")
+		birthday = field.NewTime("users", "birthday")
+		active   = field.NewBool("users", "active")
+	)
+
+	results := []struct {
+		Expression clause.Expression
+		Result     string
+		Vars       []interface{}
+	}{{
+		Expression: id.Eq(1),
+		Result:     "`users`.`id` = ?",
+		Vars:       []interface{}{uint(1)},
+	}, {
+		Expression: id.In(1, 2),
+		Result:     "`users`.`id` IN (?,?)",
+		Vars:       []interface{}{uint(1), uint(2)},
+	}, {
+		Expression: id.Between(1, 10),
+		Result:     "(`users`.`id` >= ? AND `users`.`id` <= ?)",
+		Vars:       []interface{}{uint(1), uint(10)},
+	}, {
+		Expression: name.Like("jinzhu%"),
+		Result:     "`users`.`name` LIKE ?",
+		Vars:       []interface{}{"jinzhu%"},
+	}, {
+		Expression: name.Neq("jinzhu"),
+		Result:     "`users`.`name` <> ?",
+		Vars:       []interface{}{"jinzhu"},
+	}, {
+		Expression: birthday.Lt(now),
+		Result:     "`users`.`birthday` < ?",
+		Vars:       []interface{}{now},
+	}, {
+		Expression: birthday.IsNull(),
+		Result:     "`users`.`birthday` IS NULL",
+	}, {
+		Expression: active.Eq(true),
+		Result:     "`users`.`active` = ?",
+		Vars:       []interface{}{true},
+	}}
+
+	for idx, result := range results {
+		t.Run(fmt.Sprintf("case #%v", idx), func(t *testing.T) {
+			stmt := &gorm.Statement{DB: db, Clauses: map[string]clause.Clause{}}
+			result.Expression.Build(stmt)
+			if stmt.SQL.String() != result.Result {
+				t.Errorf("generated SQL is not equal, expects %v, but got %v", result.Result, stmt.SQL.String())
+			}
+
+			if !reflect.DeepEqual(stmt.Vars, result.Vars) {
+				t.Errorf("generated vars is not equal, expects %v, but got %v", result.Vars, stmt.Vars)
+			}
+		})
+	}
+}
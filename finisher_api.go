@@ -2,9 +2,11 @@ package gorm
 
 import (
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"reflect"
+	"strconv"
 	"strings"
 
 	"gorm.io/gorm/clause"
@@ -76,6 +78,25 @@ func (db *DB) Save(value interface{}) (tx *DB) {
 	switch reflectValue.Kind() {
 	case reflect.Slice, reflect.Array:
 		if _, ok := tx.Statement.Clauses["ON CONFLICT"]; !ok {
+			// records are saved one by one if the database can't upsert
+			if !tx.Capabilities().OnConflict {
+				tx.AddError(tx.Session(&Session{}).Transaction(func(saveTx *DB) error {
+					for i := 0; i < reflectValue.Len(); i++ {
+						elem := reflectValue.Index(i)
+						if elem.Kind() != reflect.Ptr {
+							elem = elem.Addr()
+						}
+
+						result := saveTx.Save(elem.Interface())
+						if result.Error != nil {
+							return result.Error
+						}
+						tx.RowsAffected += result.RowsAffected
+					}
+					return nil
+				}))
+				return
+			}
 			tx = tx.Clauses(clause.OnConflict{UpdateAll: true})
 		}
 		tx.callbacks.Create().Execute(tx.InstanceSet("gorm:update_track_time", true))
@@ -101,7 +122,8 @@ func (db *DB) Save(value interface{}) (tx *DB) {
 
 		if tx.Error == nil && tx.RowsAffected == 0 && !tx.DryRun && !selectedUpdate {
 			result := reflect.New(tx.Statement.Schema.ModelType).Interface()
-			if err := tx.Session(&Session{}).First(result).Error; errors.Is(err, ErrRecordNotFound) {
+			// check with RowsAffected too, First doesn't return ErrRecordNotFound when AllowEmptyResult enabled
+			if found := tx.Session(&Session{}).First(result); errors.Is(found.Error, ErrRecordNotFound) || (found.Error == nil && found.RowsAffected == 0) {
 				return tx.Create(value)
 			}
 		}
@@ -170,17 +192,30 @@ func (db *DB) Find(dest interface{}, conds ...interface{}) (tx *DB) {
 	return
 }
 
-// FindInBatches find records in batches
+// FindInBatches find records in batches with keyset pagination, records are ordered by current ORDER BY columns and primary keys,
+// following batches are queried with conditions on the keys of the last record instead of OFFSET, so records won't be skipped
+// or duplicated when others are inserted or deleted concurrently, ORDER BY columns should be fields of the model and not NULL,
+// otherwise batches are queried with OFFSET
+//     db.Where("processed = ?", false).FindInBatches(&results, 100, func(tx *gorm.DB, batch int) error { ... })
+//     db.Order("created_at DESC").FindInBatches(&results, 100, func(tx *gorm.DB, batch int) error { ... })
 func (db *DB) FindInBatches(dest interface{}, batchSize int, fc func(tx *DB, batch int) error) *DB {
 	var (
-		tx = db.Order(clause.OrderByColumn{
-			Column: clause.Column{Table: clause.CurrentTable, Name: clause.PrimaryKey},
-		}).Session(&Session{})
-		queryDB      = tx
+		tx           = db.Session(&Session{})
 		rowsAffected int64
 		batch        int
 	)
 
+	keyFields, keyColumns, keyset := tx.keysetColumns(dest)
+	if tx.Error != nil {
+		return tx
+	} else if !keyset {
+		return tx.findInBatchesWithOffset(dest, batchSize, keyColumns, fc)
+	}
+
+	keyColumns[0].Reorder = true
+	tx = tx.Clauses(clause.OrderBy{Columns: keyColumns}).Session(&Session{})
+	queryDB := tx
+
 	for {
 		result := queryDB.Limit(batchSize).Find(dest)
 		rowsAffected += result.RowsAffected
@@ -188,26 +223,270 @@ func (db *DB) FindInBatches(dest interface{}, batchSize int, fc func(tx *DB, bat
 
 		if result.Error == nil && result.RowsAffected != 0 {
 			tx.AddError(fc(result, batch))
+		} else if result.Error != nil {
+			tx.AddError(result.Error)
 		}
 
 		if tx.Error != nil || int(result.RowsAffected) < batchSize {
 			break
-		} else {
-			resultsValue := reflect.Indirect(reflect.ValueOf(dest))
-			if result.Statement.Schema.PrioritizedPrimaryField == nil {
-				tx.AddError(ErrPrimaryKeyRequired)
-				break
-			} else {
-				primaryValue, _ := result.Statement.Schema.PrioritizedPrimaryField.ValueOf(resultsValue.Index(resultsValue.Len() - 1))
-				queryDB = tx.Clauses(clause.Gt{Column: clause.Column{Table: clause.CurrentTable, Name: clause.PrimaryKey}, Value: primaryValue})
+		}
+
+		resultsValue := reflect.Indirect(reflect.ValueOf(dest))
+		lastValue := resultsValue.Index(resultsValue.Len() - 1)
+		queryDB = tx.Clauses(keysetCondition(keyFields, keyColumns, lastValue))
+	}
+
+	tx.RowsAffected = rowsAffected
+	return tx
+}
+
+// keysetColumns returns fields and order columns used for keyset pagination, primary keys will be appended to ensure uniqueness,
+// keyset is false if ORDER BY can't be mapped to fields of the model, only columns of primary keys are returned then, or
+// if the model has no primary keys
+func (tx *DB) keysetColumns(dest interface{}) (fields []*schema.Field, columns []clause.OrderByColumn, keyset bool) {
+	model := tx.Statement.Model
+	if model == nil {
+		model = dest
+	}
+
+	stmt := &Statement{DB: tx}
+	if err := stmt.Parse(model); err != nil {
+		tx.AddError(err)
+		return
+	}
+
+	addField := func(field *schema.Field, desc bool) {
+		for _, f := range fields {
+			if f == field {
+				return
+			}
+		}
+
+		fields = append(fields, field)
+		columns = append(columns, clause.OrderByColumn{
+			Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Desc: desc,
+		})
+	}
+
+	keyset = true
+	if c, ok := tx.Statement.Clauses["ORDER BY"]; ok {
+		if orderBy, ok := c.Expression.(clause.OrderBy); ok {
+			for _, column := range orderBy.Columns {
+				name, desc := column.Column.Name, column.Desc
+				if column.Column.Raw {
+					if names := strings.Fields(name); len(names) == 2 && strings.EqualFold(names[1], "desc") {
+						name, desc = names[0], true
+					} else if len(names) == 2 && strings.EqualFold(names[1], "asc") {
+						name = names[0]
+					}
+				}
+
+				name = strings.Trim(name[strings.LastIndex(name, ".")+1:], "`\"'[]")
+				field := stmt.Schema.LookUpField(name)
+				if field == nil || field.DBName == "" {
+					fields, columns, keyset = nil, nil, false
+					break
+				}
+				addField(field, desc)
 			}
 		}
 	}
 
+	// records without primary keys can't be ordered uniquely, they are queried with OFFSET in current order
+	if len(stmt.Schema.PrimaryFields) == 0 {
+		return nil, nil, false
+	}
+
+	for _, field := range stmt.Schema.PrimaryFields {
+		addField(field, false)
+	}
+	return
+}
+
+// findInBatchesWithOffset finds records in batches with OFFSET, it is used if ORDER BY can't be mapped to fields of the model,
+// e.g: `LOWER(name)`, or the model has no primary keys, records are ordered by primary keys additionally
+func (tx *DB) findInBatchesWithOffset(dest interface{}, batchSize int, keyColumns []clause.OrderByColumn, fc func(tx *DB, batch int) error) *DB {
+	var (
+		rowsAffected int64
+		batch        int
+	)
+
+	if len(keyColumns) > 0 {
+		tx = tx.Clauses(clause.OrderBy{Columns: keyColumns}).Session(&Session{})
+	}
+
+	for {
+		result := tx.Limit(batchSize).Offset(batch * batchSize).Find(dest)
+		rowsAffected += result.RowsAffected
+		batch++
+
+		if result.Error == nil && result.RowsAffected != 0 {
+			tx.AddError(fc(result, batch))
+		} else if result.Error != nil {
+			tx.AddError(result.Error)
+		}
+
+		if tx.Error != nil || int(result.RowsAffected) < batchSize {
+			break
+		}
+	}
+
 	tx.RowsAffected = rowsAffected
 	return tx
 }
 
+// keysetCondition returns conditions of records after value, e.g:
+//     (a > 1) OR (a = 1 AND b > 2)
+func keysetCondition(fields []*schema.Field, columns []clause.OrderByColumn, value reflect.Value) clause.Expression {
+	var (
+		exprs  = make([]clause.Expression, 0, len(fields))
+		equals = make([]clause.Expression, 0, len(fields))
+	)
+
+	for idx, field := range fields {
+		fieldValue, _ := field.ValueOf(value)
+
+		var expr clause.Expression = clause.Gt{Column: columns[idx].Column, Value: fieldValue}
+		if columns[idx].Desc {
+			expr = clause.Lt{Column: columns[idx].Column, Value: fieldValue}
+		}
+
+		exprs = append(exprs, clause.And(append(equals[:len(equals):len(equals)], expr)...))
+		equals = append(equals, clause.Eq{Column: columns[idx].Column, Value: fieldValue})
+	}
+
+	if len(exprs) == 1 {
+		return exprs[0]
+	}
+	return clause.Or(exprs...)
+}
+
+// Page pagination of FindPage, Number starts from 1, Total and Pages will be filled after query,
+// Total will be counted with EstimatedCount if Estimated is true
+type Page struct {
+	Number    int
+	Size      int
+	Estimated bool
+	Total     int64
+	Pages     int
+}
+
+// FindPage count records that match given conditions and find records of the page into dest
+//     page, err := db.Where("status = ?", "paid").Order("id desc").FindPage(&orders, gorm.Page{Number: 2, Size: 20})
+func (db *DB) FindPage(dest interface{}, page Page) (Page, error) {
+	if page.Size <= 0 {
+		return page, fmt.Errorf("%w: size %d", ErrInvalidPage, page.Size)
+	}
+
+	if page.Number < 1 {
+		page.Number = 1
+	}
+
+	tx := db.Session(&Session{})
+	if tx.Statement.Model == nil {
+		tx = tx.Model(dest).Session(&Session{})
+	}
+
+	countDB := tx.Count
+	if page.Estimated {
+		countDB = tx.EstimatedCount
+	}
+
+	if err := countDB(&page.Total).Error; err != nil {
+		return page, err
+	}
+	page.Pages = int((page.Total + int64(page.Size) - 1) / int64(page.Size))
+
+	err := tx.Offset((page.Number - 1) * page.Size).Limit(page.Size).Find(dest).Error
+	return page, err
+}
+
+// FindInto stream records into channel ch, ch will be closed after all records sent or any error happened
+//     ch := make(chan Order, 100)
+//     go func() { err = db.Where("amount > ?", 100).FindInto(ch).Error }()
+//     for order := range ch {
+//       // ...
+//     }
+func (db *DB) FindInto(ch interface{}) (tx *DB) {
+	tx = db.getInstance()
+	chValue := reflect.ValueOf(ch)
+	if chValue.Kind() != reflect.Chan || chValue.Type().ChanDir()&reflect.SendDir == 0 {
+		tx.AddError(fmt.Errorf("%w: %T is not a sendable channel", ErrInvalidData, ch))
+		return
+	}
+	defer chValue.Close()
+
+	elemType := chValue.Type().Elem()
+	isPtr := elemType.Kind() == reflect.Ptr
+	if isPtr {
+		elemType = elemType.Elem()
+	}
+
+	if tx.Statement.Model == nil && tx.Statement.Table == "" {
+		tx = tx.Model(reflect.New(elemType).Interface())
+	}
+
+	if tx.requiresSessionTransaction() {
+		// rows are streamed in transaction with session variables
+		var rowsAffected int64
+		tx.AddError(tx.Transaction(func(stx *DB) error {
+			result := stx.sendRows(chValue, elemType, isPtr)
+			rowsAffected = result.RowsAffected
+			return result.Error
+		}))
+		tx.RowsAffected = rowsAffected
+		return
+	}
+	return tx.sendRows(chValue, elemType, isPtr)
+}
+
+// sendRows sends scanned rows to channel chValue for FindInto
+func (db *DB) sendRows(chValue reflect.Value, elemType reflect.Type, isPtr bool) (tx *DB) {
+	tx = db.getInstance()
+	rows, err := tx.Rows()
+	if err != nil {
+		tx.AddError(err)
+		return
+	}
+	defer rows.Close()
+
+	var (
+		rowsAffected int64
+		scanDB       = tx.Session(&Session{NewDB: true})
+		done         = reflect.ValueOf(tx.Statement.Context.Done())
+	)
+
+	for rows.Next() {
+		elem := reflect.New(elemType)
+		if err := scanDB.ScanRows(rows, elem.Interface()); err != nil {
+			tx.AddError(err)
+			break
+		}
+
+		if !isPtr {
+			elem = elem.Elem()
+		}
+
+		cases := []reflect.SelectCase{{Dir: reflect.SelectSend, Chan: chValue, Send: elem}}
+		if done.IsValid() && !done.IsNil() {
+			cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: done})
+		}
+
+		if chosen, _, _ := reflect.Select(cases); chosen != 0 {
+			tx.AddError(tx.Statement.Context.Err())
+			break
+		}
+		rowsAffected++
+	}
+
+	if err := rows.Err(); err != nil {
+		tx.AddError(err)
+	}
+
+	tx.RowsAffected = rowsAffected
+	return
+}
+
 func (tx *DB) assignInterfacesToValue(values ...interface{}) {
 	for _, value := range values {
 		switch v := value.(type) {
@@ -326,6 +605,200 @@ func (db *DB) FirstOrCreate(dest interface{}, conds ...interface{}) (tx *DB) {
 	return db
 }
 
+// FirstOrCreateAtomic create a record with given conditions, or find the existing one if it conflicts with a unique key,
+// the conflict target is the columns of equality conditions, ErrUniqueKeyRequired is returned if they aren't the primary
+// keys or an unique key, use FirstOrCreate in a transaction or clause.OnConflict for them,
+// if the database supports RETURNING, conflicted records are returned by the insert with `ON CONFLICT DO UPDATE` setting
+// the conflict target to itself, which writes the existing row, so it fires update triggers and locks the row,
+// otherwise it inserts with `ON CONFLICT DO NOTHING`, and only conflicted records will be queried again
+//     db.Where(Language{Code: "en"}).Attrs(Language{Name: "English"}).FirstOrCreateAtomic(&language)
+func (db *DB) FirstOrCreateAtomic(dest interface{}, conds ...interface{}) (tx *DB) {
+	tx = db.getInstance()
+	if len(conds) > 0 {
+		if exprs := tx.Statement.BuildCondition(conds[0], conds[1:]...); len(exprs) > 0 {
+			tx.Statement.AddClause(clause.Where{Exprs: exprs})
+		}
+	}
+
+	if err := tx.Statement.Parse(dest); err != nil {
+		tx.AddError(err)
+		return
+	}
+
+	tx.Statement.Dest = dest
+	tx.Statement.ReflectValue = reflect.Indirect(reflect.ValueOf(dest))
+	if tx.Statement.ReflectValue.Kind() != reflect.Struct {
+		tx.AddError(ErrInvalidData)
+		return
+	}
+
+	var (
+		where      clause.Where
+		onConflict = clause.OnConflict{DoNothing: true}
+	)
+
+	if c, ok := tx.Statement.Clauses["WHERE"]; ok {
+		where, _ = c.Expression.(clause.Where)
+		onConflict.Columns = tx.equalityColumns(where.Exprs)
+	}
+
+	if _, ok := tx.Statement.Clauses["ON CONFLICT"]; !ok && len(onConflict.Columns) > 0 {
+		conflictNames := make([]string, 0, len(onConflict.Columns))
+		for _, column := range onConflict.Columns {
+			conflictNames = append(conflictNames, column.Name)
+		}
+
+		// conflict target must be an unique key, finding and creating the record otherwise races with others
+		if !tx.Statement.Schema.HasUniqueKey(conflictNames...) {
+			tx.AddError(fmt.Errorf("%w: conflict target %v of %v", ErrUniqueKeyRequired, conflictNames, tx.Statement.Schema))
+			return
+		}
+	}
+
+	tx.assignInterfacesToValue(where.Exprs)
+
+	// initialize with attrs, conds
+	if len(tx.Statement.attrs) > 0 {
+		tx.assignInterfacesToValue(tx.Statement.attrs...)
+	}
+
+	// initialize with assigns
+	if len(tx.Statement.assigns) > 0 {
+		tx.assignInterfacesToValue(tx.Statement.assigns...)
+	}
+
+	createTx := tx.Session(&Session{})
+	if _, ok := tx.Statement.Clauses["ON CONFLICT"]; !ok {
+		if len(onConflict.Columns) > 0 && tx.Capabilities().Returning {
+			// updating conflict target to itself returns the existing record, assigned values are applied to it, the existing
+			// row is written, so update triggers are fired and the row is locked until the transaction is finished
+			updateColumns := append([]clause.Column{}, onConflict.Columns...)
+			for _, assign := range tx.Statement.assigns {
+				updateColumns = append(updateColumns, tx.equalityColumns(tx.Statement.BuildCondition(assign))...)
+			}
+
+			names := make([]string, 0, len(updateColumns))
+			for _, column := range updateColumns {
+				names = append(names, column.Name)
+			}
+			onConflict.DoNothing, onConflict.DoUpdates = false, clause.AssignmentColumns(names)
+
+			result := createTx.Clauses(onConflict, clause.Returning{}).Create(dest)
+			tx.RowsAffected = result.RowsAffected
+			tx.AddError(result.Error)
+			return
+		}
+		createTx = createTx.Clauses(onConflict)
+	}
+
+	if result := createTx.Create(dest); result.Error != nil || result.RowsAffected > 0 || tx.DryRun {
+		tx.RowsAffected = result.RowsAffected
+		tx.AddError(result.Error)
+		return
+	}
+
+	// conflicted with an existing record, reload it and apply assigned values
+	assigns := tx.Statement.assigns
+	tx.Statement.attrs, tx.Statement.assigns = nil, nil
+	return tx.Assign(assigns...).FirstOrCreate(dest)
+}
+
+// UpdateOrCreate update the record matching attrs with values, or create it with attrs and values if not found, then load it into dest
+// if the columns of attrs are the primary keys or an unique key, it is a single `ON CONFLICT (attrs) DO UPDATE` upsert,
+// otherwise it finds and updates/creates the record in a transaction, like `Where(attrs).Assign(values).FirstOrCreate(dest)`
+//     db.UpdateOrCreate(&user, User{Email: "jinzhu@example.org"}, User{Name: "jinzhu", Age: 18})
+func (db *DB) UpdateOrCreate(dest interface{}, attrs interface{}, values interface{}) (tx *DB) {
+	tx = db.getInstance()
+	if err := tx.Statement.Parse(dest); err != nil {
+		tx.AddError(err)
+		return
+	}
+
+	tx.Statement.Dest = dest
+	tx.Statement.ReflectValue = reflect.Indirect(reflect.ValueOf(dest))
+	if tx.Statement.ReflectValue.Kind() != reflect.Struct {
+		tx.AddError(ErrInvalidData)
+		return
+	}
+
+	var (
+		conds         = tx.Statement.BuildCondition(attrs)
+		conflictNames []string
+		updateNames   []string
+		onConflict    = clause.OnConflict{Columns: tx.equalityColumns(conds)}
+	)
+
+	for _, column := range onConflict.Columns {
+		conflictNames = append(conflictNames, column.Name)
+	}
+
+	if !tx.Statement.Schema.HasUniqueKey(conflictNames...) {
+		tx.AddError(tx.Transaction(func(tx *DB) error {
+			return tx.Where(attrs).Assign(values).FirstOrCreate(dest).Error
+		}))
+		return
+	}
+
+	tx.assignInterfacesToValue(conds)
+	tx.assignInterfacesToValue(values)
+
+	updateColumns := tx.equalityColumns(tx.Statement.BuildCondition(values))
+	for _, field := range tx.Statement.Schema.Fields {
+		if field.AutoUpdateTime > 0 && field.DBName != "" {
+			updateColumns = append(updateColumns, clause.Column{Name: field.DBName})
+		}
+	}
+
+	for _, column := range updateColumns {
+		if field := tx.Statement.Schema.LookUpField(column.Name); field != nil && !field.PrimaryKey && field.Updatable {
+			updateNames = append(updateNames, column.Name)
+		}
+	}
+
+	if len(updateNames) > 0 {
+		onConflict.DoUpdates = clause.AssignmentColumns(updateNames)
+	} else {
+		onConflict.DoNothing = true
+	}
+
+	result := tx.Session(&Session{}).Clauses(onConflict).Create(dest)
+	tx.RowsAffected = result.RowsAffected
+	if tx.AddError(result.Error) != nil || tx.DryRun {
+		return
+	}
+
+	// reload the saved record, as the primary key is unknown when updated, the conflicted record might be soft deleted
+	reloaded := reflect.New(tx.Statement.Schema.ModelType)
+	if err := tx.Session(&Session{}).Unscoped().Where(clause.And(conds...)).Take(reloaded.Interface()).Error; err == nil {
+		tx.Statement.ReflectValue.Set(reloaded.Elem())
+	} else {
+		tx.AddError(err)
+	}
+	return
+}
+
+// equalityColumns collect columns of equality conditions, used to build upserts
+func (tx *DB) equalityColumns(exprs []clause.Expression) (columns []clause.Column) {
+	for _, expr := range exprs {
+		if eq, ok := expr.(clause.Eq); ok {
+			name := ""
+			switch column := eq.Column.(type) {
+			case string:
+				name = column
+			case clause.Column:
+				name = column.Name
+			}
+
+			if field := tx.Statement.Schema.LookUpField(name); field != nil && field.DBName != "" {
+				columns = append(columns, clause.Column{Name: field.DBName})
+			}
+		} else if andCond, ok := expr.(clause.AndConditions); ok {
+			columns = append(columns, tx.equalityColumns(andCond.Exprs)...)
+		}
+	}
+	return
+}
+
 // Update update attributes with callbacks, refer: https://gorm.io/docs/update.html#Update-Changed-Fields
 func (db *DB) Update(column string, value interface{}) (tx *DB) {
 	tx = db.getInstance()
@@ -431,6 +904,101 @@ func (db *DB) Count(count *int64) (tx *DB) {
 	return
 }
 
+// EstimatedCount count records with estimated row numbers from database statistics or query plans, which is much faster
+// than COUNT(*) for very large tables, exact count will be used if the estimated number is less than EstimatedCountThreshold
+// or the database doesn't support estimating
+//     db.Model(&Event{}).EstimatedCount(&count)
+func (db *DB) EstimatedCount(count *int64) (tx *DB) {
+	tx = db.getInstance()
+	threshold := tx.EstimatedCountThreshold
+	if threshold == 0 {
+		threshold = 10000
+	}
+
+	if estimated, ok := tx.estimateCount(); ok && estimated >= threshold {
+		*count = estimated
+		return
+	}
+	return tx.Count(count)
+}
+
+// estimateCount returns estimated row numbers, uses table statistics if no conditions, otherwise uses EXPLAIN
+func (db *DB) estimateCount() (count int64, ok bool) {
+	stmt := db.Statement
+	if stmt.Model == nil && stmt.Table == "" {
+		return 0, false
+	}
+
+	table := stmt.Table
+	if table == "" {
+		if err := stmt.Parse(stmt.Model); err != nil {
+			return 0, false
+		}
+		table = stmt.Table
+	}
+
+	_, hasWhere := stmt.Clauses["WHERE"]
+	_, hasGroup := stmt.Clauses["GROUP BY"]
+	scoped := !stmt.Unscoped && stmt.Schema != nil && len(stmt.Schema.QueryClauses) > 0
+	useStats := !hasWhere && !hasGroup && !scoped && len(stmt.Joins) == 0
+
+	var (
+		newDB   = db.Session(&Session{NewDB: true})
+		queryDB *DB
+		dryRun  *DB
+	)
+
+	if !useStats {
+		var results []map[string]interface{}
+		dryRun = db.Session(&Session{DryRun: true}).Find(&results)
+		if dryRun.Error != nil {
+			return 0, false
+		}
+	}
+
+	switch db.Capabilities().EstimatedCount {
+	case SyntaxPostgres:
+		if useStats {
+			queryDB = newDB.Raw("SELECT reltuples::bigint FROM pg_class WHERE oid = to_regclass(?)", table)
+		} else {
+			var plan string
+			if newDB.Raw("EXPLAIN (FORMAT JSON) "+dryRun.Statement.SQL.String(), dryRun.Statement.Vars...).Row().Scan(&plan) != nil {
+				return 0, false
+			}
+
+			var plans []struct {
+				Plan struct {
+					Rows float64 `json:"Plan Rows"`
+				}
+			}
+			if json.Unmarshal([]byte(plan), &plans) != nil || len(plans) == 0 {
+				return 0, false
+			}
+			return int64(plans[0].Plan.Rows), true
+		}
+	case SyntaxMySQL:
+		if useStats {
+			queryDB = newDB.Raw("SELECT table_rows FROM information_schema.tables WHERE table_schema = DATABASE() AND table_name = ?", table)
+		} else {
+			var plans []map[string]interface{}
+			if newDB.Raw("EXPLAIN "+dryRun.Statement.SQL.String(), dryRun.Statement.Vars...).Scan(&plans).Error != nil || len(plans) == 0 {
+				return 0, false
+			}
+
+			rows, err := strconv.ParseInt(fmt.Sprint(plans[0]["rows"]), 10, 64)
+			return rows, err == nil
+		}
+	default:
+		return 0, false
+	}
+
+	var estimated sql.NullInt64
+	if queryDB.Row().Scan(&estimated) != nil || !estimated.Valid || estimated.Int64 < 0 {
+		return 0, false
+	}
+	return estimated.Int64, true
+}
+
 func (db *DB) Row() *sql.Row {
 	tx := db.getInstance().InstanceSet("rows", false)
 	tx.callbacks.Row().Execute(tx)
@@ -453,6 +1021,17 @@ func (db *DB) Rows() (*sql.Rows, error) {
 
 // Scan scan value to a struct
 func (db *DB) Scan(dest interface{}) (tx *DB) {
+	if db.requiresSessionTransaction() {
+		// rows are read in transaction with session variables
+		tx = db.getInstance()
+		tx.AddError(db.Transaction(func(stx *DB) error {
+			result := stx.Scan(dest)
+			tx.RowsAffected = result.RowsAffected
+			return result.Error
+		}))
+		return
+	}
+
 	config := *db.Config
 	currentLogger, newLogger := config.Logger, logger.Recorder.New()
 	config.Logger = newLogger
@@ -460,6 +1039,12 @@ func (db *DB) Scan(dest interface{}) (tx *DB) {
 	tx = db.getInstance()
 	tx.Config = &config
 
+	// rows are read with timeout of statement here, as they are closed before returning
+	if tx.Statement.Timeout == 0 {
+		tx.Statement.Timeout = tx.StatementTimeout
+	}
+	defer tx.Statement.withTimeout()()
+
 	if rows, err := tx.Rows(); err != nil {
 		tx.AddError(err)
 	} else {
@@ -468,6 +1053,7 @@ func (db *DB) Scan(dest interface{}) (tx *DB) {
 			tx.ScanRows(rows, dest)
 		} else {
 			tx.RowsAffected = 0
+			tx.AddError(rows.Err())
 		}
 	}
 
@@ -505,6 +1091,37 @@ func (db *DB) Pluck(column string, dest interface{}) (tx *DB) {
 	return
 }
 
+// PluckRows used to query multiple columns into a slice of light struct or map
+//     var options []struct{ ID uint; Name string }
+//     db.Model(&User{}).PluckRows(&options, "id", "name")
+//     var results []map[string]interface{}
+//     db.Table("users").PluckRows(&results, "id", "name")
+func (db *DB) PluckRows(dest interface{}, columns ...string) (tx *DB) {
+	tx = db.getInstance()
+	if tx.Statement.Model != nil {
+		tx.AddError(tx.Statement.Parse(tx.Statement.Model))
+	} else if tx.Statement.Table == "" {
+		tx.AddError(ErrModelValueRequired)
+	}
+
+	selectColumns := make([]clause.Column, len(columns))
+	for idx, column := range columns {
+		if tx.Statement.Schema != nil {
+			if f := tx.Statement.Schema.LookUpField(column); f != nil {
+				column = f.DBName
+			}
+		}
+
+		fields := strings.FieldsFunc(column, utils.IsValidDBNameChar)
+		selectColumns[idx] = clause.Column{Name: column, Raw: len(fields) != 1}
+	}
+
+	tx.Statement.AddClause(clause.Select{Distinct: tx.Statement.Distinct, Columns: selectColumns})
+	tx.Statement.Dest = dest
+	tx.callbacks.Query().Execute(tx)
+	return
+}
+
 func (db *DB) ScanRows(rows *sql.Rows, dest interface{}) error {
 	tx := db.getInstance()
 	if err := tx.Statement.Parse(dest); !errors.Is(err, schema.ErrUnsupportedDataType) {
@@ -519,19 +1136,50 @@ func (db *DB) ScanRows(rows *sql.Rows, dest interface{}) error {
 	return tx.Error
 }
 
+// TxOptions options of transaction, isolation level and read-only are passed to the driver, Deferrable is set with
+// `SET TRANSACTION DEFERRABLE` for postgres, and ignored by other databases
+//    db.TransactionWithOptions(func(tx *gorm.DB) error {
+//      // generate report
+//    }, gorm.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true, Deferrable: true})
+type TxOptions struct {
+	Isolation  sql.IsolationLevel
+	ReadOnly   bool
+	Deferrable bool
+}
+
 // Transaction start a transaction as a block, return error will rollback, otherwise to commit.
 func (db *DB) Transaction(fc func(tx *DB) error, opts ...*sql.TxOptions) (err error) {
+	var opt *sql.TxOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	return db.runTransaction(fc, opt, false)
+}
+
+// TransactionWithOptions start a transaction as a block with options, check TxOptions for details
+func (db *DB) TransactionWithOptions(fc func(tx *DB) error, opts TxOptions) (err error) {
+	return db.runTransaction(fc, &sql.TxOptions{Isolation: opts.Isolation, ReadOnly: opts.ReadOnly}, opts.Deferrable)
+}
+
+// runTransaction runs fc in a nested transaction if it is in transaction, otherwise in a new transaction with options
+func (db *DB) runTransaction(fc func(tx *DB) error, opt *sql.TxOptions, deferrable bool) (err error) {
 	panicked := true
 
 	if committer, ok := db.Statement.ConnPool.(TxCommitter); ok && committer != nil {
-		// nested transaction
-		if !db.DisableNestedTransaction {
+		// nested transaction, it is rejected instead of running without savepoint if the database doesn't support it
+		if !db.DisableNestedTransaction && !db.Capabilities().NestedTransaction {
+			err = fmt.Errorf("%w: nested transactions are not supported by %v, set DisableNestedTransaction to run them in the outer transaction", ErrUnsupportedDriver, db.Dialector.Name())
+		} else if !db.DisableNestedTransaction {
 			err = db.SavePoint(fmt.Sprintf("sp%p", fc)).Error
+			mark := db.txHooks().mark()
 			defer func() {
 				// Make sure to rollback when panic, Block error or Commit error
 				if panicked || err != nil {
 					db.RollbackTo(fmt.Sprintf("sp%p", fc))
+					db.txHooks().discard(mark)
 				}
+				// the savepoint is kept until the transaction is finished, but it is not nested anymore
+				db.savePoints().release(fmt.Sprintf("sp%p", fc))
 			}()
 		}
 
@@ -539,22 +1187,36 @@ func (db *DB) Transaction(fc func(tx *DB) error, opts ...*sql.TxOptions) (err er
 			err = fc(db.Session(&Session{}))
 		}
 	} else {
-		tx := db.Begin(opts...)
-
-		defer func() {
-			// Make sure to rollback when panic, Block error or Commit error
-			if panicked || err != nil {
-				tx.Rollback()
+		for attempt := 1; ; attempt++ {
+			err = db.transaction(fc, opt, deferrable)
+			if !db.TransactionRetry.retryable(db, err, attempt) || !db.TransactionRetry.wait(db.Statement.Context, attempt) {
+				break
 			}
-		}()
-
-		if err = tx.Error; err == nil {
-			err = fc(tx)
 		}
+	}
 
-		if err == nil {
-			err = tx.Commit().Error
+	panicked = false
+	return
+}
+
+// transaction runs fc in a new transaction
+func (db *DB) transaction(fc func(tx *DB) error, opt *sql.TxOptions, deferrable bool) (err error) {
+	panicked := true
+	tx := db.begin(opt, deferrable)
+
+	defer func() {
+		// Make sure to rollback when panic, Block error or Commit error
+		if panicked || err != nil {
+			tx.Rollback()
 		}
+	}()
+
+	if err = tx.Error; err == nil {
+		err = fc(tx)
+	}
+
+	if err == nil {
+		err = tx.Commit().Error
 	}
 
 	panicked = false
@@ -563,15 +1225,32 @@ func (db *DB) Transaction(fc func(tx *DB) error, opts ...*sql.TxOptions) (err er
 
 // Begin begins a transaction
 func (db *DB) Begin(opts ...*sql.TxOptions) *DB {
+	var opt *sql.TxOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	return db.begin(opt, false)
+}
+
+// BeginWithOptions begins a transaction with options, check TxOptions for details
+func (db *DB) BeginWithOptions(opts TxOptions) *DB {
+	return db.begin(&sql.TxOptions{Isolation: opts.Isolation, ReadOnly: opts.ReadOnly}, opts.Deferrable)
+}
+
+func (db *DB) begin(opt *sql.TxOptions, deferrable bool) *DB {
 	var (
 		// clone statement
 		tx  = db.Session(&Session{Context: db.Statement.Context})
-		opt *sql.TxOptions
 		err error
 	)
 
-	if len(opts) > 0 {
-		opt = opts[0]
+	// drivers of databases don't support read-only transactions reject them, e.g: sqlserver
+	if opt != nil && opt.ReadOnly && !tx.Capabilities().ReadOnlyTransaction {
+		opt = &sql.TxOptions{Isolation: opt.Isolation}
+	}
+
+	if tenant, ok := tx.tenant(tx.Statement.Context); ok && tenant.ConnPool != nil && !tx.Statement.InTransaction {
+		tx.Statement.ConnPool = tenant.ConnPool
 	}
 
 	if beginner, ok := tx.Statement.ConnPool.(TxBeginner); ok {
@@ -584,6 +1263,17 @@ func (db *DB) Begin(opts ...*sql.TxOptions) *DB {
 
 	if err != nil {
 		tx.AddError(err)
+	} else {
+		tx.counters.transactionOpened(1)
+		tx.Statement.InTransaction = true
+		tx.Statement.Settings.Store("gorm:savepoints", &savePoints{})
+		tx.Statement.Settings.Store("gorm:tx_hooks", &txHooks{})
+		if variables := tx.sessionVariables(); len(variables) > 0 {
+			tx.AddError(tx.setSessionVariables(variables))
+		}
+		if deferrable && tx.Capabilities().DeferrableTransaction {
+			tx.AddError(tx.Exec("SET TRANSACTION DEFERRABLE").Error)
+		}
 	}
 
 	return tx
@@ -592,7 +1282,15 @@ func (db *DB) Begin(opts ...*sql.TxOptions) *DB {
 // Commit commit a transaction
 func (db *DB) Commit() *DB {
 	if committer, ok := db.Statement.ConnPool.(TxCommitter); ok && committer != nil && !reflect.ValueOf(committer).IsNil() {
-		db.AddError(committer.Commit())
+		err := committer.Commit()
+		if !errors.Is(err, sql.ErrTxDone) && !errors.Is(err, ErrInvalidTransaction) {
+			db.counters.transactionOpened(-1)
+		}
+
+		if err == nil {
+			db.txHooks().resolve(db.Statement.Context, true)
+		}
+		db.AddError(err)
 	} else {
 		db.AddError(ErrInvalidTransaction)
 	}
@@ -603,7 +1301,12 @@ func (db *DB) Commit() *DB {
 func (db *DB) Rollback() *DB {
 	if committer, ok := db.Statement.ConnPool.(TxCommitter); ok && committer != nil {
 		if !reflect.ValueOf(committer).IsNil() {
-			db.AddError(committer.Rollback())
+			err := committer.Rollback()
+			if !errors.Is(err, sql.ErrTxDone) && !errors.Is(err, ErrInvalidTransaction) {
+				db.counters.transactionOpened(-1)
+			}
+			db.txHooks().resolve(db.Statement.Context, false)
+			db.AddError(err)
 		}
 	} else {
 		db.AddError(ErrInvalidTransaction)
@@ -613,7 +1316,11 @@ func (db *DB) Rollback() *DB {
 
 func (db *DB) SavePoint(name string) *DB {
 	if savePointer, ok := db.Dialector.(SavePointerDialectorInterface); ok {
-		db.AddError(savePointer.SavePoint(db, name))
+		if err := savePointer.SavePoint(db, name); err != nil {
+			db.AddError(err)
+		} else {
+			db.savePoints().push(name)
+		}
 	} else {
 		db.AddError(ErrUnsupportedDriver)
 	}
@@ -622,7 +1329,11 @@ func (db *DB) SavePoint(name string) *DB {
 
 func (db *DB) RollbackTo(name string) *DB {
 	if savePointer, ok := db.Dialector.(SavePointerDialectorInterface); ok {
-		db.AddError(savePointer.RollbackTo(db, name))
+		if err := savePointer.RollbackTo(db, name); err != nil {
+			db.AddError(err)
+		} else {
+			db.savePoints().rollbackTo(name)
+		}
 	} else {
 		db.AddError(ErrUnsupportedDriver)
 	}
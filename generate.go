@@ -0,0 +1,75 @@
+package gorm
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"reflect"
+
+	"gorm.io/gorm/schema"
+)
+
+// GenerateFields generate typed column references of models into package pkgName, could be used with go:generate
+//     db.GenerateFields(file, "query", &User{}, &Order{})
+//     db.Where(query.User.Name.Eq("jinzhu")).Where(query.User.Age.Gt(18)).Find(&users)
+func (db *DB) GenerateFields(w io.Writer, pkgName string, models ...interface{}) error {
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by gorm.GenerateFields. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n\nimport \"gorm.io/gorm/field\"\n", pkgName)
+
+	for _, model := range models {
+		stmt := &Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			return err
+		}
+
+		var fields []*schema.Field
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName != "" && field.Readable {
+				fields = append(fields, field)
+			}
+		}
+
+		fmt.Fprintf(&buf, "\n// %s typed columns of table %s\nvar %s = struct {\n", stmt.Schema.Name, stmt.Table, stmt.Schema.Name)
+		for _, field := range fields {
+			typ, _ := generatedFieldType(field)
+			fmt.Fprintf(&buf, "%s %s\n", field.Name, typ)
+		}
+
+		buf.WriteString("}{\n")
+		for _, field := range fields {
+			_, constructor := generatedFieldType(field)
+			fmt.Fprintf(&buf, "%s: %s(%q, %q),\n", field.Name, constructor, stmt.Table, field.DBName)
+		}
+		buf.WriteString("}\n")
+	}
+
+	source, err := format.Source(buf.Bytes())
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(source)
+	return err
+}
+
+// generatedFieldType returns the typed column type and its constructor of field
+func generatedFieldType(field *schema.Field) (string, string) {
+	switch field.GORMDataType {
+	case schema.String:
+		return "field.String", "field.NewString"
+	case schema.Bool:
+		return "field.Bool", "field.NewBool"
+	case schema.Time:
+		return "field.Time", "field.NewTime"
+	case schema.Int, schema.Uint, schema.Float:
+		switch kind := field.IndirectFieldType.Kind(); kind {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64:
+			return "field.Number[" + kind.String() + "]", "field.NewNumber[" + kind.String() + "]"
+		}
+	}
+	return "field.Field[interface{}]", "field.New[interface{}]"
+}
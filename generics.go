@@ -0,0 +1,278 @@
+package gorm
+
+import (
+	"context"
+	"database/sql"
+
+	"gorm.io/gorm/clause"
+)
+
+// TypedDB typed query builder for model T, created with G
+type TypedDB[T any] struct {
+	db *DB
+}
+
+// G returns a typed query builder for model T, results are returned with its type instead of scanning into interface{}
+//     users, err := gorm.G[User](db).Where("name = ?", "jinzhu").Find(ctx)
+//     user, err := gorm.G[User](db).Where("id = ?", 1).First(ctx)
+func G[T any](db *DB) *TypedDB[T] {
+	return newTypedDB[T](db.Model(new(T)))
+}
+
+func newTypedDB[T any](db *DB) *TypedDB[T] {
+	return &TypedDB[T]{db: db.Session(&Session{})}
+}
+
+// DB returns the underlying *gorm.DB
+func (g *TypedDB[T]) DB() *DB {
+	return g.db
+}
+
+// Clauses add clauses
+func (g *TypedDB[T]) Clauses(conds ...clause.Expression) *TypedDB[T] {
+	return newTypedDB[T](g.db.Clauses(conds...))
+}
+
+// Table specify the table you would like to run db operations
+func (g *TypedDB[T]) Table(name string, args ...interface{}) *TypedDB[T] {
+	return newTypedDB[T](g.db.Table(name, args...))
+}
+
+// Select specify fields that you want when querying, creating, updating
+func (g *TypedDB[T]) Select(query interface{}, args ...interface{}) *TypedDB[T] {
+	return newTypedDB[T](g.db.Select(query, args...))
+}
+
+// Omit specify fields that you want to ignore when creating, updating and querying
+func (g *TypedDB[T]) Omit(columns ...string) *TypedDB[T] {
+	return newTypedDB[T](g.db.Omit(columns...))
+}
+
+// Where add conditions
+func (g *TypedDB[T]) Where(query interface{}, args ...interface{}) *TypedDB[T] {
+	return newTypedDB[T](g.db.Where(query, args...))
+}
+
+// Not add NOT conditions
+func (g *TypedDB[T]) Not(query interface{}, args ...interface{}) *TypedDB[T] {
+	return newTypedDB[T](g.db.Not(query, args...))
+}
+
+// Or add OR conditions
+func (g *TypedDB[T]) Or(query interface{}, args ...interface{}) *TypedDB[T] {
+	return newTypedDB[T](g.db.Or(query, args...))
+}
+
+// Joins specify Joins conditions
+func (g *TypedDB[T]) Joins(query string, args ...interface{}) *TypedDB[T] {
+	return newTypedDB[T](g.db.Joins(query, args...))
+}
+
+// Group specify the group method on the find
+func (g *TypedDB[T]) Group(name string) *TypedDB[T] {
+	return newTypedDB[T](g.db.Group(name))
+}
+
+// Having specify HAVING conditions for GROUP BY
+func (g *TypedDB[T]) Having(query interface{}, args ...interface{}) *TypedDB[T] {
+	return newTypedDB[T](g.db.Having(query, args...))
+}
+
+// Order specify order when retrieve records from database
+func (g *TypedDB[T]) Order(value interface{}) *TypedDB[T] {
+	return newTypedDB[T](g.db.Order(value))
+}
+
+// Limit specify the number of records to be retrieved
+func (g *TypedDB[T]) Limit(limit int) *TypedDB[T] {
+	return newTypedDB[T](g.db.Limit(limit))
+}
+
+// Offset specify the number of records to skip before starting to return the records
+func (g *TypedDB[T]) Offset(offset int) *TypedDB[T] {
+	return newTypedDB[T](g.db.Offset(offset))
+}
+
+// Scopes pass current database connection to arguments `func(DB) DB`, which could be used to add conditions dynamically
+func (g *TypedDB[T]) Scopes(funcs ...func(*DB) *DB) *TypedDB[T] {
+	return newTypedDB[T](g.db.Scopes(funcs...))
+}
+
+// Preload preload associations with given conditions
+func (g *TypedDB[T]) Preload(query string, args ...interface{}) *TypedDB[T] {
+	return newTypedDB[T](g.db.Preload(query, args...))
+}
+
+// Unscoped disable global conditions like soft delete
+func (g *TypedDB[T]) Unscoped() *TypedDB[T] {
+	return newTypedDB[T](g.db.Unscoped())
+}
+
+// Find find records that match given conditions
+func (g *TypedDB[T]) Find(ctx context.Context) ([]T, error) {
+	var results []T
+	err := g.db.WithContext(ctx).Find(&results).Error
+	return results, err
+}
+
+// FindInBatches find records in batches
+func (g *TypedDB[T]) FindInBatches(ctx context.Context, batchSize int, fc func(results []T, batch int) error) error {
+	var results []T
+	return g.db.WithContext(ctx).FindInBatches(&results, batchSize, func(tx *DB, batch int) error {
+		return fc(results, batch)
+	}).Error
+}
+
+// First find first record that match given conditions, order by primary key
+func (g *TypedDB[T]) First(ctx context.Context) (T, error) {
+	var result T
+	err := g.db.WithContext(ctx).First(&result).Error
+	return result, err
+}
+
+// Take return a record that match given conditions, the order will depend on the database implementation
+func (g *TypedDB[T]) Take(ctx context.Context) (T, error) {
+	var result T
+	err := g.db.WithContext(ctx).Take(&result).Error
+	return result, err
+}
+
+// Last find last record that match given conditions, order by primary key
+func (g *TypedDB[T]) Last(ctx context.Context) (T, error) {
+	var result T
+	err := g.db.WithContext(ctx).Last(&result).Error
+	return result, err
+}
+
+// Count count records that match given conditions
+func (g *TypedDB[T]) Count(ctx context.Context) (int64, error) {
+	var count int64
+	err := g.db.WithContext(ctx).Count(&count).Error
+	return count, err
+}
+
+// Create insert the value into database
+func (g *TypedDB[T]) Create(ctx context.Context, value *T) error {
+	return g.db.WithContext(ctx).Create(value).Error
+}
+
+// CreateInBatches insert the values in batches into database
+func (g *TypedDB[T]) CreateInBatches(ctx context.Context, values *[]T, batchSize int) error {
+	return g.db.WithContext(ctx).CreateInBatches(values, batchSize).Error
+}
+
+// Update update attribute with callbacks, returns rows affected
+func (g *TypedDB[T]) Update(ctx context.Context, column string, value interface{}) (int64, error) {
+	result := g.db.WithContext(ctx).Update(column, value)
+	return result.RowsAffected, result.Error
+}
+
+// Updates update non-zero fields of value with callbacks, returns rows affected
+func (g *TypedDB[T]) Updates(ctx context.Context, value T) (int64, error) {
+	result := g.db.WithContext(ctx).Updates(&value)
+	return result.RowsAffected, result.Error
+}
+
+// Delete delete records that match given conditions, returns rows affected
+func (g *TypedDB[T]) Delete(ctx context.Context) (int64, error) {
+	result := g.db.WithContext(ctx).Delete(new(T))
+	return result.RowsAffected, result.Error
+}
+
+// Iterate returns a typed iterator of query results, rows are scanned one by one instead of loading all records into memory
+//     it, err := gorm.Iterate[Order](db.WithContext(ctx).Where("amount > ?", 100))
+//     defer it.Close()
+//     for it.Next() {
+//       order := it.Row()
+//     }
+//     err = it.Err()
+func Iterate[T any](db *DB) (*Iterator[T], error) {
+	tx := db.getInstance()
+	if tx.Statement.Model == nil && tx.Statement.Table == "" {
+		tx = tx.Model(new(T))
+	}
+
+	// rows are streamed in transaction with session variables, it is finished when the iterator is closed
+	var sessionTx *DB
+	if tx.requiresSessionTransaction() {
+		if sessionTx = tx.Begin(); sessionTx.Error != nil {
+			return nil, sessionTx.Error
+		}
+		tx = sessionTx
+	}
+
+	rows, err := tx.Rows()
+	if err != nil {
+		if sessionTx != nil {
+			sessionTx.Rollback()
+		}
+		return nil, err
+	}
+
+	return &Iterator[T]{db: tx.Session(&Session{NewDB: true}), ctx: tx.Statement.Context, rows: rows, tx: sessionTx}, nil
+}
+
+// Iterate returns a typed iterator of query results
+func (g *TypedDB[T]) Iterate(ctx context.Context) (*Iterator[T], error) {
+	return Iterate[T](g.db.WithContext(ctx))
+}
+
+// Iterator typed iterator of query results, created with Iterate
+type Iterator[T any] struct {
+	db   *DB
+	ctx  context.Context
+	rows *sql.Rows
+	tx   *DB
+	row  T
+	err  error
+}
+
+// Next prepares the next row, returns false when no more rows, context canceled or any error happened, rows will be closed in that case
+func (it *Iterator[T]) Next() bool {
+	if it.err != nil || it.rows == nil {
+		return false
+	}
+
+	if it.err = it.ctx.Err(); it.err == nil && it.rows.Next() {
+		var row T
+		if it.err = it.db.ScanRows(it.rows, &row); it.err == nil {
+			it.row = row
+			return true
+		}
+	} else if it.err == nil {
+		it.err = it.rows.Err()
+	}
+
+	it.Close()
+	return false
+}
+
+// Row returns the current row
+func (it *Iterator[T]) Row() T {
+	return it.row
+}
+
+// Err returns the error happened during iterating
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// Close closes the rows, it is safe to call Close multiple times, the transaction with session variables is committed
+// after rows are closed, it is rolled back if any error happened
+func (it *Iterator[T]) Close() error {
+	if it.rows == nil {
+		return nil
+	}
+
+	err := it.rows.Close()
+	it.rows = nil
+	if it.tx != nil {
+		if err == nil && it.err == nil {
+			err = it.tx.Commit().Error
+		} else {
+			it.tx.Rollback()
+		}
+		it.tx = nil
+	}
+	return err
+}
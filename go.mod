@@ -1,6 +1,6 @@
 module gorm.io/gorm
 
-go 1.14
+go 1.18
 
 require (
 	github.com/jinzhu/inflection v1.0.0
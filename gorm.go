@@ -20,28 +20,103 @@ type Config struct {
 	SkipDefaultTransaction bool
 	// NamingStrategy tables, columns naming strategy
 	NamingStrategy schema.Namer
+	// Namespace schema of tables, e.g: `tenant1`, could be overwritten with method `TableNamespace() string` of models
+	Namespace string
 	// FullSaveAssociations full save associations
 	FullSaveAssociations bool
 	// Logger
 	Logger logger.Interface
+	// QueryFingerprint computes fingerprints of executed statements, which are passed to Logger in context, check logger.FingerprintFromContext,
+	// and to MetricsCallback, statements of the same shape with different literals or lengths of IN lists have the same fingerprint
+	QueryFingerprint bool
+	// SensitiveColumns values of the columns are masked in logged SQL like fields tagged with `sensitive`, e.g: `password`, `token`
+	SensitiveColumns []string
+	// MetricsCallback called with metrics of each executed statement, e.g: counting and timing statements by fingerprint
+	MetricsCallback func(ctx context.Context, metrics QueryMetrics)
 	// NowFunc the function to be used when creating a new timestamp
 	NowFunc func() time.Time
 	// DryRun generate sql without execute
 	DryRun bool
 	// PrepareStmt executes the given query in cached statement
 	PrepareStmt bool
+	// PlanCache caches built SQL of repeated query shapes, building clauses are skipped for them, only queries with plain
+	// conditions and without joins are cached
+	PlanCache bool
 	// DisableAutomaticPing
 	DisableAutomaticPing bool
 	// DisableForeignKeyConstraintWhenMigrating
 	DisableForeignKeyConstraintWhenMigrating bool
+	// DropUnusedWhenMigrating drop columns, indexes and foreign keys absent from models in AutoMigrate, data of dropped columns are lost,
+	// check MigrationPlannerInterface.Plan for statements it would execute
+	DropUnusedWhenMigrating bool
+	// ConcurrentIndexes create and drop indexes without locking tables when migrating, with CONCURRENTLY for postgres,
+	// which can't run in transaction, and ALGORITHM=INPLACE, LOCK=NONE for mysql
+	ConcurrentIndexes bool
+	// ConfirmColumnConversion confirms converting types of columns with `using` tag expressions when migrating, which might lose data,
+	// AutoMigrate returns ErrColumnConversionNotConfirmed for the conversions if it is not confirmed
+	ConfirmColumnConversion bool
+	// TranslateError translates errors of database to TranslatedError, e.g: errors.Is(err, gorm.ErrDuplicatedKey)
+	TranslateError bool
+	// AllowEmptyResult First, Take and Last don't return ErrRecordNotFound if no record found, check the found record with RowsAffected
+	AllowEmptyResult bool
+	// WrapQueryErrors wraps errors of executing statements with QueryError, which has SQL, table and operation of the statement
+	WrapQueryErrors bool
+	// MigrateWithLock AutoMigrate runs with application lock, app instances starting at the same time won't race on DDL, check Lock for details
+	MigrateWithLock bool
+	// MigrationHook hooks around DDL statements executed by Migrator, e.g: auditing schema changes or blocking disallowed operations
+	MigrationHook MigrationHook
+	// Interceptors intercept statements of every operation, e.g: adding clauses or vetoing statements, check Interceptor for details
+	Interceptors []Interceptor
+	// Seeds registered seeds applied with Seed
+	Seeds *Seeds
+	// Queries named queries loaded from SQL files, check NamedQuery for details
+	Queries *Queries
+	// TenantResolver resolves tenant of statements from their contexts, check TenantResolver for details
+	TenantResolver TenantResolver
+	// ActorResolver returns actor of context, e.g: current user id, it is assigned to fields tagged with `autoCreatedBy` when creating,
+	// and `autoUpdatedBy` when creating and updating, and `softDelete:by` when soft deleting
+	ActorResolver func(ctx context.Context) (actor interface{}, ok bool)
+	// SessionVariables returns session variables of context for postgres, e.g: `app.current_user` used by row-level security policies,
+	// they are set with set_config locally at the start of transactions, statements out of transactions are executed in transactions,
+	// except Row and Rows, they return ErrInvalidTransaction out of transactions, Iterate and FindInto stream rows in transactions
+	SessionVariables func(ctx context.Context) map[string]string
+	// TenantScope scopes rows of models having tenant column to tenant of context, check TenantScope for details
+	TenantScope *TenantScope
+	// Replicas read replicas, queries are routed to them, check Replicas for details
+	Replicas *Replicas
+	// StatementTimeout default timeout of statements, the context of statement is canceled after it, check WithTimeout for details,
+	// sessions turn it off with a negative timeout, e.g: db.Session(&gorm.Session{StatementTimeout: -1})
+	StatementTimeout time.Duration
+	// TransactionRetry retries transactions of Transaction failed with serialization failures or deadlocks, nested transactions are not retried
+	TransactionRetry *RetryPolicy
 	// DisableNestedTransaction disable nested transaction
 	DisableNestedTransaction bool
 	// AllowGlobalUpdate allow global update
 	AllowGlobalUpdate bool
+	// RejectWrites rejects statements other than queries with ErrReadOnlySession, check DB.ReadOnly for details
+	RejectWrites bool
 	// QueryFields executes the SQL query with all fields of the table
 	QueryFields bool
 	// CreateBatchSize default create batch size
 	CreateBatchSize int
+	// PurgeBatchSize batch size used when purging soft deleted records, default 1000
+	PurgeBatchSize int
+	// EstimatedCountThreshold EstimatedCount uses exact count if the estimated number is less than it, default 10000
+	EstimatedCountThreshold int64
+	// NullPolicy policy of scanning NULL into non-pointer fields, could be overwritten with field tag `null:error`
+	NullPolicy NullPolicy
+	// ColumnMapper maps result columns to field names or paths like `Company.Name` when scanning
+	ColumnMapper func(column string) (fieldPath string)
+	// StrictColumns validates columns of Select, Omit, Order and map conditions against the model, returns error for unknown columns
+	StrictColumns bool
+	// AllowedColumns raw columns or aliases always allowed in StrictColumns mode, e.g: `count(*)`, `total`
+	AllowedColumns []string
+	// TimeZone time values are converted to it when saving and reading, could be overwritten with field tag `timezone`
+	TimeZone *time.Location
+	// RolePolicy resolves read and write permissions of fields from context at runtime
+	RolePolicy RolePolicy
+	// PartitionRouter routes statements of partitioned models to partitions
+	PartitionRouter PartitionRouter
 
 	// ClauseBuilders clause builder
 	ClauseBuilders map[string]clause.ClauseBuilder
@@ -54,6 +129,7 @@ type Config struct {
 
 	callbacks  *callbacks
 	cacheStore *sync.Map
+	counters   *counters
 }
 
 // DB GORM DB definition
@@ -74,12 +150,24 @@ type Session struct {
 	SkipDefaultTransaction   bool
 	DisableNestedTransaction bool
 	AllowGlobalUpdate        bool
+	ReadOnly                 bool
 	FullSaveAssociations     bool
+	DropUnusedWhenMigrating  bool
+	ConcurrentIndexes        bool
+	ConfirmColumnConversion  bool
+	AllowEmptyResult         bool
+	StatementTimeout         time.Duration
+	PartitionRouter          PartitionRouter
+	Namespace                string
 	QueryFields              bool
 	Context                  context.Context
 	Logger                   logger.Interface
 	NowFunc                  func() time.Time
 	CreateBatchSize          int
+	NullPolicy               NullPolicy
+	ColumnMapper             func(column string) (fieldPath string)
+	StrictColumns            bool
+	AllowedColumns           []string
 }
 
 // Open initialize db session based on dialector
@@ -120,6 +208,10 @@ func Open(dialector Dialector, config *Config) (db *DB, err error) {
 		config.cacheStore = &sync.Map{}
 	}
 
+	if config.counters == nil {
+		config.counters = &counters{}
+	}
+
 	db = &DB{Config: config, clone: 1}
 
 	db.callbacks = initializeCallbacks(db)
@@ -187,10 +279,34 @@ func (db *DB) Session(config *Session) *DB {
 		txConfig.AllowGlobalUpdate = true
 	}
 
+	if config.ReadOnly {
+		txConfig.RejectWrites = true
+	}
+
 	if config.FullSaveAssociations {
 		txConfig.FullSaveAssociations = true
 	}
 
+	if config.DropUnusedWhenMigrating {
+		txConfig.DropUnusedWhenMigrating = true
+	}
+
+	if config.ConcurrentIndexes {
+		txConfig.ConcurrentIndexes = true
+	}
+
+	if config.ConfirmColumnConversion {
+		txConfig.ConfirmColumnConversion = true
+	}
+
+	if config.Namespace != "" {
+		txConfig.Namespace = config.Namespace
+	}
+
+	if config.PartitionRouter != nil {
+		txConfig.PartitionRouter = config.PartitionRouter
+	}
+
 	if config.Context != nil || config.PrepareStmt || config.SkipHooks {
 		tx.Statement = tx.Statement.clone()
 		tx.Statement.DB = tx
@@ -221,6 +337,14 @@ func (db *DB) Session(config *Session) *DB {
 		txConfig.DisableNestedTransaction = true
 	}
 
+	if config.AllowEmptyResult {
+		txConfig.AllowEmptyResult = true
+	}
+
+	if config.StatementTimeout != 0 {
+		txConfig.StatementTimeout = config.StatementTimeout
+	}
+
 	if !config.NewDB {
 		tx.clone = 2
 	}
@@ -241,6 +365,22 @@ func (db *DB) Session(config *Session) *DB {
 		tx.Config.NowFunc = config.NowFunc
 	}
 
+	if config.NullPolicy != "" {
+		tx.Config.NullPolicy = config.NullPolicy
+	}
+
+	if config.ColumnMapper != nil {
+		tx.Config.ColumnMapper = config.ColumnMapper
+	}
+
+	if config.StrictColumns {
+		tx.Config.StrictColumns = true
+	}
+
+	if len(config.AllowedColumns) > 0 {
+		tx.Config.AllowedColumns = append(tx.Config.AllowedColumns[:len(tx.Config.AllowedColumns):len(tx.Config.AllowedColumns)], config.AllowedColumns...)
+	}
+
 	return tx
 }
 
@@ -256,6 +396,22 @@ func (db *DB) Debug() (tx *DB) {
 	})
 }
 
+// AllowEmpty First, Take and Last won't return ErrRecordNotFound if no record found
+//    if err := db.AllowEmpty().First(&user, "name = ?", "jinzhu").Error; err == nil && user.ID == 0 {
+//      // user not found
+//    }
+func (db *DB) AllowEmpty() (tx *DB) {
+	return db.Session(&Session{AllowEmptyResult: true})
+}
+
+// LogLevel changes log level of logger for current instance, e.g: debugging a single noisy endpoint
+//    db.WithContext(ctx).LogLevel(logger.Info).Find(&users)
+func (db *DB) LogLevel(level logger.LogLevel) (tx *DB) {
+	return db.Session(&Session{
+		Logger: db.Logger.LogMode(level),
+	})
+}
+
 // Set store value with key into current db instance's context
 func (db *DB) Set(key string, value interface{}) *DB {
 	tx := db.getInstance()
@@ -287,6 +443,10 @@ func (db *DB) Callback() *callbacks {
 
 // AddError add error to db
 func (db *DB) AddError(err error) error {
+	if db.TranslateError && err != nil {
+		err = db.translateError(err)
+	}
+
 	if db.Error == nil {
 		db.Error = err
 	} else if err != nil {
@@ -317,17 +477,18 @@ func (db *DB) getInstance() *DB {
 		if db.clone == 1 {
 			// clone with new statement
 			tx.Statement = &Statement{
-				DB:       tx,
-				ConnPool: db.Statement.ConnPool,
-				Context:  db.Statement.Context,
-				Clauses:  map[string]clause.Clause{},
-				Vars:     make([]interface{}, 0, 8),
+				DB:            tx,
+				ConnPool:      db.Statement.ConnPool,
+				Context:       db.Statement.Context,
+				Clauses:       map[string]clause.Clause{},
+				InTransaction: db.Statement.InTransaction,
 			}
 		} else {
 			// with clone statement
 			tx.Statement = db.Statement.clone()
 			tx.Statement.DB = tx
 		}
+		tx.Statement.acquireVars()
 
 		return tx
 	}
@@ -339,6 +500,23 @@ func Expr(expr string, args ...interface{}) clause.Expr {
 	return clause.Expr{SQL: expr, Vars: args}
 }
 
+// DefineRelationships define relationships of model without struct tags, e.g: for generated or third-party structs,
+// it should be called before the model is used
+//     db.DefineRelationships(&pb.User{}, func(s *schema.Schema) {
+//       s.DefineRelationship("Company", "foreignKey:CompanyRefer")
+//     })
+func (db *DB) DefineRelationships(model interface{}, fc func(*schema.Schema)) error {
+	return schema.RegisterRelationships(db.cacheStore, model, fc)
+}
+
+// ClearSchemaCache remove cached schemas of models and schemas having relationships with them, they will be parsed again
+// when used, e.g: after tags changed by plugins, all cached schemas are removed if no models given, join tables setup
+// with SetupJoinTable need to be setup again
+//     db.ClearSchemaCache(&User{})
+func (db *DB) ClearSchemaCache(models ...interface{}) {
+	schema.ClearCache(db.cacheStore, models...)
+}
+
 func (db *DB) SetupJoinTable(model interface{}, field string, joinTable interface{}) error {
 	var (
 		tx                      = db.getInstance()
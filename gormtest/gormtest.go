@@ -0,0 +1,117 @@
+// Package gormtest helps integration tests share one database, each test runs in its own transaction rolled back at
+// cleanup, so tests could run in parallel without seeing data of each other, unit tests could use Stub without database
+//    func TestCreateUser(t *testing.T) {
+//      t.Parallel()
+//      db := gormtest.Begin(t, DB)
+//      clock := gormtest.NewClock(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC))
+//      db = clock.Use(db)
+//      CreateUser(db, "jinzhu") // commits are no-op, data is rolled back after the test
+//    }
+package gormtest
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var savePointID uint64
+
+// Begin begins a transaction rolled back when tb and its subtests complete, returns db of the transaction whose Commit
+// and Rollback are no-op, transactions begun in it with Begin or Transaction are backed by savepoints
+func Begin(tb testing.TB, db *gorm.DB) *gorm.DB {
+	tb.Helper()
+
+	tx := db.Begin()
+	if tx.Error != nil {
+		tb.Fatalf("gormtest: failed to begin transaction, got error %v", tx.Error)
+	}
+
+	conn := tx.Statement.ConnPool
+	tb.Cleanup(func() {
+		tx.Statement.ConnPool = conn
+		if err := tx.Rollback().Error; err != nil {
+			tb.Errorf("gormtest: failed to rollback transaction, got error %v", err)
+		}
+	})
+
+	tx.Statement.ConnPool = &txPool{ConnPool: conn, db: tx}
+	return tx
+}
+
+// txPool connection of test transaction, its Commit and Rollback are no-op, or release and rollback to its savepoint
+type txPool struct {
+	gorm.ConnPool
+	db        *gorm.DB // db of the test transaction
+	savePoint string
+}
+
+// BeginTx begins nested transaction with savepoint
+func (pool *txPool) BeginTx(ctx context.Context, opts *sql.TxOptions) (gorm.ConnPool, error) {
+	savePointer, ok := pool.db.Dialector.(gorm.SavePointerDialectorInterface)
+	if !ok {
+		return nil, gorm.ErrUnsupportedDriver
+	}
+
+	name := fmt.Sprintf("gormtest_sp%d", atomic.AddUint64(&savePointID, 1))
+	if err := savePointer.SavePoint(pool.db.Session(&gorm.Session{Context: ctx}), name); err != nil {
+		return nil, err
+	}
+	return &txPool{ConnPool: pool.ConnPool, db: pool.db, savePoint: name}, nil
+}
+
+// Commit commits nothing, data is kept until the test transaction is rolled back
+func (pool *txPool) Commit() error {
+	return nil
+}
+
+// Rollback rollbacks to savepoint of the nested transaction, it is no-op for the test transaction
+func (pool *txPool) Rollback() error {
+	if pool.savePoint == "" {
+		return nil
+	}
+	return pool.db.Dialector.(gorm.SavePointerDialectorInterface).RollbackTo(pool.db.Session(&gorm.Session{}), pool.savePoint)
+}
+
+// Clock fake clock used as NowFunc of db, timestamps like CreatedAt, UpdatedAt are filled with its time
+type Clock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewClock returns fake clock at now
+func NewClock(now time.Time) *Clock {
+	return &Clock{now: now}
+}
+
+// Now returns current time of the clock
+func (clock *Clock) Now() time.Time {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+	return clock.now
+}
+
+// Set sets current time of the clock
+func (clock *Clock) Set(now time.Time) {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+	clock.now = now
+}
+
+// Advance moves the clock forward by d
+func (clock *Clock) Advance(d time.Duration) {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+	clock.now = clock.now.Add(d)
+}
+
+// Use returns db using the clock as NowFunc
+func (clock *Clock) Use(db *gorm.DB) *gorm.DB {
+	return db.Session(&gorm.Session{NowFunc: clock.Now})
+}
@@ -0,0 +1,289 @@
+package gormtest
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"sync"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/callbacks"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/migrator"
+	"gorm.io/gorm/schema"
+)
+
+// Stub dialector recording executed statements and serving canned results of them without database, results are
+// matched by fingerprints of statements, check logger.Fingerprint, statements without results return no rows,
+// statements with canceled contexts fail as drivers do
+//    stub := gormtest.NewStub()
+//    stub.On("SELECT * FROM `users` WHERE name = ?", gormtest.Result{Columns: []string{"id", "name"}, Rows: [][]interface{}{{1, "jinzhu"}}})
+//    db, _ := gorm.Open(stub, &gorm.Config{})
+//    repo.FindByName(db, "jinzhu")
+//    stub.Statements() // [{SQL: "SELECT * FROM `users` WHERE name = ?", Vars: ["jinzhu"]}]
+type Stub struct {
+	mu         sync.Mutex
+	results    map[string][]Result
+	statements []Statement
+}
+
+// Statement statement executed with stub
+type Statement struct {
+	SQL  string
+	Vars []interface{}
+}
+
+// Result canned result of statement, Rows are returned for queries, RowsAffected and LastInsertID for executions,
+// Error is returned instead if not nil
+type Result struct {
+	Columns      []string
+	Rows         [][]interface{}
+	RowsAffected int64
+	LastInsertID int64
+	Error        error
+}
+
+// NewStub returns stub dialector
+func NewStub() *Stub {
+	return &Stub{results: map[string][]Result{}}
+}
+
+// On serves results for statements of the same shape as sql, results are returned in order, the last one is repeated
+func (stub *Stub) On(sql string, results ...Result) *Stub {
+	stub.mu.Lock()
+	defer stub.mu.Unlock()
+	fingerprint := logger.Fingerprint(sql)
+	stub.results[fingerprint] = append(stub.results[fingerprint], results...)
+	return stub
+}
+
+// Statements returns statements executed with stub
+func (stub *Stub) Statements() []Statement {
+	stub.mu.Lock()
+	defer stub.mu.Unlock()
+	return append([]Statement(nil), stub.statements...)
+}
+
+// Reset clears executed statements and results
+func (stub *Stub) Reset() {
+	stub.mu.Lock()
+	defer stub.mu.Unlock()
+	stub.statements, stub.results = nil, map[string][]Result{}
+}
+
+// result records statement and returns its result
+func (stub *Stub) result(query string, args []driver.NamedValue) Result {
+	stub.mu.Lock()
+	defer stub.mu.Unlock()
+
+	vars := make([]interface{}, len(args))
+	for idx, arg := range args {
+		vars[idx] = arg.Value
+	}
+	stub.statements = append(stub.statements, Statement{SQL: query, Vars: vars})
+
+	fingerprint := logger.Fingerprint(query)
+	results := stub.results[fingerprint]
+	if len(results) == 0 {
+		return Result{}
+	} else if len(results) > 1 {
+		stub.results[fingerprint] = results[1:]
+	}
+	return results[0]
+}
+
+func (stub *Stub) Name() string {
+	return "stub"
+}
+
+func (stub *Stub) Initialize(db *gorm.DB) error {
+	callbacks.RegisterDefaultCallbacks(db, &callbacks.Config{})
+	db.ConnPool = sql.OpenDB(stubConnector{stub: stub})
+	return nil
+}
+
+func (stub *Stub) Migrator(db *gorm.DB) gorm.Migrator {
+	return migrator.Migrator{Config: migrator.Config{DB: db, Dialector: stub}}
+}
+
+func (stub *Stub) DataTypeOf(field *schema.Field) string {
+	return string(field.DataType)
+}
+
+func (stub *Stub) DefaultValueOf(field *schema.Field) clause.Expression {
+	return clause.Expr{SQL: "DEFAULT"}
+}
+
+func (stub *Stub) BindVarTo(writer clause.Writer, stmt *gorm.Statement, v interface{}) {
+	writer.WriteByte('?')
+}
+
+func (stub *Stub) QuoteTo(writer clause.Writer, str string) {
+	writer.WriteByte('`')
+	writer.WriteString(str)
+	writer.WriteByte('`')
+}
+
+func (stub *Stub) Explain(sql string, vars ...interface{}) string {
+	return logger.ExplainSQL(sql, nil, `'`, vars...)
+}
+
+func (stub *Stub) SavePoint(tx *gorm.DB, name string) error {
+	return tx.Exec("SAVEPOINT " + name).Error
+}
+
+func (stub *Stub) RollbackTo(tx *gorm.DB, name string) error {
+	return tx.Exec("ROLLBACK TO SAVEPOINT " + name).Error
+}
+
+// stubConnector database/sql connector of stub
+type stubConnector struct {
+	stub *Stub
+}
+
+func (connector stubConnector) Connect(context.Context) (driver.Conn, error) {
+	return &stubConn{stub: connector.stub}, nil
+}
+
+func (connector stubConnector) Driver() driver.Driver {
+	return stubDriver{stub: connector.stub}
+}
+
+type stubDriver struct {
+	stub *Stub
+}
+
+func (d stubDriver) Open(string) (driver.Conn, error) {
+	return &stubConn{stub: d.stub}, nil
+}
+
+// stubConn connection of stub, transactions are no-op
+type stubConn struct {
+	stub *Stub
+}
+
+func (conn *stubConn) Prepare(query string) (driver.Stmt, error) {
+	return &stubStmt{conn: conn, query: query}, nil
+}
+
+func (conn *stubConn) Close() error {
+	return nil
+}
+
+func (conn *stubConn) Begin() (driver.Tx, error) {
+	return conn, nil
+}
+
+func (conn *stubConn) Commit() error {
+	return nil
+}
+
+func (conn *stubConn) Rollback() error {
+	return nil
+}
+
+func (conn *stubConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	result := conn.stub.result(query, args)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return stubResult{result: result}, nil
+}
+
+func (conn *stubConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	result := conn.stub.result(query, args)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &stubRows{result: result}, nil
+}
+
+// stubResult result of execution
+type stubResult struct {
+	result Result
+}
+
+func (r stubResult) LastInsertId() (int64, error) {
+	return r.result.LastInsertID, nil
+}
+
+func (r stubResult) RowsAffected() (int64, error) {
+	return r.result.RowsAffected, nil
+}
+
+// stubStmt prepared statement of stub
+type stubStmt struct {
+	conn  *stubConn
+	query string
+}
+
+func (stmt *stubStmt) Close() error {
+	return nil
+}
+
+func (stmt *stubStmt) NumInput() int {
+	return -1
+}
+
+func (stmt *stubStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return stmt.conn.ExecContext(context.Background(), stmt.query, namedValues(args))
+}
+
+func (stmt *stubStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return stmt.conn.QueryContext(context.Background(), stmt.query, namedValues(args))
+}
+
+func namedValues(args []driver.Value) []driver.NamedValue {
+	values := make([]driver.NamedValue, len(args))
+	for idx, arg := range args {
+		values[idx] = driver.NamedValue{Ordinal: idx + 1, Value: arg}
+	}
+	return values
+}
+
+// stubRows rows of canned result
+type stubRows struct {
+	result Result
+	idx    int
+}
+
+func (rows *stubRows) Columns() []string {
+	return rows.result.Columns
+}
+
+func (rows *stubRows) Close() error {
+	return nil
+}
+
+func (rows *stubRows) Next(dest []driver.Value) error {
+	if rows.idx >= len(rows.result.Rows) {
+		return io.EOF
+	}
+
+	row := rows.result.Rows[rows.idx]
+	rows.idx++
+	for idx := range dest {
+		if idx >= len(row) {
+			dest[idx] = nil
+			continue
+		}
+
+		value, err := driver.DefaultParameterConverter.ConvertValue(row[idx])
+		if err != nil {
+			return fmt.Errorf("gormtest: invalid value of column %v: %w", idx, err)
+		}
+		dest[idx] = value
+	}
+	return nil
+}
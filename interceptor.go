@@ -0,0 +1,61 @@
+package gorm
+
+// Interceptor intercepts statements of every operation, e.g: create, query, update, delete, row, raw, BeforeQuery is
+// called before callbacks of the operation, it could inspect and rewrite the statement, e.g: adding clauses or
+// rewriting table names, the statement is not executed if it returns error, AfterQuery is called after executing in
+// reverse order for interceptors whose BeforeQuery passed, unlike hooks of models, interceptors are called for all models
+//    type SoftTenancy struct{}
+//
+//    func (SoftTenancy) BeforeQuery(db *gorm.DB, operation string) error {
+//      tenant, ok := db.Statement.Context.Value(tenantKey{}).(string)
+//      if !ok {
+//        return errors.New("tenant required")
+//      }
+//      db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{clause.Eq{Column: "tenant_id", Value: tenant}}})
+//      return nil
+//    }
+//
+//    func (SoftTenancy) AfterQuery(db *gorm.DB, operation string) {}
+//
+//    db, err := gorm.Open(sqlite.Open("gorm.db"), &gorm.Config{Interceptors: []gorm.Interceptor{SoftTenancy{}}})
+type Interceptor interface {
+	BeforeQuery(db *DB, operation string) error
+	AfterQuery(db *DB, operation string)
+}
+
+// Intercept returns session intercepted by interceptors after interceptors of current session
+//    db.Intercept(auditor).Delete(&user)
+func (db *DB) Intercept(interceptors ...Interceptor) *DB {
+	tx := db.Session(&Session{})
+	tx.Config.Interceptors = append(append(make([]Interceptor, 0, len(db.Interceptors)+len(interceptors)), db.Interceptors...), interceptors...)
+	return tx
+}
+
+// interceptorsHook executes statement if BeforeQuery of all interceptors passed, AfterQuery of passed ones are called after it
+func interceptorsHook(e *execution) {
+	intercepted, passed := beforeQuery(e.db, e.name)
+	if passed {
+		e.next()
+	}
+	afterQuery(e.db, e.name, intercepted)
+}
+
+// beforeQuery calls BeforeQuery of interceptors in order, returns interceptors whose BeforeQuery passed and false if
+// one of them vetoed the statement
+func beforeQuery(db *DB, operation string) (passed []Interceptor, ok bool) {
+	interceptors := db.Statement.DB.Interceptors
+	for idx, interceptor := range interceptors {
+		if err := interceptor.BeforeQuery(db, operation); err != nil {
+			db.AddError(err)
+			return interceptors[:idx], false
+		}
+	}
+	return interceptors, true
+}
+
+// afterQuery calls AfterQuery of interceptors in reverse order
+func afterQuery(db *DB, operation string, interceptors []Interceptor) {
+	for idx := len(interceptors) - 1; idx >= 0; idx-- {
+		interceptors[idx].AfterQuery(db, operation)
+	}
+}
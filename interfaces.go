@@ -40,6 +40,11 @@ type SavePointerDialectorInterface interface {
 	RollbackTo(tx *DB, name string) error
 }
 
+// SavePointReleaserDialectorInterface releases savepoints, savepoints are released with quoted names if not implemented
+type SavePointReleaserDialectorInterface interface {
+	ReleaseSavePoint(tx *DB, name string) error
+}
+
 type TxBeginner interface {
 	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
 }
@@ -57,3 +62,10 @@ type TxCommitter interface {
 type Valuer interface {
 	GormValue(context.Context, *DB) clause.Expr
 }
+
+// RolePolicy resolves read and write permissions of fields at runtime, e.g: by roles of current user in context,
+// fields can't be read are not scanned, fields can't be written are ignored when creating and updating
+type RolePolicy interface {
+	CanRead(ctx context.Context, field *schema.Field) bool
+	CanWrite(ctx context.Context, field *schema.Field) bool
+}
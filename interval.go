@@ -0,0 +1,61 @@
+package gorm
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm/clause"
+)
+
+// AddInterval adds interval to time, Time and Interval could be column names, clause.Column or values,
+// Interval values should be time.Duration, interval columns should be time.Duration fields with tag `interval`
+//    // expired jobs, started_at + timeout < now
+//    db.Where("? < ?", gorm.AddInterval{Time: "started_at", Interval: "timeout"}, time.Now()).Find(&jobs)
+//    db.Where("? > ?", gorm.AddInterval{Time: "created_at", Interval: 24 * time.Hour}, time.Now()).Find(&users)
+type AddInterval struct {
+	Time     interface{}
+	Interval interface{}
+}
+
+// Build build expression
+func (add AddInterval) Build(builder clause.Builder) {
+	if stmt, ok := builder.(*Statement); ok {
+		add.GormValue(stmt.Context, stmt.DB).Build(builder)
+	}
+}
+
+// GormValue builds expression with dialect's date function
+func (add AddInterval) GormValue(ctx context.Context, db *DB) clause.Expr {
+	var (
+		timeValue            = add.Time
+		interval             = add.Interval
+		duration, isDuration = add.Interval.(time.Duration)
+	)
+
+	if name, ok := timeValue.(string); ok {
+		timeValue = clause.Column{Name: name}
+	}
+
+	if name, ok := interval.(string); ok {
+		interval = clause.Column{Name: name}
+	}
+
+	switch db.Capabilities().DateAdd {
+	case SyntaxPostgres:
+		if isDuration {
+			return clause.Expr{SQL: "(? + CAST(? AS interval))", Vars: []interface{}{timeValue, strconv.FormatInt(duration.Microseconds(), 10) + " microseconds"}}
+		}
+		return clause.Expr{SQL: "(? + ?)", Vars: []interface{}{timeValue, interval}}
+	case SyntaxMySQL:
+		if isDuration {
+			interval = duration.Microseconds()
+		}
+		return clause.Expr{SQL: "DATE_ADD(?, INTERVAL ? MICROSECOND)", Vars: []interface{}{timeValue, interval}}
+	default:
+		if isDuration {
+			return clause.Expr{SQL: "strftime('%Y-%m-%d %H:%M:%f', ?, ?)", Vars: []interface{}{timeValue, strconv.FormatFloat(duration.Seconds(), 'f', -1, 64) + " seconds"}}
+		}
+		return clause.Expr{SQL: "strftime('%Y-%m-%d %H:%M:%f', ?, (? / 1000000.0) || ' seconds')", Vars: []interface{}{timeValue, interval}}
+	}
+}
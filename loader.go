@@ -0,0 +1,149 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"gorm.io/gorm/schema"
+)
+
+const (
+	// loaderWait duration lookups are collected before they are loaded in one query
+	loaderWait = time.Millisecond
+	// loaderMaxBatch max number of primary keys loaded in one query
+	loaderMaxBatch = 100
+)
+
+// Loader coalesces primary key lookups of the same model issued concurrently with the same context into one IN query,
+// e.g: lookups of GraphQL resolvers of a request, lookups of identical primary keys are loaded once
+//    var user User
+//    err := db.Loader(ctx).Find(&user, id)
+type Loader struct {
+	db  *DB
+	ctx context.Context
+}
+
+// Loader returns loader of ctx, lookups are coalesced by ctx and model, conditions of db are applied to the loading query
+func (db *DB) Loader(ctx context.Context) *Loader {
+	return &Loader{db: db, ctx: ctx}
+}
+
+type loaderKey struct {
+	ctx   context.Context
+	db    *DB
+	model reflect.Type
+}
+
+type loaderResult struct {
+	value reflect.Value
+	err   error
+}
+
+// loaderBatch lookups of primary keys being collected
+type loaderBatch struct {
+	ids     []interface{}
+	waiters map[string][]chan loaderResult
+}
+
+// loaderBatches batches being collected of db
+type loaderBatches struct {
+	mu      sync.Mutex
+	batches map[loaderKey]*loaderBatch
+}
+
+// Find finds record of primary key id into dest, it waits lookups of other goroutines to be loaded in one query,
+// returns ErrRecordNotFound if no record found
+func (l *Loader) Find(dest interface{}, id interface{}) error {
+	rv := reflect.ValueOf(dest)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("%w: loader requires pointer of struct, got %T", ErrInvalidData, dest)
+	}
+
+	s, err := schema.Parse(dest, l.db.cacheStore, l.db.NamingStrategy)
+	if err != nil {
+		return err
+	}
+
+	if len(s.PrimaryFields) != 1 {
+		return fmt.Errorf("%w: loader requires one primary key of %v", ErrPrimaryKeyRequired, s.Name)
+	}
+
+	v, _ := l.db.cacheStore.LoadOrStore("gorm:loader", &loaderBatches{batches: map[loaderKey]*loaderBatch{}})
+	var (
+		batches = v.(*loaderBatches)
+		key     = loaderKey{ctx: l.ctx, db: l.db, model: s.ModelType}
+		idKey   = fmt.Sprint(id)
+		ch      = make(chan loaderResult, 1)
+	)
+
+	batches.mu.Lock()
+	batch, ok := batches.batches[key]
+	if !ok {
+		batch = &loaderBatch{waiters: map[string][]chan loaderResult{}}
+		batches.batches[key] = batch
+		time.AfterFunc(loaderWait, func() { l.load(batches, key, batch, s) })
+	}
+
+	if _, ok := batch.waiters[idKey]; !ok {
+		batch.ids = append(batch.ids, id)
+	}
+	batch.waiters[idKey] = append(batch.waiters[idKey], ch)
+
+	if len(batch.ids) >= loaderMaxBatch {
+		delete(batches.batches, key)
+		go l.load(batches, key, batch, s)
+	}
+	batches.mu.Unlock()
+
+	result := <-ch
+	if result.err == nil {
+		rv.Elem().Set(result.value)
+	}
+	return result.err
+}
+
+// load loads records of batch in one query, and sends them to waiters
+func (l *Loader) load(batches *loaderBatches, key loaderKey, batch *loaderBatch, s *schema.Schema) {
+	batches.mu.Lock()
+	if batches.batches[key] == batch {
+		delete(batches.batches, key)
+	}
+	ids := batch.ids
+	batch.ids = nil
+	batches.mu.Unlock()
+
+	if ids == nil {
+		// loaded already when the batch is full
+		return
+	}
+
+	var (
+		results = reflect.New(reflect.SliceOf(s.ModelType))
+		err     = l.db.WithContext(l.ctx).Find(results.Interface(), ids).Error
+		records = map[string]reflect.Value{}
+	)
+
+	if err == nil {
+		for i := 0; i < results.Elem().Len(); i++ {
+			record := results.Elem().Index(i)
+			value, _ := s.PrimaryFields[0].ValueOf(record)
+			records[fmt.Sprint(value)] = record
+		}
+	}
+
+	for idKey, waiters := range batch.waiters {
+		result := loaderResult{err: err}
+		if record, ok := records[idKey]; ok {
+			result.value = record
+		} else if err == nil {
+			result.err = ErrRecordNotFound
+		}
+
+		for _, ch := range waiters {
+			ch <- result
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package gorm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"time"
+)
+
+// LocksTable table of application locks, used by dialects without advisory locks
+const LocksTable = "gorm_locks"
+
+// LockPollInterval interval of retrying to acquire application lock
+var LockPollInterval = 100 * time.Millisecond
+
+// LockTTL application locks in LocksTable locked before it are stale, e.g: holders crashed, they are taken over by others,
+// running holders refresh their locks every third of it, advisory locks are released with connections of crashed holders
+var LockTTL = 10 * time.Minute
+
+// lockRecord application lock saved in LocksTable
+type lockRecord struct {
+	Name     string `gorm:"primaryKey;size:255"`
+	LockedAt time.Time
+}
+
+// Lock acquires application lock name across app instances, waits until it is released by others or context is done,
+// advisory locks are used if the database supports them, e.g: postgres and mysql, others insert the lock into table
+// `gorm_locks`, locks in it locked before LockTTL are taken over
+//    unlock, err := db.WithContext(ctx).Lock("import_orders")
+//    if err == nil {
+//      defer unlock()
+//    }
+func (db *DB) Lock(name string) (unlock func() error, err error) {
+	var (
+		tx     = db.getInstance()
+		ctx    = tx.Statement.Context
+		conn   ConnPool
+		closer func() error
+	)
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	// advisory locks belong to connections, locks are acquired and released with the same connection, locks in LocksTable
+	// are inserted with dedicated connections, so they are visible to others before the transaction is committed, and
+	// heartbeats don't share the connection of transaction with fc
+	if tx.Statement.InTransaction && tx.Capabilities().AdvisoryLock != "" {
+		conn = tx.Statement.ConnPool
+	} else if sqlDB, err := tx.DB(); err == nil {
+		sqlConn, err := sqlDB.Conn(ctx)
+		if err != nil {
+			return nil, err
+		}
+		conn, closer = sqlConn, sqlConn.Close
+	} else if tx.Statement.InTransaction {
+		return nil, fmt.Errorf("%w: locks in %v require dedicated connections out of transactions", ErrInvalidTransaction, LocksTable)
+	} else {
+		conn = tx.Statement.ConnPool
+	}
+
+	// locks are shared by namespaces, LocksTable is created in the default one
+	locker := tx.Session(&Session{NewDB: true, Context: ctx})
+	locker.Statement.ConnPool, locker.Statement.InTransaction = conn, true
+	locker.Namespace = ""
+
+	if unlock, err = locker.lock(ctx, name); err != nil {
+		if closer != nil {
+			closer()
+		}
+		return nil, err
+	}
+
+	if closer != nil {
+		release := unlock
+		unlock = func() error {
+			defer closer()
+			return release()
+		}
+	}
+	return unlock, nil
+}
+
+// WithLock runs fc with application lock name, check Lock for details
+func (db *DB) WithLock(name string, fc func(tx *DB) error) error {
+	unlock, err := db.Lock(name)
+	if err != nil {
+		return err
+	}
+
+	defer unlock()
+	return fc(db.Session(&Session{}))
+}
+
+func (locker *DB) lock(ctx context.Context, name string) (unlock func() error, err error) {
+	switch locker.Capabilities().AdvisoryLock {
+	case SyntaxPostgres:
+		key := lockKey(name)
+		if err = locker.Exec("SELECT pg_advisory_lock(?)", key).Error; err == nil {
+			unlock = func() error {
+				return locker.Session(&Session{NewDB: true, Context: context.Background()}).Exec("SELECT pg_advisory_unlock(?)", key).Error
+			}
+		}
+		return
+	case SyntaxMySQL:
+		// mysql lock names are limited to 64 characters
+		if len(name) > 64 {
+			name = strconv.FormatInt(lockKey(name), 16)
+		}
+
+		for {
+			var acquired sql.NullInt64
+			if err = locker.Raw("SELECT GET_LOCK(?, 1)", name).Row().Scan(&acquired); err != nil {
+				return nil, err
+			}
+
+			if acquired.Int64 == 1 {
+				return func() error {
+					return locker.Session(&Session{NewDB: true, Context: context.Background()}).Exec("SELECT RELEASE_LOCK(?)", name).Error
+				}, nil
+			}
+
+			if err = ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	tx := locker.Table(LocksTable)
+	if !tx.Migrator().HasTable(LocksTable) {
+		// the table might be created by other instances at the same time
+		if err = tx.Migrator().CreateTable(&lockRecord{}); err != nil && !tx.Migrator().HasTable(LocksTable) {
+			return nil, err
+		}
+	}
+
+	for {
+		if err = locker.Table(LocksTable).Create(&lockRecord{Name: name, LockedAt: locker.NowFunc()}).Error; err == nil {
+			stop := locker.heartbeat(name)
+			return func() error {
+				stop()
+				return locker.Session(&Session{NewDB: true, Context: context.Background()}).Table(LocksTable).Where("name = ?", name).Delete(&lockRecord{}).Error
+			}, nil
+		} else if !errors.Is(locker.translateError(err), ErrDuplicatedKey) {
+			// only locks held by others are waited, e.g: permission errors, missing columns and bad connections are returned
+			return nil, err
+		}
+
+		if LockTTL > 0 {
+			stale := locker.Table(LocksTable).Where("name = ? AND locked_at < ?", name, locker.NowFunc().Add(-LockTTL)).Delete(&lockRecord{})
+			if stale.Error == nil && stale.RowsAffected > 0 {
+				continue
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(LockPollInterval):
+		}
+	}
+}
+
+// heartbeat refreshes lock name in LocksTable every third of LockTTL until it is stopped, so the lock isn't taken over
+// as stale while its holder is still running
+func (locker *DB) heartbeat(name string) (stop func()) {
+	ttl := LockTTL
+	if ttl <= 0 {
+		return func() {}
+	}
+
+	done, stopped := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(ttl / 3)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				locker.Session(&Session{NewDB: true, Context: context.Background()}).Table(LocksTable).Where("name = ?", name).Update("locked_at", locker.NowFunc())
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+// lockKey hash lock name to int64 key
+func lockKey(name string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(name))
+	return int64(h.Sum64())
+}
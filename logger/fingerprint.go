@@ -0,0 +1,112 @@
+package logger
+
+import (
+	"context"
+	"hash/fnv"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var (
+	inListRegexp      = regexp.MustCompile(`(?i)\bIN\s*\(\s*\?(?:\s*,\s*\?)*\s*\)`)
+	valuesListsRegexp = regexp.MustCompile(`(?i)\bVALUES\s*(\(\?(?:\s*,\s*\?)*\))(?:\s*,\s*\(\?(?:\s*,\s*\?)*\))+`)
+)
+
+// NormalizeSQL returns shape of sql, string and numeric literals and placeholders are replaced with ?, IN lists are collapsed to (...),
+// rows of multi-rows VALUES are collapsed to one, whitespaces are collapsed to one space
+//    NormalizeSQL("SELECT * FROM users WHERE name = 'jinzhu' AND id IN (1,2,3)") // SELECT * FROM users WHERE name = ? AND id IN (...)
+func NormalizeSQL(sql string) string {
+	var (
+		builder strings.Builder
+		runes   = []rune(sql)
+		space   bool
+	)
+
+	isIdentifier := func(r rune) bool {
+		return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+	}
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if unicode.IsSpace(r) {
+			space = builder.Len() > 0
+			continue
+		}
+
+		if space {
+			builder.WriteByte(' ')
+			space = false
+		}
+
+		switch {
+		case r == '\'':
+			for i++; i < len(runes); i++ {
+				if runes[i] == '\'' {
+					if i+1 < len(runes) && runes[i+1] == '\'' {
+						i++
+						continue
+					}
+					break
+				}
+			}
+			builder.WriteByte('?')
+		case r == '"' || r == '`' || r == '[':
+			// quoted identifiers are kept
+			end := r
+			if r == '[' {
+				end = ']'
+			}
+
+			builder.WriteRune(r)
+			for i++; i < len(runes); i++ {
+				builder.WriteRune(runes[i])
+				if runes[i] == end {
+					break
+				}
+			}
+		case unicode.IsDigit(r) && (i == 0 || !isIdentifier(runes[i-1])),
+			(r == '$' || r == ':') && i+1 < len(runes) && unicode.IsDigit(runes[i+1]),
+			r == '@' && i+2 < len(runes) && (runes[i+1] == 'p' || runes[i+1] == 'P') && unicode.IsDigit(runes[i+2]):
+			for i++; i < len(runes) && (isIdentifier(runes[i]) || runes[i] == '.'); i++ {
+			}
+			i--
+			builder.WriteByte('?')
+		case isIdentifier(r):
+			for ; i < len(runes) && isIdentifier(runes[i]); i++ {
+				builder.WriteRune(runes[i])
+			}
+			i--
+		default:
+			builder.WriteRune(r)
+		}
+	}
+
+	normalized := inListRegexp.ReplaceAllString(builder.String(), "IN (...)")
+	return valuesListsRegexp.ReplaceAllString(normalized, "VALUES $1")
+}
+
+// Fingerprint returns hash of normalized sql, statements of the same shape have the same fingerprint, check NormalizeSQL for details
+func Fingerprint(sql string) string {
+	h := fnv.New64a()
+	h.Write([]byte(NormalizeSQL(sql)))
+	return strconv.FormatUint(h.Sum64(), 16)
+}
+
+type fingerprintKey struct{}
+
+// WithFingerprint returns context carrying fingerprint of sql, it is set for loggers when QueryFingerprint is enabled
+func WithFingerprint(ctx context.Context, fingerprint string) context.Context {
+	return context.WithValue(ctx, fingerprintKey{}, fingerprint)
+}
+
+// FingerprintFromContext returns fingerprint of sql set with WithFingerprint
+func FingerprintFromContext(ctx context.Context) string {
+	if ctx != nil {
+		if fingerprint, ok := ctx.Value(fingerprintKey{}).(string); ok {
+			return fingerprint
+		}
+	}
+	return ""
+}
@@ -0,0 +1,49 @@
+package logger_test
+
+import (
+	"testing"
+
+	"gorm.io/gorm/logger"
+)
+
+func TestNormalizeSQL(t *testing.T) {
+	results := []struct {
+		SQL    string
+		Result string
+	}{
+		{
+			SQL:    "SELECT * FROM users WHERE name = 'jinzhu' AND age > 20",
+			Result: "SELECT * FROM users WHERE name = ? AND age > ?",
+		},
+		{
+			SQL:    "SELECT * FROM `users`  WHERE `name` = 'it''s'\n AND id IN (1, 2,3)",
+			Result: "SELECT * FROM `users` WHERE `name` = ? AND id IN (...)",
+		},
+		{
+			SQL:    `SELECT * FROM "users2" WHERE "id" IN ($1,$2) AND "age" = $3 LIMIT 10`,
+			Result: `SELECT * FROM "users2" WHERE "id" IN (...) AND "age" = ? LIMIT ?`,
+		},
+		{
+			SQL:    "INSERT INTO [users] ([name],[age]) VALUES (@p1,@p2),(@p3,@p4)",
+			Result: "INSERT INTO [users] ([name],[age]) VALUES (?,?)",
+		},
+		{
+			SQL:    "UPDATE t1 SET amount = 1.5e3 WHERE id = ?",
+			Result: "UPDATE t1 SET amount = ? WHERE id = ?",
+		},
+	}
+
+	for idx, r := range results {
+		if result := logger.NormalizeSQL(r.SQL); result != r.Result {
+			t.Errorf("result %d: expects %v, got %v", idx, r.Result, result)
+		}
+	}
+
+	if logger.Fingerprint("SELECT * FROM users WHERE id IN (1,2)") != logger.Fingerprint("SELECT * FROM users WHERE id IN (3, 4, 5)") {
+		t.Errorf("statements of the same shape should have the same fingerprint")
+	}
+
+	if logger.Fingerprint("SELECT * FROM users WHERE id = 1") == logger.Fingerprint("SELECT * FROM users WHERE name = 'x'") {
+		t.Errorf("statements of different shapes should have different fingerprints")
+	}
+}
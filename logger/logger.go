@@ -46,6 +46,40 @@ type Config struct {
 	SlowThreshold time.Duration
 	Colorful      bool
 	LogLevel      LogLevel
+	// TraceIDKey context key of trace or request id logged with messages, e.g: key set by tracing middleware,
+	// trace id set with WithTraceID is logged if it is nil
+	TraceIDKey interface{}
+}
+
+type traceIDKey struct{}
+
+// WithTraceID returns context carrying trace id, which is logged with messages
+func WithTraceID(ctx context.Context, traceID string) context.Context {
+	return context.WithValue(ctx, traceIDKey{}, traceID)
+}
+
+// TraceID returns trace id of context set with WithTraceID
+func TraceID(ctx context.Context) string {
+	if ctx != nil {
+		if traceID, ok := ctx.Value(traceIDKey{}).(string); ok {
+			return traceID
+		}
+	}
+	return ""
+}
+
+// traceID returns trace id of context with TraceIDKey
+func (c Config) traceID(ctx context.Context) string {
+	if c.TraceIDKey == nil {
+		return TraceID(ctx)
+	}
+
+	if ctx != nil {
+		if traceID := ctx.Value(c.TraceIDKey); traceID != nil {
+			return fmt.Sprint(traceID)
+		}
+	}
+	return ""
 }
 
 // Interface logger interface
@@ -105,6 +139,14 @@ type logger struct {
 	traceStr, traceErrStr, traceWarnStr string
 }
 
+// caller returns file with line number of caller, followed by trace id of context
+func (l logger) caller(ctx context.Context) string {
+	if traceID := l.traceID(ctx); traceID != "" {
+		return utils.FileWithLineNum() + " [trace_id:" + traceID + "]"
+	}
+	return utils.FileWithLineNum()
+}
+
 // LogMode log mode
 func (l *logger) LogMode(level LogLevel) Interface {
 	newlogger := *l
@@ -115,21 +157,21 @@ func (l *logger) LogMode(level LogLevel) Interface {
 // Info print info
 func (l logger) Info(ctx context.Context, msg string, data ...interface{}) {
 	if l.LogLevel >= Info {
-		l.Printf(l.infoStr+msg, append([]interface{}{utils.FileWithLineNum()}, data...)...)
+		l.Printf(l.infoStr+msg, append([]interface{}{l.caller(ctx)}, data...)...)
 	}
 }
 
 // Warn print warn messages
 func (l logger) Warn(ctx context.Context, msg string, data ...interface{}) {
 	if l.LogLevel >= Warn {
-		l.Printf(l.warnStr+msg, append([]interface{}{utils.FileWithLineNum()}, data...)...)
+		l.Printf(l.warnStr+msg, append([]interface{}{l.caller(ctx)}, data...)...)
 	}
 }
 
 // Error print error messages
 func (l logger) Error(ctx context.Context, msg string, data ...interface{}) {
 	if l.LogLevel >= Error {
-		l.Printf(l.errStr+msg, append([]interface{}{utils.FileWithLineNum()}, data...)...)
+		l.Printf(l.errStr+msg, append([]interface{}{l.caller(ctx)}, data...)...)
 	}
 }
 
@@ -141,24 +183,24 @@ func (l logger) Trace(ctx context.Context, begin time.Time, fc func() (string, i
 		case err != nil && l.LogLevel >= Error:
 			sql, rows := fc()
 			if rows == -1 {
-				l.Printf(l.traceErrStr, utils.FileWithLineNum(), err, float64(elapsed.Nanoseconds())/1e6, "-", sql)
+				l.Printf(l.traceErrStr, l.caller(ctx), err, float64(elapsed.Nanoseconds())/1e6, "-", sql)
 			} else {
-				l.Printf(l.traceErrStr, utils.FileWithLineNum(), err, float64(elapsed.Nanoseconds())/1e6, rows, sql)
+				l.Printf(l.traceErrStr, l.caller(ctx), err, float64(elapsed.Nanoseconds())/1e6, rows, sql)
 			}
 		case elapsed > l.SlowThreshold && l.SlowThreshold != 0 && l.LogLevel >= Warn:
 			sql, rows := fc()
 			slowLog := fmt.Sprintf("SLOW SQL >= %v", l.SlowThreshold)
 			if rows == -1 {
-				l.Printf(l.traceWarnStr, utils.FileWithLineNum(), slowLog, float64(elapsed.Nanoseconds())/1e6, "-", sql)
+				l.Printf(l.traceWarnStr, l.caller(ctx), slowLog, float64(elapsed.Nanoseconds())/1e6, "-", sql)
 			} else {
-				l.Printf(l.traceWarnStr, utils.FileWithLineNum(), slowLog, float64(elapsed.Nanoseconds())/1e6, rows, sql)
+				l.Printf(l.traceWarnStr, l.caller(ctx), slowLog, float64(elapsed.Nanoseconds())/1e6, rows, sql)
 			}
 		case l.LogLevel == Info:
 			sql, rows := fc()
 			if rows == -1 {
-				l.Printf(l.traceStr, utils.FileWithLineNum(), float64(elapsed.Nanoseconds())/1e6, "-", sql)
+				l.Printf(l.traceStr, l.caller(ctx), float64(elapsed.Nanoseconds())/1e6, "-", sql)
 			} else {
-				l.Printf(l.traceStr, utils.FileWithLineNum(), float64(elapsed.Nanoseconds())/1e6, rows, sql)
+				l.Printf(l.traceStr, l.caller(ctx), float64(elapsed.Nanoseconds())/1e6, rows, sql)
 			}
 		}
 	}
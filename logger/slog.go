@@ -0,0 +1,99 @@
+//go:build go1.21
+
+package logger
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm/utils"
+)
+
+// NewSlog returns logger writing structured records to slog logger, with fields sql, rows, elapsed_ms, error, caller, trace_id and fingerprint,
+// Colorful of config is ignored
+//    db, err := gorm.Open(sqlite.Open("gorm.db"), &gorm.Config{
+//      Logger: logger.NewSlog(slog.New(slog.NewJSONHandler(os.Stdout, nil)), logger.Config{SlowThreshold: time.Second, LogLevel: logger.Warn}),
+//    })
+func NewSlog(l *slog.Logger, config Config) Interface {
+	return &slogLogger{Logger: l, Config: config}
+}
+
+// UseSlog sets Default logger to slog logger l, logs slow SQL over 200ms and errors, it is used by databases opened afterwards
+func UseSlog(l *slog.Logger) {
+	Default = NewSlog(l, Config{SlowThreshold: 200 * time.Millisecond, LogLevel: Warn})
+}
+
+type slogLogger struct {
+	*slog.Logger
+	Config
+}
+
+// LogMode log mode
+func (l *slogLogger) LogMode(level LogLevel) Interface {
+	newlogger := *l
+	newlogger.LogLevel = level
+	return &newlogger
+}
+
+// Info logs info messages
+func (l slogLogger) Info(ctx context.Context, msg string, data ...interface{}) {
+	if l.LogLevel >= Info {
+		l.log(ctx, slog.LevelInfo, fmt.Sprintf(msg, data...))
+	}
+}
+
+// Warn logs warn messages
+func (l slogLogger) Warn(ctx context.Context, msg string, data ...interface{}) {
+	if l.LogLevel >= Warn {
+		l.log(ctx, slog.LevelWarn, fmt.Sprintf(msg, data...))
+	}
+}
+
+// Error logs error messages
+func (l slogLogger) Error(ctx context.Context, msg string, data ...interface{}) {
+	if l.LogLevel >= Error {
+		l.log(ctx, slog.LevelError, fmt.Sprintf(msg, data...))
+	}
+}
+
+// Trace logs sql, errors are logged at error level, slow sql at warn level, others at info level, rows is -1 if it is unknown
+func (l slogLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	if l.LogLevel <= Silent {
+		return
+	}
+
+	elapsed := time.Since(begin)
+	switch {
+	case err != nil && l.LogLevel >= Error:
+		sql, rows := fc()
+		l.log(ctx, slog.LevelError, "sql error", slog.String("sql", sql), slog.Int64("rows", rows),
+			slog.Float64("elapsed_ms", float64(elapsed.Nanoseconds())/1e6), slog.String("error", err.Error()))
+	case elapsed > l.SlowThreshold && l.SlowThreshold != 0 && l.LogLevel >= Warn:
+		sql, rows := fc()
+		l.log(ctx, slog.LevelWarn, fmt.Sprintf("SLOW SQL >= %v", l.SlowThreshold), slog.String("sql", sql), slog.Int64("rows", rows),
+			slog.Float64("elapsed_ms", float64(elapsed.Nanoseconds())/1e6))
+	case l.LogLevel == Info:
+		sql, rows := fc()
+		l.log(ctx, slog.LevelInfo, "sql", slog.String("sql", sql), slog.Int64("rows", rows),
+			slog.Float64("elapsed_ms", float64(elapsed.Nanoseconds())/1e6))
+	}
+}
+
+// log writes record with caller, trace id and fingerprint of context
+func (l slogLogger) log(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	attrs = append(attrs, slog.String("caller", utils.FileWithLineNum()))
+	if traceID := l.traceID(ctx); traceID != "" {
+		attrs = append(attrs, slog.String("trace_id", traceID))
+	}
+
+	if fingerprint := FingerprintFromContext(ctx); fingerprint != "" {
+		attrs = append(attrs, slog.String("fingerprint", fingerprint))
+	}
+	l.LogAttrs(ctx, level, msg, attrs...)
+}
@@ -0,0 +1,57 @@
+//go:build go1.21
+
+package logger_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm/logger"
+)
+
+func TestSlogLogger(t *testing.T) {
+	var (
+		buf bytes.Buffer
+		l   = logger.NewSlog(slog.New(slog.NewJSONHandler(&buf, nil)), logger.Config{SlowThreshold: time.Second, LogLevel: logger.Info})
+		ctx = logger.WithTraceID(context.Background(), "trace-1")
+	)
+
+	l.Trace(ctx, time.Now().Add(-10*time.Millisecond), func() (string, int64) {
+		return "SELECT * FROM users", 2
+	}, errors.New("no such table"))
+
+	var record map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("failed to parse record %v, got error %v", buf.String(), err)
+	}
+
+	if record["level"] != "ERROR" || record["sql"] != "SELECT * FROM users" || record["rows"] != float64(2) ||
+		record["error"] != "no such table" || record["trace_id"] != "trace-1" {
+		t.Errorf("invalid record, got %v", record)
+	}
+
+	if elapsed, ok := record["elapsed_ms"].(float64); !ok || elapsed < 10 {
+		t.Errorf("invalid elapsed_ms, got %v", record["elapsed_ms"])
+	}
+
+	if caller, ok := record["caller"].(string); !ok || !strings.Contains(caller, "slog_test.go") {
+		t.Errorf("invalid caller, got %v", record["caller"])
+	}
+
+	buf.Reset()
+	l.LogMode(logger.Warn).Trace(ctx, time.Now(), func() (string, int64) { return "SELECT 1", 1 }, nil)
+	if buf.Len() != 0 {
+		t.Errorf("fast sql should not be logged with warn level, got %v", buf.String())
+	}
+
+	l.Warn(context.Background(), "invalid %v", "field")
+	if !strings.Contains(buf.String(), `"msg":"invalid field"`) || strings.Contains(buf.String(), "trace_id") {
+		t.Errorf("invalid warn record, got %v", buf.String())
+	}
+}
@@ -0,0 +1,96 @@
+package gorm
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// MigrationHook hooks around DDL statements executed by Migrator, statements are explained with their vars,
+// the statement and the migration are stopped if BeforeMigrate returns error
+//    type AuditHook struct{}
+//
+//    func (AuditHook) BeforeMigrate(ctx context.Context, sql string) error {
+//      if strings.HasPrefix(sql, "DROP") {
+//        return errors.New("dropping is not allowed in production")
+//      }
+//      return nil
+//    }
+//
+//    func (AuditHook) AfterMigrate(ctx context.Context, sql string, elapsed time.Duration, err error) {
+//      log.Printf("migrated %v in %v, error %v", sql, elapsed, err)
+//    }
+//
+//    db, err := gorm.Open(sqlite.Open("gorm.db"), &gorm.Config{MigrationHook: AuditHook{}})
+type MigrationHook interface {
+	BeforeMigrate(ctx context.Context, sql string) error
+	AfterMigrate(ctx context.Context, sql string, elapsed time.Duration, err error)
+}
+
+// migrationHooker ConnPool firing MigrationHook
+type migrationHooker interface {
+	migrationHook() MigrationHook
+}
+
+// migrationHookConnPool fires MigrationHook around executed statements, queries are passed to ConnPool
+type migrationHookConnPool struct {
+	ConnPool
+	hook      MigrationHook
+	dialector Dialector
+}
+
+func (pool *migrationHookConnPool) migrationHook() MigrationHook {
+	return pool.hook
+}
+
+func (pool *migrationHookConnPool) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	sql := pool.dialector.Explain(query, args...)
+	if err := pool.hook.BeforeMigrate(ctx, sql); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	result, err := pool.ConnPool.ExecContext(ctx, query, args...)
+	pool.hook.AfterMigrate(ctx, sql, time.Since(start), err)
+	return result, err
+}
+
+func (pool *migrationHookConnPool) BeginTx(ctx context.Context, opts *sql.TxOptions) (ConnPool, error) {
+	var (
+		tx  ConnPool
+		err error
+	)
+
+	switch beginner := pool.ConnPool.(type) {
+	case TxBeginner:
+		tx, err = beginner.BeginTx(ctx, opts)
+	case ConnPoolBeginner:
+		tx, err = beginner.BeginTx(ctx, opts)
+	default:
+		err = ErrInvalidTransaction
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	return &migrationHookTx{migrationHookConnPool: &migrationHookConnPool{ConnPool: tx, hook: pool.hook, dialector: pool.dialector}}, nil
+}
+
+// migrationHookTx transaction of migrationHookConnPool
+type migrationHookTx struct {
+	*migrationHookConnPool
+}
+
+func (tx *migrationHookTx) Commit() error {
+	if committer, ok := tx.ConnPool.(TxCommitter); ok {
+		return committer.Commit()
+	}
+	return ErrInvalidTransaction
+}
+
+func (tx *migrationHookTx) Rollback() error {
+	if committer, ok := tx.ConnPool.(TxCommitter); ok {
+		return committer.Rollback()
+	}
+	return ErrInvalidTransaction
+}
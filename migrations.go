@@ -0,0 +1,259 @@
+package gorm
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultMigrationsTable default table of applied migrations history
+const DefaultMigrationsTable = "schema_migrations"
+
+// Migration versioned migration step, migrations are applied in order of ID, e.g: `202101021504_create_users`,
+// Up/Down functions are preferred to UpSQL/DownSQL, use tx.Migrator() for DDL helpers
+type Migration struct {
+	ID      string
+	Up      func(tx *DB) error
+	Down    func(tx *DB) error
+	UpSQL   string
+	DownSQL string
+	// DisableTransaction run the step without transaction, e.g: for statements can't be executed in transaction
+	DisableTransaction bool
+}
+
+// MigrationRecord applied migration saved in history table
+type MigrationRecord struct {
+	ID        string `gorm:"primaryKey;size:255"`
+	AppliedAt time.Time
+}
+
+// Migrations runs versioned migrations, applied migrations are saved in history table `TableName`
+//    migrations := db.Migrations(&gorm.Migration{
+//      ID: "202101021504_create_users",
+//      Up: func(tx *gorm.DB) error { return tx.Migrator().CreateTable(&User{}) },
+//      Down: func(tx *gorm.DB) error { return tx.Migrator().DropTable(&User{}) },
+//    }, &gorm.Migration{ID: "202101031000_add_users_age", UpSQL: "ALTER TABLE users ADD age integer"})
+//    err := migrations.Up()
+type Migrations struct {
+	DB         *DB
+	TableName  string
+	Error      error
+	migrations []*Migration
+}
+
+// Migrations returns versioned migrations runner with migrations
+func (db *DB) Migrations(migrations ...*Migration) *Migrations {
+	runner := &Migrations{DB: db, TableName: DefaultMigrationsTable}
+	for _, migration := range migrations {
+		runner.Register(migration)
+	}
+	return runner
+}
+
+// Register registers migration, returns ErrRegistered if ID is registered, ErrInvalidData if it has neither Up nor UpSQL
+func (migrations *Migrations) Register(migration *Migration) *Migrations {
+	for _, m := range migrations.migrations {
+		if m.ID == migration.ID {
+			migrations.Error = fmt.Errorf("%w: migration %v", ErrRegistered, migration.ID)
+			return migrations
+		}
+	}
+
+	if migration.Up == nil && strings.TrimSpace(migration.UpSQL) == "" {
+		migrations.Error = fmt.Errorf("%w: migration %v has neither Up nor UpSQL", ErrInvalidData, migration.ID)
+		return migrations
+	}
+
+	migrations.migrations = append(migrations.migrations, migration)
+	sort.SliceStable(migrations.migrations, func(i, j int) bool {
+		return migrations.migrations[i].ID < migrations.migrations[j].ID
+	})
+	return migrations
+}
+
+// Registered returns registered migrations in order of ID
+func (migrations *Migrations) Registered() []*Migration {
+	return append([]*Migration(nil), migrations.migrations...)
+}
+
+// Applied returns applied migrations in order of ID
+func (migrations *Migrations) Applied() (records []MigrationRecord, err error) {
+	if err = migrations.prepare(); err == nil {
+		err = migrations.history().Order("id").Find(&records).Error
+	}
+	return
+}
+
+// Pending returns migrations not applied yet
+func (migrations *Migrations) Pending() (pending []*Migration, err error) {
+	applied, err := migrations.appliedIDs()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, migration := range migrations.migrations {
+		if !applied[migration.ID] {
+			pending = append(pending, migration)
+		}
+	}
+	return
+}
+
+// Up applies all pending migrations
+func (migrations *Migrations) Up() error {
+	return migrations.withLock(migrations.up)
+}
+
+func (migrations *Migrations) up() error {
+	pending, err := migrations.Pending()
+	if err != nil {
+		return err
+	}
+
+	for _, migration := range pending {
+		if err := migrations.run(migration, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Down rolls back the last applied migration
+func (migrations *Migrations) Down() error {
+	return migrations.withLock(migrations.down)
+}
+
+func (migrations *Migrations) down() error {
+	applied, err := migrations.Applied()
+	if err != nil || len(applied) == 0 {
+		return err
+	}
+
+	migration := migrations.lookUp(applied[len(applied)-1].ID)
+	if migration == nil {
+		return fmt.Errorf("%w: %v", ErrMigrationNotFound, applied[len(applied)-1].ID)
+	}
+	return migrations.run(migration, false)
+}
+
+// To migrates to version id, applies pending migrations until id (included), and rolls back applied migrations after id,
+// rolls back all migrations if id is empty
+func (migrations *Migrations) To(id string) error {
+	if id != "" && migrations.lookUp(id) == nil {
+		return fmt.Errorf("%w: %v", ErrMigrationNotFound, id)
+	}
+
+	return migrations.withLock(func() error {
+		return migrations.to(id)
+	})
+}
+
+func (migrations *Migrations) to(id string) error {
+	applied, err := migrations.Applied()
+	if err != nil {
+		return err
+	}
+
+	for idx := len(applied) - 1; idx >= 0 && applied[idx].ID > id; idx-- {
+		migration := migrations.lookUp(applied[idx].ID)
+		if migration == nil {
+			return fmt.Errorf("%w: %v", ErrMigrationNotFound, applied[idx].ID)
+		}
+
+		if err := migrations.run(migration, false); err != nil {
+			return err
+		}
+	}
+
+	pending, err := migrations.Pending()
+	if err != nil {
+		return err
+	}
+
+	for _, migration := range pending {
+		if migration.ID <= id {
+			if err := migrations.run(migration, true); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (migrations *Migrations) run(migration *Migration, up bool) error {
+	fc := func(tx *DB) (err error) {
+		switch {
+		case up && migration.Up != nil:
+			err = migration.Up(tx)
+		case up:
+			err = tx.Exec(migration.UpSQL).Error
+		case migration.Down != nil:
+			err = migration.Down(tx)
+		case strings.TrimSpace(migration.DownSQL) != "":
+			err = tx.Exec(migration.DownSQL).Error
+		default:
+			err = fmt.Errorf("%w: %v", ErrIrreversibleMigration, migration.ID)
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to migrate %v: %w", migration.ID, err)
+		}
+
+		history := tx.Session(&Session{NewDB: true}).Table(migrations.TableName)
+		if up {
+			return history.Create(&MigrationRecord{ID: migration.ID, AppliedAt: tx.NowFunc()}).Error
+		}
+		return history.Where("id = ?", migration.ID).Delete(&MigrationRecord{}).Error
+	}
+
+	if migration.DisableTransaction {
+		return fc(migrations.DB.Session(&Session{NewDB: true}))
+	}
+	return migrations.DB.Session(&Session{NewDB: true}).Transaction(fc)
+}
+
+// withLock runs fc with application lock of history table, so migrations won't be applied by app instances at the same time
+func (migrations *Migrations) withLock(fc func() error) error {
+	if migrations.Error != nil {
+		return migrations.Error
+	}
+
+	return migrations.DB.WithLock("gorm:migrations:"+migrations.TableName, func(*DB) error {
+		return fc()
+	})
+}
+
+// prepare creates history table if not exists
+func (migrations *Migrations) prepare() error {
+	if migrations.Error != nil {
+		return migrations.Error
+	}
+
+	if tx := migrations.history(); !tx.Migrator().HasTable(migrations.TableName) {
+		return tx.Migrator().CreateTable(&MigrationRecord{})
+	}
+	return nil
+}
+
+func (migrations *Migrations) history() *DB {
+	return migrations.DB.Session(&Session{NewDB: true}).Table(migrations.TableName)
+}
+
+func (migrations *Migrations) appliedIDs() (map[string]bool, error) {
+	applied, err := migrations.Applied()
+	ids := make(map[string]bool, len(applied))
+	for _, record := range applied {
+		ids[record.ID] = true
+	}
+	return ids, err
+}
+
+func (migrations *Migrations) lookUp(id string) *Migration {
+	for _, migration := range migrations.migrations {
+		if migration.ID == id {
+			return migration
+		}
+	}
+	return nil
+}
@@ -1,25 +1,116 @@
 package gorm
 
 import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
 	"gorm.io/gorm/clause"
 	"gorm.io/gorm/schema"
 )
 
 // Migrator returns migrator
 func (db *DB) Migrator() Migrator {
+	if db.MigrationHook != nil {
+		if _, ok := db.Statement.ConnPool.(migrationHooker); !ok {
+			ctx := db.Statement.Context
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			// session with context clones statement, so ConnPool of db is not changed
+			tx := db.Session(&Session{Context: ctx})
+			tx.Statement.ConnPool = &migrationHookConnPool{ConnPool: tx.Statement.ConnPool, hook: db.MigrationHook, dialector: db.Dialector}
+			return db.Dialector.Migrator(tx)
+		}
+	}
 	return db.Dialector.Migrator(db.Session(&Session{}))
 }
 
-// AutoMigrate run auto migration for given models
+// AutoMigrate run auto migration for given models, it runs with application lock if MigrateWithLock is enabled
 func (db *DB) AutoMigrate(dst ...interface{}) error {
-	return db.Migrator().AutoMigrate(dst...)
+	if !db.MigrateWithLock {
+		return db.Migrator().AutoMigrate(dst...)
+	}
+
+	// guard with application lock, app instances starting at the same time won't race on DDL
+	return db.WithLock("gorm:automigrate", func(tx *DB) error {
+		return tx.Migrator().AutoMigrate(dst...)
+	})
 }
 
 // ViewOption view option
 type ViewOption struct {
-	Replace     bool
-	CheckOption string
-	Query       *DB
+	Replace      bool
+	CheckOption  string
+	Query        *DB
+	Materialized bool // materialized view, only supported by postgres
+}
+
+// Trigger trigger executed for each row of table, Body is used if there is no body for current dialect in Bodies,
+// it runs in `BEGIN ... END` block, postgres triggers are created with function of the same name returning NEW or OLD,
+// sqlserver triggers are executed for statements, use `inserted`, `deleted` tables instead of NEW, OLD
+//    db.Migrator().(gorm.TriggerMigratorInterface).CreateTrigger(&User{}, gorm.Trigger{Name: "trg_users_updated_at", Timing: "BEFORE", Event: "UPDATE",
+//      Body: "NEW.updated_at = CURRENT_TIMESTAMP;"})
+type Trigger struct {
+	Name   string
+	Timing string // BEFORE, AFTER, INSTEAD OF
+	Event  string // INSERT, UPDATE, DELETE
+	Body   string
+	Bodies map[string]string // bodies by dialect name or Capabilities.Trigger, e.g: `sqlite`, `postgres`
+}
+
+// TriggersInterface model declares triggers of its table, missing triggers are created by AutoMigrate
+type TriggersInterface interface {
+	Triggers() []Trigger
+}
+
+// DumpOptions options of dumping models from database
+type DumpOptions struct {
+	Package string   // package name of generated source, default `models`
+	Tables  []string // tables to dump, all tables if empty
+}
+
+// MigrationPlan DDL statements AutoMigrate would execute in order, returned by MigrationPlannerInterface.Plan
+type MigrationPlan struct {
+	Statements []string
+}
+
+var (
+	planCreateRegexp = regexp.MustCompile(`(?i)^\s*(CREATE\s|ALTER\s+TABLE\s+\S+\s+ADD\s)`)
+	planDropRegexp   = regexp.MustCompile(`(?i)^\s*(DROP\s|ALTER\s+TABLE\s+\S+\s+DROP\s)`)
+)
+
+// Diff returns human-readable diff of plan, statements creating objects are prefixed with `+`,
+// dropping with `-` and others with `~`
+func (plan MigrationPlan) Diff() string {
+	var builder strings.Builder
+	for _, sql := range plan.Statements {
+		switch {
+		case planCreateRegexp.MatchString(sql):
+			builder.WriteString("+ ")
+		case planDropRegexp.MatchString(sql):
+			builder.WriteString("- ")
+		default:
+			builder.WriteString("~ ")
+		}
+		builder.WriteString(sql)
+		builder.WriteByte('\n')
+	}
+	return builder.String()
+}
+
+// WriteTo writes statements of plan to w as SQL script
+func (plan MigrationPlan) WriteTo(w io.Writer) (n int64, err error) {
+	for _, sql := range plan.Statements {
+		written, err := fmt.Fprintf(w, "%s;\n", sql)
+		if n += int64(written); err != nil {
+			return n, err
+		}
+	}
+	return n, nil
 }
 
 type ColumnType interface {
@@ -30,6 +121,31 @@ type ColumnType interface {
 	Nullable() (nullable bool, ok bool)
 }
 
+// Constraint constraint of table in database, returned by ConstraintInspectorInterface.GetConstraints
+type Constraint struct {
+	Name    string
+	Type    string // PRIMARY KEY, UNIQUE, FOREIGN KEY or CHECK
+	Columns []string
+}
+
+// ForeignKey foreign key constraint of table in database, returned by ConstraintInspectorInterface.GetForeignKeys
+type ForeignKey struct {
+	Name              string
+	Columns           []string
+	ReferencedTable   string
+	ReferencedColumns []string
+	OnDelete          string
+	OnUpdate          string
+}
+
+// TableType table type interface
+type TableType interface {
+	Schema() string
+	Name() string
+	Type() string
+	Comment() (comment string, ok bool)
+}
+
 type Migrator interface {
 	// AutoMigrate
 	AutoMigrate(dst ...interface{}) error
@@ -68,3 +184,46 @@ type Migrator interface {
 	HasIndex(dst interface{}, name string) bool
 	RenameIndex(dst interface{}, oldName, newName string) error
 }
+
+// MigrationPlannerInterface migrator plans AutoMigrate without executing it, implemented by migrator.Migrator
+//    plan, err := db.Migrator().(gorm.MigrationPlannerInterface).Plan(&User{})
+type MigrationPlannerInterface interface {
+	Plan(dst ...interface{}) (MigrationPlan, error)
+	ScriptTo(w io.Writer, dst ...interface{}) error
+}
+
+// TableInspectorInterface migrator lists tables and their types, implemented by migrator.Migrator
+type TableInspectorInterface interface {
+	GetTables() (tableList []string, err error)
+	TableType(dst interface{}) (TableType, error)
+}
+
+// ModelDumperInterface migrator generates models from existing tables, implemented by migrator.Migrator
+type ModelDumperInterface interface {
+	DumpModels(w io.Writer, options DumpOptions) error
+}
+
+// ViewRefresherInterface migrator refreshes materialized views, implemented by migrator.Migrator
+type ViewRefresherInterface interface {
+	RefreshView(name string, concurrently bool) error
+}
+
+// TriggerMigratorInterface migrator manages triggers, implemented by migrator.Migrator
+type TriggerMigratorInterface interface {
+	CreateTrigger(dst interface{}, trigger Trigger) error
+	DropTrigger(dst interface{}, name string) error
+	HasTrigger(dst interface{}, name string) bool
+}
+
+// ConstraintInspectorInterface migrator lists constraints of tables in database, implemented by migrator.Migrator
+type ConstraintInspectorInterface interface {
+	GetConstraints(dst interface{}) ([]Constraint, error)
+	GetForeignKeys(dst interface{}) ([]ForeignKey, error)
+}
+
+// PartitionMigratorInterface migrator manages partitions of partitioned tables, implemented by migrator.Migrator
+type PartitionMigratorInterface interface {
+	CreatePartition(dst interface{}, partition Partition) error
+	DropPartition(dst interface{}, name string) error
+	HasPartition(dst interface{}, name string) bool
+}
@@ -0,0 +1,130 @@
+package migrator
+
+import (
+	"regexp"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// CollationOf returns charset and collation clause of field for current dialect, charset is only supported by mysql
+func (m Migrator) CollationOf(field *schema.Field) (sql string) {
+	switch m.DB.Capabilities().Collation {
+	case gorm.SyntaxMySQL:
+		if field.Charset != "" {
+			sql += " CHARACTER SET " + field.Charset
+		}
+
+		if field.Collation != "" {
+			sql += " COLLATE " + field.Collation
+		}
+	case gorm.SyntaxPostgres:
+		if field.Collation != "" {
+			sql += ` COLLATE "` + strings.ReplaceAll(field.Collation, `"`, `""`) + `"`
+		}
+	default:
+		if field.Collation != "" {
+			sql += " COLLATE " + field.Collation
+		}
+	}
+	return
+}
+
+// columnCollation charset and collation of column in database
+type columnCollation struct {
+	charset, collation string
+}
+
+// columnCollationsKey context key of collations of columns loaded once for table by AutoMigrate
+type columnCollationsKey struct{}
+
+// columnCollations collations of columns of table, ok is false if they are not supported by current dialect
+type columnCollations struct {
+	loaded     bool
+	ok         bool
+	collations map[string]columnCollation
+}
+
+// ColumnCollation returns charset and collation of column in database, collation is empty if it is the default one,
+// ok is false if it is not supported by current dialect
+func (m Migrator) ColumnCollation(value interface{}, column string) (charset string, collation string, ok bool) {
+	cache := &columnCollations{}
+	if ctx := m.DB.Statement.Context; ctx != nil {
+		if c, ok := ctx.Value(columnCollationsKey{}).(*columnCollations); ok {
+			cache = c
+		}
+	}
+
+	if !cache.loaded {
+		cache.collations, cache.ok = m.columnCollations(value)
+		cache.loaded = true
+	}
+
+	c, found := cache.collations[column]
+	return c.charset, c.collation, cache.ok && found
+}
+
+// columnCollations loads charsets and collations of all columns of table with one query
+func (m Migrator) columnCollations(value interface{}) (collations map[string]columnCollation, ok bool) {
+	m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		var rows []map[string]interface{}
+		switch m.DB.Capabilities().Collation {
+		case gorm.SyntaxMySQL:
+			if err := m.DB.Raw(
+				"SELECT COLUMN_NAME AS column_name, CHARACTER_SET_NAME AS charset_name, COLLATION_NAME AS collation_name FROM information_schema.COLUMNS WHERE table_schema = ? AND table_name = ?",
+				m.DB.Migrator().CurrentDatabase(), stmt.Table,
+			).Find(&rows).Error; err != nil {
+				return err
+			}
+		case gorm.SyntaxPostgres:
+			if err := m.DB.Raw(
+				"SELECT column_name AS column_name, collation_name AS collation_name FROM information_schema.columns WHERE table_schema = CURRENT_SCHEMA() AND table_name = ?",
+				stmt.Table,
+			).Find(&rows).Error; err != nil {
+				return err
+			}
+		case gorm.SyntaxSQLite:
+			var createSQL string
+			if err := m.DB.Raw("SELECT sql FROM sqlite_master WHERE type = ? AND name = ?", "table", stmt.Table).Row().Scan(&createSQL); err != nil {
+				return err
+			}
+
+			for _, column := range stmt.Schema.DBNames {
+				definition := regexp.MustCompile("[`\"'(,\\s]" + regexp.QuoteMeta(column) + "[`\"']?\\s+([^,]*)").FindStringSubmatch(createSQL)
+				if len(definition) == 2 {
+					row := map[string]interface{}{"column_name": column}
+					if matches := regexp.MustCompile("(?i)\\sCOLLATE\\s+[`\"']?(\\w+)").FindStringSubmatch(definition[1]); len(matches) == 2 {
+						row["collation_name"] = matches[1]
+					}
+					rows = append(rows, row)
+				}
+			}
+		default:
+			return nil
+		}
+
+		collations, ok = map[string]columnCollation{}, true
+		for _, row := range rows {
+			collations[dumpString(row["column_name"])] = columnCollation{
+				charset: dumpString(row["charset_name"]), collation: dumpString(row["collation_name"]),
+			}
+		}
+		return nil
+	})
+	return
+}
+
+// collationChanged returns true if charset or collation of field is different from the column
+func (m Migrator) collationChanged(value interface{}, field *schema.Field) bool {
+	if field.Collation == "" && field.Charset == "" {
+		return false
+	}
+
+	charset, collation, ok := m.ColumnCollation(value, field.DBName)
+	if !ok {
+		return false
+	}
+	return (field.Collation != "" && !strings.EqualFold(collation, field.Collation)) ||
+		(field.Charset != "" && m.DB.Capabilities().Collation == gorm.SyntaxMySQL && !strings.EqualFold(charset, field.Charset))
+}
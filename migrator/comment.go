@@ -0,0 +1,128 @@
+package migrator
+
+import (
+	"database/sql"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// commentTable sets comment of table declared by model with schema.TableCommenter, supported by mysql and postgres
+func (m Migrator) commentTable(tx *gorm.DB, stmt *gorm.Statement) error {
+	switch m.DB.Capabilities().Comment {
+	case gorm.SyntaxMySQL:
+		return tx.Exec("ALTER TABLE ? COMMENT = ?", m.CurrentTable(stmt), m.commentValue(stmt.Schema.Comment)).Error
+	case gorm.SyntaxPostgres:
+		comment := m.commentValue(stmt.Schema.Comment)
+		if stmt.Schema.Comment == "" {
+			comment = clause.Expr{SQL: "NULL"}
+		}
+		return tx.Exec("COMMENT ON TABLE ? IS ?", m.CurrentTable(stmt), comment).Error
+	}
+	return nil
+}
+
+// commentValue returns quoted comment, DDL statements don't support bind variables
+func (m Migrator) commentValue(comment string) clause.Expr {
+	if m.DB.Capabilities().Comment == gorm.SyntaxPostgres {
+		return clause.Expr{SQL: m.Dialector.Explain("$1", comment)}
+	}
+	return clause.Expr{SQL: m.Dialector.Explain("?", comment)}
+}
+
+// migrateTableComment synchronizes comment of existing table with the model, comments of tables are kept if models
+// don't implement schema.TableCommenter
+func (m Migrator) migrateTableComment(tx *gorm.DB, value interface{}) error {
+	if !m.DB.Capabilities().TableComment {
+		return nil
+	}
+
+	return m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		if _, ok := reflect.New(stmt.Schema.ModelType).Interface().(schema.TableCommenter); !ok {
+			return nil
+		}
+
+		tableType, err := m.TableType(value)
+		if err != nil {
+			return nil
+		}
+
+		if comment, _ := tableType.Comment(); comment != stmt.Schema.Comment {
+			return m.commentTable(tx, stmt)
+		}
+		return nil
+	})
+}
+
+// commentIndex sets comment of index after it is created, comments of mysql indexes are created with them
+func (m Migrator) commentIndex(tx *gorm.DB, stmt *gorm.Statement, idx schema.Index) error {
+	if idx.Comment == "" || m.DB.Capabilities().Comment != gorm.SyntaxPostgres {
+		return nil
+	}
+
+	name := idx.Name
+	if stmt.Namespace != "" {
+		name = stmt.Namespace + "." + name
+	}
+	return tx.Exec("COMMENT ON INDEX ? IS ?", clause.Table{Name: name}, m.commentValue(idx.Comment)).Error
+}
+
+// columnCommentsKey context key of comments of columns loaded once for table by AutoMigrate
+type columnCommentsKey struct{}
+
+// columnComments comments of columns of table
+type columnComments struct {
+	loaded   bool
+	comments map[string]string
+}
+
+// columnCommentChanged returns true if comment of column is different from field, postgres comments are synchronized
+// by its driver, only mysql is checked
+func (m Migrator) columnCommentChanged(value interface{}, field *schema.Field) bool {
+	if m.DB.Capabilities().Comment != gorm.SyntaxMySQL {
+		return false
+	}
+
+	cache := &columnComments{}
+	if ctx := m.DB.Statement.Context; ctx != nil {
+		if c, ok := ctx.Value(columnCommentsKey{}).(*columnComments); ok {
+			cache = c
+		}
+	}
+
+	if !cache.loaded {
+		cache.comments = m.columnComments(value)
+		cache.loaded = true
+	}
+
+	comment, ok := cache.comments[field.DBName]
+	return ok && comment != field.Comment
+}
+
+// columnComments loads comments of all columns of table with one query
+func (m Migrator) columnComments(value interface{}) (comments map[string]string) {
+	m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		rows, err := m.DB.Raw(
+			"SELECT column_name, column_comment FROM information_schema.columns WHERE table_schema = ? AND table_name = ?",
+			m.namespace(stmt), stmt.Table,
+		).Rows()
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		comments = map[string]string{}
+		for rows.Next() {
+			var name string
+			var comment sql.NullString
+			if err := rows.Scan(&name, &comment); err != nil {
+				return err
+			}
+			comments[name] = comment.String
+		}
+		return rows.Err()
+	})
+	return
+}
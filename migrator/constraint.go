@@ -0,0 +1,140 @@
+package migrator
+
+import (
+	"regexp"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var sqliteCheckRegexp = regexp.MustCompile("(?i)CONSTRAINT\\s+[`\"\\[]?(\\w+)[`\"\\]]?\\s+CHECK\\s*\\(")
+
+const constraintsSQL = `SELECT tc.constraint_name AS constraint_name, tc.constraint_type AS constraint_type, kcu.column_name AS column_name
+FROM information_schema.table_constraints tc LEFT JOIN information_schema.key_column_usage kcu
+ON tc.constraint_schema = kcu.constraint_schema AND tc.table_name = kcu.table_name AND tc.constraint_name = kcu.constraint_name
+WHERE tc.table_schema = ? AND tc.table_name = ? ORDER BY tc.constraint_name, kcu.ordinal_position`
+
+// GetConstraints returns primary key, unique, foreign key and check constraints of table in database,
+// primary keys of sqlite are not named
+func (m Migrator) GetConstraints(value interface{}) (constraints []gorm.Constraint, err error) {
+	err = m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		if m.Dialector.Name() == "sqlite" {
+			return m.sqliteConstraints(stmt, &constraints)
+		}
+
+		var namespace interface{} = m.namespace(stmt)
+		if m.Dialector.Name() == "postgres" {
+			namespace = m.postgresNamespace(stmt)
+		}
+
+		var rows []map[string]interface{}
+		if err := m.DB.Session(&gorm.Session{NewDB: true}).Raw(constraintsSQL, namespace, stmt.Table).Find(&rows).Error; err != nil {
+			return err
+		}
+
+		indexByName := map[string]int{}
+		for _, row := range rows {
+			name, typ := dumpString(row["constraint_name"]), strings.ToUpper(dumpString(row["constraint_type"]))
+			// NOT NULL columns are check constraints of postgres
+			if name == "" || (typ == "CHECK" && strings.HasSuffix(name, "_not_null")) {
+				continue
+			}
+
+			idx, ok := indexByName[name]
+			if !ok {
+				idx = len(constraints)
+				indexByName[name] = idx
+				constraints = append(constraints, gorm.Constraint{Name: name, Type: typ})
+			}
+
+			if column := dumpString(row["column_name"]); column != "" {
+				constraints[idx].Columns = append(constraints[idx].Columns, column)
+			}
+		}
+		return nil
+	})
+	return
+}
+
+// sqliteConstraints looks up constraints of sqlite with PRAGMA statements and DDL of table
+func (m Migrator) sqliteConstraints(stmt *gorm.Statement, constraints *[]gorm.Constraint) error {
+	var (
+		columns, indexes []map[string]interface{}
+		db               = m.DB.Session(&gorm.Session{NewDB: true})
+	)
+
+	if err := db.Raw(m.sqlitePragma(stmt, "table_info")+"(?)", clause.Table{Name: stmt.Table}).Find(&columns).Error; err != nil {
+		return err
+	}
+
+	primaryKey := gorm.Constraint{Type: "PRIMARY KEY"}
+	for seq := int64(1); seq <= int64(len(columns)); seq++ {
+		for _, column := range columns {
+			if dumpInt(column["pk"]) == seq {
+				primaryKey.Columns = append(primaryKey.Columns, dumpString(column["name"]))
+			}
+		}
+	}
+
+	if len(primaryKey.Columns) > 0 {
+		*constraints = append(*constraints, primaryKey)
+	}
+
+	if err := db.Raw(m.sqlitePragma(stmt, "index_list")+"(?)", clause.Table{Name: stmt.Table}).Find(&indexes).Error; err != nil {
+		return err
+	}
+
+	for _, index := range indexes {
+		if dumpString(index["origin"]) != "u" {
+			continue
+		}
+
+		var infos []map[string]interface{}
+		if err := db.Raw(m.sqlitePragma(stmt, "index_info")+"(?)", clause.Table{Name: dumpString(index["name"])}).Find(&infos).Error; err != nil {
+			return err
+		}
+
+		unique := gorm.Constraint{Name: dumpString(index["name"]), Type: "UNIQUE"}
+		for _, info := range infos {
+			unique.Columns = append(unique.Columns, dumpString(info["name"]))
+		}
+		*constraints = append(*constraints, unique)
+	}
+
+	table, err := m.dumpTable(stmt.Namespace, stmt.Table)
+	if err != nil {
+		return err
+	}
+
+	for _, fk := range table.ForeignKeys {
+		*constraints = append(*constraints, gorm.Constraint{Name: fk.Name, Type: "FOREIGN KEY", Columns: fk.Columns})
+	}
+
+	var createSQL string
+	db.Raw("SELECT sql FROM "+m.sqliteMaster(stmt)+" WHERE type = ? AND name = ?", "table", stmt.Table).Row().Scan(&createSQL)
+	for _, matches := range sqliteCheckRegexp.FindAllStringSubmatch(createSQL, -1) {
+		*constraints = append(*constraints, gorm.Constraint{Name: matches[1], Type: "CHECK"})
+	}
+	return nil
+}
+
+// GetForeignKeys returns foreign keys of table in its namespace with their referenced tables, columns and actions,
+// foreign keys of sqlite without names in DDL are named `fk_<table>_<id>`
+func (m Migrator) GetForeignKeys(value interface{}) (foreignKeys []gorm.ForeignKey, err error) {
+	err = m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		table, err := m.dumpTable(stmt.Namespace, stmt.Table)
+		if err != nil {
+			return err
+		}
+
+		for _, fk := range table.ForeignKeys {
+			foreignKeys = append(foreignKeys, gorm.ForeignKey{
+				Name: fk.Name, Columns: fk.Columns, ReferencedTable: fk.RefTable, ReferencedColumns: fk.RefColumns,
+				OnDelete: fk.OnDelete, OnUpdate: fk.OnUpdate,
+			})
+		}
+		return nil
+	})
+	return
+}
@@ -0,0 +1,27 @@
+package migrator
+
+import (
+	"regexp"
+	"strings"
+)
+
+var columnTypeSizeRegexp = regexp.MustCompile(`\s*\([^)]*\)`)
+
+// columnTypeAliases aliases of column types, which are returned as database type names by postgres
+var columnTypeAliases = map[string]string{
+	"integer": "int4", "int": "int4", "serial": "int4", "bigint": "int8", "bigserial": "int8", "smallint": "int2", "smallserial": "int2",
+	"boolean": "bool", "real": "float4", "double precision": "float8", "decimal": "numeric",
+	"character varying": "varchar", "character": "bpchar", "char": "bpchar",
+	"timestamp with time zone": "timestamptz", "timestamp without time zone": "timestamp",
+	"time with time zone": "timetz", "time without time zone": "time",
+}
+
+// columnTypeName returns normalized name of column type without size and precision, e.g: `varchar` of `varchar(100)`
+func columnTypeName(dataType string) string {
+	name := strings.ToLower(strings.TrimSpace(columnTypeSizeRegexp.ReplaceAllString(dataType, "")))
+
+	if alias, ok := columnTypeAliases[name]; ok {
+		return alias
+	}
+	return name
+}
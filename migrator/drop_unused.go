@@ -0,0 +1,80 @@
+package migrator
+
+import (
+	"gorm.io/gorm"
+)
+
+// dropUnused drops foreign keys, indexes and columns of table owned by model but absent from it, used by AutoMigrate
+// when DropUnusedWhenMigrating is enabled, check Migrator.Plan for statements it would execute
+func (m Migrator) dropUnused(tx *gorm.DB, value interface{}) error {
+	return m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		table, err := m.dumpTable(stmt.Namespace, stmt.Table)
+		if err != nil {
+			return err
+		}
+
+		constraints, constrainedColumns := map[string]bool{}, map[string]bool{}
+		for _, rel := range stmt.Schema.Relationships.Relations {
+			if constraint := rel.ParseConstraint(); constraint != nil && constraint.Schema == stmt.Schema {
+				constraints[constraint.Name] = true
+				for _, field := range constraint.ForeignKeys {
+					constrainedColumns[field.DBName] = true
+				}
+			}
+		}
+
+		// foreign keys of columns of model are owned by the model only if it declares constraints of them, otherwise
+		// they might be declared by other models, e.g: has-many relationships of parents, they are dropped with columns
+		owned := func(fk *dumpForeignKey) bool {
+			for _, column := range fk.Columns {
+				if _, ok := stmt.Schema.FieldsByDBName[column]; !ok || constrainedColumns[column] {
+					return true
+				}
+			}
+			return false
+		}
+
+		for _, fk := range table.ForeignKeys {
+			if constraints[fk.Name] || !owned(fk) {
+				// indexes of kept foreign keys are kept, e.g: mysql
+				constraints[fk.Name] = true
+			} else if m.Dialector.Name() != "sqlite" {
+				// sqlite doesn't support dropping constraints, they are dropped with columns
+				if err := tx.Migrator().DropConstraint(value, fk.Name); err != nil {
+					return err
+				}
+			}
+		}
+
+		indexes := map[string]bool{}
+		for _, idx := range stmt.Schema.ParseIndexes() {
+			indexes[idx.Name] = true
+		}
+
+		for _, index := range table.Indexes {
+			if indexes[index.Name] || constraints[index.Name] {
+				continue
+			}
+
+			// indexes of unique columns are created by database
+			if len(index.Columns) == 1 && index.Unique {
+				if field := stmt.Schema.LookUpField(index.Columns[0]); field != nil && (field.Unique || field.PrimaryKey) {
+					continue
+				}
+			}
+
+			if err := m.dropIndex(tx, value, index.Name); err != nil {
+				return err
+			}
+		}
+
+		for _, column := range table.Columns {
+			if _, ok := stmt.Schema.FieldsByDBName[column.Name]; !ok {
+				if err := tx.Migrator().DropColumn(value, column.Name); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,527 @@
+package migrator
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jinzhu/inflection"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+var sqliteForeignKeyRegexp = regexp.MustCompile("(?i)CONSTRAINT\\s+[`\"\\[]?(\\w+)[`\"\\]]?\\s+FOREIGN KEY\\s*\\(([^)]*)\\)")
+
+type dumpColumn struct {
+	Name          string
+	Type          string
+	Size          int
+	Nullable      bool
+	PrimaryKey    bool
+	AutoIncrement bool
+	Unique        bool
+	Default       *string
+}
+
+type dumpIndex struct {
+	Name    string
+	Unique  bool
+	Columns []string
+}
+
+type dumpForeignKey struct {
+	Name       string
+	Columns    []string
+	RefTable   string
+	RefColumns []string
+	OnDelete   string
+	OnUpdate   string
+}
+
+type dumpTable struct {
+	Name        string
+	Columns     []*dumpColumn
+	Indexes     []*dumpIndex
+	ForeignKeys []*dumpForeignKey
+}
+
+// GetTables returns tables of current database, or of schema Namespace if it is specified
+func (m Migrator) GetTables() (tableList []string, err error) {
+	stmt := &gorm.Statement{DB: m.DB, Namespace: m.DB.Namespace}
+	switch m.Dialector.Name() {
+	case "sqlite":
+		err = m.DB.Raw("SELECT name FROM "+m.sqliteMaster(stmt)+" WHERE type = ? AND name NOT LIKE ?", "table", "sqlite_%").Scan(&tableList).Error
+	case "postgres":
+		err = m.DB.Raw("SELECT table_name FROM information_schema.tables WHERE table_schema = ? AND table_type = ?", m.postgresNamespace(stmt), "BASE TABLE").Scan(&tableList).Error
+	default:
+		err = m.DB.Raw("SELECT table_name FROM information_schema.tables WHERE table_schema = ? AND table_type = ?", m.namespace(stmt), "BASE TABLE").Scan(&tableList).Error
+	}
+
+	sort.Strings(tableList)
+	return
+}
+
+// DumpModels introspects tables, columns, indexes and foreign keys of database and writes go models with gorm tags to w,
+// struct and field names are guessed with NamingStrategy, explicit `column` tags and TableName methods are generated if they don't match
+//    db.Migrator().(gorm.ModelDumperInterface).DumpModels(os.Stdout, gorm.DumpOptions{Package: "models", Tables: []string{"users", "companies"}})
+func (m Migrator) DumpModels(w io.Writer, options gorm.DumpOptions) error {
+	tableNames := options.Tables
+	if len(tableNames) == 0 {
+		var err error
+		if tableNames, err = m.GetTables(); err != nil {
+			return err
+		}
+	}
+
+	tables := make([]*dumpTable, 0, len(tableNames))
+	structNames := map[string]string{}
+	for _, name := range tableNames {
+		table, err := m.dumpTable("", name)
+		if err != nil {
+			return fmt.Errorf("failed to dump table %v: %w", name, err)
+		}
+		tables = append(tables, table)
+		structNames[name] = m.dumpStructName(name)
+	}
+
+	var (
+		body    bytes.Buffer
+		imports = map[string]bool{}
+		pkgName = options.Package
+	)
+
+	if pkgName == "" {
+		pkgName = "models"
+	}
+
+	for _, table := range tables {
+		m.writeDumpModel(&body, table, structNames, imports)
+	}
+
+	var source bytes.Buffer
+	source.WriteString("// Code generated by gorm DumpModels. DO NOT EDIT.\n\npackage " + pkgName + "\n\n")
+	if len(imports) > 0 {
+		var stdImports, otherImports []string
+		for pkg := range imports {
+			if strings.Contains(strings.SplitN(pkg, "/", 2)[0], ".") {
+				otherImports = append(otherImports, strconv.Quote(pkg))
+			} else {
+				stdImports = append(stdImports, strconv.Quote(pkg))
+			}
+		}
+		sort.Strings(stdImports)
+		sort.Strings(otherImports)
+		source.WriteString("import (\n" + strings.Join(stdImports, "\n") + "\n\n" + strings.Join(otherImports, "\n") + "\n)\n\n")
+	}
+	source.Write(body.Bytes())
+
+	result, err := format.Source(source.Bytes())
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(result)
+	return err
+}
+
+func (m Migrator) dumpStructName(table string) string {
+	name := table
+	if ns, ok := m.DB.NamingStrategy.(schema.NamingStrategy); ok {
+		name = strings.TrimPrefix(name, ns.TablePrefix)
+		if ns.SingularTable {
+			return schema.ToFieldName(name)
+		}
+	}
+	return schema.ToFieldName(inflection.Singular(name))
+}
+
+func (m Migrator) writeDumpModel(w *bytes.Buffer, table *dumpTable, structNames map[string]string, imports map[string]bool) {
+	var (
+		structName = structNames[table.Name]
+		fieldNames = map[string]string{}
+		tags       = map[string][]string{}
+		usedNames  = map[string]bool{}
+	)
+
+	for _, column := range table.Columns {
+		fieldName := schema.ToFieldName(column.Name)
+		if fieldName == "" || (fieldName[0] >= '0' && fieldName[0] <= '9') {
+			fieldName = "F" + fieldName
+		}
+
+		for usedNames[fieldName] {
+			fieldName += "_"
+		}
+		usedNames[fieldName] = true
+		fieldNames[column.Name] = fieldName
+
+		if m.DB.NamingStrategy.ColumnName(table.Name, fieldName) != column.Name {
+			tags[column.Name] = append(tags[column.Name], "column:"+column.Name)
+		}
+
+		if column.PrimaryKey {
+			tags[column.Name] = append(tags[column.Name], "primaryKey")
+		} else if !column.Nullable {
+			tags[column.Name] = append(tags[column.Name], "not null")
+		}
+
+		if column.Size > 0 {
+			tags[column.Name] = append(tags[column.Name], "size:"+strconv.Itoa(column.Size))
+		}
+
+		if column.Unique {
+			tags[column.Name] = append(tags[column.Name], "unique")
+		}
+
+		if column.Default != nil && !column.AutoIncrement {
+			tags[column.Name] = append(tags[column.Name], "default:"+*column.Default)
+		}
+	}
+
+	for _, index := range table.Indexes {
+		kind := "index"
+		if index.Unique {
+			kind = "uniqueIndex"
+		}
+
+		for idx, column := range index.Columns {
+			tag := kind + ":" + index.Name
+			if len(index.Columns) > 1 {
+				tag += ",priority:" + strconv.Itoa(idx+1)
+			}
+			tags[column] = append(tags[column], tag)
+		}
+	}
+
+	fmt.Fprintf(w, "type %s struct {\n", structName)
+	for _, column := range table.Columns {
+		goType, pkg := dumpGoType(column)
+		if pkg != "" {
+			imports[pkg] = true
+		}
+
+		fmt.Fprintf(w, "%s %s", fieldNames[column.Name], goType)
+		if len(tags[column.Name]) > 0 {
+			fmt.Fprintf(w, " `gorm:\"%s\"`", strings.Join(tags[column.Name], ";"))
+		}
+		w.WriteString("\n")
+	}
+
+	// belongs to relations for foreign keys referencing dumped tables
+	for _, fk := range table.ForeignKeys {
+		refStruct, ok := structNames[fk.RefTable]
+		if !ok || len(fk.Columns) != len(fk.RefColumns) {
+			continue
+		}
+
+		name := refStruct
+		if len(fk.Columns) == 1 && strings.HasSuffix(fk.Columns[0], "_id") {
+			name = schema.ToFieldName(strings.TrimSuffix(fk.Columns[0], "_id"))
+		}
+
+		if usedNames[name] {
+			continue
+		}
+		usedNames[name] = true
+
+		var foreignKeys, references []string
+		for idx, column := range fk.Columns {
+			foreignKeys = append(foreignKeys, fieldNames[column])
+			references = append(references, schema.ToFieldName(fk.RefColumns[idx]))
+		}
+		fmt.Fprintf(w, "%s *%s `gorm:\"foreignKey:%s;references:%s\"`\n", name, refStruct, strings.Join(foreignKeys, ","), strings.Join(references, ","))
+	}
+	w.WriteString("}\n\n")
+
+	if m.DB.NamingStrategy.TableName(structName) != table.Name {
+		fmt.Fprintf(w, "// TableName table name of %s\nfunc (%s) TableName() string {\n\treturn %q\n}\n\n", structName, structName, table.Name)
+	}
+}
+
+// dumpGoType returns go type of column and the package needs to be imported
+func dumpGoType(column *dumpColumn) (goType string, pkg string) {
+	dbType := strings.ToLower(column.Type)
+	baseType := strings.TrimSpace(strings.SplitN(dbType, "(", 2)[0])
+
+	switch {
+	case baseType == "tinyint" && strings.HasPrefix(dbType, "tinyint(1)"), strings.HasPrefix(baseType, "bool"):
+		goType = "bool"
+	case strings.Contains(baseType, "int") || strings.Contains(baseType, "serial"):
+		goType = "int"
+		if strings.HasPrefix(baseType, "big") || baseType == "int8" || baseType == "integer" {
+			goType = "int64"
+		}
+
+		if strings.Contains(dbType, "unsigned") {
+			goType = "u" + goType
+		}
+	case strings.Contains(baseType, "float"), strings.Contains(baseType, "double"), strings.Contains(baseType, "real"),
+		strings.Contains(baseType, "numeric"), strings.Contains(baseType, "decimal"), baseType == "money":
+		goType = "float64"
+	case strings.Contains(baseType, "date"), strings.Contains(baseType, "time"):
+		if column.Name == "deleted_at" && column.Nullable {
+			return "gorm.DeletedAt", "gorm.io/gorm"
+		}
+		goType, pkg = "time.Time", "time"
+	case strings.Contains(baseType, "blob"), strings.Contains(baseType, "binary"), baseType == "bytea":
+		return "[]byte", ""
+	default:
+		goType = "string"
+	}
+
+	if column.Nullable && !column.PrimaryKey {
+		goType = "*" + goType
+	}
+	return goType, pkg
+}
+
+// dumpTable introspects table in namespace, current schema or database is used if namespace is blank
+func (m Migrator) dumpTable(namespace, name string) (*dumpTable, error) {
+	var (
+		table                         = &dumpTable{Name: name}
+		columns, indexes, foreignKeys []map[string]interface{}
+		db                            = m.DB.Session(&gorm.Session{NewDB: true})
+		stmt                          = &gorm.Statement{DB: m.DB, Namespace: namespace}
+	)
+
+	switch m.Dialector.Name() {
+	case "sqlite":
+		if err := db.Raw(m.sqlitePragma(stmt, "table_info")+"(?)", clause.Table{Name: name}).Find(&columns).Error; err != nil {
+			return nil, err
+		}
+
+		var pks int
+		for _, column := range columns {
+			if dumpInt(column["pk"]) > 0 {
+				pks++
+			}
+		}
+
+		for _, column := range columns {
+			c := &dumpColumn{
+				Name: dumpString(column["name"]), Type: dumpString(column["type"]), Nullable: dumpInt(column["notnull"]) == 0,
+				PrimaryKey: dumpInt(column["pk"]) > 0, Default: dumpNullString(column["dflt_value"]),
+			}
+			c.AutoIncrement = c.PrimaryKey && pks == 1 && strings.EqualFold(c.Type, "integer")
+			table.Columns = append(table.Columns, c)
+		}
+
+		var indexList []map[string]interface{}
+		if err := db.Raw(m.sqlitePragma(stmt, "index_list")+"(?)", clause.Table{Name: name}).Find(&indexList).Error; err != nil {
+			return nil, err
+		}
+
+		for _, index := range indexList {
+			var infos []map[string]interface{}
+			if err := db.Raw(m.sqlitePragma(stmt, "index_info")+"(?)", clause.Table{Name: dumpString(index["name"])}).Find(&infos).Error; err != nil {
+				return nil, err
+			}
+
+			for _, info := range infos {
+				indexes = append(indexes, map[string]interface{}{
+					"index_name": index["name"], "origin": index["origin"], "is_unique": dumpInt(index["unique"]) == 1, "column_name": info["name"],
+				})
+			}
+		}
+
+		var fkList []map[string]interface{}
+		if err := db.Raw(m.sqlitePragma(stmt, "foreign_key_list")+"(?)", clause.Table{Name: name}).Find(&fkList).Error; err != nil {
+			return nil, err
+		}
+
+		// foreign keys of sqlite are not named, names are looked up in DDL of table by their first column
+		var createSQL string
+		db.Raw("SELECT sql FROM "+m.sqliteMaster(stmt)+" WHERE type = ? AND name = ?", "table", name).Row().Scan(&createSQL)
+		namesByColumn := map[string]string{}
+		for _, matches := range sqliteForeignKeyRegexp.FindAllStringSubmatch(createSQL, -1) {
+			namesByColumn[strings.Trim(strings.TrimSpace(strings.Split(matches[2], ",")[0]), "`\"[]")] = matches[1]
+		}
+
+		namesByID := map[string]string{}
+		for _, fk := range fkList {
+			id := dumpString(fk["id"])
+			if _, ok := namesByID[id]; !ok {
+				if namesByID[id] = namesByColumn[dumpString(fk["from"])]; namesByID[id] == "" {
+					namesByID[id] = "fk_" + name + "_" + id
+				}
+			}
+			fkName := namesByID[id]
+
+			foreignKeys = append(foreignKeys, map[string]interface{}{
+				"constraint_name": fkName, "column_name": fk["from"],
+				"referenced_table_name": fk["table"], "referenced_column_name": fk["to"],
+				"delete_rule": fk["on_delete"], "update_rule": fk["on_update"],
+			})
+		}
+	case "postgres":
+		schema := m.postgresNamespace(stmt)
+		if err := db.Raw(`SELECT c.column_name AS column_name, c.udt_name AS column_type, c.is_nullable AS is_nullable,
+c.column_default AS column_default, c.character_maximum_length AS size, c.is_identity AS is_identity,
+(SELECT COUNT(*) FROM information_schema.table_constraints tc JOIN information_schema.key_column_usage kcu
+ON tc.constraint_name = kcu.constraint_name AND tc.table_schema = kcu.table_schema
+WHERE tc.constraint_type = 'PRIMARY KEY' AND tc.table_schema = c.table_schema AND tc.table_name = c.table_name AND kcu.column_name = c.column_name) AS primary_key
+FROM information_schema.columns c WHERE c.table_schema = ? AND c.table_name = ? ORDER BY c.ordinal_position`, schema, name).Find(&columns).Error; err != nil {
+			return nil, err
+		}
+
+		for _, column := range columns {
+			c := &dumpColumn{
+				Name: dumpString(column["column_name"]), Type: dumpString(column["column_type"]), Size: int(dumpInt(column["size"])),
+				Nullable: dumpString(column["is_nullable"]) == "YES", PrimaryKey: dumpInt(column["primary_key"]) > 0,
+				Default: dumpNullString(column["column_default"]),
+			}
+			c.AutoIncrement = dumpString(column["is_identity"]) == "YES" || (c.Default != nil && strings.HasPrefix(*c.Default, "nextval("))
+			table.Columns = append(table.Columns, c)
+		}
+
+		if err := db.Raw(`SELECT i.relname AS index_name, ix.indisunique AS is_unique, a.attname AS column_name
+FROM pg_class t JOIN pg_index ix ON t.oid = ix.indrelid JOIN pg_class i ON i.oid = ix.indexrelid
+JOIN pg_attribute a ON a.attrelid = t.oid AND a.attnum = ANY(ix.indkey) JOIN pg_namespace n ON n.oid = t.relnamespace
+WHERE n.nspname = ? AND t.relname = ? AND NOT ix.indisprimary
+ORDER BY i.relname, array_position(ix.indkey::int2[], a.attnum)`, schema, name).Find(&indexes).Error; err != nil {
+			return nil, err
+		}
+
+		if err := db.Raw(`SELECT tc.constraint_name AS constraint_name, kcu.column_name AS column_name,
+ccu.table_name AS referenced_table_name, ccu.column_name AS referenced_column_name, rc.delete_rule AS delete_rule, rc.update_rule AS update_rule
+FROM information_schema.table_constraints tc JOIN information_schema.key_column_usage kcu
+ON tc.constraint_name = kcu.constraint_name AND tc.table_schema = kcu.table_schema
+JOIN information_schema.constraint_column_usage ccu ON tc.constraint_name = ccu.constraint_name AND tc.table_schema = ccu.table_schema
+JOIN information_schema.referential_constraints rc ON tc.constraint_name = rc.constraint_name AND tc.table_schema = rc.constraint_schema
+WHERE tc.constraint_type = 'FOREIGN KEY' AND tc.table_schema = ? AND tc.table_name = ?
+ORDER BY tc.constraint_name, kcu.ordinal_position`, schema, name).Find(&foreignKeys).Error; err != nil {
+			return nil, err
+		}
+	default:
+		currentDatabase := m.namespace(stmt)
+		if err := db.Raw(`SELECT column_name AS column_name, column_type AS column_type, is_nullable AS is_nullable,
+column_key AS column_key, extra AS extra, column_default AS column_default, character_maximum_length AS size
+FROM information_schema.columns WHERE table_schema = ? AND table_name = ? ORDER BY ordinal_position`, currentDatabase, name).Find(&columns).Error; err != nil {
+			return nil, err
+		}
+
+		for _, column := range columns {
+			c := &dumpColumn{
+				Name: dumpString(column["column_name"]), Type: dumpString(column["column_type"]),
+				Nullable: dumpString(column["is_nullable"]) == "YES", PrimaryKey: dumpString(column["column_key"]) == "PRI",
+				AutoIncrement: strings.Contains(strings.ToLower(dumpString(column["extra"])), "auto_increment"),
+				Default:       dumpNullString(column["column_default"]),
+			}
+
+			if strings.Contains(strings.ToLower(c.Type), "char") {
+				c.Size = int(dumpInt(column["size"]))
+			}
+			table.Columns = append(table.Columns, c)
+		}
+
+		if err := db.Raw(`SELECT index_name AS index_name, non_unique = 0 AS is_unique, column_name AS column_name
+FROM information_schema.statistics WHERE table_schema = ? AND table_name = ? AND index_name <> 'PRIMARY'
+ORDER BY index_name, seq_in_index`, currentDatabase, name).Find(&indexes).Error; err != nil {
+			return nil, err
+		}
+
+		if err := db.Raw(`SELECT kcu.constraint_name AS constraint_name, kcu.column_name AS column_name,
+kcu.referenced_table_name AS referenced_table_name, kcu.referenced_column_name AS referenced_column_name,
+rc.delete_rule AS delete_rule, rc.update_rule AS update_rule
+FROM information_schema.key_column_usage kcu JOIN information_schema.referential_constraints rc
+ON kcu.constraint_schema = rc.constraint_schema AND kcu.constraint_name = rc.constraint_name
+WHERE kcu.table_schema = ? AND kcu.table_name = ? AND kcu.referenced_table_name IS NOT NULL
+ORDER BY kcu.constraint_name, kcu.ordinal_position`, currentDatabase, name).Find(&foreignKeys).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	if m.Dialector.Name() == "sqlite" {
+		for _, column := range table.Columns {
+			if idx := strings.Index(column.Type, "("); idx > 0 && strings.Contains(strings.ToLower(column.Type), "char") {
+				column.Size, _ = strconv.Atoi(strings.TrimSuffix(column.Type[idx+1:], ")"))
+			}
+		}
+	}
+
+	indexByName := map[string]*dumpIndex{}
+	for _, index := range indexes {
+		indexName := dumpString(index["index_name"])
+		if origin := dumpString(index["origin"]); origin == "pk" {
+			continue
+		}
+
+		if indexByName[indexName] == nil {
+			indexByName[indexName] = &dumpIndex{Name: indexName, Unique: dumpBool(index["is_unique"])}
+			table.Indexes = append(table.Indexes, indexByName[indexName])
+		}
+		indexByName[indexName].Columns = append(indexByName[indexName].Columns, dumpString(index["column_name"]))
+	}
+
+	// unique constraints of single column are generated as `unique` tags
+	for idx := 0; idx < len(table.Indexes); idx++ {
+		if index := table.Indexes[idx]; index.Unique && len(index.Columns) == 1 && strings.HasPrefix(index.Name, "sqlite_autoindex_") {
+			for _, column := range table.Columns {
+				if column.Name == index.Columns[0] {
+					column.Unique = true
+				}
+			}
+			table.Indexes = append(table.Indexes[:idx], table.Indexes[idx+1:]...)
+			idx--
+		}
+	}
+
+	fkByName := map[string]*dumpForeignKey{}
+	for _, fk := range foreignKeys {
+		fkName := dumpString(fk["constraint_name"])
+		if fkByName[fkName] == nil {
+			fkByName[fkName] = &dumpForeignKey{
+				Name: fkName, RefTable: dumpString(fk["referenced_table_name"]),
+				OnDelete: dumpString(fk["delete_rule"]), OnUpdate: dumpString(fk["update_rule"]),
+			}
+			table.ForeignKeys = append(table.ForeignKeys, fkByName[fkName])
+		}
+		fkByName[fkName].Columns = append(fkByName[fkName].Columns, dumpString(fk["column_name"]))
+		fkByName[fkName].RefColumns = append(fkByName[fkName].RefColumns, dumpString(fk["referenced_column_name"]))
+	}
+
+	return table, nil
+}
+
+func dumpString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case []byte:
+		return string(v)
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func dumpNullString(value interface{}) *string {
+	if value == nil {
+		return nil
+	}
+
+	str := dumpString(value)
+	return &str
+}
+
+func dumpInt(value interface{}) int64 {
+	i, _ := strconv.ParseInt(dumpString(value), 10, 64)
+	return i
+}
+
+func dumpBool(value interface{}) bool {
+	switch v := value.(type) {
+	case bool:
+		return v
+	default:
+		b, _ := strconv.ParseBool(dumpString(value))
+		return b
+	}
+}
@@ -0,0 +1,80 @@
+package migrator
+
+import (
+	"database/sql"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// ForeignKeyActions returns OnDelete, OnUpdate actions of foreign key constraint name in database,
+// ok is false if the constraint is not found or it is not supported by current dialect
+func (m Migrator) ForeignKeyActions(value interface{}, name string) (onDelete string, onUpdate string, ok bool) {
+	m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		constraint, _, table := m.GuessConstraintAndTable(stmt, name)
+		if constraint != nil {
+			name = constraint.Name
+		}
+
+		var deleteRule, updateRule sql.NullString
+		switch m.Dialector.Name() {
+		case "mysql":
+			if err := m.DB.Raw(
+				"SELECT DELETE_RULE, UPDATE_RULE FROM information_schema.REFERENTIAL_CONSTRAINTS WHERE CONSTRAINT_SCHEMA = ? AND TABLE_NAME = ? AND CONSTRAINT_NAME = ?",
+				m.DB.Migrator().CurrentDatabase(), table, name,
+			).Row().Scan(&deleteRule, &updateRule); err == nil {
+				onDelete, onUpdate, ok = deleteRule.String, updateRule.String, true
+			}
+		case "postgres":
+			if err := m.DB.Raw(
+				"SELECT rc.delete_rule, rc.update_rule FROM information_schema.referential_constraints rc JOIN information_schema.table_constraints tc "+
+					"ON rc.constraint_schema = tc.constraint_schema AND rc.constraint_name = tc.constraint_name WHERE tc.table_schema = CURRENT_SCHEMA() AND tc.table_name = ? AND tc.constraint_name = ?",
+				table, name,
+			).Row().Scan(&deleteRule, &updateRule); err == nil {
+				onDelete, onUpdate, ok = deleteRule.String, updateRule.String, true
+			}
+		case "sqlite":
+			// foreign keys of sqlite are not named, look up by columns of constraint
+			if constraint == nil || len(constraint.ForeignKeys) == 0 {
+				return nil
+			}
+
+			var foreignKeys []map[string]interface{}
+			if err := m.DB.Raw("PRAGMA foreign_key_list(?)", clause.Table{Name: table}).Scan(&foreignKeys).Error; err == nil {
+				for _, fk := range foreignKeys {
+					if dumpString(fk["from"]) == constraint.ForeignKeys[0].DBName && dumpString(fk["table"]) == constraint.ReferenceSchema.Table {
+						onDelete, onUpdate, ok = dumpString(fk["on_delete"]), dumpString(fk["on_update"]), true
+						break
+					}
+				}
+			}
+		}
+		return nil
+	})
+	return
+}
+
+// foreignKeyActionsChanged returns true if OnDelete, OnUpdate actions of constraint are different from database
+func (m Migrator) foreignKeyActionsChanged(value interface{}, constraint *schema.Constraint) bool {
+	// sqlite doesn't support altering constraints
+	if m.Dialector.Name() == "sqlite" {
+		return false
+	}
+
+	onDelete, onUpdate, ok := m.ForeignKeyActions(value, constraint.Name)
+	if !ok {
+		return false
+	}
+	return m.foreignKeyAction(onDelete) != m.foreignKeyAction(constraint.OnDelete) ||
+		m.foreignKeyAction(onUpdate) != m.foreignKeyAction(constraint.OnUpdate)
+}
+
+// foreignKeyAction returns normalized action, unspecified action is `NO ACTION`, which is the same as `RESTRICT` for mysql
+func (m Migrator) foreignKeyAction(action string) string {
+	action = schema.NormalizeConstraintAction(action)
+	if action == "" || (action == "RESTRICT" && m.Dialector.Name() == "mysql") {
+		return "NO ACTION"
+	}
+	return action
+}
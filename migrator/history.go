@@ -0,0 +1,169 @@
+package migrator
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// migrateHistory creates history table of temporal models declared with schema.HistoryTabler, adds missing columns to it,
+// and creates triggers writing old row versions into it on updating and deleting
+func (m Migrator) migrateHistory(tx *gorm.DB, value interface{}) error {
+	return m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		if stmt.Schema == nil || stmt.Schema.HistoryTable == "" {
+			return nil
+		}
+
+		history := stmt.Schema.HistoryTable
+		if stmt.Namespace != "" && !strings.Contains(history, ".") {
+			history = stmt.Namespace + "." + history
+		}
+
+		var (
+			changed bool
+			fields  = m.historyFields(stmt)
+		)
+
+		if !m.hasTable(tx, history) {
+			createTableSQL, values := "CREATE TABLE ? (", []interface{}{clause.Table{Name: history}}
+			for idx, dbName := range append(stmt.Schema.DBNames, schema.HistoryValidFrom, schema.HistoryValidTo, schema.HistoryOperation) {
+				if idx > 0 {
+					createTableSQL += ","
+				}
+				createTableSQL += "? ?"
+				values = append(values, clause.Column{Name: dbName}, clause.Expr{SQL: fields[dbName]})
+			}
+
+			if err := m.DB.Exec(createTableSQL+")", values...).Error; err != nil {
+				return err
+			}
+		} else {
+			columnTypes, err := tx.Migrator().ColumnTypes(history)
+			if err != nil {
+				return err
+			}
+
+			columns := map[string]bool{}
+			for _, columnType := range columnTypes {
+				columns[columnType.Name()] = true
+			}
+
+			for _, dbName := range stmt.Schema.DBNames {
+				if !columns[dbName] {
+					if err := m.DB.Exec(
+						"ALTER TABLE ? ADD ? ?", clause.Table{Name: history}, clause.Column{Name: dbName}, clause.Expr{SQL: fields[dbName]},
+					).Error; err != nil {
+						return err
+					}
+					changed = true
+				}
+			}
+		}
+
+		triggers := m.triggerMigrator(tx)
+		for _, trigger := range m.historyTriggers(stmt, history) {
+			// triggers list columns of history table, recreate them with new columns
+			if changed && triggers.HasTrigger(value, trigger.Name) {
+				if err := triggers.DropTrigger(value, trigger.Name); err != nil {
+					return err
+				}
+			}
+
+			if !triggers.HasTrigger(value, trigger.Name) {
+				if err := triggers.CreateTrigger(value, trigger); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// historyFields returns data types of columns of history table, columns of model are not primary keys, unique
+// or auto-incremented in history table
+func (m Migrator) historyFields(stmt *gorm.Statement) map[string]string {
+	fields := map[string]string{
+		schema.HistoryValidFrom: m.Dialector.DataTypeOf(&schema.Field{DataType: schema.Time, Precision: 3}),
+		schema.HistoryValidTo:   m.Dialector.DataTypeOf(&schema.Field{DataType: schema.Time, Precision: 3}) + " NOT NULL",
+		schema.HistoryOperation: m.Dialector.DataTypeOf(&schema.Field{DataType: schema.String, Size: 10}),
+	}
+
+	for _, dbName := range stmt.Schema.DBNames {
+		field := *stmt.Schema.FieldsByDBName[dbName]
+		field.AutoIncrement = false
+		fields[dbName] = m.DataTypeOf(&field)
+	}
+	return fields
+}
+
+// historyTriggers returns triggers of table writing old row versions into history table, version of row is valid
+// from the end of its last version, or its creating time
+func (m Migrator) historyTriggers(stmt *gorm.Statement, history string) (triggers []gorm.Trigger) {
+	var (
+		quoted    = stmt.Quote(history)
+		columns   = make([]string, 0, len(stmt.Schema.DBNames))
+		validFrom = "OLD.%s"
+		now       = "CURRENT_TIMESTAMP"
+	)
+
+	switch m.Dialector.Name() {
+	case "sqlite":
+		// times are compared as strings, write them in UTC with the same format
+		validFrom, now = "strftime('%%Y-%%m-%%d %%H:%%M:%%f', OLD.%s)", "strftime('%Y-%m-%d %H:%M:%f','now')"
+	case "mysql":
+		now = "CURRENT_TIMESTAMP(3)"
+	case "sqlserver":
+		validFrom, now = "d.%s", "SYSDATETIMEOFFSET()"
+	}
+
+	for _, dbName := range stmt.Schema.DBNames {
+		columns = append(columns, stmt.Quote(dbName))
+	}
+
+	if field := stmt.Schema.CreatedTimeField(); field != nil {
+		validFrom = fmt.Sprintf(validFrom, stmt.Quote(field.DBName))
+	} else {
+		validFrom = "NULL"
+	}
+
+	for _, operation := range []string{"UPDATE", "DELETE"} {
+		var (
+			values     = make([]string, 0, len(columns))
+			conditions = make([]string, 0, len(stmt.Schema.PrimaryFieldDBNames))
+			insertSQL  = "INSERT INTO " + quoted + " (" + strings.Join(columns, ",") + "," + stmt.Quote(schema.HistoryValidFrom) + "," +
+				stmt.Quote(schema.HistoryValidTo) + "," + stmt.Quote(schema.HistoryOperation) + ") SELECT "
+			trigger = gorm.Trigger{Name: fmt.Sprintf("trg_%s_history_%s", stmt.Table, strings.ToLower(operation)), Timing: "AFTER", Event: operation}
+		)
+
+		for _, column := range columns {
+			values = append(values, "OLD."+column)
+		}
+
+		for _, dbName := range stmt.Schema.PrimaryFieldDBNames {
+			conditions = append(conditions, quoted+"."+stmt.Quote(dbName)+" = OLD."+stmt.Quote(dbName))
+		}
+
+		// old rows of sqlserver triggers are in pseudo table deleted
+		if m.Dialector.Name() == "sqlserver" {
+			for idx, column := range columns {
+				values[idx] = "d." + column
+			}
+
+			for idx, dbName := range stmt.Schema.PrimaryFieldDBNames {
+				conditions[idx] = quoted + "." + stmt.Quote(dbName) + " = d." + stmt.Quote(dbName)
+			}
+
+			trigger.Body = insertSQL + strings.Join(values, ",") + ",COALESCE((SELECT MAX(" + stmt.Quote(schema.HistoryValidTo) + ") FROM " + quoted +
+				" WHERE " + strings.Join(conditions, " AND ") + ")," + validFrom + ")," + now + ",'" + operation + "' FROM deleted d;"
+		} else {
+			trigger.Body = insertSQL + strings.Join(values, ",") + ",COALESCE(MAX(" + quoted + "." + stmt.Quote(schema.HistoryValidTo) + ")," + validFrom + ")," +
+				now + ",'" + operation + "' FROM " + quoted + " WHERE " + strings.Join(conditions, " AND ") + ";"
+		}
+
+		triggers = append(triggers, trigger)
+	}
+	return
+}
@@ -0,0 +1,121 @@
+package migrator
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+var (
+	indexWhereRegexp     = regexp.MustCompile(`(?is)\sWHERE\s(.*)$`)
+	indexIncludeRegexp   = regexp.MustCompile(`(?i)\sINCLUDE\s*\(([^)]*)\)`)
+	indexWithRegexp      = regexp.MustCompile(`(?i)\sWITH\s*\(([^)]*)\)`)
+	indexNormalizeRegexp = regexp.MustCompile("::[a-z_]+( varying)?|[\\s\"`()\\[\\]]")
+)
+
+// IndexDefinition returns DDL of index name in database, ok is false if it is not found or not supported by current dialect
+func (m Migrator) IndexDefinition(value interface{}, name string) (definition string, ok bool) {
+	m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		if idx := stmt.Schema.LookIndex(name); idx != nil {
+			name = idx.Name
+		}
+
+		var err error
+		switch m.Dialector.Name() {
+		case "sqlite":
+			err = m.DB.Raw("SELECT sql FROM sqlite_master WHERE type = ? AND tbl_name = ? AND name = ?", "index", stmt.Table, name).Row().Scan(&definition)
+		case "postgres":
+			err = m.DB.Raw("SELECT indexdef FROM pg_indexes WHERE schemaname = CURRENT_SCHEMA() AND tablename = ? AND indexname = ?", stmt.Table, name).Row().Scan(&definition)
+		default:
+			return nil
+		}
+		ok = err == nil && definition != ""
+		return nil
+	})
+	return
+}
+
+// createIndex creates index idx with its comment through migrator of dialector, indexes of tables in other schemas
+// are created with Migrator.CreateIndex, as drivers don't qualify them with namespaces of statements
+func (m Migrator) createIndex(tx *gorm.DB, value interface{}, idx schema.Index) (err error) {
+	if m.namespaced(value) {
+		err = m.CreateIndex(value, idx.Name)
+	} else {
+		err = tx.Migrator().CreateIndex(value, idx.Name)
+	}
+
+	if err == nil && idx.Comment != "" {
+		err = m.RunWithValue(value, func(stmt *gorm.Statement) error {
+			return m.commentIndex(tx, stmt, idx)
+		})
+	}
+	return err
+}
+
+// dropIndex drops index name through migrator of dialector
+func (m Migrator) dropIndex(tx *gorm.DB, value interface{}, name string) error {
+	return tx.Migrator().DropIndex(value, name)
+}
+
+// concurrentIndexes returns true if indexes should be created and dropped concurrently, returns error for postgres
+// in transaction as CONCURRENTLY can't run in transaction, e.g: migrations should set DisableTransaction
+func (m Migrator) concurrentIndexes() (bool, error) {
+	if !m.DB.ConcurrentIndexes {
+		return false, nil
+	}
+
+	if _, ok := m.DB.Statement.ConnPool.(gorm.TxCommitter); ok && m.Dialector.Name() == "postgres" {
+		return true, fmt.Errorf("%w: indexes can't be created or dropped concurrently in transaction", gorm.ErrInvalidTransaction)
+	}
+	return true, nil
+}
+
+// indexChanged returns true if predicate, expressions, INCLUDE columns or storage parameters of index are different from database,
+// indexes are compared by normalized DDL, it is only supported by dialects implemented IndexDefinition
+func (m Migrator) indexChanged(value interface{}, idx schema.Index) bool {
+	definition, ok := m.IndexDefinition(value, idx.Name)
+	if !ok {
+		return false
+	}
+
+	var predicate string
+	if loc := indexWhereRegexp.FindStringSubmatchIndex(definition); loc != nil {
+		predicate = definition[loc[2]:loc[3]]
+		definition = definition[:loc[0]]
+	}
+
+	if normalizeIndexSQL(predicate) != normalizeIndexSQL(idx.Where) {
+		return true
+	}
+
+	if m.Dialector.Name() == "postgres" {
+		var include, with string
+		if matches := indexIncludeRegexp.FindStringSubmatch(definition); len(matches) == 2 {
+			include = matches[1]
+		}
+
+		if matches := indexWithRegexp.FindStringSubmatch(definition); len(matches) == 2 {
+			with = strings.ReplaceAll(matches[1], "'", "")
+		}
+
+		if normalizeIndexSQL(include) != normalizeIndexSQL(strings.Join(idx.Include, ",")) || normalizeIndexSQL(with) != normalizeIndexSQL(idx.With) {
+			return true
+		}
+	}
+
+	definition = normalizeIndexSQL(definition)
+	for _, field := range idx.Fields {
+		if field.Expression != "" && !strings.Contains(definition, normalizeIndexSQL(field.Expression)) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeIndexSQL lower-cases sql, removes quotes, parentheses, spaces and type casts, e.g: `(("Status")::text = 'active'::text)` => `status='active'`
+func normalizeIndexSQL(sql string) string {
+	return indexNormalizeRegexp.ReplaceAllString(strings.ToLower(sql), "")
+}
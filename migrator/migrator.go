@@ -33,10 +33,18 @@ func (m Migrator) RunWithValue(value interface{}, fc func(*gorm.Statement) error
 	if m.DB.Statement != nil {
 		stmt.Table = m.DB.Statement.Table
 		stmt.TableExpr = m.DB.Statement.TableExpr
+		stmt.Namespace = m.DB.Statement.Namespace
 	}
 
 	if table, ok := value.(string); ok {
 		stmt.Table = table
+		if tables := strings.Split(table, "."); len(tables) == 2 {
+			stmt.TableExpr = &clause.Expr{SQL: stmt.Quote(table)}
+			stmt.Table, stmt.Namespace = tables[1], tables[0]
+		} else if stmt.Namespace == "" && m.DB.Namespace != "" {
+			stmt.TableExpr = &clause.Expr{SQL: stmt.Quote(m.DB.Namespace + "." + table)}
+			stmt.Namespace = m.DB.Namespace
+		}
 	} else if err := stmt.Parse(value); err != nil {
 		return err
 	}
@@ -52,11 +60,56 @@ func (m Migrator) DataTypeOf(field *schema.Field) string {
 		}
 	}
 
+	// arrays are saved as native arrays for postgres, as JSON for others
+	if field.GORMDataType == schema.Array && field.Serializer == nil {
+		elemField := *field
+		elemField.DataType, elemField.Size = field.ArrayElemDataType()
+		elemField.GORMDataType = elemField.DataType
+		if m.Dialector.Name() == "postgres" {
+			return m.Dialector.DataTypeOf(&elemField) + "[]"
+		} else if m.Dialector.Name() == "mysql" {
+			return "JSON"
+		}
+
+		elemField.DataType, elemField.GORMDataType, elemField.Size = schema.String, schema.String, 0
+		return m.Dialector.DataTypeOf(&elemField)
+	}
+
+	// intervals are saved as interval for postgres, as microseconds for others
+	if field.GORMDataType == schema.Interval && field.Serializer == nil {
+		if m.Dialector.Name() == "postgres" {
+			return "interval"
+		}
+
+		intField := *field
+		intField.DataType, intField.GORMDataType, intField.Size = schema.Int, schema.Int, 64
+		return m.Dialector.DataTypeOf(&intField)
+	}
+
+	// mysql supports native enum types
+	if len(field.EnumValues) > 0 && m.Dialector.Name() == "mysql" {
+		values := make([]string, len(field.EnumValues))
+		for idx, value := range field.EnumValues {
+			values[idx] = "'" + strings.ReplaceAll(value, "'", "''") + "'"
+		}
+		return "ENUM(" + strings.Join(values, ",") + ")"
+	}
+
 	return m.Dialector.DataTypeOf(field)
 }
 
 func (m Migrator) FullDataTypeOf(field *schema.Field) (expr clause.Expr) {
-	expr.SQL = m.DataTypeOf(field)
+	expr.SQL = m.DataTypeOf(field) + m.CollationOf(field)
+
+	if field.GeneratedAs != "" {
+		expr.SQL += " GENERATED ALWAYS AS (" + field.GeneratedAs + ")"
+		// postgres only supports stored generated columns
+		if field.GeneratedStored || m.Dialector.Name() == "postgres" {
+			expr.SQL += " STORED"
+		} else {
+			expr.SQL += " VIRTUAL"
+		}
+	}
 
 	if field.NotNull {
 		expr.SQL += " NOT NULL"
@@ -66,7 +119,7 @@ func (m Migrator) FullDataTypeOf(field *schema.Field) (expr clause.Expr) {
 		expr.SQL += " UNIQUE"
 	}
 
-	if field.HasDefaultValue && (field.DefaultValueInterface != nil || field.DefaultValue != "") {
+	if field.HasDefaultValue && field.GeneratedAs == "" && (field.DefaultValueInterface != nil || field.DefaultValue != "") {
 		if field.DefaultValueInterface != nil {
 			defaultStmt := &gorm.Statement{Vars: []interface{}{field.DefaultValueInterface}}
 			m.Dialector.BindVarTo(defaultStmt, defaultStmt, field.DefaultValueInterface)
@@ -83,14 +136,36 @@ func (m Migrator) FullDataTypeOf(field *schema.Field) (expr clause.Expr) {
 func (m Migrator) AutoMigrate(values ...interface{}) error {
 	for _, value := range m.ReorderModels(values, true) {
 		tx := m.DB.Session(&gorm.Session{})
-		if !tx.Migrator().HasTable(value) {
+		if materialized, err := m.migrateMaterializedView(tx, value); err != nil {
+			return err
+		} else if materialized {
+			continue
+		}
+
+		if !m.hasTable(tx, value) {
 			if err := tx.Migrator().CreateTable(value); err != nil {
 				return err
 			}
 		} else {
+			// drop unused objects first, as sqlite recreates table when dropping columns
+			if m.DB.DropUnusedWhenMigrating {
+				if err := m.dropUnused(tx, value); err != nil {
+					return err
+				}
+			}
+
 			if err := m.RunWithValue(value, func(stmt *gorm.Statement) (errr error) {
 				columnTypes, _ := m.DB.Migrator().ColumnTypes(value)
 
+				// collations and comments of columns are loaded once for the table
+				ctx := m.DB.Statement.Context
+				if ctx == nil {
+					ctx = context.Background()
+				}
+				ctx = context.WithValue(ctx, columnCollationsKey{}, &columnCollations{})
+				ctx = context.WithValue(ctx, columnCommentsKey{}, &columnComments{})
+				columnMigrator := m.DB.Session(&gorm.Session{Context: ctx}).Migrator()
+
 				for _, field := range stmt.Schema.FieldsByDBName {
 					var foundColumn gorm.ColumnType
 
@@ -106,7 +181,7 @@ func (m Migrator) AutoMigrate(values ...interface{}) error {
 						if err := tx.Migrator().AddColumn(value, field.DBName); err != nil {
 							return err
 						}
-					} else if err := m.DB.Migrator().MigrateColumn(value, field, foundColumn); err != nil {
+					} else if err := columnMigrator.MigrateColumn(value, field, foundColumn); err != nil {
 						// found, smart migrate
 						return err
 					}
@@ -117,6 +192,19 @@ func (m Migrator) AutoMigrate(values ...interface{}) error {
 						if constraint := rel.ParseConstraint(); constraint != nil {
 							if constraint.Schema == stmt.Schema {
 								if !tx.Migrator().HasConstraint(value, constraint.Name) {
+									if err := tx.Migrator().CreateConstraint(value, constraint.Name); err != nil {
+										return err
+									}
+								} else if m.foreignKeyActionsChanged(value, constraint) {
+									// recreate constraint with new OnDelete, OnUpdate actions
+									if err := constraint.Validate(); err != nil {
+										return err
+									}
+
+									if err := tx.Migrator().DropConstraint(value, constraint.Name); err != nil {
+										return err
+									}
+
 									if err := tx.Migrator().CreateConstraint(value, constraint.Name); err != nil {
 										return err
 									}
@@ -135,8 +223,17 @@ func (m Migrator) AutoMigrate(values ...interface{}) error {
 				}
 
 				for _, idx := range stmt.Schema.ParseIndexes() {
-					if !tx.Migrator().HasIndex(value, idx.Name) {
-						if err := tx.Migrator().CreateIndex(value, idx.Name); err != nil {
+					if !m.hasIndex(tx, value, idx.Name) {
+						if err := m.createIndex(tx, value, idx); err != nil {
+							return err
+						}
+					} else if m.indexChanged(value, idx) {
+						// recreate index with new predicate, expressions, INCLUDE columns or storage parameters
+						if err := m.dropIndex(tx, value, idx.Name); err != nil {
+							return err
+						}
+
+						if err := m.createIndex(tx, value, idx); err != nil {
 							return err
 						}
 					}
@@ -147,6 +244,18 @@ func (m Migrator) AutoMigrate(values ...interface{}) error {
 				return err
 			}
 		}
+
+		if err := m.migrateTableComment(tx, value); err != nil {
+			return err
+		}
+
+		if err := m.migrateHistory(tx, value); err != nil {
+			return err
+		}
+
+		if err := m.migrateTriggers(tx, value); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -156,6 +265,10 @@ func (m Migrator) CreateTable(values ...interface{}) error {
 	for _, value := range m.ReorderModels(values, false) {
 		tx := m.DB.Session(&gorm.Session{})
 		if err := m.RunWithValue(value, func(stmt *gorm.Statement) (errr error) {
+			if err := m.createNamespace(stmt); err != nil {
+				return err
+			}
+
 			var (
 				createTableSQL          = "CREATE TABLE ? ("
 				values                  = []interface{}{m.CurrentTable(stmt)}
@@ -182,11 +295,11 @@ func (m Migrator) CreateTable(values ...interface{}) error {
 
 			for _, idx := range stmt.Schema.ParseIndexes() {
 				if m.CreateIndexAfterCreateTable {
-					defer func(value interface{}, name string) {
+					defer func(value interface{}, idx schema.Index) {
 						if errr == nil {
-							errr = tx.Migrator().CreateIndex(value, name)
+							errr = m.createIndex(tx, value, idx)
 						}
-					}(value, idx.Name)
+					}(value, idx)
 				} else {
 					if idx.Class != "" {
 						createTableSQL += idx.Class + " "
@@ -197,8 +310,13 @@ func (m Migrator) CreateTable(values ...interface{}) error {
 						createTableSQL += " " + idx.Option
 					}
 
-					createTableSQL += ","
 					values = append(values, clause.Expr{SQL: idx.Name}, tx.Migrator().(BuildIndexOptionsInterface).BuildIndexOptions(idx.Fields, stmt))
+					if idx.Comment != "" && m.Dialector.Name() == "mysql" {
+						createTableSQL += " COMMENT ?"
+						values = append(values, m.commentValue(idx.Comment))
+					}
+
+					createTableSQL += ","
 				}
 			}
 
@@ -206,6 +324,10 @@ func (m Migrator) CreateTable(values ...interface{}) error {
 				if !m.DB.DisableForeignKeyConstraintWhenMigrating {
 					if constraint := rel.ParseConstraint(); constraint != nil {
 						if constraint.Schema == stmt.Schema {
+							if err := constraint.Validate(); err != nil {
+								return err
+							}
+
 							sql, vars := buildConstraint(constraint)
 							createTableSQL += sql + ","
 							values = append(values, vars...)
@@ -216,18 +338,30 @@ func (m Migrator) CreateTable(values ...interface{}) error {
 
 			for _, chk := range stmt.Schema.ParseCheckConstraints() {
 				createTableSQL += "CONSTRAINT ? CHECK (?),"
-				values = append(values, clause.Column{Name: chk.Name}, clause.Expr{SQL: chk.Constraint})
+				values = append(values, clause.Column{Name: chk.Name}, checkExpression(chk))
 			}
 
 			createTableSQL = strings.TrimSuffix(createTableSQL, ",")
 
 			createTableSQL += ")"
 
+			if sql, vars := m.partitionClause(stmt); sql != "" {
+				createTableSQL += sql
+				values = append(values, vars...)
+			}
+
 			if tableOption, ok := m.DB.Get("gorm:table_options"); ok {
 				createTableSQL += fmt.Sprint(tableOption)
 			}
 
-			errr = tx.Exec(createTableSQL, values...).Error
+			if stmt.Schema.Comment != "" && m.Dialector.Name() == "mysql" {
+				createTableSQL += " COMMENT = ?"
+				values = append(values, m.commentValue(stmt.Schema.Comment))
+			}
+
+			if errr = tx.Exec(createTableSQL, values...).Error; errr == nil && stmt.Schema.Comment != "" && m.Dialector.Name() == "postgres" {
+				errr = m.commentTable(tx, stmt)
+			}
 			return errr
 		}); err != nil {
 			return err
@@ -253,8 +387,8 @@ func (m Migrator) HasTable(value interface{}) bool {
 	var count int64
 
 	m.RunWithValue(value, func(stmt *gorm.Statement) error {
-		currentDatabase := m.DB.Migrator().CurrentDatabase()
-		return m.DB.Raw("SELECT count(*) FROM information_schema.tables WHERE table_schema = ? AND table_name = ? AND table_type = ?", currentDatabase, stmt.Table, "BASE TABLE").Row().Scan(&count)
+		namespace := m.namespace(stmt)
+		return m.DB.Raw("SELECT count(*) FROM information_schema.tables WHERE table_schema = ? AND table_name = ? AND table_type = ?", namespace, stmt.Table, "BASE TABLE").Row().Scan(&count)
 	})
 
 	return count > 0
@@ -314,7 +448,11 @@ func (m Migrator) DropColumn(value interface{}, name string) error {
 func (m Migrator) AlterColumn(value interface{}, field string) error {
 	return m.RunWithValue(value, func(stmt *gorm.Statement) error {
 		if field := stmt.Schema.LookUpField(field); field != nil {
-			fileType := clause.Expr{SQL: m.DataTypeOf(field)}
+			fileType := clause.Expr{SQL: m.DataTypeOf(field) + m.CollationOf(field)}
+			if field.Using != "" && m.Dialector.Name() == "postgres" {
+				fileType.SQL += " USING " + field.Using
+			}
+
 			return m.DB.Exec(
 				"ALTER TABLE ? ALTER COLUMN ? TYPE ?",
 				m.CurrentTable(stmt), clause.Column{Name: field.DBName}, fileType,
@@ -328,7 +466,7 @@ func (m Migrator) AlterColumn(value interface{}, field string) error {
 func (m Migrator) HasColumn(value interface{}, field string) bool {
 	var count int64
 	m.RunWithValue(value, func(stmt *gorm.Statement) error {
-		currentDatabase := m.DB.Migrator().CurrentDatabase()
+		namespace := m.namespace(stmt)
 		name := field
 		if field := stmt.Schema.LookUpField(field); field != nil {
 			name = field.DBName
@@ -336,7 +474,7 @@ func (m Migrator) HasColumn(value interface{}, field string) bool {
 
 		return m.DB.Raw(
 			"SELECT count(*) FROM INFORMATION_SCHEMA.columns WHERE table_schema = ? AND table_name = ? AND column_name = ?",
-			currentDatabase, stmt.Table, name,
+			namespace, stmt.Table, name,
 		).Row().Scan(&count)
 	})
 
@@ -362,7 +500,7 @@ func (m Migrator) RenameColumn(value interface{}, oldName, newName string) error
 
 func (m Migrator) MigrateColumn(value interface{}, field *schema.Field, columnType gorm.ColumnType) error {
 	// found, smart migrate
-	fullDataType := strings.ToLower(m.DB.Migrator().FullDataTypeOf(field).SQL)
+	fullDataType := strings.ToLower(strings.Replace(m.DB.Migrator().FullDataTypeOf(field).SQL, m.CollationOf(field), "", 1))
 	realDataType := strings.ToLower(columnType.DatabaseTypeName())
 
 	alterColumn := false
@@ -396,6 +534,24 @@ func (m Migrator) MigrateColumn(value interface{}, field *schema.Field, columnTy
 		}
 	}
 
+	// check charset and collation
+	if !alterColumn && m.collationChanged(value, field) {
+		alterColumn = true
+	}
+
+	// check comment
+	if !alterColumn && m.columnCommentChanged(value, field) {
+		alterColumn = true
+	}
+
+	// check type of column with conversion expression
+	if field.Using != "" && m.Dialector.Name() == "postgres" && columnTypeName(m.DataTypeOf(field)) != columnTypeName(realDataType) {
+		if !m.DB.ConfirmColumnConversion {
+			return fmt.Errorf("%w: converting %v from %v to %v using %v", gorm.ErrColumnConversionNotConfirmed, field.DBName, realDataType, m.DataTypeOf(field), field.Using)
+		}
+		alterColumn = true
+	}
+
 	if alterColumn {
 		return m.DB.Migrator().AlterColumn(value, field.Name)
 	}
@@ -406,7 +562,7 @@ func (m Migrator) MigrateColumn(value interface{}, field *schema.Field, columnTy
 func (m Migrator) ColumnTypes(value interface{}) (columnTypes []gorm.ColumnType, err error) {
 	columnTypes = make([]gorm.ColumnType, 0)
 	err = m.RunWithValue(value, func(stmt *gorm.Statement) error {
-		rows, err := m.DB.Session(&gorm.Session{}).Table(stmt.Table).Limit(1).Rows()
+		rows, err := m.DB.Session(&gorm.Session{}).Table("?", m.CurrentTable(stmt)).Limit(1).Rows()
 		if err == nil {
 			defer rows.Close()
 			rawColumnTypes, err := rows.ColumnTypes()
@@ -421,14 +577,6 @@ func (m Migrator) ColumnTypes(value interface{}) (columnTypes []gorm.ColumnType,
 	return
 }
 
-func (m Migrator) CreateView(name string, option gorm.ViewOption) error {
-	return gorm.ErrNotImplemented
-}
-
-func (m Migrator) DropView(name string) error {
-	return gorm.ErrNotImplemented
-}
-
 func buildConstraint(constraint *schema.Constraint) (sql string, results []interface{}) {
 	sql = "CONSTRAINT ? FOREIGN KEY ? REFERENCES ??"
 	if constraint.OnDelete != "" {
@@ -493,17 +641,29 @@ func (m Migrator) GuessConstraintAndTable(stmt *gorm.Statement, name string) (_
 	return nil, nil, ""
 }
 
+// checkExpression returns expression of check constraint, Expression of check is used if it is built with quoted columns
+func checkExpression(chk schema.Check) clause.Expression {
+	if chk.Expression != nil {
+		return chk.Expression
+	}
+	return clause.Expr{SQL: chk.Constraint}
+}
+
 func (m Migrator) CreateConstraint(value interface{}, name string) error {
 	return m.RunWithValue(value, func(stmt *gorm.Statement) error {
 		constraint, chk, table := m.GuessConstraintAndTable(stmt, name)
 		if chk != nil {
 			return m.DB.Exec(
 				"ALTER TABLE ? ADD CONSTRAINT ? CHECK (?)",
-				m.CurrentTable(stmt), clause.Column{Name: chk.Name}, clause.Expr{SQL: chk.Constraint},
+				m.CurrentTable(stmt), clause.Column{Name: chk.Name}, checkExpression(*chk),
 			).Error
 		}
 
 		if constraint != nil {
+			if err := constraint.Validate(); err != nil {
+				return err
+			}
+
 			var vars = []interface{}{clause.Table{Name: table}}
 			if stmt.TableExpr != nil {
 				vars[0] = stmt.TableExpr
@@ -531,7 +691,7 @@ func (m Migrator) DropConstraint(value interface{}, name string) error {
 func (m Migrator) HasConstraint(value interface{}, name string) bool {
 	var count int64
 	m.RunWithValue(value, func(stmt *gorm.Statement) error {
-		currentDatabase := m.DB.Migrator().CurrentDatabase()
+		namespace := m.namespace(stmt)
 		constraint, chk, table := m.GuessConstraintAndTable(stmt, name)
 		if constraint != nil {
 			name = constraint.Name
@@ -541,7 +701,7 @@ func (m Migrator) HasConstraint(value interface{}, name string) bool {
 
 		return m.DB.Raw(
 			"SELECT count(*) FROM INFORMATION_SCHEMA.table_constraints WHERE constraint_schema = ? AND table_name = ? AND constraint_name = ?",
-			currentDatabase, table, name,
+			namespace, table, name,
 		).Row().Scan(&count)
 	})
 
@@ -578,21 +738,72 @@ func (m Migrator) CreateIndex(value interface{}, name string) error {
 		if idx := stmt.Schema.LookIndex(name); idx != nil {
 			opts := m.DB.Migrator().(BuildIndexOptionsInterface).BuildIndexOptions(idx.Fields, stmt)
 			values := []interface{}{clause.Column{Name: idx.Name}, m.CurrentTable(stmt), opts}
+			if m.Dialector.Name() == "sqlite" && stmt.Namespace != "" {
+				// indexes of attached databases are qualified with schema, tables are not
+				values[0], values[1] = clause.Table{Name: stmt.Namespace + "." + idx.Name}, clause.Table{Name: stmt.Table}
+			}
 
 			createIndexSQL := "CREATE "
 			if idx.Class != "" {
 				createIndexSQL += idx.Class + " "
 			}
-			createIndexSQL += "INDEX ? ON ??"
 
-			if idx.Type != "" {
-				createIndexSQL += " USING " + idx.Type
+			concurrently, err := m.concurrentIndexes()
+			if err != nil {
+				return err
+			}
+
+			createIndexSQL += "INDEX "
+			if concurrently && m.Dialector.Name() == "postgres" {
+				createIndexSQL += "CONCURRENTLY "
+			}
+
+			if m.Dialector.Name() == "postgres" && idx.Type != "" {
+				createIndexSQL += "? ON ? USING " + idx.Type + " ?"
+			} else {
+				createIndexSQL += "? ON ??"
+
+				if idx.Type != "" {
+					createIndexSQL += " USING " + idx.Type
+				}
+			}
+
+			// INCLUDE columns and storage parameters are supported by postgres and sqlserver
+			supportsInclude := m.Dialector.Name() == "postgres" || m.Dialector.Name() == "sqlserver"
+			if len(idx.Include) > 0 && supportsInclude {
+				var include []interface{}
+				for _, column := range idx.Include {
+					include = append(include, clause.Column{Name: column})
+				}
+				createIndexSQL += " INCLUDE ?"
+				values = append(values, include)
 			}
 
 			if idx.Option != "" {
 				createIndexSQL += " " + idx.Option
 			}
 
+			if idx.With != "" && m.Dialector.Name() == "postgres" {
+				createIndexSQL += " WITH (" + idx.With + ")"
+			}
+
+			if idx.Where != "" {
+				createIndexSQL += " WHERE " + idx.Where
+			}
+
+			if idx.With != "" && m.Dialector.Name() == "sqlserver" {
+				createIndexSQL += " WITH (" + idx.With + ")"
+			}
+
+			if idx.Comment != "" && m.Dialector.Name() == "mysql" {
+				createIndexSQL += " COMMENT ?"
+				values = append(values, m.commentValue(idx.Comment))
+			}
+
+			if concurrently && m.Dialector.Name() == "mysql" {
+				createIndexSQL += " ALGORITHM=INPLACE LOCK=NONE"
+			}
+
 			return m.DB.Exec(createIndexSQL, values...).Error
 		}
 
@@ -606,6 +817,17 @@ func (m Migrator) DropIndex(value interface{}, name string) error {
 			name = idx.Name
 		}
 
+		concurrently, err := m.concurrentIndexes()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case concurrently && m.Dialector.Name() == "postgres":
+			return m.DB.Exec("DROP INDEX CONCURRENTLY IF EXISTS ?", clause.Column{Name: name}).Error
+		case concurrently && m.Dialector.Name() == "mysql":
+			return m.DB.Exec("DROP INDEX ? ON ? ALGORITHM=INPLACE LOCK=NONE", clause.Column{Name: name}, m.CurrentTable(stmt)).Error
+		}
 		return m.DB.Exec("DROP INDEX ? ON ?", clause.Column{Name: name}, m.CurrentTable(stmt)).Error
 	})
 }
@@ -613,14 +835,14 @@ func (m Migrator) DropIndex(value interface{}, name string) error {
 func (m Migrator) HasIndex(value interface{}, name string) bool {
 	var count int64
 	m.RunWithValue(value, func(stmt *gorm.Statement) error {
-		currentDatabase := m.DB.Migrator().CurrentDatabase()
+		namespace := m.namespace(stmt)
 		if idx := stmt.Schema.LookIndex(name); idx != nil {
 			name = idx.Name
 		}
 
 		return m.DB.Raw(
 			"SELECT count(*) FROM information_schema.statistics WHERE table_schema = ? AND table_name = ? AND index_name = ?",
-			currentDatabase, stmt.Table, name,
+			namespace, stmt.Table, name,
 		).Row().Scan(&count)
 	})
 
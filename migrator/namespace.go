@@ -0,0 +1,171 @@
+package migrator
+
+import (
+	"database/sql"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TableType table type of database, implements gorm.TableType
+type TableType struct {
+	SchemaValue  string
+	NameValue    string
+	TypeValue    string
+	CommentValue sql.NullString
+}
+
+// Schema returns schema of table
+func (t TableType) Schema() string {
+	return t.SchemaValue
+}
+
+// Name returns name of table
+func (t TableType) Name() string {
+	return t.NameValue
+}
+
+// Type returns type of table, e.g: `BASE TABLE`, `VIEW`
+func (t TableType) Type() string {
+	return t.TypeValue
+}
+
+// Comment returns comment of table
+func (t TableType) Comment() (comment string, ok bool) {
+	return t.CommentValue.String, t.CommentValue.Valid
+}
+
+// TableType returns schema, name, type and comment of table in database
+func (m Migrator) TableType(value interface{}) (tableType gorm.TableType, err error) {
+	err = m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		result := TableType{SchemaValue: m.namespace(stmt), NameValue: stmt.Table}
+
+		var err error
+		switch m.Dialector.Name() {
+		case "sqlite":
+			if result.SchemaValue == "" {
+				result.SchemaValue = "main"
+			}
+			err = m.DB.Raw(
+				"SELECT CASE type WHEN 'table' THEN 'BASE TABLE' ELSE 'VIEW' END FROM "+m.sqliteMaster(stmt)+" WHERE type IN ('table', 'view') AND name = ?",
+				stmt.Table,
+			).Row().Scan(&result.TypeValue)
+		case "postgres":
+			err = m.DB.Raw(
+				"SELECT table_schema, table_type, obj_description((quote_ident(table_schema) || '.' || quote_ident(table_name))::regclass, 'pg_class') FROM information_schema.tables WHERE table_schema = ? AND table_name = ?",
+				m.postgresNamespace(stmt), stmt.Table,
+			).Row().Scan(&result.SchemaValue, &result.TypeValue, &result.CommentValue)
+		case "mysql":
+			err = m.DB.Raw(
+				"SELECT table_type, table_comment FROM information_schema.tables WHERE table_schema = ? AND table_name = ?",
+				result.SchemaValue, stmt.Table,
+			).Row().Scan(&result.TypeValue, &result.CommentValue)
+		default:
+			err = m.DB.Raw(
+				"SELECT table_schema, table_type FROM information_schema.tables WHERE table_schema = COALESCE(?, SCHEMA_NAME()) AND table_name = ?",
+				sql.NullString{String: stmt.Namespace, Valid: stmt.Namespace != ""}, stmt.Table,
+			).Row().Scan(&result.SchemaValue, &result.TypeValue)
+		}
+
+		tableType = result
+		return err
+	})
+	return
+}
+
+// namespace returns schema of table, returns current database if it is not specified, except for sqlite and postgres
+func (m Migrator) namespace(stmt *gorm.Statement) string {
+	if stmt.Namespace != "" {
+		return stmt.Namespace
+	}
+
+	switch m.Dialector.Name() {
+	case "sqlite", "postgres":
+		return ""
+	}
+	return m.DB.Migrator().CurrentDatabase()
+}
+
+// postgresNamespace returns schema of table for postgres, CURRENT_SCHEMA() if it is not specified
+func (m Migrator) postgresNamespace(stmt *gorm.Statement) interface{} {
+	if stmt.Namespace != "" {
+		return stmt.Namespace
+	}
+	return clause.Expr{SQL: "CURRENT_SCHEMA()"}
+}
+
+// sqliteMaster returns sqlite_master table of attached database of table
+func (m Migrator) sqliteMaster(stmt *gorm.Statement) string {
+	if stmt.Namespace != "" {
+		return stmt.Quote(stmt.Namespace) + ".sqlite_master"
+	}
+	return "sqlite_master"
+}
+
+// sqlitePragma returns PRAGMA statement of attached database of table
+func (m Migrator) sqlitePragma(stmt *gorm.Statement, pragma string) string {
+	if stmt.Namespace != "" {
+		return "PRAGMA " + stmt.Quote(stmt.Namespace) + "." + pragma
+	}
+	return "PRAGMA " + pragma
+}
+
+// namespaced returns true if table of value is qualified with schema
+func (m Migrator) namespaced(value interface{}) (namespaced bool) {
+	m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		namespaced = stmt.Namespace != ""
+		return nil
+	})
+	return
+}
+
+// createNamespace creates schema of table if it doesn't exist, sqlite databases should be attached before migrating
+func (m Migrator) createNamespace(stmt *gorm.Statement) error {
+	if stmt.Namespace == "" {
+		return nil
+	}
+
+	switch m.Dialector.Name() {
+	case "postgres":
+		return m.DB.Exec("CREATE SCHEMA IF NOT EXISTS ?", clause.Table{Name: stmt.Namespace}).Error
+	case "mysql":
+		return m.DB.Exec("CREATE DATABASE IF NOT EXISTS ?", clause.Table{Name: stmt.Namespace}).Error
+	case "sqlserver":
+		return m.DB.Exec(fmt.Sprintf("IF SCHEMA_ID(?) IS NULL EXEC('CREATE SCHEMA %s')", stmt.Quote(stmt.Namespace)), stmt.Namespace).Error
+	}
+	return nil
+}
+
+// hasTable checks table exists, tables of attached databases are looked up in their sqlite_master for sqlite,
+// as HasTable of the driver only checks the main database
+func (m Migrator) hasTable(tx *gorm.DB, value interface{}) (found bool) {
+	m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		if m.Dialector.Name() != "sqlite" || stmt.Namespace == "" {
+			found = tx.Migrator().HasTable(value)
+			return nil
+		}
+
+		var count int64
+		err := m.DB.Raw("SELECT count(*) FROM "+m.sqliteMaster(stmt)+" WHERE type = ? AND name = ?", "table", stmt.Table).Row().Scan(&count)
+		found = count > 0
+		return err
+	})
+	return
+}
+
+// hasIndex checks index exists, check hasTable for details
+func (m Migrator) hasIndex(tx *gorm.DB, value interface{}, name string) (found bool) {
+	m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		if m.Dialector.Name() != "sqlite" || stmt.Namespace == "" {
+			found = tx.Migrator().HasIndex(value, name)
+			return nil
+		}
+
+		var count int64
+		err := m.DB.Raw("SELECT count(*) FROM "+m.sqliteMaster(stmt)+" WHERE type = ? AND tbl_name = ? AND name = ?", "index", stmt.Table, name).Row().Scan(&count)
+		found = count > 0
+		return err
+	})
+	return
+}
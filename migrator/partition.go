@@ -0,0 +1,122 @@
+package migrator
+
+import (
+	"fmt"
+	"regexp"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// partitionClause returns `PARTITION BY` clause of partitioned table, only postgres supports declarative partitioning,
+// partitions of other dialects are tables with the same structure
+func (m Migrator) partitionClause(stmt *gorm.Statement) (sql string, values []interface{}) {
+	if spec := stmt.Schema.Partition; spec != nil && m.DB.Capabilities().Partition == gorm.SyntaxPostgres {
+		var columns []interface{}
+		for _, column := range spec.Columns {
+			columns = append(columns, clause.Column{Name: column})
+		}
+		return " PARTITION BY " + string(spec.Type) + " ?", []interface{}{columns}
+	}
+	return "", nil
+}
+
+// CreatePartition creates partition of partitioned table if not exists, partitions of postgres are attached with bounds,
+// mysql and sqlite partitions are tables with the same structure, use PartitionRouter to route statements to them
+//    db.Migrator().(gorm.PartitionMigratorInterface).CreatePartition(&Event{}, gorm.MonthlyPartition("events", time.Now()))
+func (m Migrator) CreatePartition(value interface{}, partition gorm.Partition) error {
+	partitions, ok := m.DB.Migrator().(gorm.PartitionMigratorInterface)
+	if !ok {
+		partitions = m
+	}
+
+	if partitions.HasPartition(value, partition.Name) {
+		return nil
+	}
+
+	return m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		if stmt.Schema == nil || stmt.Schema.Partition == nil {
+			return fmt.Errorf("%w: %v is not partitioned", gorm.ErrInvalidData, stmt.Table)
+		}
+
+		switch m.DB.Capabilities().Partition {
+		case gorm.SyntaxPostgres:
+			sql, values := "CREATE TABLE ? PARTITION OF ?", []interface{}{clause.Table{Name: partition.Name}, m.CurrentTable(stmt)}
+			switch {
+			case stmt.Schema.Partition.Type == schema.PartitionRange && partition.From != nil:
+				sql += " FOR VALUES FROM (?) TO (?)"
+				values = append(values, partition.From, partition.To)
+			case stmt.Schema.Partition.Type == schema.PartitionList && len(partition.Values) > 0:
+				sql += " FOR VALUES IN ?"
+				values = append(values, partition.Values)
+			case stmt.Schema.Partition.Type == schema.PartitionHash && partition.Modulus > 0:
+				sql += fmt.Sprintf(" FOR VALUES WITH (MODULUS %d, REMAINDER %d)", partition.Modulus, partition.Remainder)
+			default:
+				sql += " DEFAULT"
+			}
+			return m.DB.Exec(sql, values...).Error
+		case gorm.SyntaxMySQL:
+			return m.DB.Exec("CREATE TABLE ? LIKE ?", clause.Table{Name: partition.Name}, m.CurrentTable(stmt)).Error
+		case gorm.SyntaxSQLite:
+			// copy definitions of table and its indexes, index names are prefixed with partition name
+			var definitions []struct {
+				Type string
+				SQL  string
+			}
+
+			if err := m.DB.Raw("SELECT type, sql FROM sqlite_master WHERE tbl_name = ? AND sql IS NOT NULL ORDER BY type DESC", stmt.Table).Scan(&definitions).Error; err != nil {
+				return err
+			}
+
+			if len(definitions) == 0 {
+				return fmt.Errorf("%w: table %v", gorm.ErrRecordNotFound, stmt.Table)
+			}
+
+			table := "[`\"]?" + regexp.QuoteMeta(stmt.Table) + "[`\"]?"
+			tableRegexp := regexp.MustCompile(`(?i)^(CREATE TABLE\s+)` + table)
+			indexRegexp := regexp.MustCompile(`(?i)^(CREATE (?:UNIQUE )?INDEX\s+)[` + "`" + `"]?(\w+)[` + "`" + `"]?(\s+ON\s+)` + table)
+			for _, definition := range definitions {
+				sql := definition.SQL
+				if definition.Type == "table" {
+					sql = tableRegexp.ReplaceAllStringFunc(sql, func(s string) string {
+						return tableRegexp.FindStringSubmatch(s)[1] + m.DB.Statement.Quote(partition.Name)
+					})
+				} else {
+					sql = indexRegexp.ReplaceAllStringFunc(sql, func(s string) string {
+						matches := indexRegexp.FindStringSubmatch(s)
+						return matches[1] + m.DB.Statement.Quote(partition.Name+"_"+matches[2]) + matches[3] + m.DB.Statement.Quote(partition.Name)
+					})
+				}
+
+				if err := m.DB.Exec(sql).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		return fmt.Errorf("%w: partitions of %v", gorm.ErrNotImplemented, m.Dialector.Name())
+	})
+}
+
+// DropPartition drops partition name of partitioned table
+func (m Migrator) DropPartition(value interface{}, name string) error {
+	return m.DB.Exec("DROP TABLE IF EXISTS ?", clause.Table{Name: name}).Error
+}
+
+// HasPartition returns true if partition name of partitioned table exists
+func (m Migrator) HasPartition(value interface{}, name string) bool {
+	if m.DB.Capabilities().Partition != gorm.SyntaxPostgres {
+		return m.DB.Migrator().HasTable(name)
+	}
+
+	var count int64
+	m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		return m.DB.Raw(
+			"SELECT count(*) FROM pg_inherits i JOIN pg_class c ON c.oid = i.inhrelid JOIN pg_class p ON p.oid = i.inhparent "+
+				"JOIN pg_namespace n ON n.oid = c.relnamespace WHERE n.nspname = CURRENT_SCHEMA() AND p.relname = ? AND c.relname = ?",
+			stmt.Table, name,
+		).Row().Scan(&count)
+	})
+	return count > 0
+}
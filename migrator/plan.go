@@ -0,0 +1,91 @@
+package migrator
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+
+	"gorm.io/gorm"
+)
+
+// Plan returns DDL statements AutoMigrate would execute for values without running them,
+// database is introspected as usual, statements are recorded instead of executed
+//    plan, err := db.Migrator().(gorm.MigrationPlannerInterface).Plan(&User{}, &Pet{})
+//    fmt.Print(plan.Diff())
+func (m Migrator) Plan(values ...interface{}) (plan gorm.MigrationPlan, err error) {
+	plan.Statements, err = m.record(func(tx *gorm.DB) error {
+		return tx.Migrator().AutoMigrate(values...)
+	})
+	return
+}
+
+// ScriptTo writes CREATE statements of tables, indexes and constraints of values for current dialect to w,
+// tables are created in order of dependencies like AutoMigrate, database is not introspected and changed
+//    err := db.Migrator().(gorm.MigrationPlannerInterface).ScriptTo(os.Stdout, &User{}, &Pet{})
+func (m Migrator) ScriptTo(w io.Writer, values ...interface{}) error {
+	statements, err := m.record(func(tx *gorm.DB) error {
+		for _, value := range m.ReorderModels(values, true) {
+			if err := tx.Migrator().CreateTable(value); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = gorm.MigrationPlan{Statements: statements}.WriteTo(w)
+	return err
+}
+
+// record runs fc with session recording executed statements instead of running them
+func (m Migrator) record(fc func(tx *gorm.DB) error) ([]string, error) {
+	ctx := m.DB.Statement.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	// session with context clones statement, so ConnPool of m.DB is not changed
+	recorder := &planRecorder{ConnPool: m.DB.Statement.ConnPool, dialector: m.Dialector}
+	tx := m.DB.Session(&gorm.Session{Context: ctx})
+	// recorded statements are not executed, migration hooks are disabled
+	config := *tx.Config
+	config.MigrationHook = nil
+	tx.Config = &config
+	// statements are recorded by recorder, they are not switched to tenants or replicas
+	tx.Statement.ConnPool, tx.Statement.InTransaction = recorder, true
+
+	err := fc(tx)
+	return recorder.statements, err
+}
+
+// planRecorder records executed statements, queries are passed to ConnPool
+type planRecorder struct {
+	gorm.ConnPool
+	dialector  gorm.Dialector
+	statements []string
+}
+
+func (recorder *planRecorder) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	recorder.statements = append(recorder.statements, recorder.dialector.Explain(query, args...))
+	return driver.RowsAffected(0), nil
+}
+
+func (recorder *planRecorder) BeginTx(ctx context.Context, opts *sql.TxOptions) (gorm.ConnPool, error) {
+	return &planTx{planRecorder: recorder}, nil
+}
+
+// planTx transaction of planRecorder, nothing to commit or rollback
+type planTx struct {
+	*planRecorder
+}
+
+func (planTx) Commit() error {
+	return nil
+}
+
+func (planTx) Rollback() error {
+	return nil
+}
@@ -0,0 +1,131 @@
+package migrator
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+var triggerReturnRegexp = regexp.MustCompile(`(?i)\bRETURN\b`)
+
+// CreateTrigger creates trigger of table, check gorm.Trigger for details
+func (m Migrator) CreateTrigger(value interface{}, trigger gorm.Trigger) error {
+	return m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		var (
+			name  = stmt.Quote(trigger.Name)
+			table = m.quoteTable(stmt)
+			body  = trigger.Body
+		)
+
+		syntax := m.DB.Capabilities().Trigger
+		if b, ok := trigger.Bodies[m.Dialector.Name()]; ok {
+			body = b
+		} else if b, ok := trigger.Bodies[string(syntax)]; ok {
+			body = b
+		}
+
+		switch syntax {
+		case gorm.SyntaxPostgres:
+			// trigger function returns OLD for DELETE events, returned row is ignored by AFTER triggers
+			if !triggerReturnRegexp.MatchString(body) {
+				if strings.EqualFold(strings.TrimSpace(trigger.Event), "DELETE") {
+					body += " RETURN OLD;"
+				} else {
+					body += " RETURN NEW;"
+				}
+			}
+
+			if err := m.DB.Exec(fmt.Sprintf(
+				"CREATE OR REPLACE FUNCTION %s() RETURNS trigger AS $$ BEGIN %s END $$ LANGUAGE plpgsql", name, body,
+			)).Error; err != nil {
+				return err
+			}
+
+			return m.DB.Exec(fmt.Sprintf(
+				"CREATE TRIGGER %s %s %s ON %s FOR EACH ROW EXECUTE PROCEDURE %s()", name, trigger.Timing, trigger.Event, table, name,
+			)).Error
+		case gorm.SyntaxSQLServer:
+			return m.DB.Exec(fmt.Sprintf("CREATE TRIGGER %s ON %s %s %s AS BEGIN %s END", name, table, trigger.Timing, trigger.Event, body)).Error
+		}
+
+		return m.DB.Exec(fmt.Sprintf(
+			"CREATE TRIGGER %s %s %s ON %s FOR EACH ROW BEGIN %s END", name, trigger.Timing, trigger.Event, table, body,
+		)).Error
+	})
+}
+
+// DropTrigger drops trigger name of table, functions of postgres triggers are dropped with them
+func (m Migrator) DropTrigger(value interface{}, name string) error {
+	return m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		switch m.DB.Capabilities().Trigger {
+		case gorm.SyntaxPostgres:
+			if err := m.DB.Exec(fmt.Sprintf("DROP TRIGGER IF EXISTS %s ON %s", stmt.Quote(name), m.quoteTable(stmt))).Error; err != nil {
+				return err
+			}
+			return m.DB.Exec(fmt.Sprintf("DROP FUNCTION IF EXISTS %s()", stmt.Quote(name))).Error
+		case gorm.SyntaxMySQL:
+			return m.DB.Exec("DROP TRIGGER IF EXISTS " + stmt.Quote(name)).Error
+		}
+		return m.DB.Exec("DROP TRIGGER " + stmt.Quote(name)).Error
+	})
+}
+
+// HasTrigger returns true if trigger name of table exists
+func (m Migrator) HasTrigger(value interface{}, name string) bool {
+	var count int64
+	m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		switch m.DB.Capabilities().Trigger {
+		case gorm.SyntaxSQLite:
+			return m.DB.Raw("SELECT count(*) FROM sqlite_master WHERE type = ? AND tbl_name = ? AND name = ?", "trigger", stmt.Table, name).Row().Scan(&count)
+		case gorm.SyntaxPostgres:
+			return m.DB.Raw(
+				"SELECT count(*) FROM information_schema.triggers WHERE event_object_schema = CURRENT_SCHEMA() AND event_object_table = ? AND trigger_name = ?",
+				stmt.Table, name,
+			).Row().Scan(&count)
+		case gorm.SyntaxSQLServer:
+			return m.DB.Raw("SELECT count(*) FROM sys.triggers WHERE parent_id = OBJECT_ID(?) AND name = ?", stmt.Table, name).Row().Scan(&count)
+		}
+
+		return m.DB.Raw(
+			"SELECT count(*) FROM information_schema.triggers WHERE trigger_schema = ? AND event_object_table = ? AND trigger_name = ?",
+			m.DB.Migrator().CurrentDatabase(), stmt.Table, name,
+		).Row().Scan(&count)
+	})
+	return count > 0
+}
+
+// quoteTable returns quoted current table of stmt
+func (m Migrator) quoteTable(stmt *gorm.Statement) string {
+	if stmt.TableExpr != nil {
+		return stmt.TableExpr.SQL
+	}
+	return stmt.Quote(stmt.Table)
+}
+
+// migrateTriggers creates missing triggers declared by model with gorm.TriggersInterface
+func (m Migrator) migrateTriggers(tx *gorm.DB, value interface{}) error {
+	triggers, ok := value.(gorm.TriggersInterface)
+	if !ok {
+		return nil
+	}
+
+	migrator := m.triggerMigrator(tx)
+	for _, trigger := range triggers.Triggers() {
+		if !migrator.HasTrigger(value, trigger.Name) {
+			if err := migrator.CreateTrigger(value, trigger); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// triggerMigrator returns migrator of dialector if it implements gorm.TriggerMigratorInterface, m otherwise
+func (m Migrator) triggerMigrator(tx *gorm.DB) gorm.TriggerMigratorInterface {
+	if triggers, ok := tx.Migrator().(gorm.TriggerMigratorInterface); ok {
+		return triggers
+	}
+	return m
+}
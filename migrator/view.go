@@ -0,0 +1,98 @@
+package migrator
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CreateView creates view name with option.Query, materialized views are only supported by databases of Capabilities.MaterializedView, e.g: postgres
+//    db.Migrator().CreateView("user_stats", gorm.ViewOption{Query: db.Model(&User{}).Select("role, count(*) AS total").Group("role"), Materialized: true})
+func (m Migrator) CreateView(name string, option gorm.ViewOption) error {
+	if option.Query == nil {
+		return gorm.ErrSubQueryRequired
+	}
+
+	if option.Materialized && !m.DB.Capabilities().MaterializedView {
+		return fmt.Errorf("%w: materialized view of %v", gorm.ErrNotImplemented, m.Dialector.Name())
+	}
+
+	sql := new(strings.Builder)
+	sql.WriteString("CREATE ")
+	if option.Replace && !option.Materialized {
+		sql.WriteString("OR REPLACE ")
+	}
+
+	if option.Materialized {
+		sql.WriteString("MATERIALIZED ")
+	}
+	sql.WriteString("VIEW ")
+	m.QuoteTo(sql, name)
+	sql.WriteString(" AS ")
+
+	stmt := &gorm.Statement{DB: m.DB}
+	stmt.AddVar(sql, option.Query)
+	if option.CheckOption != "" && !option.Materialized {
+		sql.WriteString(" ")
+		sql.WriteString(option.CheckOption)
+	}
+
+	tx := m.DB.Session(&gorm.Session{})
+	if option.Replace && option.Materialized {
+		if err := tx.Exec("DROP MATERIALIZED VIEW IF EXISTS ?", clause.Table{Name: name}).Error; err != nil {
+			return err
+		}
+	}
+	return tx.Exec(m.Explain(sql.String(), stmt.Vars...)).Error
+}
+
+// DropView drops view name, materialized views are dropped with `DROP MATERIALIZED VIEW`
+func (m Migrator) DropView(name string) error {
+	if m.hasMaterializedView(name) {
+		return m.DB.Exec("DROP MATERIALIZED VIEW IF EXISTS ?", clause.Table{Name: name}).Error
+	}
+	return m.DB.Exec("DROP VIEW IF EXISTS ?", clause.Table{Name: name}).Error
+}
+
+// RefreshView refreshes data of materialized view name, refreshing concurrently won't lock out queries against the view,
+// it requires a unique index on the view and is ignored if not supported
+func (m Migrator) RefreshView(name string, concurrently bool) error {
+	if !m.DB.Capabilities().MaterializedView {
+		return fmt.Errorf("%w: materialized view of %v", gorm.ErrNotImplemented, m.Dialector.Name())
+	}
+
+	if concurrently {
+		return m.DB.Exec("REFRESH MATERIALIZED VIEW CONCURRENTLY ?", clause.Table{Name: name}).Error
+	}
+	return m.DB.Exec("REFRESH MATERIALIZED VIEW ?", clause.Table{Name: name}).Error
+}
+
+func (m Migrator) hasMaterializedView(name string) bool {
+	var count int64
+	if m.DB.Capabilities().MaterializedView {
+		m.DB.Raw("SELECT count(*) FROM pg_matviews WHERE schemaname = CURRENT_SCHEMA() AND matviewname = ?", name).Row().Scan(&count)
+	}
+	return count > 0
+}
+
+// migrateMaterializedView migrates model backed by materialized view, the view is created with CreateView,
+// missing indexes of model are created if the view exists, returns false if value is not backed by materialized view
+func (m Migrator) migrateMaterializedView(tx *gorm.DB, value interface{}) (materialized bool, err error) {
+	err = m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		if materialized = stmt.Schema != nil && stmt.Schema.MaterializedView; !materialized || !m.hasMaterializedView(stmt.Table) {
+			return nil
+		}
+
+		for _, idx := range stmt.Schema.ParseIndexes() {
+			if !tx.Migrator().HasIndex(value, idx.Name) {
+				if err := tx.Migrator().CreateIndex(value, idx.Name); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	return
+}
@@ -0,0 +1,265 @@
+package gorm
+
+import (
+	"bufio"
+	"bytes"
+	"database/sql"
+	"fmt"
+	"go/ast"
+	"io/fs"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm/clause"
+)
+
+// QueryResult expected result of named query
+type QueryResult string
+
+const (
+	// QueryMany query returns any number of rows, it is the default
+	QueryMany QueryResult = "many"
+	// QueryOne query returns one row, ErrRecordNotFound is returned if there is no row
+	QueryOne QueryResult = "one"
+	// QueryExec statement returns no rows, it is executed by NamedQuery
+	QueryExec QueryResult = "exec"
+)
+
+// NamedSQL named query parsed from SQL file, Params are named params in SQL, e.g: @active
+type NamedSQL struct {
+	Name   string
+	File   string
+	Doc    string
+	SQL    string
+	Result QueryResult
+	Params []string
+}
+
+// Queries named queries loaded from SQL files, queries are declared by `-- name: <name> [:one|:many|:exec]` comments,
+// comments between it and SQL of the query are docs of the query
+//    -- name: find_active_users :many
+//    -- active users older than age
+//    SELECT * FROM users WHERE active = @active AND age > @age;
+//
+//    queries, err := gorm.LoadQueries(os.DirFS("queries"), "*.sql")
+//    db, err := gorm.Open(sqlite.Open("gorm.db"), &gorm.Config{Queries: queries})
+//    db.NamedQuery("find_active_users", map[string]interface{}{"active": true, "age": 18}).Find(&users)
+type Queries struct {
+	queries map[string]*NamedSQL
+}
+
+// LoadQueries loads named queries of files matching patterns in fsys, returns ErrRegistered if names are duplicated
+func LoadQueries(fsys fs.FS, patterns ...string) (*Queries, error) {
+	queries := &Queries{queries: map[string]*NamedSQL{}}
+	for _, pattern := range patterns {
+		files, err := fs.Glob(fsys, pattern)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, file := range files {
+			data, err := fs.ReadFile(fsys, file)
+			if err != nil {
+				return nil, err
+			}
+
+			if err := queries.parse(file, data); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return queries, nil
+}
+
+// parse parses named queries of SQL file
+func (queries *Queries) parse(file string, data []byte) error {
+	var (
+		query   *NamedSQL
+		doc     []string
+		sql     []string
+		scanner = bufio.NewScanner(bytes.NewReader(data))
+	)
+
+	finish := func() error {
+		if query == nil {
+			return nil
+		}
+
+		query.Doc = strings.Join(doc, "\n")
+		query.SQL = strings.TrimSuffix(strings.TrimSpace(strings.Join(sql, "\n")), ";")
+		if query.SQL == "" {
+			return fmt.Errorf("%w: query %v of %v has no SQL", ErrInvalidData, query.Name, file)
+		} else if _, ok := queries.queries[query.Name]; ok {
+			return fmt.Errorf("%w: query %v of %v", ErrRegistered, query.Name, file)
+		}
+
+		query.Params = namedParams(query.SQL)
+		queries.queries[query.Name] = query
+		return nil
+	}
+
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if comment := strings.TrimSpace(strings.TrimPrefix(text, "--")); strings.HasPrefix(text, "--") && strings.HasPrefix(comment, "name:") {
+			if err := finish(); err != nil {
+				return err
+			}
+
+			fields := strings.Fields(strings.TrimPrefix(comment, "name:"))
+			if len(fields) == 0 || len(fields) > 2 {
+				return fmt.Errorf("%w: invalid query declaration at %v:%d", ErrInvalidData, file, line)
+			}
+
+			query, doc, sql = &NamedSQL{Name: fields[0], File: file, Result: QueryMany}, nil, nil
+			if len(fields) == 2 {
+				switch result := QueryResult(strings.TrimPrefix(fields[1], ":")); result {
+				case QueryMany, QueryOne, QueryExec:
+					query.Result = result
+				default:
+					return fmt.Errorf("%w: invalid result %v of query %v at %v:%d", ErrInvalidData, fields[1], query.Name, file, line)
+				}
+			}
+		} else if query != nil && len(sql) == 0 && strings.HasPrefix(text, "--") {
+			doc = append(doc, comment)
+		} else if query != nil && (len(sql) > 0 || text != "") {
+			sql = append(sql, scanner.Text())
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return finish()
+}
+
+// namedParams returns names of params in sql in order, quoted strings, `--` comments and variables like @@version are skipped
+func namedParams(sql string) (params []string) {
+	var (
+		quote byte
+		seen  = map[string]bool{}
+	)
+
+	for i := 0; i < len(sql); i++ {
+		switch c := sql[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '-' && i+1 < len(sql) && sql[i+1] == '-':
+			for i++; i+1 < len(sql) && sql[i+1] != '\n'; i++ {
+			}
+		case c == '@' && i+1 < len(sql) && sql[i+1] == '@':
+			for i++; i+1 < len(sql) && isParamChar(sql[i+1]); i++ {
+			}
+		case c == '@':
+			j := i + 1
+			for j < len(sql) && isParamChar(sql[j]) {
+				j++
+			}
+
+			if name := sql[i+1 : j]; name != "" && !seen[name] {
+				seen[name] = true
+				params = append(params, name)
+			}
+			i = j - 1
+		}
+	}
+	return
+}
+
+func isParamChar(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// namedArgs returns names of named args, keys of maps and exported fields of structs like clause.NamedExpr
+func namedArgs(args []interface{}) map[string]bool {
+	names := map[string]bool{}
+
+	var appendFields func(reflect.Value)
+	appendFields = func(value reflect.Value) {
+		if value = reflect.Indirect(value); value.Kind() == reflect.Struct {
+			for i := 0; i < value.NumField(); i++ {
+				if field := value.Type().Field(i); ast.IsExported(field.Name) {
+					names[field.Name] = true
+					if field.Anonymous {
+						appendFields(value.Field(i))
+					}
+				}
+			}
+		}
+	}
+
+	for _, arg := range args {
+		switch v := arg.(type) {
+		case sql.NamedArg:
+			names[v.Name] = true
+		case map[string]interface{}:
+			for k := range v {
+				names[k] = true
+			}
+		default:
+			appendFields(reflect.ValueOf(arg))
+		}
+	}
+	return names
+}
+
+// Get returns named query, nil if it is not loaded
+func (queries *Queries) Get(name string) *NamedSQL {
+	if queries == nil {
+		return nil
+	}
+	return queries.queries[name]
+}
+
+// Names returns sorted names of loaded queries
+func (queries *Queries) Names() []string {
+	if queries == nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(queries.queries))
+	for name := range queries.queries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NamedQuery returns raw query of named query loaded in Config.Queries with args, args are named args, maps or structs
+// for named params, queries returning rows are scanned with Find or Scan, statements of `:exec` are executed
+//    db.NamedQuery("find_active_users", map[string]interface{}{"active": true, "age": 18}).Find(&users)
+//    db.NamedQuery("find_user", sql.Named("id", 1)).Scan(&user) // ErrRecordNotFound if not found for `:one`
+//    db.NamedQuery("deactivate_users", map[string]interface{}{"before": lastYear}).RowsAffected
+func (db *DB) NamedQuery(name string, args ...interface{}) (tx *DB) {
+	tx = db.getInstance()
+	query := tx.Queries.Get(name)
+	if query == nil {
+		tx.AddError(fmt.Errorf("%w: %v", ErrNamedQueryNotFound, name))
+		return
+	}
+
+	if len(query.Params) > 0 {
+		names := namedArgs(args)
+		for _, param := range query.Params {
+			if !names[param] {
+				tx.AddError(fmt.Errorf("%w: param %v of query %v is missing", ErrInvalidData, param, name))
+				return
+			}
+		}
+	}
+
+	tx.Statement.SQL = strings.Builder{}
+	clause.NamedExpr{SQL: query.SQL, Vars: args}.Build(tx.Statement)
+
+	switch query.Result {
+	case QueryOne:
+		tx.Statement.RaiseErrorOnNotFound = true
+	case QueryExec:
+		tx.callbacks.Raw().Execute(tx)
+	}
+	return
+}
@@ -0,0 +1,136 @@
+// Package nplusone detects N+1 queries in development, queries of the same shape executed repeatedly with different
+// single values in a scope, e.g: loading associations of records one by one in a loop, are reported via logger
+//    db.Use(nplusone.New(nplusone.Config{Threshold: 5}))
+//    ctx := nplusone.WithScope(r.Context()) // scope of request
+//    db.WithContext(ctx).Find(&users)
+package nplusone
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/utils"
+)
+
+// PluginName name of the plugin, its callback is registered as `nplusone:detect` after queries
+const PluginName = "gorm:nplusone"
+
+// DefaultThreshold default number of executions with different values reported as N+1 queries
+const DefaultThreshold = 5
+
+// Report N+1 query found in scope
+type Report struct {
+	SQL         string   // SQL of the query
+	Fingerprint string   // fingerprint of the query, check logger.Fingerprint
+	Count       int      // executions with different values
+	CallSites   []string // distinct call sites of the executions
+}
+
+// Config config of the plugin
+type Config struct {
+	// Threshold queries executed more than Threshold times with different values are reported, DefaultThreshold if zero
+	Threshold int
+	// Reporter reports N+1 queries, logs them with Logger of db as warnings if nil
+	Reporter func(ctx context.Context, report Report)
+}
+
+// Detector N+1 query detector, implements gorm.Plugin
+type Detector struct {
+	Config
+}
+
+// New returns N+1 query detector with config
+func New(config Config) *Detector {
+	if config.Threshold <= 0 {
+		config.Threshold = DefaultThreshold
+	}
+	return &Detector{Config: config}
+}
+
+// Name returns name of the plugin
+func (d *Detector) Name() string {
+	return PluginName
+}
+
+// Initialize registers callback detecting N+1 queries
+func (d *Detector) Initialize(db *gorm.DB) error {
+	return db.Callback().Query().After("*").Register("nplusone:detect", d.detect)
+}
+
+type scopeKey struct{}
+
+// scope queries tracked in scope
+type scope struct {
+	mu      sync.Mutex
+	queries map[string]*query
+}
+
+// query executions of query shape
+type query struct {
+	values    map[string]bool
+	callSites []string
+	reported  bool
+}
+
+// WithScope returns context tracking queries in a new scope, e.g: scope of a request, queries of contexts without scope are not tracked
+func WithScope(ctx context.Context) context.Context {
+	return context.WithValue(ctx, scopeKey{}, &scope{queries: map[string]*query{}})
+}
+
+func (d *Detector) detect(db *gorm.DB) {
+	stmt := db.Statement
+	if db.Error != nil || stmt.Context == nil || stmt.SQL.Len() == 0 || len(stmt.Vars) != 1 {
+		return
+	}
+
+	s, ok := stmt.Context.Value(scopeKey{}).(*scope)
+	if !ok {
+		return
+	}
+
+	var (
+		sql         = stmt.SQL.String()
+		fingerprint = logger.Fingerprint(sql)
+		value       = fmt.Sprint(stmt.Vars[0])
+		callSite    = utils.FileWithLineNum()
+	)
+
+	s.mu.Lock()
+	q, ok := s.queries[fingerprint]
+	if !ok {
+		q = &query{values: map[string]bool{}}
+		s.queries[fingerprint] = q
+	}
+
+	q.values[value] = true
+	if !containsString(q.callSites, callSite) {
+		q.callSites = append(q.callSites, callSite)
+	}
+
+	var report *Report
+	if !q.reported && len(q.values) > d.Threshold {
+		q.reported = true
+		report = &Report{SQL: sql, Fingerprint: fingerprint, Count: len(q.values), CallSites: append([]string(nil), q.callSites...)}
+	}
+	s.mu.Unlock()
+
+	if report != nil {
+		if d.Reporter != nil {
+			d.Reporter(stmt.Context, *report)
+		} else {
+			db.Logger.Warn(stmt.Context, "N+1 query detected, executed %d times with different values: %s, call sites: %v", report.Count, report.SQL, report.CallSites)
+		}
+	}
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
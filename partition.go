@@ -0,0 +1,102 @@
+package gorm
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// Partition partition of partitioned table, bounds are decided by partition type of model,
+// partition without bounds is the default partition
+//    db.Migrator().(gorm.PartitionMigratorInterface).CreatePartition(&Event{}, gorm.Partition{Name: "events_2021", From: "2021-01-01", To: "2022-01-01"})
+type Partition struct {
+	Name string
+	// From, To bounds of range partition, From is inclusive, To is exclusive
+	From, To interface{}
+	// Values values of list partition
+	Values []interface{}
+	// Modulus, Remainder of hash partition
+	Modulus, Remainder int
+}
+
+// MonthlyPartition returns range partition `<table>_<yyyy>_<mm>` of the month of t
+func MonthlyPartition(table string, t time.Time) Partition {
+	from := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	return Partition{Name: fmt.Sprintf("%s_%04d_%02d", table, t.Year(), t.Month()), From: from, To: from.AddDate(0, 1, 0)}
+}
+
+// PartitionRouter routes statements of partitioned models to partitions, returns name of partition,
+// or empty string to use the partitioned table
+type PartitionRouter interface {
+	Route(stmt *Statement) (partition string, err error)
+}
+
+// MonthlyPartitionRouter routes statements with values of models to monthly partitions by time field Field,
+// partitions are created when AutoCreate is true, e.g: `Session(&gorm.Session{PartitionRouter: &gorm.MonthlyPartitionRouter{Field: "CreatedAt"}})`
+type MonthlyPartitionRouter struct {
+	Field      string
+	AutoCreate bool
+	created    sync.Map
+}
+
+// Route returns monthly partition of values, values of different months are not routed
+func (router *MonthlyPartitionRouter) Route(stmt *Statement) (string, error) {
+	field := stmt.Schema.LookUpField(router.Field)
+	if field == nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidField, router.Field)
+	}
+
+	var (
+		partition Partition
+		routed    bool
+		route     = func(rv reflect.Value) bool {
+			value, zero := field.ValueOf(rv)
+			t, ok := value.(time.Time)
+			if pt, isPtr := value.(*time.Time); isPtr && pt != nil {
+				t, ok = *pt, true
+			}
+
+			if !ok || zero || t.IsZero() {
+				return false
+			}
+
+			p := MonthlyPartition(stmt.Table, t)
+			if routed && p.Name != partition.Name {
+				return false
+			}
+			partition, routed = p, true
+			return true
+		}
+	)
+
+	switch rv := reflect.Indirect(stmt.ReflectValue); rv.Kind() {
+	case reflect.Struct:
+		route(rv)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			if !route(reflect.Indirect(rv.Index(i))) {
+				return "", nil
+			}
+		}
+	}
+
+	if !routed {
+		return "", nil
+	}
+
+	if router.AutoCreate {
+		if _, ok := router.created.Load(partition.Name); !ok {
+			partitions, ok := stmt.DB.Session(&Session{NewDB: true}).Migrator().(PartitionMigratorInterface)
+			if !ok {
+				return "", fmt.Errorf("%w: partitions are not supported by migrator of %v", ErrUnsupportedDriver, stmt.DB.Dialector.Name())
+			}
+
+			if err := partitions.CreatePartition(stmt.Model, partition); err != nil {
+				return "", err
+			}
+			router.created.Store(partition.Name, true)
+		}
+	}
+	return partition.Name, nil
+}
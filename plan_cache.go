@@ -0,0 +1,269 @@
+package gorm
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"reflect"
+	"strconv"
+	"sync"
+
+	"gorm.io/gorm/clause"
+)
+
+// planCacheSize max number of query shapes cached
+const planCacheSize = 1024
+
+// planCache built SQL of query shapes, keyed by shapes of clauses, table and number of preceding vars
+type planCache struct {
+	mu    sync.RWMutex
+	plans map[string]*plan
+}
+
+// planKeyPool buffers of keys of query shapes
+var planKeyPool = sync.Pool{New: func() interface{} {
+	buf := make([]byte, 0, 256)
+	return &buf
+}}
+
+// plan built SQL of query shape, it is invalid if vars of the shape can't be extracted without building
+type plan struct {
+	sql     string
+	vars    int
+	invalid bool
+}
+
+func (db *DB) planCache() *planCache {
+	if v, ok := db.cacheStore.Load("gorm:plan_cache"); ok {
+		return v.(*planCache)
+	}
+	v, _ := db.cacheStore.LoadOrStore("gorm:plan_cache", &planCache{plans: map[string]*plan{}})
+	return v.(*planCache)
+}
+
+// buildFromPlan builds clauses with cached SQL of their shape, returns false if the shape isn't cached, the shape is
+// cached after clauses are built by store
+func (stmt *Statement) buildFromPlan(clauses []string) (store func(), ok bool) {
+	buf := planKeyPool.Get().(*[]byte)
+	defer func() {
+		*buf = (*buf)[:0]
+		planKeyPool.Put(buf)
+	}()
+
+	start, sqlStart := len(stmt.Vars), stmt.SQL.Len()
+	key, cacheable := stmt.planKey((*buf)[:0], clauses)
+	*buf = key
+	if !cacheable {
+		stmt.Vars = stmt.Vars[:start]
+		return nil, false
+	}
+
+	cache := stmt.DB.planCache()
+	cache.mu.RLock()
+	p, ok := cache.plans[string(key)]
+	cache.mu.RUnlock()
+
+	if ok {
+		if !p.invalid && p.vars == len(stmt.Vars)-start {
+			stmt.SQL.WriteString(p.sql)
+			return nil, true
+		}
+		stmt.Vars = stmt.Vars[:start]
+		return nil, false
+	}
+
+	vars, keyString := len(stmt.Vars)-start, string(key)
+	stmt.Vars = stmt.Vars[:start]
+	return func() {
+		// vars extracted from the shape should be the same as vars added when building
+		p := &plan{sql: stmt.SQL.String()[sqlStart:], vars: vars, invalid: len(stmt.Vars)-start != vars}
+
+		cache.mu.Lock()
+		if len(cache.plans) < planCacheSize {
+			cache.plans[keyString] = p
+		}
+		cache.mu.Unlock()
+	}, false
+}
+
+// planKey returns key of shape of clauses and appends their vars to stmt.Vars, only SELECT, FROM without joins,
+// WHERE with plain conditions, ORDER BY columns and LIMIT are cacheable
+func (stmt *Statement) planKey(key []byte, clauses []string) ([]byte, bool) {
+	if stmt.redacting() || (stmt.TableExpr != nil && len(stmt.TableExpr.Vars) > 0) {
+		return key, false
+	}
+
+	key = append(append(key, stmt.Table...), '|')
+	if stmt.TableExpr != nil {
+		key = append(key, stmt.TableExpr.SQL...)
+	}
+	key = strconv.AppendInt(append(key, '|'), int64(len(stmt.Vars)), 10)
+
+	for _, name := range clauses {
+		c, ok := stmt.Clauses[name]
+		if !ok {
+			continue
+		} else if c.Builder != nil || c.BeforeExpression != nil || c.AfterNameExpression != nil || c.AfterExpression != nil {
+			return key, false
+		}
+
+		key = append(append(append(append(append(key, '|'), name...), ':'), c.Name...), ':')
+
+		switch expr := c.Expression.(type) {
+		case clause.Select:
+			if expr.Expression != nil {
+				return key, false
+			} else if expr.Distinct {
+				key = append(key, "DISTINCT "...)
+			}
+			for _, column := range expr.Columns {
+				key = appendPlanColumn(key, column)
+			}
+		case clause.From:
+			if len(expr.Joins) > 0 {
+				return key, false
+			}
+			for _, table := range expr.Tables {
+				key = append(append(append(append(key, table.Name...), ' '), table.Alias...), ',')
+			}
+		case clause.Where:
+			// clause builders might write vars of conditions differently, others are keyed by all their values
+			if _, ok := stmt.DB.ClauseBuilders[name]; ok {
+				return key, false
+			}
+
+			for _, e := range expr.Exprs {
+				if key, ok = stmt.appendPlanExpr(key, e); !ok {
+					return key, false
+				}
+			}
+		case clause.OrderBy:
+			if expr.Expression != nil {
+				return key, false
+			}
+			for _, column := range expr.Columns {
+				if key = appendPlanColumn(key, column.Column); column.Desc {
+					key = append(key, "DESC"...)
+				}
+			}
+		case clause.Limit:
+			key = strconv.AppendInt(append(strconv.AppendInt(key, int64(expr.Limit), 10), ','), int64(expr.Offset), 10)
+		default:
+			return key, false
+		}
+	}
+	return key, true
+}
+
+func appendPlanColumn(key []byte, column clause.Column) []byte {
+	key = append(append(append(append(append(key, column.Table...), '.'), column.Name...), ' '), column.Alias...)
+	if column.Raw {
+		key = append(key, " raw"...)
+	}
+	return append(key, ',')
+}
+
+// appendPlanExpr appends shape of condition to key and its vars to stmt.Vars, returns false if it isn't cacheable
+func (stmt *Statement) appendPlanExpr(key []byte, expr clause.Expression) ([]byte, bool) {
+	var (
+		column  interface{}
+		value   interface{}
+		op      string
+		notNull bool
+	)
+
+	switch e := expr.(type) {
+	case clause.Expr:
+		key = append(key, "expr "...)
+		if e.WithoutParentheses {
+			key = append(key, "np "...)
+		}
+		key = append(append(key, e.SQL...), ',')
+
+		idx := 0
+		for i := 0; i < len(e.SQL) && idx < len(e.Vars); i++ {
+			if e.SQL[i] == '?' {
+				if !planVar(e.Vars[idx]) {
+					return key, false
+				}
+				stmt.Vars = append(stmt.Vars, e.Vars[idx])
+				idx++
+			}
+		}
+		return key, true
+	case clause.IN:
+		if key, ok := appendPlanQuoted(append(key, "in "...), e.Column); ok {
+			key = append(strconv.AppendInt(key, int64(len(e.Values)), 10), ',')
+			for _, v := range e.Values {
+				if !planVar(v) {
+					return key, false
+				}
+			}
+			stmt.Vars = append(stmt.Vars, e.Values...)
+			return key, true
+		}
+		return key, false
+	case clause.Eq:
+		column, value, op, notNull = e.Column, e.Value, "=", true
+	case clause.Neq:
+		column, value, op, notNull = e.Column, e.Value, "<>", true
+	case clause.Gt:
+		column, value, op = e.Column, e.Value, ">"
+	case clause.Gte:
+		column, value, op = e.Column, e.Value, ">="
+	case clause.Lt:
+		column, value, op = e.Column, e.Value, "<"
+	case clause.Lte:
+		column, value, op = e.Column, e.Value, "<="
+	default:
+		return key, false
+	}
+
+	key, ok := appendPlanQuoted(key, column)
+	if !ok {
+		return key, false
+	}
+	key = append(key, op...)
+
+	if notNull {
+		// NULL of Eq and Neq is written as IS NULL, IS NOT NULL
+		if value == nil {
+			return append(key, " null,"...), true
+		} else if _, ok := value.(driver.Valuer); ok || reflect.ValueOf(value).Kind() == reflect.Ptr {
+			return key, false
+		}
+	}
+
+	if !planVar(value) {
+		return key, false
+	}
+	stmt.Vars = append(stmt.Vars, value)
+	return append(key, " ?,"...), true
+}
+
+func appendPlanQuoted(key []byte, column interface{}) ([]byte, bool) {
+	switch c := column.(type) {
+	case clause.Column:
+		return appendPlanColumn(key, c), true
+	case string:
+		return append(append(key, c...), ','), true
+	}
+	return key, false
+}
+
+// planVar returns true if v is added as one var when building
+func planVar(v interface{}) bool {
+	switch v.(type) {
+	case nil:
+		return true
+	case Valuer, clause.Expression, clause.Column, clause.Table, sql.NamedArg, []byte, []interface{}, *DB:
+		return false
+	case driver.Valuer:
+		return true
+	}
+
+	switch reflect.ValueOf(v).Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.Func, reflect.Chan:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,102 @@
+package gorm
+
+import (
+	"regexp"
+	"strings"
+)
+
+// RedactedValue replaces values of sensitive columns in logged SQL
+const RedactedValue = "[REDACTED]"
+
+var (
+	sensitiveColumnRegexp    = regexp.MustCompile("(?i)([\\w]+)[\"`\\]]?\\s*(?:=|<>|!=|<=|>=|<|>|\\bLIKE|\\bIN)\\s*\\(?\\s*$")
+	valuesPlaceholdersRegexp = regexp.MustCompile(`^[\s(),?$@pP:0-9]*$`)
+)
+
+// redaction sensitive vars of statement
+type redaction struct {
+	vars    map[int]bool // indexes of sensitive vars
+	columns []string     // columns of INSERT VALUES being built
+	offset  int          // end of SQL checked for VALUES
+}
+
+// redacting returns true if there are sensitive columns for statement, declared with field tag `sensitive` or SensitiveColumns
+func (stmt *Statement) redacting() bool {
+	return len(stmt.DB.SensitiveColumns) > 0 || (stmt.Schema != nil && len(stmt.Schema.SensitiveFields) > 0)
+}
+
+// sensitiveColumn returns true if column is sensitive
+func (stmt *Statement) sensitiveColumn(column string) bool {
+	column = strings.Trim(strings.TrimSpace(column), "\"`[]")
+	if idx := strings.LastIndex(column, "."); idx >= 0 {
+		column = strings.Trim(column[idx+1:], "\"`[]")
+	}
+
+	for _, c := range stmt.DB.SensitiveColumns {
+		if strings.EqualFold(c, column) {
+			return true
+		}
+	}
+
+	if stmt.Schema != nil {
+		if field := stmt.Schema.LookUpField(column); field != nil {
+			return field.Sensitive
+		}
+	}
+	return false
+}
+
+// sensitiveVar returns true if the idx-th value added to the end of SQL belongs to sensitive column, the column is
+// looked up from the condition or assignment before it, e.g: `password = ?`, or columns list of INSERT VALUES
+func (stmt *Statement) sensitiveVar(idx int) bool {
+	if stmt.redaction == nil {
+		stmt.redaction = &redaction{}
+	}
+
+	var (
+		r    = stmt.redaction
+		sql  = stmt.SQL.String()
+		tail = sql
+	)
+
+	if len(tail) > 256 {
+		tail = tail[len(tail)-256:]
+	}
+
+	// rows of VALUES are checked incrementally, as SQL of batch creating might be large
+	if r.columns != nil && r.offset <= len(sql) && valuesPlaceholdersRegexp.MatchString(sql[r.offset:]) {
+		r.offset = len(sql)
+		return idx < len(r.columns) && stmt.sensitiveColumn(r.columns[idx])
+	}
+
+	r.columns = nil
+	if strings.HasSuffix(tail, ") VALUES (") {
+		end := len(sql) - len(") VALUES (")
+		if start := strings.LastIndex(sql[:end], "("); start >= 0 {
+			r.columns, r.offset = strings.Split(sql[start+1:end], ","), len(sql)
+			return idx < len(r.columns) && stmt.sensitiveColumn(r.columns[idx])
+		}
+	}
+
+	if matches := sensitiveColumnRegexp.FindStringSubmatch(tail); len(matches) > 1 {
+		return stmt.sensitiveColumn(matches[1])
+	}
+	return false
+}
+
+// redactedVars returns vars of statement with values of sensitive columns replaced with RedactedValue, used for logging
+func (stmt *Statement) redactedVars() []interface{} {
+	if stmt.redaction == nil || len(stmt.redaction.vars) == 0 {
+		return stmt.Vars
+	}
+
+	vars := make([]interface{}, len(stmt.Vars))
+	for idx, v := range stmt.Vars {
+		if stmt.redaction.vars[idx] {
+			vars[idx] = RedactedValue
+		} else {
+			vars[idx] = v
+		}
+	}
+	return vars
+}
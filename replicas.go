@@ -0,0 +1,192 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Replicas connection pools of read replicas, queries are routed to them in round-robin, writes, locking queries, statements
+// in transactions or routed with Primary, reads after writes with context of WithReadYourWrites, and reads when lags of all
+// replicas are over MaxLag are routed to the primary
+//    db, err := gorm.Open(mysql.Open(primaryDSN), &gorm.Config{
+//      Replicas: &gorm.Replicas{ConnPools: []gorm.ConnPool{replicaDB}, MaxLag: time.Second, LagCheck: checkLag},
+//    })
+type Replicas struct {
+	ConnPools []ConnPool
+	// StickyDuration duration reads are routed to the primary after a write with context of WithReadYourWrites, default 5s
+	StickyDuration time.Duration
+	// LagCheck returns replication lag of replica, replicas with lags over MaxLag or failed to check are skipped
+	LagCheck func(ctx context.Context, replica ConnPool) (time.Duration, error)
+	// MaxLag max replication lag of replicas to serve reads, default 1s
+	MaxLag time.Duration
+	// LagCheckInterval results of LagCheck are cached for the interval, default 1s
+	LagCheckInterval time.Duration
+
+	next   uint32
+	mu     sync.Mutex
+	health map[int]replicaHealth
+}
+
+// replicaHealth cached result of lag check of replica
+type replicaHealth struct {
+	healthy   bool
+	checkedAt time.Time
+}
+
+// Primary routes statements to the primary, e.g: reads that must see latest writes
+//    db.Primary().First(&user, 1)
+func (db *DB) Primary() (tx *DB) {
+	tx = db.getInstance()
+	tx.Statement.Settings.Store("gorm:primary", true)
+	return
+}
+
+// ReadOnly returns session that only queries, creating, updating, deleting and executing statements are rejected with
+// ErrReadOnlySession, and queries are routed to replicas if there are any, e.g: side effect free reports
+//    reports := db.ReadOnly()
+//    reports.Model(&Order{}).Select("SUM(amount)").Scan(&total)
+//    reports.Delete(&Order{}, 1) // ErrReadOnlySession
+func (db *DB) ReadOnly() *DB {
+	return db.Session(&Session{ReadOnly: true})
+}
+
+type readYourWritesKey struct{}
+
+// WithReadYourWrites returns context whose reads are routed to the primary after writes with it in StickyDuration of Replicas
+//    ctx := gorm.WithReadYourWrites(r.Context())
+//    db.WithContext(ctx).Create(&user)
+//    db.WithContext(ctx).First(&user, user.ID) // read from the primary
+func WithReadYourWrites(ctx context.Context) context.Context {
+	return context.WithValue(ctx, readYourWritesKey{}, new(int64))
+}
+
+// lastWrite returns unix nano of last write with ctx, nil if ctx is not returned by WithReadYourWrites
+func lastWrite(ctx context.Context) *int64 {
+	if ctx != nil {
+		if v, ok := ctx.Value(readYourWritesKey{}).(*int64); ok {
+			return v
+		}
+	}
+	return nil
+}
+
+// replicasHook executes reads with replicas, writes are recorded for contexts returned by WithReadYourWrites
+func replicasHook(e *execution) {
+	stmt := e.db.Statement
+	if replica := stmt.DB.Replicas.route(e.db, e.name); replica != nil {
+		primary := stmt.ConnPool
+		stmt.ConnPool = replica
+		defer func() { stmt.ConnPool = primary }()
+	}
+
+	e.next()
+
+	if e.name != "query" && e.name != "row" {
+		stmt.DB.Replicas.written(e.db)
+	}
+}
+
+// rejectWritesHook rejects statements other than reads with ErrReadOnlySession in RejectWrites mode
+func rejectWritesHook(e *execution) {
+	if !isReadStatement(e.db.Statement, e.name) {
+		e.db.AddError(fmt.Errorf("%w: %s", ErrReadOnlySession, e.name))
+		return
+	}
+	e.next()
+}
+
+// route returns replica to execute statement of processor, nil if it should be executed by the primary
+func (replicas *Replicas) route(db *DB, processor string) ConnPool {
+	stmt := db.Statement
+	if replicas == nil || len(replicas.ConnPools) == 0 || stmt.InTransaction || !isReadStatement(stmt, processor) {
+		return nil
+	}
+
+	if primary, ok := stmt.Settings.Load("gorm:primary"); ok && primary.(bool) {
+		return nil
+	}
+
+	if last := lastWrite(stmt.Context); last != nil {
+		sticky := replicas.StickyDuration
+		if sticky <= 0 {
+			sticky = 5 * time.Second
+		}
+
+		if written := atomic.LoadInt64(last); written > 0 && time.Since(time.Unix(0, written)) < sticky {
+			return nil
+		}
+	}
+
+	for i := 0; i < len(replicas.ConnPools); i++ {
+		idx := int(atomic.AddUint32(&replicas.next, 1)-1) % len(replicas.ConnPools)
+		if replicas.healthy(stmt.Context, idx) {
+			return replicas.ConnPools[idx]
+		}
+	}
+	return nil
+}
+
+// isReadStatement returns true if statement executed by processor only reads, locking queries are not
+func isReadStatement(stmt *Statement, processor string) bool {
+	if processor != "query" && processor != "row" {
+		return false
+	}
+
+	if _, ok := stmt.Clauses["FOR"]; ok {
+		return false
+	}
+
+	if sql := strings.TrimSpace(stmt.SQL.String()); sql != "" {
+		if sql = strings.ToUpper(sql); !strings.HasPrefix(sql, "SELECT") && !strings.HasPrefix(sql, "WITH") {
+			return false
+		}
+	}
+	return true
+}
+
+// healthy returns true if lag of replica idx is in MaxLag, results are cached for LagCheckInterval
+func (replicas *Replicas) healthy(ctx context.Context, idx int) bool {
+	if replicas.LagCheck == nil {
+		return true
+	}
+
+	interval, maxLag := replicas.LagCheckInterval, replicas.MaxLag
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	if maxLag <= 0 {
+		maxLag = time.Second
+	}
+
+	replicas.mu.Lock()
+	defer replicas.mu.Unlock()
+
+	if health, ok := replicas.health[idx]; ok && time.Since(health.checkedAt) < interval {
+		return health.healthy
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	lag, err := replicas.LagCheck(ctx, replicas.ConnPools[idx])
+	if replicas.health == nil {
+		replicas.health = map[int]replicaHealth{}
+	}
+	replicas.health[idx] = replicaHealth{healthy: err == nil && lag <= maxLag, checkedAt: time.Now()}
+	return replicas.health[idx].healthy
+}
+
+// written records write of statement for WithReadYourWrites
+func (replicas *Replicas) written(db *DB) {
+	if replicas != nil && db.Error == nil {
+		if last := lastWrite(db.Statement.Context); last != nil {
+			atomic.StoreInt64(last, time.Now().UnixNano())
+		}
+	}
+}
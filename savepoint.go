@@ -0,0 +1,134 @@
+package gorm
+
+import (
+	"fmt"
+	"sync"
+
+	"gorm.io/gorm/clause"
+)
+
+// savePoints active savepoints of transaction, shared by sessions of the transaction
+type savePoints struct {
+	mu    sync.Mutex
+	names []string
+	seq   int
+}
+
+// savePoints returns active savepoints of transaction, nil if db is not in transaction
+func (db *DB) savePoints() *savePoints {
+	if value, ok := db.Statement.Settings.Load("gorm:savepoints"); ok {
+		return value.(*savePoints)
+	}
+	return nil
+}
+
+// push adds savepoint name, savepoint of existing name is moved to the end like databases do
+func (sp *savePoints) push(name string) {
+	if sp != nil {
+		sp.mu.Lock()
+		defer sp.mu.Unlock()
+		sp.remove(name, false)
+		sp.names = append(sp.names, name)
+	}
+}
+
+// remove removes savepoints after name, and the savepoint itself if inclusive
+func (sp *savePoints) remove(name string, inclusive bool) {
+	for idx := len(sp.names) - 1; idx >= 0; idx-- {
+		if sp.names[idx] == name {
+			if inclusive {
+				sp.names = sp.names[:idx]
+			} else {
+				sp.names = append(sp.names[:idx], sp.names[idx+1:]...)
+			}
+			return
+		}
+	}
+}
+
+// rollbackTo removes savepoints created after name, the savepoint itself is kept
+func (sp *savePoints) rollbackTo(name string) {
+	if sp != nil {
+		sp.mu.Lock()
+		defer sp.mu.Unlock()
+		for idx := len(sp.names) - 1; idx >= 0; idx-- {
+			if sp.names[idx] == name {
+				sp.names = sp.names[:idx+1]
+				return
+			}
+		}
+	}
+}
+
+// release removes savepoint name and savepoints created after it
+func (sp *savePoints) release(name string) {
+	if sp != nil {
+		sp.mu.Lock()
+		defer sp.mu.Unlock()
+		sp.remove(name, true)
+	}
+}
+
+// SavePointDepth returns number of active savepoints of current transaction, 0 if there is no savepoint or it is not in transaction
+func (db *DB) SavePointDepth() int {
+	if sp := db.savePoints(); sp != nil {
+		sp.mu.Lock()
+		defer sp.mu.Unlock()
+		return len(sp.names)
+	}
+	return 0
+}
+
+// SavePointAuto creates savepoint with name generated in current transaction, release releases the savepoint,
+// rollback rolls back to the savepoint, helpers could use it to create nested savepoints without knowing names of others
+//    name, release, rollback := tx.SavePointAuto()
+//    if err := doSomething(tx); err != nil {
+//      return rollback()
+//    }
+//    return release()
+func (db *DB) SavePointAuto() (name string, release func() error, rollback func() error) {
+	if sp := db.savePoints(); sp != nil {
+		sp.mu.Lock()
+		sp.seq++
+		name = fmt.Sprintf("gorm_sp_%d", sp.seq)
+		sp.mu.Unlock()
+	} else {
+		name = fmt.Sprintf("gorm_sp_%p", db.Statement)
+	}
+
+	err := db.SavePoint(name).Error
+	release = func() error {
+		if err != nil {
+			return err
+		}
+		return db.ReleaseSavePoint(name).Error
+	}
+
+	rollback = func() error {
+		if err != nil {
+			return err
+		}
+		return db.RollbackTo(name).Error
+	}
+	return
+}
+
+// ReleaseSavePoint releases savepoint name with SavePointReleaserDialectorInterface of dialector or quoted name, savepoints
+// are kept until the transaction is finished if the database can't release them, e.g: sqlserver
+func (db *DB) ReleaseSavePoint(name string) *DB {
+	if db.Capabilities().ReleaseSavePoint {
+		var err error
+		if releaser, ok := db.Dialector.(SavePointReleaserDialectorInterface); ok {
+			err = releaser.ReleaseSavePoint(db, name)
+		} else {
+			err = db.Exec("RELEASE SAVEPOINT ?", clause.Column{Name: name}).Error
+		}
+
+		if err != nil {
+			db.AddError(err)
+			return db
+		}
+	}
+	db.savePoints().release(name)
+	return db
+}
@@ -1,20 +1,46 @@
 package gorm
 
 import (
+	"context"
 	"database/sql"
 	"database/sql/driver"
+	"fmt"
 	"reflect"
 	"strings"
+	"sync"
 	"time"
 
 	"gorm.io/gorm/schema"
 )
 
+// NullPolicy policy of scanning NULL into non-pointer fields
+type NullPolicy string
+
+const (
+	// NullAsZero set the field to zero value, default policy
+	NullAsZero NullPolicy = "zero"
+	// NullAsError return ErrNullValue
+	NullAsError NullPolicy = "error"
+	// NullTrack set the field to zero value and track it in Statement.NullFields
+	NullTrack NullPolicy = "track"
+)
+
+// scanValueOf returns the value to scan the field into, protobuf timestamp messages are scanned as time.Time,
+// fields with serializer, arrays and intervals are scanned as raw database values
+func scanValueOf(field *schema.Field, fieldType reflect.Type) interface{} {
+	if isDecodedField(field) {
+		return new(interface{})
+	} else if schema.IsTimestampMessage(field.FieldType) {
+		return new(*time.Time)
+	}
+	return reflect.New(reflect.PtrTo(fieldType)).Interface()
+}
+
 func prepareValues(values []interface{}, db *DB, columnTypes []*sql.ColumnType, columns []string) {
 	if db.Statement.Schema != nil {
 		for idx, name := range columns {
 			if field := db.Statement.Schema.LookUpField(name); field != nil {
-				values[idx] = reflect.New(reflect.PtrTo(field.FieldType)).Interface()
+				values[idx] = scanValueOf(field, field.FieldType)
 				continue
 			}
 			values[idx] = new(interface{})
@@ -49,10 +75,88 @@ func scanIntoMap(mapValue map[string]interface{}, values []interface{}, columns
 	}
 }
 
-func Scan(rows *sql.Rows, db *DB, initialized bool) {
+// scanBuffer buffers of scanning rows, they are reused across queries to cut allocations
+type scanBuffer struct {
+	values         []interface{}
+	fields         []*schema.Field
+	assignedFields map[[2]*schema.Field]bool
+	dests          map[scanDestKey]interface{} // values to scan fields into
+}
+
+// scanDestKey key of value to scan field into, fields of self-referenced joins appear in more than one column
+type scanDestKey struct {
+	field *schema.Field
+	idx   int
+}
+
+// maxPooledColumns buffers of more columns are not pooled, so rare wide results don't pin memory
+const maxPooledColumns = 256
+
+var scanBufferPool = sync.Pool{New: func() interface{} {
+	return &scanBuffer{assignedFields: map[[2]*schema.Field]bool{}, dests: map[scanDestKey]interface{}{}}
+}}
+
+// getScanBuffer returns buffer for columns from pool
+func getScanBuffer(columns int) *scanBuffer {
+	buf := scanBufferPool.Get().(*scanBuffer)
+	if cap(buf.values) < columns {
+		buf.values, buf.fields = make([]interface{}, columns), make([]*schema.Field, columns)
+	}
+	buf.values, buf.fields = buf.values[:columns], buf.fields[:columns]
+	return buf
+}
+
+// putScanBuffer resets buffer and puts it back to pool, scanned values shouldn't be referenced after it
+func putScanBuffer(buf *scanBuffer) {
+	if cap(buf.values) > maxPooledColumns {
+		return
+	}
+
+	for idx, field := range buf.fields {
+		// scanned values are released, so the buffer doesn't keep them alive
+		if dest, ok := buf.dests[scanDestKey{field: field, idx: idx}]; ok && field != nil {
+			rv := reflect.ValueOf(dest).Elem()
+			rv.Set(reflect.Zero(rv.Type()))
+		}
+		buf.values[idx], buf.fields[idx] = nil, nil
+	}
+
+	if len(buf.dests) > maxPooledColumns {
+		buf.dests = map[scanDestKey]interface{}{}
+	}
+	for key := range buf.assignedFields {
+		delete(buf.assignedFields, key)
+	}
+	scanBufferPool.Put(buf)
+}
+
+// scanColumns returns columns of rows mapped with ColumnMapper, columns of drivers are cached and returned by
+// following calls, so they are mapped in a copy
+func scanColumns(rows *sql.Rows, db *DB) []string {
 	columns, _ := rows.Columns()
-	values := make([]interface{}, len(columns))
+	if db.ColumnMapper == nil {
+		return columns
+	}
+
+	mapped := make([]string, len(columns))
+	for idx, column := range columns {
+		if fieldPath := db.ColumnMapper(column); fieldPath != "" {
+			mapped[idx] = fieldPath
+		} else {
+			mapped[idx] = column
+		}
+	}
+	return mapped
+}
+
+func Scan(rows *sql.Rows, db *DB, initialized bool) {
+	columns := scanColumns(rows, db)
+	buf := getScanBuffer(len(columns))
+	defer putScanBuffer(buf)
+
+	values := buf.values
 	db.RowsAffected = 0
+	db.Statement.NullFields = nil
 
 	switch dest := db.Statement.Dest.(type) {
 	case map[string]interface{}, *map[string]interface{}:
@@ -103,7 +207,7 @@ func Scan(rows *sql.Rows, db *DB, initialized bool) {
 			var (
 				reflectValueType = db.Statement.ReflectValue.Type().Elem()
 				isPtr            = reflectValueType.Kind() == reflect.Ptr
-				fields           = make([]*schema.Field, len(columns))
+				fields           = buf.fields
 				joinFields       [][2]*schema.Field
 			)
 
@@ -118,26 +222,9 @@ func Scan(rows *sql.Rows, db *DB, initialized bool) {
 					Schema, _ = schema.Parse(db.Statement.Dest, db.cacheStore, db.NamingStrategy)
 				}
 
-				for idx, column := range columns {
-					if field := Schema.LookUpField(column); field != nil && field.Readable {
-						fields[idx] = field
-					} else if names := strings.Split(column, "__"); len(names) > 1 {
-						if rel, ok := Schema.Relationships.Relations[names[0]]; ok {
-							if field := rel.FieldSchema.LookUpField(strings.Join(names[1:], "__")); field != nil && field.Readable {
-								fields[idx] = field
-
-								if len(joinFields) == 0 {
-									joinFields = make([][2]*schema.Field, len(columns))
-								}
-								joinFields[idx] = [2]*schema.Field{rel.Field, field}
-								continue
-							}
-						}
-						values[idx] = &sql.RawBytes{}
-					} else {
-						values[idx] = &sql.RawBytes{}
-					}
-				}
+				joinFields = lookUpScanFields(Schema, db.Statement.Joins, columns, values, buf)
+				applyReadPolicy(db, fields, values)
+				prepareScanValues(values, fields, buf)
 			}
 
 			// pluck values into slice of data
@@ -158,31 +245,7 @@ func Scan(rows *sql.Rows, db *DB, initialized bool) {
 				if isPluck {
 					db.AddError(rows.Scan(elem.Interface()))
 				} else {
-					for idx, field := range fields {
-						if field != nil {
-							values[idx] = reflect.New(reflect.PtrTo(field.IndirectFieldType)).Interface()
-						}
-					}
-
-					db.AddError(rows.Scan(values...))
-
-					for idx, field := range fields {
-						if len(joinFields) != 0 && joinFields[idx][0] != nil {
-							value := reflect.ValueOf(values[idx]).Elem()
-							relValue := joinFields[idx][0].ReflectValueOf(elem)
-
-							if relValue.Kind() == reflect.Ptr && relValue.IsNil() {
-								if value.IsNil() {
-									continue
-								}
-								relValue.Set(reflect.New(relValue.Type().Elem()))
-							}
-
-							field.Set(relValue, values[idx])
-						} else if field != nil {
-							field.Set(elem, values[idx])
-						}
-					}
+					scanIntoStruct(db, rows, elem, values, fields, joinFields)
 				}
 
 				if isPtr {
@@ -197,51 +260,320 @@ func Scan(rows *sql.Rows, db *DB, initialized bool) {
 			}
 
 			if initialized || rows.Next() {
-				for idx, column := range columns {
-					if field := Schema.LookUpField(column); field != nil && field.Readable {
-						values[idx] = reflect.New(reflect.PtrTo(field.IndirectFieldType)).Interface()
-					} else if names := strings.Split(column, "__"); len(names) > 1 {
-						if rel, ok := Schema.Relationships.Relations[names[0]]; ok {
-							if field := rel.FieldSchema.LookUpField(strings.Join(names[1:], "__")); field != nil && field.Readable {
-								values[idx] = reflect.New(reflect.PtrTo(field.IndirectFieldType)).Interface()
-								continue
-							}
-						}
-						values[idx] = &sql.RawBytes{}
-					} else {
-						values[idx] = &sql.RawBytes{}
-					}
-				}
+				fields, joinFields := buf.fields, lookUpScanFields(Schema, db.Statement.Joins, columns, values, buf)
+				applyReadPolicy(db, fields, values)
+				prepareScanValues(values, fields, buf)
 
 				db.RowsAffected++
-				db.AddError(rows.Scan(values...))
-
-				for idx, column := range columns {
-					if field := Schema.LookUpField(column); field != nil && field.Readable {
-						field.Set(db.Statement.ReflectValue, values[idx])
-					} else if names := strings.Split(column, "__"); len(names) > 1 {
-						if rel, ok := Schema.Relationships.Relations[names[0]]; ok {
-							if field := rel.FieldSchema.LookUpField(strings.Join(names[1:], "__")); field != nil && field.Readable {
-								relValue := rel.Field.ReflectValueOf(db.Statement.ReflectValue)
-								value := reflect.ValueOf(values[idx]).Elem()
-
-								if relValue.Kind() == reflect.Ptr && relValue.IsNil() {
-									if value.IsNil() {
-										continue
-									}
-									relValue.Set(reflect.New(relValue.Type().Elem()))
-								}
-
-								field.Set(relValue, values[idx])
-							}
-						}
+				scanIntoStruct(db, rows, db.Statement.ReflectValue, values, fields, joinFields)
+			}
+		}
+	}
+
+	// errors of reading rows, e.g: the statement canceled after timeout
+	if err := rows.Err(); err != nil {
+		db.AddError(err)
+		return
+	}
+
+	if db.RowsAffected == 0 && db.Statement.RaiseErrorOnNotFound && !db.AllowEmptyResult {
+		db.AddError(ErrRecordNotFound)
+	}
+}
+
+// ScanInto scan rows into reflectValues by index, e.g: rows returned by INSERT ... RETURNING are scanned into records
+// being created, rows more than reflectValues are only counted
+func ScanInto(rows *sql.Rows, db *DB, reflectValues ...reflect.Value) {
+	columns := scanColumns(rows, db)
+	buf := getScanBuffer(len(columns))
+	defer putScanBuffer(buf)
+
+	var (
+		sch    = db.Statement.Schema
+		values = buf.values
+		fields = buf.fields
+	)
+
+	db.RowsAffected = 0
+	db.Statement.NullFields = nil
+	if sch != nil {
+		lookUpScanFields(sch, nil, columns, values, buf)
+		applyReadPolicy(db, fields, values)
+		prepareScanValues(values, fields, buf)
+	}
+
+	for rows.Next() {
+		if sch != nil && int(db.RowsAffected) < len(reflectValues) {
+			scanIntoStruct(db, rows, reflectValues[db.RowsAffected], values, fields, nil)
+		}
+		db.RowsAffected++
+	}
+	db.AddError(rows.Err())
+}
+
+// lookUpScanFields returns fields of columns, columns of joined relations could be prefixed with relation name or table name,
+// e.g: `Company__name`, `companies.name`; duplicated columns like `SELECT users.*, companies.*` will be assigned to the next relation
+// joined by statement
+func lookUpScanFields(sch *schema.Schema, joins []join, columns []string, values []interface{}, buf *scanBuffer) (joinFields [][2]*schema.Field) {
+	var (
+		relations      []*schema.Relationship
+		joined         []bool
+		relationIdx    = -1
+		fields         = buf.fields
+		assignedFields = buf.assignedFields
+	)
+	// nested structs declared in the struct, relations of embedded structs are excluded
+	for _, field := range sch.Fields {
+		if rel, ok := sch.Relationships.Relations[field.Name]; ok && len(field.BindNames) == 1 && (rel.Type == schema.BelongsTo || rel.Type == schema.HasOne) {
+			relations = append(relations, rel)
+			joined = append(joined, joinedRelation(joins, rel))
+		}
+	}
+
+	setField := func(idx int, rel *schema.Relationship, field *schema.Field) {
+		fields[idx] = field
+		if rel == nil {
+			assignedFields[[2]*schema.Field{nil, field}] = true
+			return
+		}
+
+		assignedFields[[2]*schema.Field{rel.Field, field}] = true
+		if len(joinFields) == 0 {
+			joinFields = make([][2]*schema.Field, len(columns))
+		}
+		joinFields[idx] = [2]*schema.Field{rel.Field, field}
+	}
+
+	lookUpRelField := func(rel *schema.Relationship, name string) *schema.Field {
+		if field := rel.FieldSchema.LookUpField(name); field != nil && field.Readable && !assignedFields[[2]*schema.Field{rel.Field, field}] {
+			return field
+		}
+		return nil
+	}
+
+COLUMNS:
+	for idx, column := range columns {
+		if names, ok := splitScanColumn(column); ok && sch.LookUpField(column) == nil {
+			if names[0] == sch.Table {
+				if field := sch.LookUpField(names[1]); field != nil && field.Readable {
+					setField(idx, nil, field)
+					continue
+				}
+			}
+
+			if rel, ok := sch.Relationships.Relations[names[0]]; ok {
+				if field := rel.FieldSchema.LookUpField(names[1]); field != nil && field.Readable {
+					setField(idx, rel, field)
+					continue
+				}
+			}
+
+			for relIdx, rel := range relations {
+				if names[0] == rel.FieldSchema.Table {
+					if field := rel.FieldSchema.LookUpField(names[1]); field != nil && field.Readable {
+						setField(idx, rel, field)
+						relationIdx = relIdx
+						continue COLUMNS
 					}
 				}
 			}
 		}
+
+		field := sch.LookUpField(column)
+		if field != nil && !field.Readable {
+			field = nil
+		}
+
+		unassigned := field != nil && !assignedFields[[2]*schema.Field{nil, field}]
+		if unassigned && relationIdx < 0 {
+			setField(idx, nil, field)
+			continue
+		}
+
+		// duplicated columns or columns after joined columns, scan them into the next relation joined by statement
+		if field != nil || relationIdx >= 0 {
+			relIdx := relationIdx
+			if relIdx < 0 {
+				relIdx = 0
+			}
+
+			for ; relIdx < len(relations); relIdx++ {
+				if !joined[relIdx] {
+					continue
+				}
+
+				if relField := lookUpRelField(relations[relIdx], column); relField != nil {
+					setField(idx, relations[relIdx], relField)
+					relationIdx = relIdx
+					continue COLUMNS
+				}
+			}
+		}
+
+		// duplicated columns without joined relations are scanned into the same field as before, the last one wins
+		if field != nil {
+			setField(idx, nil, field)
+			continue
+		}
+
+		values[idx] = &sql.RawBytes{}
 	}
 
-	if db.RowsAffected == 0 && db.Statement.RaiseErrorOnNotFound {
-		db.AddError(ErrRecordNotFound)
+	return
+}
+
+// joinedRelation returns true if relation is joined by statement, with Joins of its name or raw joins of its table
+func joinedRelation(joins []join, rel *schema.Relationship) bool {
+	for _, j := range joins {
+		if j.Name == rel.Name {
+			return true
+		}
+
+		for _, word := range strings.Fields(j.Name) {
+			if strings.Trim(word, "`\"'[]") == rel.FieldSchema.Table {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// splitScanColumn splits prefixed column, e.g: `Company__name`, `companies.name`, into prefix and name without allocation
+func splitScanColumn(column string) (names [2]string, ok bool) {
+	idx, sep := strings.IndexByte(column, '.'), 1
+	if underscores := strings.Index(column, "__"); underscores >= 0 && (idx < 0 || underscores < idx) {
+		idx, sep = underscores, 2
+	}
+
+	if idx < 0 {
+		return names, false
+	}
+	return [2]string{column[:idx], column[idx+sep:]}, true
+}
+
+// prepareScanValues prepares values to scan fields into, they are reused for all rows and pooled with buffer,
+// as scanning overwrites them
+func prepareScanValues(values []interface{}, fields []*schema.Field, buf *scanBuffer) {
+	for idx, field := range fields {
+		if field != nil {
+			key := scanDestKey{field: field, idx: idx}
+			dest, ok := buf.dests[key]
+			if !ok {
+				dest = scanValueOf(field, field.IndirectFieldType)
+				buf.dests[key] = dest
+			}
+			values[idx] = dest
+		}
+	}
+}
+
+// applyReadPolicy skips fields can't be read with RolePolicy
+func applyReadPolicy(db *DB, fields []*schema.Field, values []interface{}) {
+	if db.RolePolicy != nil {
+		for idx, field := range fields {
+			if field != nil && !db.RolePolicy.CanRead(db.Statement.Context, field) {
+				fields[idx] = nil
+				values[idx] = &sql.RawBytes{}
+			}
+		}
+	}
+}
+
+// scanIntoStruct scan current row into reflectValue
+func scanIntoStruct(db *DB, rows *sql.Rows, reflectValue reflect.Value, values []interface{}, fields []*schema.Field, joinFields [][2]*schema.Field) {
+	db.AddError(rows.Scan(values...))
+
+	var (
+		nullFields []string
+		trackNull  = db.NullPolicy == NullTrack
+	)
+
+	for idx, field := range fields {
+		if field == nil {
+			continue
+		}
+
+		policy := db.NullPolicy
+		if field.NullPolicy != "" {
+			policy = NullPolicy(field.NullPolicy)
+		}
+		trackNull = trackNull || policy == NullTrack
+
+		name := field.Name
+		if len(joinFields) != 0 && joinFields[idx][0] != nil {
+			name = joinFields[idx][0].Name + "." + field.Name
+		}
+
+		if value := reflect.ValueOf(values[idx]).Elem(); value.IsNil() && field.FieldType.Kind() != reflect.Ptr {
+			if _, ok := value.Interface().(sql.Scanner); !ok {
+				switch policy {
+				case NullAsError:
+					db.AddError(fmt.Errorf("%w: %s", ErrNullValue, name))
+				case NullTrack:
+					nullFields = append(nullFields, name)
+				}
+			}
+		}
+
+		if len(joinFields) != 0 && joinFields[idx][0] != nil {
+			value := reflect.ValueOf(values[idx]).Elem()
+			relValue := joinFields[idx][0].ReflectValueOf(reflectValue)
+
+			if relValue.Kind() == reflect.Ptr && relValue.IsNil() {
+				if value.IsNil() {
+					continue
+				}
+				relValue.Set(reflect.New(relValue.Type().Elem()))
+			}
+
+			setScannedField(db, field, relValue, values[idx])
+		} else {
+			setScannedField(db, field, reflectValue, values[idx])
+		}
+	}
+
+	if trackNull {
+		db.Statement.NullFields = append(db.Statement.NullFields, nullFields)
+	}
+}
+
+// isDecodedField returns true if raw database values of field are decoded, e.g: fields with serializer, arrays and intervals
+func isDecodedField(field *schema.Field) bool {
+	return field.Serializer != nil || field.GORMDataType == schema.Array || field.GORMDataType == schema.Interval
+}
+
+// decodeField set raw database value to field with serializer, array or interval, check isDecodedField
+func decodeField(ctx context.Context, field *schema.Field, reflectValue reflect.Value, value interface{}) error {
+	if field.Serializer != nil {
+		return field.Serializer.Scan(ctx, field, reflectValue, value)
+	} else if field.GORMDataType == schema.Array {
+		return field.ScanArray(reflectValue, value)
+	}
+	return field.ScanInterval(reflectValue, value)
+}
+
+// setScannedField set scanned value to field, deserialize it if field has serializer or is array or interval
+func setScannedField(db *DB, field *schema.Field, reflectValue reflect.Value, value interface{}) {
+	if isDecodedField(field) {
+		db.AddError(decodeField(db.Statement.Context, field, reflectValue, *(value.(*interface{}))))
+	} else {
+		field.Set(reflectValue, value)
+		if field.GORMDataType == schema.Time {
+			loc := field.ReadTimeZone
+			if loc == nil {
+				loc = field.TimeZone
+			}
+			if loc == nil {
+				loc = db.TimeZone
+			}
+
+			if loc != nil {
+				if fieldValue := field.ReflectValueOf(reflectValue); fieldValue.CanSet() {
+					if v := schema.InTimeZone(fieldValue.Interface(), loc); v != nil {
+						fieldValue.Set(reflect.ValueOf(v))
+					}
+				}
+			}
+		}
 	}
 }
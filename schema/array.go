@@ -0,0 +1,228 @@
+package schema
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var (
+	scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+	valuerType  = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+)
+
+// IsArrayType check if the type is a slice of basic types like []string, []int64, saved as array columns,
+// types implemented sql.Scanner or driver.Valuer like pq.StringArray are serialized by themselves
+func IsArrayType(typ reflect.Type) bool {
+	if typ.Kind() != reflect.Slice {
+		return false
+	}
+
+	if ptr := reflect.PtrTo(typ); ptr.Implements(scannerType) || ptr.Implements(valuerType) {
+		return false
+	}
+
+	switch typ.Elem().Kind() {
+	case reflect.String, reflect.Bool, reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+// ArrayElemDataType returns data type and size of array elements
+func (field *Field) ArrayElemDataType() (DataType, int) {
+	switch field.IndirectFieldType.Elem().Kind() {
+	case reflect.String:
+		return String, 0
+	case reflect.Bool:
+		return Bool, 0
+	case reflect.Float32:
+		return Float, 32
+	case reflect.Float64:
+		return Float, 64
+	case reflect.Int8:
+		return Int, 8
+	case reflect.Int16:
+		return Int, 16
+	case reflect.Int32:
+		return Int, 32
+	case reflect.Uint16:
+		return Uint, 16
+	case reflect.Uint32:
+		return Uint, 32
+	case reflect.Uint, reflect.Uint64:
+		return Uint, 64
+	default:
+		return Int, 64
+	}
+}
+
+// ArrayValue returns database value of array, saved as postgres array literal like `{"a","b"}` if postgres is true, otherwise as JSON
+func ArrayValue(value interface{}, postgres bool) (interface{}, error) {
+	rv := reflect.ValueOf(value)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil, nil
+		}
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() != reflect.Slice {
+		return value, nil
+	} else if rv.IsNil() {
+		return nil, nil
+	}
+
+	if !postgres {
+		result, err := json.Marshal(rv.Interface())
+		return string(result), err
+	}
+
+	var builder strings.Builder
+	builder.WriteByte('{')
+	for i := 0; i < rv.Len(); i++ {
+		if i > 0 {
+			builder.WriteByte(',')
+		}
+
+		if elem := rv.Index(i); elem.Kind() == reflect.String {
+			builder.WriteByte('"')
+			builder.WriteString(strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(elem.String()))
+			builder.WriteByte('"')
+		} else {
+			builder.WriteString(fmt.Sprint(elem.Interface()))
+		}
+	}
+	builder.WriteByte('}')
+	return builder.String(), nil
+}
+
+// ScanArray scan database value of array into field, both postgres array literal and JSON are supported
+func (field *Field) ScanArray(dst reflect.Value, dbValue interface{}) error {
+	fieldValue := reflect.New(field.FieldType)
+	if dbValue != nil {
+		var data string
+		switch v := dbValue.(type) {
+		case []byte:
+			data = string(v)
+		case string:
+			data = v
+		default:
+			return fmt.Errorf("failed to scan array value %#v into field %v", dbValue, field.Name)
+		}
+
+		elem := fieldValue.Elem()
+		if elem.Kind() == reflect.Ptr {
+			elem.Set(reflect.New(field.IndirectFieldType))
+			elem = elem.Elem()
+		}
+
+		if data = strings.TrimSpace(data); strings.HasPrefix(data, "[") {
+			if err := json.Unmarshal([]byte(data), elem.Addr().Interface()); err != nil {
+				return err
+			}
+		} else if data != "" {
+			values, err := parseArrayLiteral(data)
+			if err != nil {
+				return fmt.Errorf("failed to scan array value into field %v: %w", field.Name, err)
+			}
+
+			slice := reflect.MakeSlice(elem.Type(), len(values), len(values))
+			for idx, value := range values {
+				if value == nil {
+					continue
+				}
+
+				if err := setArrayElem(slice.Index(idx), *value); err != nil {
+					return fmt.Errorf("failed to scan array value into field %v: %w", field.Name, err)
+				}
+			}
+			elem.Set(slice)
+		}
+	}
+
+	field.ReflectValueOf(dst).Set(fieldValue.Elem())
+	return nil
+}
+
+// parseArrayLiteral parse one dimensional postgres array literal like `{a,"b c",NULL}`
+func parseArrayLiteral(data string) (values []*string, err error) {
+	if len(data) < 2 || data[0] != '{' || data[len(data)-1] != '}' {
+		return nil, fmt.Errorf("invalid array literal %v", data)
+	}
+
+	data = data[1 : len(data)-1]
+	for idx := 0; idx < len(data); {
+		var (
+			value  strings.Builder
+			quoted = data[idx] == '"'
+		)
+
+		if quoted {
+			for idx++; idx < len(data) && data[idx] != '"'; idx++ {
+				if data[idx] == '\\' && idx+1 < len(data) {
+					idx++
+				}
+				value.WriteByte(data[idx])
+			}
+
+			if idx >= len(data) {
+				return nil, fmt.Errorf("unterminated quoted value in array literal %v", data)
+			}
+			idx++
+		} else {
+			for ; idx < len(data) && data[idx] != ','; idx++ {
+				value.WriteByte(data[idx])
+			}
+		}
+
+		str := value.String()
+		if !quoted && strings.EqualFold(str, "NULL") {
+			values = append(values, nil)
+		} else {
+			values = append(values, &str)
+		}
+
+		if idx < len(data) {
+			if data[idx] != ',' {
+				return nil, fmt.Errorf("invalid array literal %v", data)
+			}
+			idx++
+		}
+	}
+	return values, nil
+}
+
+func setArrayElem(elem reflect.Value, value string) error {
+	switch elem.Kind() {
+	case reflect.String:
+		elem.SetString(value)
+	case reflect.Bool:
+		elem.SetBool(value == "t" || strings.EqualFold(value, "true"))
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+		elem.SetFloat(f)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		elem.SetInt(i)
+	default:
+		u, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		elem.SetUint(u)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package schema_test
+
+import (
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+
+	"gorm.io/gorm/schema"
+)
+
+type StringArray []string
+
+func (a *StringArray) Scan(value interface{}) error {
+	return errors.New("not implemented")
+}
+
+func (a StringArray) Value() (driver.Value, error) {
+	return nil, nil
+}
+
+type ArrayModel struct {
+	ID       uint
+	Tags     []string
+	Scores   []int64
+	Flags    *[]bool
+	Data     []byte
+	Scanners StringArray `gorm:"type:text[]"`
+}
+
+func TestParseArrayField(t *testing.T) {
+	s, err := schema.Parse(&ArrayModel{}, &sync.Map{}, schema.NamingStrategy{})
+	if err != nil {
+		t.Fatalf("failed to parse array model, got error %v", err)
+	}
+
+	for _, name := range []string{"Tags", "Scores", "Flags"} {
+		if field := s.LookUpField(name); field.DataType != schema.Array {
+			t.Errorf("%v should be array, got %v", name, field.DataType)
+		}
+	}
+
+	if field := s.LookUpField("Data"); field.DataType != schema.Bytes {
+		t.Errorf("Data should be bytes, got %v", field.DataType)
+	}
+
+	if schema.IsArrayType(reflect.TypeOf(StringArray{})) {
+		t.Errorf("StringArray implemented Scanner/Valuer should not be array")
+	}
+
+	if dataType, size := s.LookUpField("Scores").ArrayElemDataType(); dataType != schema.Int || size != 64 {
+		t.Errorf("incorrect elem data type of Scores, got %v %v", dataType, size)
+	}
+}
+
+func TestArrayValue(t *testing.T) {
+	results := []struct {
+		Value    interface{}
+		Postgres bool
+		Result   interface{}
+	}{
+		{Value: []string{"a", `b "c"`, `d\e`}, Postgres: true, Result: `{"a","b \"c\"","d\\e"}`},
+		{Value: []int64{1, 2}, Postgres: true, Result: `{1,2}`},
+		{Value: []string{"a", "b"}, Postgres: false, Result: `["a","b"]`},
+		{Value: []string(nil), Postgres: true, Result: nil},
+		{Value: (*[]bool)(nil), Postgres: false, Result: nil},
+	}
+
+	for _, result := range results {
+		if value, err := schema.ArrayValue(result.Value, result.Postgres); err != nil || value != result.Result {
+			t.Errorf("incorrect array value of %#v, expects %v, got %v, error %v", result.Value, result.Result, value, err)
+		}
+	}
+}
+
+func TestScanArray(t *testing.T) {
+	s, _ := schema.Parse(&ArrayModel{}, &sync.Map{}, schema.NamingStrategy{})
+
+	var model ArrayModel
+	reflectValue := reflect.ValueOf(&model).Elem()
+	values := map[string]interface{}{
+		"Tags":   []byte(`{a,"b \"c\"",NULL,"d\\e",""}`),
+		"Scores": `[1,2,3]`,
+		"Flags":  "{t,f,true}",
+	}
+
+	for name, value := range values {
+		if err := s.LookUpField(name).ScanArray(reflectValue, value); err != nil {
+			t.Fatalf("failed to scan %v, got error %v", name, err)
+		}
+	}
+
+	if !reflect.DeepEqual(model.Tags, []string{"a", `b "c"`, "", `d\e`, ""}) {
+		t.Errorf("incorrect tags, got %#v", model.Tags)
+	}
+
+	if !reflect.DeepEqual(model.Scores, []int64{1, 2, 3}) {
+		t.Errorf("incorrect scores, got %#v", model.Scores)
+	}
+
+	if model.Flags == nil || !reflect.DeepEqual(*model.Flags, []bool{true, false, true}) {
+		t.Errorf("incorrect flags, got %#v", model.Flags)
+	}
+
+	if err := s.LookUpField("Scores").ScanArray(reflectValue, "{1,x}"); err == nil {
+		t.Errorf("should returns error for invalid array element")
+	}
+
+	if err := s.LookUpField("Tags").ScanArray(reflectValue, nil); err != nil || model.Tags != nil {
+		t.Errorf("NULL should be scanned as nil, got %#v, error %v", model.Tags, err)
+	}
+}
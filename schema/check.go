@@ -3,11 +3,14 @@ package schema
 import (
 	"regexp"
 	"strings"
+
+	"gorm.io/gorm/clause"
 )
 
 type Check struct {
 	Name       string
-	Constraint string // length(phone) >= 10
+	Constraint string            // length(phone) >= 10
+	Expression clause.Expression // built constraint with quoted columns if not nil, e.g: checks of enum fields
 	*Field
 }
 
@@ -26,6 +29,19 @@ func (schema *Schema) ParseCheckConstraints() map[string]Check {
 				name := schema.namer.CheckerName(schema.Table, field.DBName)
 				checks[name] = Check{Name: name, Constraint: chk, Field: field}
 			}
+		} else if len(field.EnumValues) > 0 {
+			values := make([]string, len(field.EnumValues))
+			for idx, value := range field.EnumValues {
+				values[idx] = "'" + strings.ReplaceAll(value, "'", "''") + "'"
+			}
+
+			name := schema.namer.CheckerName(schema.Table, field.DBName)
+			checks[name] = Check{
+				Name:       name,
+				Constraint: field.DBName + " IN (" + strings.Join(values, ",") + ")",
+				Expression: clause.Expr{SQL: "? IN (" + strings.Join(values, ",") + ")", Vars: []interface{}{clause.Column{Name: field.DBName}}},
+				Field:      field,
+			}
 		}
 	}
 	return checks
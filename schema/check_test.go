@@ -12,6 +12,14 @@ type UserCheck struct {
 	Name  string `gorm:"check:name_checker,name <> 'jinzhu'"`
 	Name2 string `gorm:"check:name <> 'jinzhu'"`
 	Name3 string `gorm:"check:,name <> 'jinzhu'"`
+	State string `gorm:"type:enum;values:draft,published"`
+	Role  UserRole
+}
+
+type UserRole string
+
+func (UserRole) EnumValues() []string {
+	return []string{"admin", "o'neil"}
 }
 
 func TestParseCheck(t *testing.T) {
@@ -33,6 +41,18 @@ func TestParseCheck(t *testing.T) {
 			Name:       "chk_user_checks_name3",
 			Constraint: "name <> 'jinzhu'",
 		},
+		"chk_user_checks_state": {
+			Name:       "chk_user_checks_state",
+			Constraint: "state IN ('draft','published')",
+		},
+		"chk_user_checks_role": {
+			Name:       "chk_user_checks_role",
+			Constraint: "role IN ('admin','o''neil')",
+		},
+	}
+
+	if field := user.LookUpField("State"); field.DataType != schema.String || !reflect.DeepEqual(field.EnumValues, []string{"draft", "published"}) {
+		t.Errorf("enum field should be string with values, got %v %v", field.DataType, field.EnumValues)
 	}
 
 	checks := user.ParseCheckConstraints()
@@ -0,0 +1,259 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// DefinitionVersion version of definition format, increased for incompatible changes
+const DefinitionVersion = 1
+
+// Definition metadata of parsed schema in stable JSON format, used by external tools like docs generators and admin UIs
+type Definition struct {
+	Version       int                      `json:"version"`
+	Name          string                   `json:"name"`
+	Table         string                   `json:"table"`
+	Fields        []FieldDefinition        `json:"fields"`
+	Indexes       []IndexDefinition        `json:"indexes,omitempty"`
+	Checks        []CheckDefinition        `json:"checks,omitempty"`
+	Relationships []RelationshipDefinition `json:"relationships,omitempty"`
+	Constraints   []ConstraintDefinition   `json:"constraints,omitempty"`
+}
+
+// FieldDefinition metadata of field
+type FieldDefinition struct {
+	Name          string   `json:"name"`
+	DBName        string   `json:"db_name"`
+	GoType        string   `json:"go_type"`
+	DataType      DataType `json:"data_type,omitempty"`
+	PrimaryKey    bool     `json:"primary_key,omitempty"`
+	AutoIncrement bool     `json:"auto_increment,omitempty"`
+	NotNull       bool     `json:"not_null,omitempty"`
+	Unique        bool     `json:"unique,omitempty"`
+	Size          int      `json:"size,omitempty"`
+	Precision     int      `json:"precision,omitempty"`
+	Scale         int      `json:"scale,omitempty"`
+	Default       *string  `json:"default,omitempty"`
+	Comment       string   `json:"comment,omitempty"`
+	Collation     string   `json:"collation,omitempty"`
+	Charset       string   `json:"charset,omitempty"`
+	EnumValues    []string `json:"enum_values,omitempty"`
+	GeneratedAs   string   `json:"generated_as,omitempty"`
+	Creatable     bool     `json:"creatable"`
+	Updatable     bool     `json:"updatable"`
+	Readable      bool     `json:"readable"`
+}
+
+// IndexDefinition metadata of index
+type IndexDefinition struct {
+	Name    string                 `json:"name"`
+	Class   string                 `json:"class,omitempty"`
+	Type    string                 `json:"type,omitempty"`
+	Where   string                 `json:"where,omitempty"`
+	Comment string                 `json:"comment,omitempty"`
+	Option  string                 `json:"option,omitempty"`
+	Fields  []IndexFieldDefinition `json:"fields"`
+}
+
+// IndexFieldDefinition metadata of index field
+type IndexFieldDefinition struct {
+	Field      string `json:"field,omitempty"`
+	Expression string `json:"expression,omitempty"`
+	Sort       string `json:"sort,omitempty"`
+	Collate    string `json:"collate,omitempty"`
+	Length     int    `json:"length,omitempty"`
+}
+
+// CheckDefinition metadata of check constraint
+type CheckDefinition struct {
+	Name       string `json:"name"`
+	Constraint string `json:"constraint"`
+	Field      string `json:"field,omitempty"`
+}
+
+// RelationshipDefinition metadata of relationship
+type RelationshipDefinition struct {
+	Name        string                 `json:"name"`
+	Type        RelationshipType       `json:"type"`
+	Schema      string                 `json:"schema"`
+	Table       string                 `json:"table"`
+	JoinTable   string                 `json:"join_table,omitempty"`
+	Polymorphic *PolymorphicDefinition `json:"polymorphic,omitempty"`
+	References  []ReferenceDefinition  `json:"references"`
+}
+
+// PolymorphicDefinition metadata of polymorphic relationship
+type PolymorphicDefinition struct {
+	ID    string `json:"id"`
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// ReferenceDefinition metadata of relationship reference, fields are db names
+type ReferenceDefinition struct {
+	PrimaryKey    string `json:"primary_key,omitempty"`
+	PrimaryValue  string `json:"primary_value,omitempty"`
+	ForeignKey    string `json:"foreign_key"`
+	OwnPrimaryKey bool   `json:"own_primary_key,omitempty"`
+}
+
+// ConstraintDefinition metadata of foreign key constraint
+type ConstraintDefinition struct {
+	Name           string   `json:"name"`
+	ForeignKeys    []string `json:"foreign_keys"`
+	ReferenceTable string   `json:"reference_table"`
+	References     []string `json:"references"`
+	OnDelete       string   `json:"on_delete,omitempty"`
+	OnUpdate       string   `json:"on_update,omitempty"`
+}
+
+// Definition returns definition of schema, fields are in declaration order, others are sorted by name
+func (schema *Schema) Definition() *Definition {
+	definition := &Definition{Version: DefinitionVersion, Name: schema.Name, Table: schema.Table, Fields: []FieldDefinition{}}
+
+	for _, field := range schema.Fields {
+		if field.DBName == "" {
+			continue
+		}
+
+		fieldDefinition := FieldDefinition{
+			Name:          field.Name,
+			DBName:        field.DBName,
+			GoType:        field.FieldType.String(),
+			DataType:      field.DataType,
+			PrimaryKey:    field.PrimaryKey,
+			AutoIncrement: field.AutoIncrement,
+			NotNull:       field.NotNull,
+			Unique:        field.Unique,
+			Size:          field.Size,
+			Precision:     field.Precision,
+			Scale:         field.Scale,
+			Comment:       field.Comment,
+			Collation:     field.Collation,
+			Charset:       field.Charset,
+			EnumValues:    field.EnumValues,
+			GeneratedAs:   field.GeneratedAs,
+			Creatable:     field.Creatable,
+			Updatable:     field.Updatable,
+			Readable:      field.Readable,
+		}
+
+		if field.HasDefaultValue && field.DefaultValue != "" {
+			defaultValue := field.DefaultValue
+			fieldDefinition.Default = &defaultValue
+		}
+		definition.Fields = append(definition.Fields, fieldDefinition)
+	}
+
+	for _, index := range schema.ParseIndexes() {
+		indexDefinition := IndexDefinition{
+			Name: index.Name, Class: index.Class, Type: index.Type, Where: index.Where, Comment: index.Comment, Option: index.Option,
+		}
+
+		for _, option := range index.Fields {
+			fieldDefinition := IndexFieldDefinition{Expression: option.Expression, Sort: option.Sort, Collate: option.Collate, Length: option.Length}
+			if option.Field != nil {
+				fieldDefinition.Field = option.DBName
+			}
+			indexDefinition.Fields = append(indexDefinition.Fields, fieldDefinition)
+		}
+		definition.Indexes = append(definition.Indexes, indexDefinition)
+	}
+	sort.Slice(definition.Indexes, func(i, j int) bool {
+		return definition.Indexes[i].Name < definition.Indexes[j].Name
+	})
+
+	for _, check := range schema.ParseCheckConstraints() {
+		checkDefinition := CheckDefinition{Name: check.Name, Constraint: check.Constraint}
+		if check.Field != nil {
+			checkDefinition.Field = check.DBName
+		}
+		definition.Checks = append(definition.Checks, checkDefinition)
+	}
+	sort.Slice(definition.Checks, func(i, j int) bool {
+		return definition.Checks[i].Name < definition.Checks[j].Name
+	})
+
+	for _, rel := range schema.Relationships.Relations {
+		relDefinition := RelationshipDefinition{
+			Name: rel.Name, Type: rel.Type, Schema: rel.FieldSchema.Name, Table: rel.FieldSchema.Table, References: []ReferenceDefinition{},
+		}
+
+		if rel.JoinTable != nil {
+			relDefinition.JoinTable = rel.JoinTable.Table
+		}
+
+		if rel.Polymorphic != nil {
+			relDefinition.Polymorphic = &PolymorphicDefinition{
+				ID: rel.Polymorphic.PolymorphicID.DBName, Type: rel.Polymorphic.PolymorphicType.DBName, Value: rel.Polymorphic.Value,
+			}
+		}
+
+		for _, ref := range rel.References {
+			refDefinition := ReferenceDefinition{PrimaryValue: ref.PrimaryValue, ForeignKey: ref.ForeignKey.DBName, OwnPrimaryKey: ref.OwnPrimaryKey}
+			if ref.PrimaryKey != nil {
+				refDefinition.PrimaryKey = ref.PrimaryKey.DBName
+			}
+			relDefinition.References = append(relDefinition.References, refDefinition)
+		}
+		definition.Relationships = append(definition.Relationships, relDefinition)
+
+		if constraint := rel.ParseConstraint(); constraint != nil && constraint.Schema == schema {
+			constraintDefinition := ConstraintDefinition{
+				Name: constraint.Name, ReferenceTable: constraint.ReferenceSchema.Table, OnDelete: constraint.OnDelete, OnUpdate: constraint.OnUpdate,
+			}
+
+			for _, field := range constraint.ForeignKeys {
+				constraintDefinition.ForeignKeys = append(constraintDefinition.ForeignKeys, field.DBName)
+			}
+
+			for _, field := range constraint.References {
+				constraintDefinition.References = append(constraintDefinition.References, field.DBName)
+			}
+			definition.Constraints = append(definition.Constraints, constraintDefinition)
+		}
+	}
+	sort.Slice(definition.Relationships, func(i, j int) bool {
+		return definition.Relationships[i].Name < definition.Relationships[j].Name
+	})
+	sort.Slice(definition.Constraints, func(i, j int) bool {
+		return definition.Constraints[i].Name < definition.Constraints[j].Name
+	})
+
+	return definition
+}
+
+// MarshalJSON marshals schema as its definition
+func (schema *Schema) MarshalJSON() ([]byte, error) {
+	return json.Marshal(schema.Definition())
+}
+
+// LoadDefinitions load definitions exported by Schema.MarshalJSON from r, both single definition and list of definitions are supported
+func LoadDefinitions(r io.Reader) ([]*Definition, error) {
+	var raw json.RawMessage
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, err
+	}
+
+	var definitions []*Definition
+	if len(raw) > 0 && raw[0] == '[' {
+		if err := json.Unmarshal(raw, &definitions); err != nil {
+			return nil, err
+		}
+	} else {
+		var definition Definition
+		if err := json.Unmarshal(raw, &definition); err != nil {
+			return nil, err
+		}
+		definitions = append(definitions, &definition)
+	}
+
+	for _, definition := range definitions {
+		if definition.Version > DefinitionVersion {
+			return nil, fmt.Errorf("unsupported schema definition version %v of %v", definition.Version, definition.Name)
+		}
+	}
+	return definitions, nil
+}
@@ -0,0 +1,102 @@
+package schema_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sync"
+	"testing"
+
+	"gorm.io/gorm/schema"
+	"gorm.io/gorm/utils/tests"
+)
+
+func TestSchemaDefinition(t *testing.T) {
+	user, err := schema.Parse(&tests.User{}, &sync.Map{}, schema.NamingStrategy{})
+	if err != nil {
+		t.Fatalf("failed to parse user, got error %v", err)
+	}
+
+	data, err := json.Marshal(user)
+	if err != nil {
+		t.Fatalf("failed to marshal schema, got error %v", err)
+	}
+
+	if again, _ := json.Marshal(user); !bytes.Equal(data, again) {
+		t.Errorf("schema definition should be stable, got %s and %s", data, again)
+	}
+
+	definitions, err := schema.LoadDefinitions(bytes.NewReader(data))
+	if err != nil || len(definitions) != 1 {
+		t.Fatalf("failed to load definition, got %v, error %v", definitions, err)
+	}
+
+	definition := definitions[0]
+	if !reflect.DeepEqual(definition, user.Definition()) {
+		t.Errorf("loaded definition should be equal, expects %#v, got %#v", user.Definition(), definition)
+	}
+
+	if definition.Version != schema.DefinitionVersion || definition.Name != "User" || definition.Table != "users" {
+		t.Errorf("incorrect definition, got %#v", definition)
+	}
+
+	if len(definition.Fields) != len(user.DBNames) || definition.Fields[0].DBName != "id" || !definition.Fields[0].PrimaryKey {
+		t.Errorf("incorrect fields, got %#v", definition.Fields)
+	}
+
+	if len(definition.Relationships) != len(user.Relationships.Relations) {
+		t.Errorf("incorrect relationships, got %#v", definition.Relationships)
+	}
+
+	for _, rel := range definition.Relationships {
+		switch rel.Name {
+		case "Company":
+			if rel.Type != schema.BelongsTo || rel.Table != "companies" || rel.References[0].ForeignKey != "company_id" {
+				t.Errorf("incorrect company relationship, got %#v", rel)
+			}
+		case "Languages":
+			if rel.Type != schema.Many2Many || rel.JoinTable != "user_speaks" {
+				t.Errorf("incorrect languages relationship, got %#v", rel)
+			}
+		case "Toys":
+			if rel.Polymorphic == nil || rel.Polymorphic.Type != "owner_type" || rel.Polymorphic.Value != "users" {
+				t.Errorf("incorrect toys relationship, got %#v", rel)
+			}
+		}
+	}
+
+	var hasCompanyConstraint bool
+	for _, constraint := range definition.Constraints {
+		if constraint.ReferenceTable == "companies" && reflect.DeepEqual(constraint.ForeignKeys, []string{"company_id"}) {
+			hasCompanyConstraint = true
+		}
+	}
+
+	if !hasCompanyConstraint {
+		t.Errorf("should have company constraint, got %#v", definition.Constraints)
+	}
+
+	list, _ := json.Marshal([]*schema.Schema{user, user})
+	if definitions, err := schema.LoadDefinitions(bytes.NewReader(list)); err != nil || len(definitions) != 2 {
+		t.Errorf("failed to load list of definitions, got %v, error %v", len(definitions), err)
+	}
+
+	if _, err := schema.LoadDefinitions(bytes.NewReader([]byte(`{"version": 99, "name": "User"}`))); err == nil {
+		t.Errorf("should returns error for unsupported version")
+	}
+}
+
+func TestIndexDefinition(t *testing.T) {
+	user, _ := schema.Parse(&UserIndex{}, &sync.Map{}, schema.NamingStrategy{})
+	definition := user.Definition()
+
+	for idx, index := range definition.Indexes {
+		if idx > 0 && definition.Indexes[idx-1].Name >= index.Name {
+			t.Errorf("indexes should be sorted by name, got %v", definition.Indexes)
+		}
+
+		if index.Name == "idx_name" && (index.Class != "UNIQUE" || index.Fields[0].Field != "name2") {
+			t.Errorf("incorrect index idx_name, got %#v", index)
+		}
+	}
+}
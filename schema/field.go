@@ -20,6 +20,22 @@ type TimeType int64
 
 var TimeReflectType = reflect.TypeOf(time.Time{})
 
+// TimestampMessage protobuf timestamp message like *timestamppb.Timestamp, saved and scanned as time.Time
+type TimestampMessage interface {
+	AsTime() time.Time
+}
+
+// IsTimestampMessage check if the type is a protobuf timestamp message pointer
+func IsTimestampMessage(typ reflect.Type) bool {
+	if typ.Kind() != reflect.Ptr || typ.Elem().Kind() != reflect.Struct || !typ.Implements(reflect.TypeOf((*TimestampMessage)(nil)).Elem()) {
+		return false
+	}
+
+	seconds, hasSeconds := typ.Elem().FieldByName("Seconds")
+	nanos, hasNanos := typ.Elem().FieldByName("Nanos")
+	return hasSeconds && hasNanos && seconds.Type.Kind() == reflect.Int64 && nanos.Type.Kind() == reflect.Int32
+}
+
 const (
 	UnixSecond      TimeType = 1
 	UnixMillisecond TimeType = 2
@@ -27,13 +43,16 @@ const (
 )
 
 const (
-	Bool   DataType = "bool"
-	Int    DataType = "int"
-	Uint   DataType = "uint"
-	Float  DataType = "float"
-	String DataType = "string"
-	Time   DataType = "time"
-	Bytes  DataType = "bytes"
+	Bool     DataType = "bool"
+	Int      DataType = "int"
+	Uint     DataType = "uint"
+	Float    DataType = "float"
+	String   DataType = "string"
+	Time     DataType = "time"
+	Bytes    DataType = "bytes"
+	Enum     DataType = "enum"
+	Array    DataType = "array"
+	Interval DataType = "interval"
 )
 
 type Field struct {
@@ -51,14 +70,30 @@ type Field struct {
 	HasDefaultValue        bool
 	AutoCreateTime         TimeType
 	AutoUpdateTime         TimeType
+	AutoCreatedBy          bool
+	AutoUpdatedBy          bool
 	DefaultValue           string
 	DefaultValueInterface  interface{}
 	NotNull                bool
 	Unique                 bool
 	Comment                string
+	Collation              string
+	Charset                string
+	Using                  string
 	Size                   int
 	Precision              int
 	Scale                  int
+	EnumValues             []string
+	StateTransitions       map[string][]string
+	GeneratedAs            string
+	GeneratedStored        bool
+	Serializer             SerializerInterface
+	JSONPatch              bool
+	Sensitive              bool   // values are masked in logged SQL, e.g: passwords, tokens
+	NullPolicy             string // policy of scanning NULL set with tag `null`, e.g: `null:error`, overrides NullPolicy of config
+	TimeZone               *time.Location
+	ReadTimeZone           *time.Location
+	ValidationRules        []ValidationRule
 	FieldType              reflect.Type
 	IndirectFieldType      reflect.Type
 	StructField            reflect.StructField
@@ -138,6 +173,8 @@ func (schema *Schema) ParseField(fieldStruct reflect.StructField) *Field {
 
 	if dbName, ok := field.TagSettings["COLUMN"]; ok {
 		field.DBName = dbName
+	} else if dbName, _ := parseProtobufNames(fieldStruct.Tag); dbName != "" {
+		field.DBName = dbName
 	}
 
 	if val, ok := field.TagSettings["PRIMARYKEY"]; ok && utils.CheckTruth(val) {
@@ -188,6 +225,20 @@ func (schema *Schema) ParseField(fieldStruct reflect.StructField) *Field {
 		field.Comment = val
 	}
 
+	// collation and charset of column, e.g: `gorm:"collate:utf8mb4_unicode_ci;charset:utf8mb4"`, charset is only supported by mysql
+	if val, ok := field.TagSettings["COLLATE"]; ok {
+		field.Collation = val
+	}
+
+	if val, ok := field.TagSettings["CHARSET"]; ok {
+		field.Charset = val
+	}
+
+	// conversion expression used when changing type of column, e.g: `gorm:"type:integer;using:age::integer"`, supported by postgres
+	if val, ok := field.TagSettings["USING"]; ok {
+		field.Using = val
+	}
+
 	// default value is function or null or blank (primary keys)
 	skipParseDefaultValue := strings.Contains(field.DefaultValue, "(") &&
 		strings.Contains(field.DefaultValue, ")") || strings.ToLower(field.DefaultValue) == "null" || field.DefaultValue == ""
@@ -235,10 +286,23 @@ func (schema *Schema) ParseField(fieldStruct reflect.StructField) *Field {
 			field.DataType = Time
 		} else if fieldValue.Type().ConvertibleTo(reflect.TypeOf(&time.Time{})) {
 			field.DataType = Time
+		} else if IsTimestampMessage(field.FieldType) {
+			field.DataType = Time
 		}
 	case reflect.Array, reflect.Slice:
 		if reflect.Indirect(fieldValue).Type().Elem() == reflect.TypeOf(uint8(0)) {
 			field.DataType = Bytes
+		} else if IsArrayType(reflect.Indirect(fieldValue).Type()) {
+			field.DataType = Array
+		}
+	}
+
+	// durations are saved as interval for postgres, microseconds for others with tag `interval`
+	if _, ok := field.TagSettings["INTERVAL"]; ok {
+		if field.IndirectFieldType == DurationReflectType {
+			field.DataType = Interval
+		} else {
+			schema.err = fmt.Errorf("interval requires time.Duration for field %v", field.Name)
 		}
 	}
 
@@ -268,19 +332,102 @@ func (schema *Schema) ParseField(fieldStruct reflect.StructField) *Field {
 		}
 	}
 
+	// actor of context is assigned to fields tagged with `autoCreatedBy` and `autoUpdatedBy`, check Config.ActorResolver
+	if _, ok := field.TagSettings["AUTOCREATEDBY"]; ok {
+		field.AutoCreatedBy = true
+	}
+
+	if _, ok := field.TagSettings["AUTOUPDATEDBY"]; ok {
+		field.AutoUpdatedBy = true
+	}
+
 	if val, ok := field.TagSettings["TYPE"]; ok {
 		switch DataType(strings.ToLower(val)) {
 		case Bool, Int, Uint, Float, String, Time, Bytes:
 			field.DataType = DataType(strings.ToLower(val))
+		case Enum:
+			field.DataType = String
 		default:
 			field.DataType = DataType(val)
 		}
 	}
 
+	if val, ok := field.TagSettings["VALUES"]; ok {
+		for _, v := range strings.Split(val, ",") {
+			field.EnumValues = append(field.EnumValues, strings.TrimSpace(v))
+		}
+	} else if enum, ok := fieldValue.Interface().(EnumValuesInterface); ok {
+		field.EnumValues = enum.EnumValues()
+	}
+
+	if machine, ok := fieldValue.Interface().(StateTransitionsInterface); ok {
+		field.StateTransitions = machine.StateTransitions()
+	}
+
+	if name, ok := field.TagSettings["SERIALIZER"]; ok {
+		if field.Serializer, ok = GetSerializer(name); !ok {
+			schema.err = fmt.Errorf("invalid serializer type %v for field %v", name, field.Name)
+		}
+	} else if serializer, ok := GetTypeSerializer(field.FieldType); ok {
+		field.Serializer = serializer
+	} else if serializer, ok := GetTypeSerializer(field.IndirectFieldType); ok {
+		field.Serializer = serializer
+	}
+
+	if val, ok := field.TagSettings["SENSITIVE"]; ok && utils.CheckTruth(val) {
+		field.Sensitive = true
+	}
+
+	if val, ok := field.TagSettings["NULL"]; ok {
+		field.NullPolicy = strings.ToLower(val)
+	}
+
+	// only changed keys of json fields are updated with jsonPatch, e.g: `gorm:"serializer:json;jsonPatch"`
+	if val, ok := field.TagSettings["JSONPATCH"]; ok && utils.CheckTruth(val) {
+		switch field.Serializer.(type) {
+		case JSONSerializer, *JSONSerializer:
+			field.JSONPatch = true
+		default:
+			schema.err = fmt.Errorf("jsonPatch requires json serializer for field %v", field.Name)
+		}
+	}
+
+	if _, ok := field.TagSettings["TYPE"]; !ok && field.Serializer != nil {
+		field.DataType = String
+		field.GORMDataType = String
+	}
+
 	if field.GORMDataType == "" {
 		field.GORMDataType = field.DataType
 	}
 
+	// time values are converted to time zone when saving and reading, e.g: `gorm:"timezone:UTC;readTimezone:Asia/Shanghai"`,
+	// time values of fields with serializer are converted to time zone before serializing, e.g: `gorm:"timezone:UTC;serializer:json"`
+	for _, key := range []string{"TIMEZONE", "READTIMEZONE"} {
+		if name, ok := field.TagSettings[key]; ok {
+			serializedTime := key == "TIMEZONE" && field.Serializer != nil && field.IndirectFieldType.ConvertibleTo(TimeReflectType)
+			loc, err := time.LoadLocation(name)
+			if err != nil {
+				schema.err = fmt.Errorf("invalid time zone %v for field %v: %w", name, field.Name, err)
+			} else if field.GORMDataType != Time && !serializedTime {
+				schema.err = fmt.Errorf("time zone requires time field for field %v", field.Name)
+			} else if key == "TIMEZONE" {
+				field.TimeZone = loc
+			} else {
+				field.ReadTimeZone = loc
+			}
+		}
+	}
+
+	// values are validated before creating and updating, e.g: `gorm:"validate:required,max=255"`
+	if val, ok := field.TagSettings["VALIDATE"]; ok {
+		rules, err := parseValidationRules(val)
+		if err != nil {
+			schema.err = fmt.Errorf("%w for field %v", err, field.Name)
+		}
+		field.ValidationRules = rules
+	}
+
 	if field.Size == 0 {
 		switch reflect.Indirect(fieldValue).Kind() {
 		case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64, reflect.Float64:
@@ -327,6 +474,16 @@ func (schema *Schema) ParseField(fieldStruct reflect.StructField) *Field {
 		}
 	}
 
+	// generated columns are computed by database, e.g: `gorm:"->;generatedAs:price*quantity;stored"`
+	if expr, ok := field.TagSettings["GENERATEDAS"]; ok && expr != "" {
+		field.GeneratedAs = expr
+		field.Creatable = false
+		field.Updatable = false
+		if val, ok := field.TagSettings["STORED"]; ok && utils.CheckTruth(val) {
+			field.GeneratedStored = true
+		}
+	}
+
 	if _, ok := field.TagSettings["EMBEDDED"]; ok || (fieldStruct.Anonymous && !isValuer && (field.Creatable || field.Updatable || field.Readable)) {
 		if reflect.Indirect(fieldValue).Kind() == reflect.Struct {
 			var err error
@@ -822,4 +979,48 @@ func (field *Field) setupValuerAndSetter() {
 			}
 		}
 	}
+
+	if IsTimestampMessage(field.FieldType) {
+		field.setupTimestampMessage(fallbackSetter)
+	}
+	field.setupFastAccessors()
+}
+
+// setupTimestampMessage converts protobuf timestamp message from/to time.Time
+func (field *Field) setupTimestampMessage(fallbackSetter func(reflect.Value, interface{}, func(reflect.Value, interface{}) error) error) {
+	valueOf := field.ValueOf
+	field.ValueOf = func(value reflect.Value) (interface{}, bool) {
+		fieldValue, zero := valueOf(value)
+		if msg, ok := fieldValue.(TimestampMessage); ok && !zero {
+			return msg.AsTime(), false
+		}
+		return nil, true
+	}
+
+	field.Set = func(value reflect.Value, v interface{}) error {
+		var t time.Time
+		switch data := v.(type) {
+		case time.Time:
+			t = data
+		case *time.Time:
+			if data == nil {
+				field.ReflectValueOf(value).Set(reflect.New(field.FieldType).Elem())
+				return nil
+			}
+			t = *data
+		case string:
+			var err error
+			if t, err = now.Parse(data); err != nil {
+				return fmt.Errorf("failed to set string %v to timestamp field %v, failed to parse it as time, got error %v", v, field.Name, err)
+			}
+		default:
+			return fallbackSetter(value, v, field.Set)
+		}
+
+		msg := reflect.New(field.IndirectFieldType)
+		msg.Elem().FieldByName("Seconds").SetInt(t.Unix())
+		msg.Elem().FieldByName("Nanos").SetInt(int64(t.Nanosecond()))
+		field.ReflectValueOf(value).Set(msg)
+		return nil
+	}
 }
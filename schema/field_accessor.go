@@ -0,0 +1,125 @@
+package schema
+
+import (
+	"reflect"
+	"time"
+	"unsafe"
+)
+
+// setupFastAccessors speeds up ValueOf and Set of fields of common types, values are read and written through offsets
+// of fields in addressable structs, pure reflection is used for other values and fields behind embedded pointers
+func (field *Field) setupFastAccessors() {
+	if field.Schema == nil || field.Schema.ModelType == nil || field.Schema.ModelType.Kind() != reflect.Struct {
+		return
+	}
+
+	var (
+		offset    uintptr
+		ownerType = field.Schema.ModelType
+		fieldType = ownerType
+	)
+
+	for _, idx := range field.StructField.Index {
+		// fields of embedded pointers are allocated when set
+		if idx < 0 || fieldType.Kind() != reflect.Struct || idx >= fieldType.NumField() {
+			return
+		}
+
+		structField := fieldType.Field(idx)
+		offset += structField.Offset
+		fieldType = structField.Type
+	}
+
+	if fieldType != field.FieldType {
+		return
+	}
+
+	var (
+		getter func(unsafe.Pointer) (interface{}, bool)
+		setter func(unsafe.Pointer, interface{}) bool
+	)
+
+	switch fieldType {
+	case reflect.TypeOf(""):
+		getter, setter = fastValueOf[string](), fastSet[string]()
+	case reflect.TypeOf(int(0)):
+		getter, setter = fastValueOf[int](), fastSet[int]()
+	case reflect.TypeOf(int32(0)):
+		getter, setter = fastValueOf[int32](), fastSet[int32]()
+	case reflect.TypeOf(int64(0)):
+		getter, setter = fastValueOf[int64](), fastSet[int64]()
+	case reflect.TypeOf(uint(0)):
+		getter, setter = fastValueOf[uint](), fastSet[uint]()
+	case reflect.TypeOf(uint32(0)):
+		getter, setter = fastValueOf[uint32](), fastSet[uint32]()
+	case reflect.TypeOf(uint64(0)):
+		getter, setter = fastValueOf[uint64](), fastSet[uint64]()
+	case reflect.TypeOf(float64(0)):
+		getter, setter = fastValueOf[float64](), fastSet[float64]()
+	case reflect.TypeOf(false):
+		getter, setter = fastValueOf[bool](), fastSet[bool]()
+	case TimeReflectType:
+		getter, setter = fastValueOf[time.Time](), fastSet[time.Time]()
+	default:
+		return
+	}
+
+	pointerOf := func(value reflect.Value) (unsafe.Pointer, bool) {
+		value = reflect.Indirect(value)
+		if !value.CanAddr() || value.Type() != ownerType {
+			return nil, false
+		}
+		return unsafe.Add(unsafe.Pointer(value.UnsafeAddr()), offset), true
+	}
+
+	valueOf, set := field.ValueOf, field.Set
+	field.ValueOf = func(value reflect.Value) (interface{}, bool) {
+		if p, ok := pointerOf(value); ok {
+			return getter(p)
+		}
+		return valueOf(value)
+	}
+
+	field.Set = func(value reflect.Value, v interface{}) error {
+		if p, ok := pointerOf(value); ok && setter(p, v) {
+			return nil
+		}
+		return set(value, v)
+	}
+}
+
+// fastValueOf returns getter of value of type T at pointer
+func fastValueOf[T comparable]() func(unsafe.Pointer) (interface{}, bool) {
+	var zero T
+	return func(p unsafe.Pointer) (interface{}, bool) {
+		v := *(*T)(p)
+		return v, v == zero
+	}
+}
+
+// fastSet returns setter of value of type T at pointer, values of T, *T and **T scanned from rows are accepted,
+// nil pointers are set as zero value, it returns false for values of other types
+func fastSet[T any]() func(unsafe.Pointer, interface{}) bool {
+	return func(p unsafe.Pointer, v interface{}) bool {
+		var zero T
+		switch data := v.(type) {
+		case T:
+			*(*T)(p) = data
+		case *T:
+			if data == nil {
+				*(*T)(p) = zero
+			} else {
+				*(*T)(p) = *data
+			}
+		case **T:
+			if data == nil || *data == nil {
+				*(*T)(p) = zero
+			} else {
+				*(*T)(p) = **data
+			}
+		default:
+			return false
+		}
+		return true
+	}
+}
@@ -258,3 +258,71 @@ func TestParseFieldWithPermission(t *testing.T) {
 		checkSchemaField(t, user, &f, func(f *schema.Field) {})
 	}
 }
+
+func TestFieldFastAccessors(t *testing.T) {
+	type FastEmbedded struct {
+		Code  string
+		Score float64
+	}
+
+	type FastPtrEmbedded struct {
+		Note string
+	}
+
+	type FastName string
+
+	type FastAccessor struct {
+		ID        uint
+		Name      string
+		Alias     FastName
+		Active    bool
+		CreatedAt time.Time
+		FastEmbedded
+		*FastPtrEmbedded
+	}
+
+	s, err := schema.Parse(&FastAccessor{}, &sync.Map{}, schema.NamingStrategy{})
+	if err != nil {
+		t.Fatalf("failed to parse schema, got error %v", err)
+	}
+
+	var (
+		now   = time.Now()
+		name  = "jinzhu"
+		pname = &name
+		value FastAccessor
+		rv    = reflect.ValueOf(&value)
+	)
+
+	for field, v := range map[string]interface{}{"ID": uint(3), "Name": &pname, "Alias": "alias", "Active": true, "CreatedAt": now, "Code": "code", "Score": "1.5", "Note": "note"} {
+		if err := s.LookUpField(field).Set(rv, v); err != nil {
+			t.Fatalf("failed to set %v, got error %v", field, err)
+		}
+	}
+
+	expected := FastAccessor{ID: 3, Name: name, Alias: "alias", Active: true, CreatedAt: now, FastEmbedded: FastEmbedded{Code: "code", Score: 1.5}, FastPtrEmbedded: &FastPtrEmbedded{Note: "note"}}
+	if !reflect.DeepEqual(value, expected) {
+		t.Errorf("invalid values, expects %+v, got %+v", expected, value)
+	}
+
+	var nilName *string
+	s.LookUpField("Name").Set(rv, &nilName)
+	if value.Name != "" {
+		t.Errorf("should set zero value for nil pointer, got %v", value.Name)
+	}
+
+	// values of non-addressable structs are read with reflection
+	for _, v := range []reflect.Value{rv, reflect.ValueOf(value)} {
+		if code, isZero := s.LookUpField("Code").ValueOf(v); code != "code" || isZero {
+			t.Errorf("invalid value of Code, got %v, %v", code, isZero)
+		}
+
+		if name, isZero := s.LookUpField("Name").ValueOf(v); name != "" || !isZero {
+			t.Errorf("invalid value of Name, got %v, %v", name, isZero)
+		}
+
+		if createdAt, isZero := s.LookUpField("CreatedAt").ValueOf(v); createdAt != now || isZero {
+			t.Errorf("invalid value of CreatedAt, got %v, %v", createdAt, isZero)
+		}
+	}
+}
@@ -12,7 +12,9 @@ type Index struct {
 	Type    string // btree, hash, gist, spgist, gin, and brin
 	Where   string
 	Comment string
-	Option  string // WITH PARSER parser_name
+	Option  string   // WITH PARSER parser_name
+	Include []string // non-key columns of covering index, e.g: `include:name\,age`
+	With    string   // storage parameters, e.g: `with:fillfactor=70`
 	Fields  []IndexOption
 }
 
@@ -49,6 +51,23 @@ func (schema *Schema) ParseIndexes() map[string]Index {
 				if idx.Option == "" {
 					idx.Option = index.Option
 				}
+				if idx.With == "" {
+					idx.With = index.With
+				}
+
+			include:
+				for _, name := range index.Include {
+					if f := schema.LookUpField(name); f != nil {
+						name = f.DBName
+					}
+
+					for _, n := range idx.Include {
+						if n == name {
+							continue include
+						}
+					}
+					idx.Include = append(idx.Include, name)
+				}
 
 				idx.Fields = append(idx.Fields, index.Fields...)
 				sort.Slice(idx.Fields, func(i, j int) bool {
@@ -82,6 +101,72 @@ func (schema *Schema) LookIndex(name string) *Index {
 	return nil
 }
 
+// HasUniqueKey check the columns are exactly the primary keys, an unique field or the fields of an unique index
+func (schema *Schema) HasUniqueKey(dbNames ...string) bool {
+	if len(dbNames) == 0 {
+		return false
+	}
+
+	sameColumns := func(names []string) bool {
+		if len(names) != len(dbNames) {
+			return false
+		}
+
+		for _, name := range names {
+			found := false
+			for _, dbName := range dbNames {
+				if name == dbName {
+					found = true
+					break
+				}
+			}
+
+			if !found {
+				return false
+			}
+		}
+		return true
+	}
+
+	if sameColumns(schema.PrimaryFieldDBNames) {
+		return true
+	}
+
+	if len(dbNames) == 1 {
+		if field := schema.LookUpField(dbNames[0]); field != nil && field.Unique {
+			return true
+		}
+	}
+
+	for _, names := range schema.uniqueKeys {
+		if sameColumns(names) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseUniqueKeys returns columns of unique indexes without conditions and expressions, they are parsed once with schema
+func (schema *Schema) parseUniqueKeys() (keys [][]string) {
+	for _, index := range schema.ParseIndexes() {
+		if index.Class == "UNIQUE" && index.Where == "" {
+			names := make([]string, 0, len(index.Fields))
+			for _, field := range index.Fields {
+				if field.Expression != "" {
+					break
+				}
+				names = append(names, field.DBName)
+			}
+
+			if len(names) == len(index.Fields) {
+				keys = append(keys, names)
+			}
+		}
+	}
+	return
+}
+
 func parseFieldIndexes(field *Field) (indexes []Index) {
 	for _, value := range strings.Split(field.Tag.Get("gorm"), ";") {
 		if value != "" {
@@ -117,6 +202,13 @@ func parseFieldIndexes(field *Field) (indexes []Index) {
 					priority = 10
 				}
 
+				var include []string
+				for _, column := range strings.Split(settings["INCLUDE"], ",") {
+					if column = strings.TrimSpace(column); column != "" {
+						include = append(include, column)
+					}
+				}
+
 				indexes = append(indexes, Index{
 					Name:    name,
 					Class:   settings["CLASS"],
@@ -124,6 +216,8 @@ func parseFieldIndexes(field *Field) (indexes []Index) {
 					Where:   settings["WHERE"],
 					Comment: settings["COMMENT"],
 					Option:  settings["OPTION"],
+					Include: include,
+					With:    settings["WITH"],
 					Fields: []IndexOption{{
 						Field:      field,
 						Expression: settings["EXPRESSION"],
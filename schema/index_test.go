@@ -18,6 +18,7 @@ type UserIndex struct {
 	Age          int64  `gorm:"index:profile,expression:ABS(age),option:WITH PARSER parser_name"`
 	OID          int64  `gorm:"index:idx_id;index:idx_oid,unique"`
 	MemberNumber string `gorm:"index:idx_id,priority:1"`
+	Name7        string `gorm:"index:idx_covering,include:name\\, Age,with:fillfactor=70"`
 }
 
 func TestParseIndex(t *testing.T) {
@@ -78,6 +79,12 @@ func TestParseIndex(t *testing.T) {
 			Class:  "UNIQUE",
 			Fields: []schema.IndexOption{{Field: &schema.Field{Name: "OID"}}},
 		},
+		"idx_covering": {
+			Name:    "idx_covering",
+			Include: []string{"name", "age"},
+			With:    "fillfactor=70",
+			Fields:  []schema.IndexOption{{Field: &schema.Field{Name: "Name7"}}},
+		},
 	}
 
 	indices := user.ParseIndexes()
@@ -88,7 +95,11 @@ func TestParseIndex(t *testing.T) {
 			t.Fatalf("Failed to found index %v from parsed indices %+v", k, indices)
 		}
 
-		for _, name := range []string{"Name", "Class", "Type", "Where", "Comment", "Option"} {
+		if !reflect.DeepEqual(result.Include, v.Include) {
+			t.Errorf("index %v INCLUDE columns should equal, expects %v, got %v", k, result.Include, v.Include)
+		}
+
+		for _, name := range []string{"Name", "Class", "Type", "Where", "Comment", "Option", "With"} {
 			if reflect.ValueOf(result).FieldByName(name).Interface() != reflect.ValueOf(v).FieldByName(name).Interface() {
 				t.Errorf(
 					"index %v %v should equal, expects %v, got %v",
@@ -114,3 +125,22 @@ func TestParseIndex(t *testing.T) {
 		}
 	}
 }
+
+func TestHasUniqueKey(t *testing.T) {
+	user, err := schema.Parse(&UserIndex{}, &sync.Map{}, schema.NamingStrategy{})
+	if err != nil {
+		t.Fatalf("failed to parse user index, got error %v", err)
+	}
+
+	for _, names := range [][]string{{"name2"}, {"name4"}, {"o_id"}} {
+		if !user.HasUniqueKey(names...) {
+			t.Errorf("%v should be an unique key", names)
+		}
+	}
+
+	for _, names := range [][]string{{}, {"name"}, {"name3"}, {"name2", "name4"}, {"member_number", "o_id"}} {
+		if user.HasUniqueKey(names...) {
+			t.Errorf("%v should not be an unique key", names)
+		}
+	}
+}
@@ -8,6 +8,25 @@ type GormDataTypeInterface interface {
 	GormDataType() string
 }
 
+// EnumValuesInterface string enum types, values will be validated and constrained with CHECK when migrating
+type EnumValuesInterface interface {
+	EnumValues() []string
+}
+
+// StateTransitionsInterface state machine types, declares states allowed to transition to from each state, e.g:
+// `{"draft": {"paid", "cancelled"}, "paid": {"shipped"}}`, updating fields of the type is validated with the transitions
+type StateTransitionsInterface interface {
+	StateTransitions() map[string][]string
+}
+
+// RelationshipsDefiner define relationships of fields without struct tags
+//     func (User) DefineRelationships(s *schema.Schema) {
+//       s.DefineRelationship("Company", "foreignKey:CompanyRefer")
+//     }
+type RelationshipsDefiner interface {
+	DefineRelationships(*Schema)
+}
+
 type CreateClausesInterface interface {
 	CreateClauses(*Field) []clause.Interface
 }
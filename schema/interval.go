@@ -0,0 +1,143 @@
+package schema
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DurationReflectType reflect type of time.Duration
+var DurationReflectType = reflect.TypeOf(time.Duration(0))
+
+// IntervalValue returns database value of duration, saved as interval like `90000000 microseconds` if postgres is true,
+// otherwise as microseconds
+func IntervalValue(value interface{}, postgres bool) (interface{}, error) {
+	rv := reflect.ValueOf(value)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil, nil
+		}
+		rv = rv.Elem()
+	}
+
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		micros := time.Duration(rv.Int()).Microseconds()
+		if postgres {
+			return strconv.FormatInt(micros, 10) + " microseconds", nil
+		}
+		return micros, nil
+	}
+	return value, nil
+}
+
+// ScanInterval scan database value of interval into field, both postgres interval and microseconds are supported
+func (field *Field) ScanInterval(dst reflect.Value, dbValue interface{}) error {
+	fieldValue := reflect.New(field.FieldType)
+	if dbValue != nil {
+		var duration time.Duration
+		switch v := dbValue.(type) {
+		case int64:
+			duration = time.Duration(v) * time.Microsecond
+		case []byte, string:
+			var err error
+			if duration, err = ParseInterval(fmt.Sprintf("%s", v)); err != nil {
+				return fmt.Errorf("failed to scan interval value into field %v: %w", field.Name, err)
+			}
+		default:
+			return fmt.Errorf("failed to scan interval value %#v into field %v", dbValue, field.Name)
+		}
+
+		elem := fieldValue.Elem()
+		if elem.Kind() == reflect.Ptr {
+			elem.Set(reflect.New(field.IndirectFieldType))
+			elem = elem.Elem()
+		}
+		elem.SetInt(int64(duration))
+	}
+
+	field.ReflectValueOf(dst).Set(fieldValue.Elem())
+	return nil
+}
+
+var intervalUnits = map[string]time.Duration{
+	"microsecond": time.Microsecond,
+	"millisecond": time.Millisecond,
+	"second":      time.Second,
+	"sec":         time.Second,
+	"minute":      time.Minute,
+	"min":         time.Minute,
+	"hour":        time.Hour,
+	"day":         24 * time.Hour,
+	"week":        7 * 24 * time.Hour,
+	"month":       30 * 24 * time.Hour,
+	"mon":         30 * 24 * time.Hour,
+	"year":        365 * 24 * time.Hour,
+}
+
+// ParseInterval parse microseconds or postgres interval like `1 year 2 mons 3 days -04:05:06.5`,
+// months are treated as 30 days and years as 365 days
+func ParseInterval(str string) (time.Duration, error) {
+	str = strings.TrimPrefix(strings.TrimSpace(str), "@")
+	if micros, err := strconv.ParseInt(strings.TrimSpace(str), 10, 64); err == nil {
+		return time.Duration(micros) * time.Microsecond, nil
+	}
+
+	var (
+		result time.Duration
+		fields = strings.Fields(str)
+	)
+
+	for idx := 0; idx < len(fields); idx++ {
+		if strings.Contains(fields[idx], ":") {
+			duration, err := parseIntervalClock(fields[idx])
+			if err != nil {
+				return 0, err
+			}
+			result += duration
+			continue
+		}
+
+		if idx+1 >= len(fields) {
+			return 0, fmt.Errorf("invalid interval %v", str)
+		}
+
+		value, err := strconv.ParseFloat(fields[idx], 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid interval %v", str)
+		}
+
+		idx++
+		unit, ok := intervalUnits[strings.TrimSuffix(strings.ToLower(fields[idx]), "s")]
+		if !ok {
+			return 0, fmt.Errorf("invalid interval unit %v", fields[idx])
+		}
+		result += time.Duration(value * float64(unit))
+	}
+	return result, nil
+}
+
+// parseIntervalClock parse clock part of interval like `-04:05:06.5`
+func parseIntervalClock(str string) (time.Duration, error) {
+	negative := strings.HasPrefix(str, "-")
+	parts := strings.Split(strings.TrimLeft(str, "+-"), ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, fmt.Errorf("invalid interval %v", str)
+	}
+
+	var result time.Duration
+	for idx, unit := range []time.Duration{time.Hour, time.Minute, time.Second}[:len(parts)] {
+		value, err := strconv.ParseFloat(parts[idx], 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid interval %v", str)
+		}
+		result += time.Duration(value * float64(unit))
+	}
+
+	if negative {
+		return -result, nil
+	}
+	return result, nil
+}
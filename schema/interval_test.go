@@ -0,0 +1,48 @@
+package schema_test
+
+import (
+	"testing"
+	"time"
+
+	"gorm.io/gorm/schema"
+)
+
+func TestParseInterval(t *testing.T) {
+	results := map[string]time.Duration{
+		"90000000":              90 * time.Second,
+		"01:30:00":              90 * time.Minute,
+		"-00:00:01.5":           -1500 * time.Millisecond,
+		"3 days 04:05:06":       3*24*time.Hour + 4*time.Hour + 5*time.Minute + 6*time.Second,
+		"1 year 2 mons -1 days": 365*24*time.Hour + 60*24*time.Hour - 24*time.Hour,
+		"@ 2 hours 30 mins":     150 * time.Minute,
+		"1500000 microseconds":  1500 * time.Millisecond,
+		"1 day -01:00:00":       23 * time.Hour,
+	}
+
+	for str, expected := range results {
+		if duration, err := schema.ParseInterval(str); err != nil || duration != expected {
+			t.Errorf("failed to parse interval %v, expects %v, got %v, error %v", str, expected, duration, err)
+		}
+	}
+
+	for _, str := range []string{"1 fortnight", "1 day x", "1:2:3:4"} {
+		if _, err := schema.ParseInterval(str); err == nil {
+			t.Errorf("should returns error when parsing invalid interval %v", str)
+		}
+	}
+}
+
+func TestIntervalValue(t *testing.T) {
+	duration := 1500 * time.Millisecond
+	if value, _ := schema.IntervalValue(duration, true); value != "1500000 microseconds" {
+		t.Errorf("incorrect postgres interval value, got %v", value)
+	}
+
+	if value, _ := schema.IntervalValue(&duration, false); value != int64(1500000) {
+		t.Errorf("incorrect interval value, got %v", value)
+	}
+
+	if value, _ := schema.IntervalValue((*time.Duration)(nil), false); value != nil {
+		t.Errorf("nil interval should be saved as NULL, got %v", value)
+	}
+}
@@ -146,3 +146,25 @@ func (ns NamingStrategy) toDBName(name string) string {
 	smap.Store(name, ret)
 	return ret
 }
+
+// ToFieldName convert db name to go field name, common initialisms are upper cased, e.g: company_id -> CompanyID
+func ToFieldName(dbName string) string {
+	var buf strings.Builder
+	for _, part := range strings.FieldsFunc(dbName, func(r rune) bool { return r == '_' || r == '-' || r == ' ' || r == '.' }) {
+		upper := strings.ToUpper(part)
+		isInitialism := false
+		for _, initialism := range commonInitialisms {
+			if upper == initialism {
+				isInitialism = true
+				break
+			}
+		}
+
+		if isInitialism {
+			buf.WriteString(upper)
+		} else {
+			buf.WriteString(strings.ToUpper(part[:1]) + part[1:])
+		}
+	}
+	return buf.String()
+}
@@ -72,3 +72,20 @@ func TestNamingStrategy(t *testing.T) {
 		t.Errorf("invalid column name generated, got %v", columdName)
 	}
 }
+
+func TestToFieldName(t *testing.T) {
+	maps := map[string]string{
+		"company_id":  "CompanyID",
+		"created_at":  "CreatedAt",
+		"api_url":     "APIURL",
+		"user_uuid":   "UserUUID",
+		"name":        "Name",
+		"nickname_v2": "NicknameV2",
+	}
+
+	for dbName, fieldName := range maps {
+		if result := ToFieldName(dbName); result != fieldName {
+			t.Errorf("%v's field name should be %v, but got %v", dbName, fieldName, result)
+		}
+	}
+}
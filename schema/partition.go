@@ -0,0 +1,72 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PartitionType partitioning method of table
+type PartitionType string
+
+const (
+	PartitionRange PartitionType = "RANGE"
+	PartitionList  PartitionType = "LIST"
+	PartitionHash  PartitionType = "HASH"
+)
+
+// PartitionSpec partitioning of table, declared with field tag `partition`, e.g: `gorm:"partition:range"`,
+// or returned by method `PartitionSpec() PartitionSpec` of model
+type PartitionSpec struct {
+	Type    PartitionType
+	Columns []string
+}
+
+// Partitioner model declares its partitioning
+type Partitioner interface {
+	PartitionSpec() PartitionSpec
+}
+
+// parsePartitionSpec parses partitioning of schema, returns nil if table is not partitioned
+func (schema *Schema) parsePartitionSpec(partitioner interface{}) (*PartitionSpec, error) {
+	spec := &PartitionSpec{}
+	if p, ok := partitioner.(Partitioner); ok {
+		*spec = p.PartitionSpec()
+		spec.Columns = append([]string(nil), spec.Columns...)
+		for idx, column := range spec.Columns {
+			field := schema.LookUpField(column)
+			if field == nil {
+				return nil, fmt.Errorf("invalid partition column %v of schema %v", column, schema)
+			}
+			spec.Columns[idx] = field.DBName
+		}
+	} else {
+		for _, field := range schema.Fields {
+			if value, ok := field.TagSettings["PARTITION"]; ok && field.DBName != "" {
+				partitionType := PartitionType(strings.ToUpper(strings.TrimSpace(value)))
+				if partitionType == "" || partitionType == "PARTITION" {
+					partitionType = PartitionRange
+				}
+
+				if spec.Type != "" && spec.Type != partitionType {
+					return nil, fmt.Errorf("conflicting partition types %v, %v of schema %v", spec.Type, partitionType, schema)
+				}
+				spec.Type = partitionType
+				spec.Columns = append(spec.Columns, field.DBName)
+			}
+		}
+	}
+
+	if len(spec.Columns) == 0 {
+		return nil, nil
+	}
+
+	if spec.Type == "" {
+		spec.Type = PartitionRange
+	}
+
+	switch spec.Type {
+	case PartitionRange, PartitionList, PartitionHash:
+		return spec, nil
+	}
+	return nil, fmt.Errorf("unsupported partition type %v of schema %v", spec.Type, schema)
+}
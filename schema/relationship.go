@@ -1,10 +1,12 @@
 package schema
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
 	"regexp"
 	"strings"
+	"sync"
 
 	"github.com/jinzhu/inflection"
 	"gorm.io/gorm/clause"
@@ -54,6 +56,45 @@ type Reference struct {
 	OwnPrimaryKey bool
 }
 
+type relationshipsKey struct {
+	reflect.Type
+}
+
+// RegisterRelationships register relationships definition of model into cacheStore, used for models that can't
+// implement RelationshipsDefiner, it should be registered before model is parsed
+func RegisterRelationships(cacheStore *sync.Map, model interface{}, fc func(*Schema)) error {
+	modelType := reflect.ValueOf(model).Type()
+	for modelType.Kind() == reflect.Slice || modelType.Kind() == reflect.Array || modelType.Kind() == reflect.Ptr {
+		modelType = modelType.Elem()
+	}
+
+	if modelType.Kind() != reflect.Struct {
+		return fmt.Errorf("%w: %v", ErrUnsupportedDataType, modelType)
+	}
+
+	if _, ok := cacheStore.Load(modelType); ok {
+		return fmt.Errorf("relationships of %v should be defined before it is parsed", modelType)
+	}
+
+	cacheStore.Store(relationshipsKey{modelType}, fc)
+	return nil
+}
+
+// DefineRelationship define relationship of field with tag settings, e.g:
+//     s.DefineRelationship("Company", "foreignKey:CompanyRefer;references:ID")
+//     s.DefineRelationship("Languages", "many2many:user_languages")
+func (schema *Schema) DefineRelationship(fieldName string, tag string) {
+	field := schema.LookUpField(fieldName)
+	if field == nil {
+		schema.err = fmt.Errorf("failed to define relationship, missing field %v for %v", fieldName, schema)
+		return
+	}
+
+	for key, value := range ParseTagSetting(tag, ";") {
+		field.TagSettings[key] = value
+	}
+}
+
 func (schema *Schema) parseRelation(field *Field) *Relationship {
 	var (
 		err        error
@@ -483,6 +524,12 @@ func (schema *Schema) guessRelation(relation *Relationship, field *Field, gl gue
 	}
 }
 
+// ErrInvalidConstraint invalid foreign key constraint, e.g: unknown OnDelete action
+var ErrInvalidConstraint = errors.New("invalid constraint")
+
+// constraintActions supported OnDelete, OnUpdate actions
+var constraintActions = map[string]bool{"CASCADE": true, "SET NULL": true, "SET DEFAULT": true, "RESTRICT": true, "NO ACTION": true}
+
 type Constraint struct {
 	Name            string
 	Field           *Field
@@ -537,6 +584,36 @@ func (rel *Relationship) ParseConstraint() *Constraint {
 	return &constraint
 }
 
+// Validate checks OnDelete, OnUpdate actions of constraint, `SET NULL` requires nullable foreign keys,
+// `SET DEFAULT` requires default values for not null foreign keys
+func (constraint *Constraint) Validate() error {
+	for _, action := range []string{constraint.OnDelete, constraint.OnUpdate} {
+		action = NormalizeConstraintAction(action)
+		if action == "" {
+			continue
+		}
+
+		if !constraintActions[action] {
+			return fmt.Errorf("%w: unsupported action %v of %v", ErrInvalidConstraint, action, constraint.Name)
+		}
+
+		for _, field := range constraint.ForeignKeys {
+			switch {
+			case action == "SET NULL" && (field.NotNull || field.PrimaryKey):
+				return fmt.Errorf("%w: %v of %v can't set not null foreign key %v to NULL", ErrInvalidConstraint, action, constraint.Name, field.Name)
+			case action == "SET DEFAULT" && (field.NotNull || field.PrimaryKey) && !field.HasDefaultValue:
+				return fmt.Errorf("%w: %v of %v requires default value of foreign key %v", ErrInvalidConstraint, action, constraint.Name, field.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// NormalizeConstraintAction returns upper-cased action with single spaces, e.g: `set  null` => `SET NULL`
+func NormalizeConstraintAction(action string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(action), " "))
+}
+
 func (rel *Relationship) ToQueryConditions(reflectValue reflect.Value) (conds []clause.Expression) {
 	table := rel.FieldSchema.Table
 	foreignFields := []*Field{}
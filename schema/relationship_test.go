@@ -1,6 +1,7 @@
 package schema_test
 
 import (
+	"errors"
 	"sync"
 	"testing"
 
@@ -433,3 +434,88 @@ func TestEmbeddedRelation(t *testing.T) {
 		t.Fatalf("expects created by relations, but not found")
 	}
 }
+
+type DefinedProfile struct {
+	gorm.Model
+	Refer string
+}
+
+type DefinedUser struct {
+	gorm.Model
+	Profile   DefinedProfile
+	ProfileID string
+}
+
+func (DefinedUser) DefineRelationships(s *schema.Schema) {
+	s.DefineRelationship("Profile", "foreignKey:ProfileID;references:Refer")
+}
+
+func TestDefineRelationships(t *testing.T) {
+	checkStructRelation(t, &DefinedUser{}, Relation{
+		Name: "Profile", Type: schema.BelongsTo, Schema: "DefinedUser", FieldSchema: "DefinedProfile",
+		References: []Reference{{"Refer", "DefinedProfile", "ProfileID", "DefinedUser", "", false}},
+	})
+
+	type Profile struct {
+		gorm.Model
+		UserRefer uint
+	}
+
+	type User struct {
+		gorm.Model
+		Profiles []Profile
+	}
+
+	cacheStore := &sync.Map{}
+	if err := schema.RegisterRelationships(cacheStore, &User{}, func(s *schema.Schema) {
+		s.DefineRelationship("Profiles", "foreignKey:UserRefer")
+	}); err != nil {
+		t.Fatalf("failed to register relationships, got error %v", err)
+	}
+
+	s, err := schema.Parse(&User{}, cacheStore, schema.NamingStrategy{})
+	if err != nil {
+		t.Fatalf("failed to parse schema, got error %v", err)
+	}
+
+	checkSchemaRelation(t, s, Relation{
+		Name: "Profiles", Type: schema.HasMany, Schema: "User", FieldSchema: "Profile",
+		References: []Reference{{"ID", "User", "UserRefer", "Profile", "", true}},
+	})
+
+	if err := schema.RegisterRelationships(cacheStore, &User{}, func(s *schema.Schema) {}); err == nil {
+		t.Errorf("should returns error when registering relationships for parsed model")
+	}
+}
+
+func TestConstraintValidate(t *testing.T) {
+	type Company struct {
+		ID int
+	}
+
+	type User struct {
+		ID               int
+		CompanyID        *int
+		Company          Company `gorm:"constraint:OnDelete:set  null,OnUpdate:CASCADE"`
+		ManagerCompanyID int     `gorm:"not null"`
+		ManagerCompany   Company `gorm:"constraint:OnDelete:SET NULL"`
+		OwnerCompanyID   int     `gorm:"not null;default:1"`
+		OwnerCompany     Company `gorm:"constraint:OnDelete:SET DEFAULT,OnUpdate:RESTRICT"`
+		ParentCompanyID  int
+		ParentCompany    Company `gorm:"constraint:OnDelete:DROP"`
+	}
+
+	user, err := schema.Parse(&User{}, &sync.Map{}, schema.NamingStrategy{})
+	if err != nil {
+		t.Fatalf("failed to parse user, got error %v", err)
+	}
+
+	for name, valid := range map[string]bool{"Company": true, "ManagerCompany": false, "OwnerCompany": true, "ParentCompany": false} {
+		err := user.Relationships.Relations[name].ParseConstraint().Validate()
+		if valid && err != nil {
+			t.Errorf("constraint of %v should be valid, got error %v", name, err)
+		} else if !valid && !errors.Is(err, schema.ErrInvalidConstraint) {
+			t.Errorf("constraint of %v should be invalid, got %v", name, err)
+		}
+	}
+}
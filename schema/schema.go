@@ -6,10 +6,12 @@ import (
 	"fmt"
 	"go/ast"
 	"reflect"
+	"strings"
 	"sync"
 
 	"gorm.io/gorm/clause"
 	"gorm.io/gorm/logger"
+	"gorm.io/gorm/utils"
 )
 
 // ErrUnsupportedDataType unsupported data type
@@ -19,6 +21,9 @@ type Schema struct {
 	Name                      string
 	ModelType                 reflect.Type
 	Table                     string
+	Namespace                 string // schema of table, e.g: `public`
+	Comment                   string // comment of table
+	HistoryTable              string // history table of old row versions, empty if model is not temporal
 	PrioritizedPrimaryField   *Field
 	DBNames                   []string
 	PrimaryFields             []*Field
@@ -26,7 +31,10 @@ type Schema struct {
 	Fields                    []*Field
 	FieldsByName              map[string]*Field
 	FieldsByDBName            map[string]*Field
-	FieldsWithDefaultDBValue  []*Field // fields with default value assigned by database
+	FieldsWithDefaultDBValue  []*Field               // fields with default value assigned by database
+	FieldsWithReturning       []*Field               // fields read back from database after creating, tagged with `returning`
+	SensitiveFields           []*Field               // fields tagged with `sensitive`, their values are masked in logged SQL
+	DefaultOrder              []clause.OrderByColumn // order of queries without order, declared by DefaultOrderer or tag `defaultOrder`
 	Relationships             Relationships
 	CreateClauses             []clause.Interface
 	QueryClauses              []clause.Interface
@@ -37,6 +45,12 @@ type Schema struct {
 	BeforeDelete, AfterDelete bool
 	BeforeSave, AfterSave     bool
 	AfterFind                 bool
+	Validate                  bool           // model has method `Validate(context.Context) error`
+	AfterCommit               bool           // model has method `AfterCommit(context.Context)`
+	AfterRollback             bool           // model has method `AfterRollback(context.Context)`
+	MaterializedView          bool           // model backed by materialized view, it is read-only
+	Partition                 *PartitionSpec // partitioning of table, nil if it is not partitioned
+	uniqueKeys                [][]string     // columns of unique indexes, checked by HasUniqueKey
 	err                       error
 	initialized               chan struct{}
 	namer                     Namer
@@ -57,6 +71,16 @@ func (schema Schema) MakeSlice() reflect.Value {
 	return results
 }
 
+// CreatedTimeField returns time field filled with creating time, e.g: CreatedAt, nil if there is no such field
+func (schema Schema) CreatedTimeField() *Field {
+	for _, field := range schema.Fields {
+		if field.AutoCreateTime > 0 && field.DataType == Time && field.DBName != "" {
+			return field
+		}
+	}
+	return nil
+}
+
 func (schema Schema) LookUpField(name string) *Field {
 	if field, ok := schema.FieldsByDBName[name]; ok {
 		return field
@@ -71,6 +95,41 @@ type Tabler interface {
 	TableName() string
 }
 
+// TableNamespacer model declares schema of its table, e.g: `tenant1`, table names like `tenant1.users` declare it too
+type TableNamespacer interface {
+	TableNamespace() string
+}
+
+// TableCommenter model declares comment of its table, comments are synchronized by AutoMigrate
+type TableCommenter interface {
+	TableComment() string
+}
+
+// HistoryTabler model declares history table of its old row versions, which is maintained by triggers created
+// by AutoMigrate, query historical state with db.AsOf
+type HistoryTabler interface {
+	HistoryTable() string
+}
+
+// columns of history table besides columns of model, a row version is valid in [valid_from, valid_to)
+const (
+	HistoryValidFrom = "history_valid_from"
+	HistoryValidTo   = "history_valid_to"
+	HistoryOperation = "history_operation" // UPDATE or DELETE
+)
+
+// DefaultOrderer model declares default order of its queries without order, e.g: `created_at DESC`, fields tagged with
+// `defaultOrder` or `defaultOrder:desc` declare it too in order of fields
+type DefaultOrderer interface {
+	DefaultOrder() string
+}
+
+// MaterializedViewer model backed by materialized view if MaterializedView returns true, e.g: reporting models,
+// AutoMigrate won't create table for it, but creates indexes of it
+type MaterializedViewer interface {
+	MaterializedView() bool
+}
+
 // get data type from dialector
 func Parse(dest interface{}, cacheStore *sync.Map, namer Namer) (*Schema, error) {
 	if dest == nil {
@@ -104,10 +163,18 @@ func Parse(dest interface{}, cacheStore *sync.Map, namer Namer) (*Schema, error)
 		tableName = en.Table
 	}
 
+	var namespace string
+	if namespacer, ok := modelValue.Interface().(TableNamespacer); ok {
+		namespace = namespacer.TableNamespace()
+	} else if tables := strings.Split(tableName, "."); len(tables) == 2 {
+		namespace = tables[0]
+	}
+
 	schema := &Schema{
 		Name:           modelType.Name(),
 		ModelType:      modelType,
 		Table:          tableName,
+		Namespace:      namespace,
 		FieldsByName:   map[string]*Field{},
 		FieldsByDBName: map[string]*Field{},
 		Relationships:  Relationships{Relations: map[string]*Relationship{}},
@@ -165,6 +232,13 @@ func Parse(dest interface{}, cacheStore *sync.Map, namer Namer) (*Schema, error)
 			schema.FieldsByName[field.Name] = field
 		}
 
+		// protobuf json name like `json=userName`
+		if _, jsonName := parseProtobufNames(field.Tag); jsonName != "" {
+			if _, ok := schema.FieldsByName[jsonName]; !ok {
+				schema.FieldsByName[jsonName] = field
+			}
+		}
+
 		field.setupValuerAndSetter()
 	}
 
@@ -195,6 +269,24 @@ func Parse(dest interface{}, cacheStore *sync.Map, namer Namer) (*Schema, error)
 		if field.HasDefaultValue && field.DefaultValueInterface == nil {
 			schema.FieldsWithDefaultDBValue = append(schema.FieldsWithDefaultDBValue, field)
 		}
+
+		if v, ok := field.TagSettings["RETURNING"]; ok && utils.CheckTruth(v) {
+			schema.FieldsWithReturning = append(schema.FieldsWithReturning, field)
+		}
+
+		if field.Sensitive {
+			schema.SensitiveFields = append(schema.SensitiveFields, field)
+		}
+	}
+
+	schema.uniqueKeys = schema.parseUniqueKeys()
+
+	for _, field := range schema.Fields {
+		if v, ok := field.TagSettings["DEFAULTORDER"]; ok && field.DBName != "" {
+			schema.DefaultOrder = append(schema.DefaultOrder, clause.OrderByColumn{
+				Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Desc: strings.EqualFold(v, "desc"),
+			})
+		}
 	}
 
 	if field := schema.PrioritizedPrimaryField; field != nil {
@@ -223,6 +315,42 @@ func Parse(dest interface{}, cacheStore *sync.Map, namer Namer) (*Schema, error)
 		}
 	}
 
+	if methodValue := modelValue.MethodByName("Validate"); methodValue.IsValid() && methodValue.Type().String() == "func(context.Context) error" {
+		schema.Validate = true
+	}
+
+	if methodValue := modelValue.MethodByName("AfterCommit"); methodValue.IsValid() && methodValue.Type().String() == "func(context.Context)" {
+		schema.AfterCommit = true
+	}
+
+	if methodValue := modelValue.MethodByName("AfterRollback"); methodValue.IsValid() && methodValue.Type().String() == "func(context.Context)" {
+		schema.AfterRollback = true
+	}
+
+	if viewer, ok := modelValue.Interface().(MaterializedViewer); ok {
+		schema.MaterializedView = viewer.MaterializedView()
+	}
+
+	if commenter, ok := modelValue.Interface().(TableCommenter); ok {
+		schema.Comment = commenter.TableComment()
+	}
+
+	if orderer, ok := modelValue.Interface().(DefaultOrderer); ok {
+		if order := strings.TrimSpace(orderer.DefaultOrder()); order != "" {
+			schema.DefaultOrder = []clause.OrderByColumn{{Column: clause.Column{Name: order, Raw: true}}}
+		}
+	}
+
+	if historyTabler, ok := modelValue.Interface().(HistoryTabler); ok {
+		schema.HistoryTable = historyTabler.HistoryTable()
+	}
+
+	var err error
+	if schema.Partition, err = schema.parsePartitionSpec(modelValue.Interface()); err != nil {
+		schema.err = err
+		return schema, err
+	}
+
 	if v, loaded := cacheStore.LoadOrStore(modelType, schema); loaded {
 		s := v.(*Schema)
 		<-s.initialized
@@ -231,6 +359,18 @@ func Parse(dest interface{}, cacheStore *sync.Map, namer Namer) (*Schema, error)
 
 	defer close(schema.initialized)
 	if _, embedded := schema.cacheStore.Load(embeddedCacheKey); !embedded {
+		if definer, ok := modelValue.Interface().(RelationshipsDefiner); ok {
+			definer.DefineRelationships(schema)
+		}
+
+		if v, ok := cacheStore.Load(relationshipsKey{modelType}); ok {
+			v.(func(*Schema))(schema)
+		}
+
+		if schema.err != nil {
+			return schema, schema.err
+		}
+
 		for _, field := range schema.Fields {
 			if field.DataType == "" && (field.Creatable || field.Updatable || field.Readable) {
 				if schema.parseRelation(field); schema.err != nil {
@@ -279,3 +419,50 @@ func getOrParse(dest interface{}, cacheStore *sync.Map, namer Namer) (*Schema, e
 
 	return Parse(dest, cacheStore, namer)
 }
+
+// ClearCache remove parsed schemas of models from cacheStore, schemas having relationships with them are removed as well,
+// all parsed schemas are removed if no models given, relationships registered with RegisterRelationships are kept
+func ClearCache(cacheStore *sync.Map, models ...interface{}) {
+	modelTypes := map[reflect.Type]bool{}
+	for _, model := range models {
+		modelType := reflect.ValueOf(model).Type()
+		for modelType.Kind() == reflect.Slice || modelType.Kind() == reflect.Array || modelType.Kind() == reflect.Ptr {
+			modelType = modelType.Elem()
+		}
+		modelTypes[modelType] = true
+	}
+
+	for changed := true; changed; {
+		changed = false
+		cacheStore.Range(func(key, value interface{}) bool {
+			s, ok := value.(*Schema)
+			if !ok || modelTypes[s.ModelType] {
+				return true
+			}
+
+			removed := len(models) == 0
+			for _, rel := range s.Relationships.Relations {
+				if modelTypes[rel.FieldSchema.ModelType] || (rel.JoinTable != nil && modelTypes[rel.JoinTable.ModelType]) {
+					removed = true
+				}
+			}
+
+			if removed {
+				modelTypes[s.ModelType], changed = true, true
+			}
+			return true
+		})
+	}
+
+	cacheStore.Range(func(key, value interface{}) bool {
+		if s, ok := value.(*Schema); ok && modelTypes[s.ModelType] {
+			for _, rel := range s.Relationships.Relations {
+				if rel.JoinTable != nil {
+					cacheStore.Delete(rel.JoinTable.ModelType)
+				}
+			}
+			cacheStore.Delete(key)
+		}
+		return true
+	})
+}
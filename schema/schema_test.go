@@ -297,3 +297,29 @@ func TestEmbeddedStructForCustomizedNamingStrategy(t *testing.T) {
 		})
 	}
 }
+
+func TestClearCache(t *testing.T) {
+	cacheStore := &sync.Map{}
+	user, _ := schema.Parse(&tests.User{}, cacheStore, schema.NamingStrategy{})
+	company, _ := schema.Parse(&tests.Company{}, cacheStore, schema.NamingStrategy{})
+	toy, _ := schema.Parse(&tests.Toy{}, cacheStore, schema.NamingStrategy{})
+
+	schema.ClearCache(cacheStore, &tests.Company{})
+
+	if s, _ := schema.Parse(&tests.Company{}, cacheStore, schema.NamingStrategy{}); s == company {
+		t.Errorf("company schema should be parsed again")
+	}
+
+	if s, _ := schema.Parse(&tests.User{}, cacheStore, schema.NamingStrategy{}); s == user {
+		t.Errorf("user schema having relationship with company should be parsed again")
+	}
+
+	if s, _ := schema.Parse(&tests.Toy{}, cacheStore, schema.NamingStrategy{}); s != toy {
+		t.Errorf("toy schema should be kept")
+	}
+
+	schema.ClearCache(cacheStore)
+	if s, _ := schema.Parse(&tests.Toy{}, cacheStore, schema.NamingStrategy{}); s == toy {
+		t.Errorf("toy schema should be parsed again after clearing all")
+	}
+}
@@ -0,0 +1,326 @@
+package schema
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// SerializerInterface serializer of field, used with tag `serializer:name`, the value is serialized before saving
+// and deserialized into field after scanning
+type SerializerInterface interface {
+	Scan(ctx context.Context, field *Field, dst reflect.Value, dbValue interface{}) error
+	Value(ctx context.Context, field *Field, dst reflect.Value, fieldValue interface{}) (interface{}, error)
+}
+
+var serializerMap = sync.Map{}
+
+func init() {
+	RegisterSerializer("json", JSONSerializer{})
+	RegisterSerializer("text", TextSerializer{})
+	RegisterSerializer("encrypted", EncryptedSerializer{})
+}
+
+// RegisterSerializer register serializer with name
+//     schema.RegisterSerializer("encrypted", schema.EncryptedSerializer{KeyProvider: provider})
+func RegisterSerializer(name string, serializer SerializerInterface) {
+	serializerMap.Store(strings.ToLower(name), serializer)
+}
+
+// GetSerializer get serializer by name
+func GetSerializer(name string) (serializer SerializerInterface, ok bool) {
+	v, ok := serializerMap.Load(strings.ToLower(name))
+	if ok {
+		serializer, ok = v.(SerializerInterface)
+	}
+	return serializer, ok
+}
+
+var typeSerializerMap = sync.Map{}
+
+// RegisterTypeSerializer register serializer for fields of type typ, used if the field doesn't have serializer tag,
+// it should be registered before parsing models
+//     schema.RegisterTypeSerializer(reflect.TypeOf(map[string]string{}), schema.JSONSerializer{})
+func RegisterTypeSerializer(typ reflect.Type, serializer SerializerInterface) {
+	typeSerializerMap.Store(typ, serializer)
+}
+
+// GetTypeSerializer get serializer registered for type typ
+func GetTypeSerializer(typ reflect.Type) (serializer SerializerInterface, ok bool) {
+	v, ok := typeSerializerMap.Load(typ)
+	if ok {
+		serializer, ok = v.(SerializerInterface)
+	}
+	return serializer, ok
+}
+
+// JSONSerializer json serializer
+type JSONSerializer struct{}
+
+// Scan implements serializer interface
+func (JSONSerializer) Scan(ctx context.Context, field *Field, dst reflect.Value, dbValue interface{}) error {
+	fieldValue := reflect.New(field.FieldType)
+	if dbValue != nil {
+		var bytes []byte
+		switch v := dbValue.(type) {
+		case []byte:
+			bytes = v
+		case string:
+			bytes = []byte(v)
+		default:
+			return fmt.Errorf("failed to unmarshal JSON value: %#v", dbValue)
+		}
+
+		if len(bytes) > 0 {
+			if err := json.Unmarshal(bytes, fieldValue.Interface()); err != nil {
+				return err
+			}
+		}
+	}
+
+	field.ReflectValueOf(dst).Set(fieldValue.Elem())
+	return nil
+}
+
+// Value implements serializer interface
+func (JSONSerializer) Value(ctx context.Context, field *Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	result, err := json.Marshal(fieldValue)
+	return string(result), err
+}
+
+// TextSerializer text serializer, saves values with encoding.TextMarshaler and scans with encoding.TextUnmarshaler
+type TextSerializer struct{}
+
+// Scan implements serializer interface
+func (TextSerializer) Scan(ctx context.Context, field *Field, dst reflect.Value, dbValue interface{}) error {
+	fieldValue := reflect.New(field.FieldType)
+	if dbValue != nil {
+		var bytes []byte
+		switch v := dbValue.(type) {
+		case []byte:
+			bytes = v
+		case string:
+			bytes = []byte(v)
+		default:
+			bytes = []byte(fmt.Sprint(v))
+		}
+
+		elem := fieldValue.Elem()
+		if elem.Kind() == reflect.Ptr {
+			elem.Set(reflect.New(field.IndirectFieldType))
+			elem = elem.Elem()
+		}
+
+		unmarshaler, ok := elem.Addr().Interface().(encoding.TextUnmarshaler)
+		if !ok {
+			return fmt.Errorf("%v doesn't implement encoding.TextUnmarshaler for field %v", field.FieldType, field.Name)
+		}
+
+		if err := unmarshaler.UnmarshalText(bytes); err != nil {
+			return err
+		}
+	}
+
+	field.ReflectValueOf(dst).Set(fieldValue.Elem())
+	return nil
+}
+
+// Value implements serializer interface
+func (TextSerializer) Value(ctx context.Context, field *Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	switch rv := reflect.ValueOf(fieldValue); rv.Kind() {
+	case reflect.Invalid:
+		return nil, nil
+	case reflect.Ptr, reflect.Map, reflect.Slice:
+		if rv.IsNil() {
+			return nil, nil
+		}
+	}
+
+	if marshaler, ok := fieldValue.(encoding.TextMarshaler); ok {
+		result, err := marshaler.MarshalText()
+		return string(result), err
+	}
+	return fmt.Sprint(fieldValue), nil
+}
+
+var (
+	// ErrMissingKeyProvider missing key provider of encrypted serializer
+	ErrMissingKeyProvider = errors.New("missing key provider for encrypted serializer")
+	// ErrInvalidCiphertext invalid ciphertext
+	ErrInvalidCiphertext = errors.New("invalid ciphertext")
+)
+
+// KeyProvider provides keys of EncryptedSerializer, keys should be 16, 24 or 32 bytes to select AES-128, AES-192 or AES-256
+type KeyProvider interface {
+	// CurrentKey returns key and its id used to encrypt values
+	CurrentKey(ctx context.Context) (keyID string, key []byte, err error)
+	// Key returns key of keyID used to decrypt values
+	Key(ctx context.Context, keyID string) (key []byte, err error)
+}
+
+// EncryptedSerializer encrypts values with AES-GCM, values are saved as `keyID:base64(nonce+ciphertext)`,
+// so keys could be rotated without breaking existing data, string and []byte are encrypted as is, other types are encrypted as JSON,
+// serializer without KeyProvider uses KeyProvider of serializer registered as `encrypted` when encrypting and decrypting,
+// so fields parsed before registering it work too
+//     schema.RegisterSerializer("encrypted", schema.EncryptedSerializer{KeyProvider: provider})
+//     type User struct {
+//       SSN string `gorm:"serializer:encrypted"`
+//     }
+type EncryptedSerializer struct {
+	KeyProvider KeyProvider
+}
+
+// Scan implements serializer interface
+func (es EncryptedSerializer) Scan(ctx context.Context, field *Field, dst reflect.Value, dbValue interface{}) error {
+	fieldValue := reflect.New(field.FieldType)
+	if dbValue != nil {
+		var data string
+		switch v := dbValue.(type) {
+		case []byte:
+			data = string(v)
+		case string:
+			data = v
+		default:
+			return fmt.Errorf("%w: %#v", ErrInvalidCiphertext, dbValue)
+		}
+
+		plaintext, err := es.decrypt(ctx, data)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt field %v: %w", field.Name, err)
+		}
+
+		elem := fieldValue.Elem()
+		if elem.Kind() == reflect.Ptr {
+			elem.Set(reflect.New(field.IndirectFieldType))
+			elem = elem.Elem()
+		}
+
+		switch {
+		case elem.Kind() == reflect.String:
+			elem.SetString(string(plaintext))
+		case elem.Kind() == reflect.Slice && elem.Type().Elem().Kind() == reflect.Uint8:
+			elem.SetBytes(plaintext)
+		default:
+			if err := json.Unmarshal(plaintext, elem.Addr().Interface()); err != nil {
+				return err
+			}
+		}
+	}
+
+	field.ReflectValueOf(dst).Set(fieldValue.Elem())
+	return nil
+}
+
+// Value implements serializer interface
+func (es EncryptedSerializer) Value(ctx context.Context, field *Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	rv := reflect.ValueOf(fieldValue)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil, nil
+		}
+		rv = rv.Elem()
+	}
+
+	var plaintext []byte
+	switch {
+	case !rv.IsValid():
+		return nil, nil
+	case rv.Kind() == reflect.String:
+		plaintext = []byte(rv.String())
+	case rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8:
+		plaintext = rv.Bytes()
+	default:
+		var err error
+		if plaintext, err = json.Marshal(rv.Interface()); err != nil {
+			return nil, err
+		}
+	}
+
+	return es.encrypt(ctx, plaintext)
+}
+
+// keyProvider returns KeyProvider of serializer, or KeyProvider of serializer registered as `encrypted` if it is nil
+func (es EncryptedSerializer) keyProvider() KeyProvider {
+	if es.KeyProvider == nil {
+		switch registered, _ := GetSerializer("encrypted"); serializer := registered.(type) {
+		case EncryptedSerializer:
+			return serializer.KeyProvider
+		case *EncryptedSerializer:
+			if serializer != nil {
+				return serializer.KeyProvider
+			}
+		}
+	}
+	return es.KeyProvider
+}
+
+func (es EncryptedSerializer) encrypt(ctx context.Context, plaintext []byte) (string, error) {
+	keyProvider := es.keyProvider()
+	if keyProvider == nil {
+		return "", ErrMissingKeyProvider
+	}
+
+	keyID, key, err := keyProvider.CurrentKey(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+
+	return keyID + ":" + base64.StdEncoding.EncodeToString(gcm.Seal(nonce, nonce, plaintext, nil)), nil
+}
+
+func (es EncryptedSerializer) decrypt(ctx context.Context, data string) ([]byte, error) {
+	keyProvider := es.keyProvider()
+	if keyProvider == nil {
+		return nil, ErrMissingKeyProvider
+	}
+
+	idx := strings.LastIndexByte(data, ':')
+	if idx < 0 {
+		return nil, ErrInvalidCiphertext
+	}
+
+	key, err := keyProvider.Key(ctx, data[:idx])
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	ciphertext, err := base64.StdEncoding.DecodeString(data[idx+1:])
+	if err != nil || len(ciphertext) < gcm.NonceSize() {
+		return nil, ErrInvalidCiphertext
+	}
+
+	return gcm.Open(nil, ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():], nil)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -4,6 +4,7 @@ import (
 	"reflect"
 	"regexp"
 	"strings"
+	"time"
 
 	"gorm.io/gorm/clause"
 	"gorm.io/gorm/utils"
@@ -11,6 +12,19 @@ import (
 
 var embeddedCacheKey = "embedded_cache_store"
 
+// parseProtobufNames returns field name and json name of protobuf generated field, e.g:
+//     `protobuf:"bytes,2,opt,name=user_name,json=userName,proto3"`
+func parseProtobufNames(tag reflect.StructTag) (name, jsonName string) {
+	for _, setting := range strings.Split(tag.Get("protobuf"), ",") {
+		if strings.HasPrefix(setting, "name=") {
+			name = strings.TrimPrefix(setting, "name=")
+		} else if strings.HasPrefix(setting, "json=") {
+			jsonName = strings.TrimPrefix(setting, "json=")
+		}
+	}
+	return
+}
+
 func ParseTagSetting(str string, sep string) map[string]string {
 	settings := map[string]string{}
 	names := strings.Split(str, sep)
@@ -195,3 +209,21 @@ type embeddedNamer struct {
 	Table string
 	Namer
 }
+
+// InTimeZone converts time.Time or *time.Time value to time zone loc, other values are returned as is
+func InTimeZone(value interface{}, loc *time.Location) interface{} {
+	if loc == nil {
+		return value
+	}
+
+	switch v := value.(type) {
+	case time.Time:
+		return v.In(loc)
+	case *time.Time:
+		if v != nil {
+			t := v.In(loc)
+			return &t
+		}
+	}
+	return value
+}
@@ -0,0 +1,117 @@
+package schema
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ValidationRule validation rule of field, parsed from tag `validate`, e.g: `gorm:"validate:required,max=255"`
+type ValidationRule struct {
+	Name  string
+	Param string
+}
+
+func (rule ValidationRule) String() string {
+	if rule.Param == "" {
+		return rule.Name
+	}
+	return rule.Name + "=" + rule.Param
+}
+
+var emailRegexp = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+
+// parseValidationRules parse validation rules like `required,min=3,max=255,oneof=a b c,email`
+func parseValidationRules(str string) ([]ValidationRule, error) {
+	var rules []ValidationRule
+	for _, s := range strings.Split(str, ",") {
+		if s = strings.TrimSpace(s); s == "" {
+			continue
+		}
+
+		rule := ValidationRule{Name: strings.ToLower(s)}
+		if idx := strings.Index(s, "="); idx != -1 {
+			rule.Name, rule.Param = strings.ToLower(strings.TrimSpace(s[:idx])), strings.TrimSpace(s[idx+1:])
+		}
+
+		switch rule.Name {
+		case "required", "email":
+		case "min", "max", "len":
+			if _, err := strconv.ParseFloat(rule.Param, 64); err != nil {
+				return nil, fmt.Errorf("invalid param %q of validation rule %v", rule.Param, rule.Name)
+			}
+		case "oneof":
+			if rule.Param == "" {
+				return nil, fmt.Errorf("missing param of validation rule %v", rule.Name)
+			}
+		default:
+			return nil, fmt.Errorf("unknown validation rule %v", rule.Name)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// Validate returns false if value is invalid for rule, nil values only fail rule `required`,
+// zero values are skipped by rules `email` and `oneof`
+func (rule ValidationRule) Validate(value interface{}) bool {
+	reflectValue := reflect.ValueOf(value)
+	for reflectValue.Kind() == reflect.Ptr || reflectValue.Kind() == reflect.Interface {
+		if reflectValue.IsNil() {
+			return rule.Name != "required"
+		}
+		reflectValue = reflectValue.Elem()
+	}
+
+	if !reflectValue.IsValid() {
+		return rule.Name != "required"
+	}
+
+	switch rule.Name {
+	case "required":
+		return !reflectValue.IsZero()
+	case "email":
+		return reflectValue.IsZero() || reflectValue.Kind() != reflect.String || emailRegexp.MatchString(reflectValue.String())
+	case "oneof":
+		if reflectValue.IsZero() {
+			return true
+		}
+
+		str := fmt.Sprint(reflectValue.Interface())
+		for _, v := range strings.Fields(rule.Param) {
+			if v == str {
+				return true
+			}
+		}
+		return false
+	}
+
+	param, _ := strconv.ParseFloat(rule.Param, 64)
+	var size float64
+	switch reflectValue.Kind() {
+	case reflect.String:
+		size = float64(utf8.RuneCountInString(reflectValue.String()))
+	case reflect.Slice, reflect.Array, reflect.Map:
+		size = float64(reflectValue.Len())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		size = float64(reflectValue.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		size = float64(reflectValue.Uint())
+	case reflect.Float32, reflect.Float64:
+		size = reflectValue.Float()
+	default:
+		return true
+	}
+
+	switch rule.Name {
+	case "min":
+		return size >= param
+	case "max":
+		return size <= param
+	default:
+		return size == param
+	}
+}
@@ -0,0 +1,67 @@
+package schema_test
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"gorm.io/gorm/schema"
+)
+
+func TestParseValidationRules(t *testing.T) {
+	type Product struct {
+		ID    uint
+		Name  string   `gorm:"validate:required,max=5"`
+		Code  string   `gorm:"validate:len=3"`
+		Price *float64 `gorm:"validate:min=0.5"`
+		Size  string   `gorm:"validate:oneof=S M L"`
+	}
+
+	s, err := schema.Parse(&Product{}, &sync.Map{}, schema.NamingStrategy{})
+	if err != nil {
+		t.Fatalf("failed to parse product, got error %v", err)
+	}
+
+	name := s.LookUpField("Name")
+	if len(name.ValidationRules) != 2 || name.ValidationRules[1].String() != "max=5" {
+		t.Fatalf("invalid validation rules, got %+v", name.ValidationRules)
+	}
+
+	price := 0.4
+	results := []struct {
+		Field string
+		Value interface{}
+		Valid bool
+	}{
+		{"Name", "", false},
+		{"Name", "héllo", true},
+		{"Name", "hello!", false},
+		{"Code", "abc", true},
+		{"Code", "ab", false},
+		{"Price", (*float64)(nil), true},
+		{"Price", &price, false},
+		{"Size", "M", true},
+		{"Size", "XL", false},
+		{"Size", "", true},
+	}
+
+	for _, result := range results {
+		valid := true
+		for _, rule := range s.LookUpField(result.Field).ValidationRules {
+			valid = valid && rule.Validate(result.Value)
+		}
+
+		if valid != result.Valid {
+			t.Errorf("validation result of %v for field %v should be %v", result.Value, result.Field, result.Valid)
+		}
+	}
+
+	type InvalidProduct struct {
+		ID   uint
+		Name string `gorm:"validate:unknown"`
+	}
+
+	if _, err := schema.Parse(&InvalidProduct{}, &sync.Map{}, schema.NamingStrategy{}); err == nil || !strings.Contains(err.Error(), "unknown validation rule") {
+		t.Errorf("should returns error for unknown validation rule, got %v", err)
+	}
+}
@@ -0,0 +1,161 @@
+package gorm
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+var scopeType = reflect.TypeOf(func(*DB) *DB { return nil })
+
+// ScopeInfo registered scope, Args are types of arguments bound by Scope
+type ScopeInfo struct {
+	Name string
+	Args []reflect.Type
+}
+
+// registeredScope scope or factory of scope registered with RegisterScope
+type registeredScope struct {
+	ScopeInfo
+	factory reflect.Value
+}
+
+// scopeRegistry scopes registered with RegisterScope, shared by sessions of db
+type scopeRegistry struct {
+	mu     sync.RWMutex
+	scopes map[string]*registeredScope
+}
+
+func (db *DB) scopeRegistry() *scopeRegistry {
+	v, _ := db.cacheStore.LoadOrStore("gorm:scopes", &scopeRegistry{scopes: map[string]*registeredScope{}})
+	return v.(*scopeRegistry)
+}
+
+// RegisterScope registers scope by name, scope is func(*gorm.DB) *gorm.DB or func returns it, whose arguments are bound
+// with arguments of Scope, returns ErrRegistered if name is registered
+//    db.RegisterScope("active", func(db *gorm.DB) *gorm.DB {
+//      return db.Where("active = ?", true)
+//    })
+//    db.RegisterScope("recent", func(days int) func(*gorm.DB) *gorm.DB {
+//      return func(db *gorm.DB) *gorm.DB {
+//        return db.Where("created_at > ?", time.Now().AddDate(0, 0, -days))
+//      }
+//    })
+//    db.Scope("active").Scope("recent", 7).Find(&users)
+func (db *DB) RegisterScope(name string, scope interface{}) error {
+	fn := reflect.ValueOf(scope)
+	if !fn.IsValid() || fn.Kind() != reflect.Func || fn.IsNil() {
+		return fmt.Errorf("%w: scope %v should be func, got %T", ErrInvalidData, name, scope)
+	}
+
+	info := ScopeInfo{Name: name}
+	if fnType := fn.Type(); fnType != scopeType {
+		if fnType.NumOut() != 1 || fnType.Out(0) != scopeType {
+			return fmt.Errorf("%w: scope %v should return func(*gorm.DB) *gorm.DB, got %v", ErrInvalidData, name, fnType)
+		}
+
+		for i := 0; i < fnType.NumIn(); i++ {
+			info.Args = append(info.Args, fnType.In(i))
+		}
+	}
+
+	registry := db.scopeRegistry()
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	if _, ok := registry.scopes[name]; ok {
+		return fmt.Errorf("%w: scope %v", ErrRegistered, name)
+	}
+	registry.scopes[name] = &registeredScope{ScopeInfo: info, factory: fn}
+	return nil
+}
+
+// Scope applies scope registered with RegisterScope by name, args are bound to arguments of the scope
+//    db.Scope("recent", 7).Find(&orders)
+func (db *DB) Scope(name string, args ...interface{}) (tx *DB) {
+	registry := db.scopeRegistry()
+	registry.mu.RLock()
+	scope, ok := registry.scopes[name]
+	registry.mu.RUnlock()
+
+	if !ok {
+		tx = db.getInstance()
+		tx.AddError(fmt.Errorf("%w: %v", ErrScopeNotFound, name))
+		return
+	}
+
+	if scope.factory.Type() == scopeType {
+		if len(args) > 0 {
+			tx = db.getInstance()
+			tx.AddError(fmt.Errorf("%w: scope %v expects no arguments, got %v", ErrInvalidData, name, len(args)))
+			return
+		}
+		return db.Scopes(scope.factory.Interface().(func(*DB) *DB))
+	}
+
+	values, err := scope.bind(args)
+	if err != nil {
+		tx = db.getInstance()
+		tx.AddError(err)
+		return
+	}
+	return db.Scopes(scope.factory.Call(values)[0].Interface().(func(*DB) *DB))
+}
+
+// bind converts args to arguments of factory
+func (scope *registeredScope) bind(args []interface{}) ([]reflect.Value, error) {
+	fnType := scope.factory.Type()
+	if fnType.IsVariadic() && len(args) < len(scope.Args)-1 || !fnType.IsVariadic() && len(args) != len(scope.Args) {
+		return nil, fmt.Errorf("%w: scope %v expects arguments %v, got %v", ErrInvalidData, scope.Name, scope.Args, len(args))
+	}
+
+	values := make([]reflect.Value, len(args))
+	for idx, arg := range args {
+		var argType reflect.Type
+		if fnType.IsVariadic() && idx >= len(scope.Args)-1 {
+			argType = scope.Args[len(scope.Args)-1].Elem()
+		} else {
+			argType = scope.Args[idx]
+		}
+
+		value := reflect.ValueOf(arg)
+		switch {
+		case !value.IsValid():
+			switch argType.Kind() {
+			case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Map, reflect.Func, reflect.Chan:
+				value = reflect.Zero(argType)
+			default:
+				return nil, fmt.Errorf("%w: argument %d of scope %v should be %v, got nil", ErrInvalidData, idx, scope.Name, argType)
+			}
+		case value.Type().AssignableTo(argType):
+		case value.Type().ConvertibleTo(argType) && (value.Kind() == argType.Kind() || isNumberKind(value.Kind()) && isNumberKind(argType.Kind())):
+			value = value.Convert(argType)
+		default:
+			return nil, fmt.Errorf("%w: argument %d of scope %v should be %v, got %T", ErrInvalidData, idx, scope.Name, argType, arg)
+		}
+		values[idx] = value
+	}
+	return values, nil
+}
+
+func isNumberKind(kind reflect.Kind) bool {
+	return kind >= reflect.Int && kind <= reflect.Float64
+}
+
+// RegisteredScopes returns scopes registered with RegisterScope sorted by name
+func (db *DB) RegisteredScopes() []ScopeInfo {
+	registry := db.scopeRegistry()
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	scopes := make([]ScopeInfo, 0, len(registry.scopes))
+	for _, scope := range registry.scopes {
+		scopes = append(scopes, scope.ScopeInfo)
+	}
+
+	sort.Slice(scopes, func(i, j int) bool {
+		return scopes[i].Name < scopes[j].Name
+	})
+	return scopes
+}
@@ -0,0 +1,247 @@
+package gorm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"reflect"
+	"time"
+
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// DefaultSeedsTable default table of applied seeds history
+const DefaultSeedsTable = "schema_seeds"
+
+// Seed named seed set, fixtures are loaded before Run is called, seeds it depends on are applied before it,
+// it is applied once for its Key in one transaction
+type Seed struct {
+	Name string
+	// Key idempotency key, Name is used if empty, change it to apply changed seed again
+	Key       string
+	DependsOn []string
+	// Environments environments the seed is applied in, e.g: `development`, applied in all environments if empty
+	Environments []string
+	Fixtures     []Fixture
+	Run          func(tx *DB) error
+}
+
+// Fixture fixture file of model, it contains list of records keyed by names of fields or columns, records are upserted by primary keys
+//    gorm.Fixture{Model: &User{}, Path: "fixtures/users.json"}
+type Fixture struct {
+	Model interface{}
+	Path  string
+}
+
+// SeedRecord applied seed saved in history table
+type SeedRecord struct {
+	Key       string `gorm:"primaryKey;size:255"`
+	Name      string `gorm:"size:255"`
+	AppliedAt time.Time
+}
+
+// Seeds registered seeds, applied with db.Seed, applied seeds are saved in history table `TableName`
+//    db, err := gorm.Open(sqlite.Open("gorm.db"), &gorm.Config{Seeds: gorm.NewSeeds(
+//      &gorm.Seed{Name: "base", Fixtures: []gorm.Fixture{{Model: &Language{}, Path: "fixtures/languages.json"}}},
+//      &gorm.Seed{Name: "demo", DependsOn: []string{"base"}, Environments: []string{"development"}, Run: seedDemoUsers},
+//    )})
+//    err = db.Seed(ctx, "demo")
+type Seeds struct {
+	TableName string
+	// Environment current environment, seeds not declared for it are skipped
+	Environment string
+	// FS file system of fixture files, files are read from os if nil, e.g: embed.FS
+	FS fs.FS
+	// Unmarshal unmarshals fixture files, json.Unmarshal if nil, e.g: yaml.Unmarshal for YAML fixture files
+	Unmarshal func(data []byte, v interface{}) error
+	Error     error
+	seeds     []*Seed
+}
+
+// NewSeeds returns registry of seeds
+func NewSeeds(seeds ...*Seed) *Seeds {
+	registry := &Seeds{TableName: DefaultSeedsTable}
+	for _, seed := range seeds {
+		registry.Register(seed)
+	}
+	return registry
+}
+
+// Register registers seed, returns ErrRegistered if name is registered
+func (seeds *Seeds) Register(seed *Seed) *Seeds {
+	if seeds.lookUp(seed.Name) != nil {
+		seeds.Error = fmt.Errorf("%w: seed %v", ErrRegistered, seed.Name)
+		return seeds
+	}
+	seeds.seeds = append(seeds.seeds, seed)
+	return seeds
+}
+
+func (seeds *Seeds) lookUp(name string) *Seed {
+	for _, seed := range seeds.seeds {
+		if seed.Name == name {
+			return seed
+		}
+	}
+	return nil
+}
+
+// key returns idempotency key of seed
+func (seed *Seed) key() string {
+	if seed.Key != "" {
+		return seed.Key
+	}
+	return seed.Name
+}
+
+// Seed applies seeds of names registered in Config.Seeds and seeds they depend on in dependency order, seeds applied
+// for their keys and seeds not declared for the environment are skipped
+func (db *DB) Seed(ctx context.Context, names ...string) error {
+	seeds := db.Config.Seeds
+	if seeds == nil {
+		return fmt.Errorf("%w: %v", ErrSeedNotFound, names)
+	} else if seeds.Error != nil {
+		return seeds.Error
+	}
+
+	var (
+		ordered  []*Seed
+		visiting = map[string]bool{}
+		visited  = map[string]bool{}
+		visit    func(name string) error
+	)
+
+	visit = func(name string) error {
+		seed := seeds.lookUp(name)
+		if seed == nil {
+			return fmt.Errorf("%w: %v", ErrSeedNotFound, name)
+		} else if visited[name] {
+			return nil
+		} else if visiting[name] {
+			return fmt.Errorf("cyclic dependencies of seed %v", name)
+		}
+
+		visiting[name] = true
+		for _, dep := range seed.DependsOn {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		visiting[name], visited[name] = false, true
+		ordered = append(ordered, seed)
+		return nil
+	}
+
+	for _, name := range names {
+		if err := visit(name); err != nil {
+			return err
+		}
+	}
+
+	tx := db.Session(&Session{NewDB: true, Context: ctx})
+	return tx.WithLock("gorm:seeds:"+seeds.TableName, func(*DB) error {
+		history := tx.Table(seeds.TableName)
+		if !history.Migrator().HasTable(seeds.TableName) {
+			if err := history.Migrator().CreateTable(&SeedRecord{}); err != nil {
+				return err
+			}
+		}
+
+		for _, seed := range ordered {
+			if !seeds.inEnvironment(seed) {
+				continue
+			}
+
+			var count int64
+			if err := tx.Table(seeds.TableName).Where(map[string]interface{}{"key": seed.key()}).Count(&count).Error; err != nil || count > 0 {
+				return err
+			}
+
+			if err := tx.Transaction(func(tx *DB) error {
+				for _, fixture := range seed.Fixtures {
+					if err := seeds.load(tx, fixture); err != nil {
+						return fmt.Errorf("failed to load fixture %v of seed %v: %w", fixture.Path, seed.Name, err)
+					}
+				}
+
+				if seed.Run != nil {
+					if err := seed.Run(tx); err != nil {
+						return fmt.Errorf("failed to seed %v: %w", seed.Name, err)
+					}
+				}
+				return tx.Session(&Session{NewDB: true}).Table(seeds.TableName).Create(&SeedRecord{Key: seed.key(), Name: seed.Name, AppliedAt: tx.NowFunc()}).Error
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// inEnvironment returns true if seed is declared for the environment
+func (seeds *Seeds) inEnvironment(seed *Seed) bool {
+	if len(seed.Environments) == 0 {
+		return true
+	}
+
+	for _, env := range seed.Environments {
+		if env == seeds.Environment {
+			return true
+		}
+	}
+	return false
+}
+
+// load upserts records of fixture file
+func (seeds *Seeds) load(tx *DB, fixture Fixture) error {
+	var (
+		data []byte
+		err  error
+	)
+
+	if seeds.FS != nil {
+		data, err = fs.ReadFile(seeds.FS, fixture.Path)
+	} else {
+		data, err = os.ReadFile(fixture.Path)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	unmarshal := seeds.Unmarshal
+	if unmarshal == nil {
+		unmarshal = json.Unmarshal
+	}
+
+	var rows []map[string]interface{}
+	if err := unmarshal(data, &rows); err != nil {
+		return err
+	}
+
+	s, err := schema.Parse(fixture.Model, tx.cacheStore, tx.NamingStrategy)
+	if err != nil || len(rows) == 0 {
+		return err
+	}
+
+	records := reflect.MakeSlice(reflect.SliceOf(s.ModelType), len(rows), len(rows))
+	for idx, row := range rows {
+		for name, value := range row {
+			field := s.LookUpField(name)
+			if field == nil {
+				return fmt.Errorf("unknown field %v of %v", name, s.Name)
+			}
+
+			if err := field.Set(records.Index(idx), value); err != nil {
+				return err
+			}
+		}
+	}
+
+	value := reflect.New(records.Type())
+	value.Elem().Set(records)
+	return tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(value.Interface()).Error
+}
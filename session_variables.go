@@ -0,0 +1,117 @@
+package gorm
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// sessionVariables returns session variables of transaction if they are supported, e.g: postgres, nil if there is no variable,
+// search_path is set to namespace of tenant, so tables of joins, preloads and join tables are looked up in schema of tenant,
+// statement_timeout is set to timeout of statement, so statements of the transaction are aborted by server
+func (db *DB) sessionVariables() map[string]string {
+	if db.Dialector == nil || !db.Capabilities().SessionVariables {
+		return nil
+	}
+
+	variables := db.configuredSessionVariables()
+	tenant, ok := db.tenant(db.Statement.Context)
+	timeout := db.Statement.Timeout
+	if timeout == 0 {
+		timeout = db.StatementTimeout
+	}
+
+	if (ok && tenant.Namespace != "") || (timeout > 0 && db.Capabilities().StatementTimeout) {
+		configured := variables
+		variables = make(map[string]string, len(configured)+2)
+		for name, value := range configured {
+			variables[name] = value
+		}
+
+		if ok && tenant.Namespace != "" {
+			variables["search_path"] = db.Statement.Quote(tenant.Namespace)
+		}
+
+		if timeout > 0 && db.Capabilities().StatementTimeout {
+			variables["statement_timeout"] = strconv.FormatInt(timeout.Milliseconds(), 10)
+		}
+	}
+	return variables
+}
+
+// configuredSessionVariables returns session variables of context with Config.SessionVariables
+func (db *DB) configuredSessionVariables() map[string]string {
+	if db.SessionVariables == nil || db.Statement.Context == nil {
+		return nil
+	}
+	return db.SessionVariables(db.Statement.Context)
+}
+
+// requiresSessionTransaction returns true if statements out of transactions are executed in transactions with session
+// variables of Config.SessionVariables
+func (db *DB) requiresSessionTransaction() bool {
+	return !db.Statement.InTransaction && db.Dialector != nil && db.Capabilities().SessionVariables && len(db.configuredSessionVariables()) > 0
+}
+
+// setSessionVariables sets session variables locally in transaction with set_config, they are reset when the transaction is finished
+func (db *DB) setSessionVariables(variables map[string]string) error {
+	names := make([]string, 0, len(variables))
+	for name := range variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := db.Exec("SELECT set_config(?, ?, true)", name, variables[name]).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sessionVariablesEnabled returns true if statement is executed in transaction with session variables
+func sessionVariablesEnabled(e *execution) bool {
+	return !e.db.DryRun && e.db.requiresSessionTransaction()
+}
+
+// sessionVariablesHook executes statement in transaction with session variables of Config.SessionVariables if it is not in
+// transaction, search_path of tenant and statement_timeout don't open transactions, statements out of transactions rely on
+// tables qualified with namespace of tenant and deadline of context instead, Row and Rows are rejected as the transaction
+// can't be finished after rows are read, they should be called in transactions, Iterate, FindInto, Scan and exports read
+// rows in transactions themselves
+func sessionVariablesHook(e *execution) {
+	db, stmt := e.db, e.db.Statement
+	if e.name == "row" {
+		// rows are read after executing, the transaction can't be finished before they are closed
+		db.AddError(fmt.Errorf("%w: session variables require transaction for Row and Rows, call them in Transaction or use Iterate, FindInto and Scan", ErrInvalidTransaction))
+		return
+	}
+
+	tx := db.Begin()
+	if tx.Error != nil {
+		db.AddError(tx.Error)
+		return
+	}
+
+	// hooks and savepoints of the transaction are used by callbacks, e.g: AfterCommit
+	connPool := stmt.ConnPool
+	stmt.ConnPool, stmt.InTransaction = tx.Statement.ConnPool, true
+	for _, key := range []string{"gorm:tx_hooks", "gorm:savepoints"} {
+		if value, ok := tx.Statement.Settings.Load(key); ok {
+			stmt.Settings.Store(key, value)
+		}
+	}
+
+	defer func() {
+		stmt.ConnPool, stmt.InTransaction = connPool, false
+		stmt.Settings.Delete("gorm:tx_hooks")
+		stmt.Settings.Delete("gorm:savepoints")
+		if db.Error == nil {
+			db.AddError(tx.Commit().Error)
+		} else {
+			tx.Rollback()
+		}
+	}()
+
+	e.next()
+}
@@ -0,0 +1,447 @@
+// Package sharding routes statements of sharded models to physical tables and connection pools of shards by shard key,
+// queries without shard key are executed in all shards and their results are merged if ScatterGather is enabled
+//    db.Use(sharding.New().Register(sharding.Config{
+//      Key:     "UserID",
+//      Shards:  sharding.Tables("orders", 4),
+//      Resolve: sharding.Hash(4),
+//    }, &Order{}))
+//    db.Where("user_id = ?", 1).Find(&orders) // SELECT * FROM `orders_2` WHERE user_id = 1
+package sharding
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PluginName name of the plugin, its callbacks are registered as `sharding:route_<operation>`, `gorm:query` is wrapped to scatter queries
+const PluginName = "gorm:sharding"
+
+var (
+	// ErrMissingShardKey statement of sharded model without value of shard key
+	ErrMissingShardKey = errors.New("missing shard key")
+	// ErrCrossShard statement across multiple shards, e.g: creating records of different shards in batch
+	ErrCrossShard = errors.New("cross shard statement")
+)
+
+// Shard physical table and connection pool of shard, the default connection pool is used if ConnPool is nil,
+// connection pools are not switched in transactions
+type Shard struct {
+	Table    string
+	ConnPool gorm.ConnPool
+}
+
+// Config sharding of models
+type Config struct {
+	// Key name or column of shard key field
+	Key    string
+	Shards []Shard
+	// Resolve returns index of shard in Shards of value of shard key
+	Resolve func(key interface{}) (int, error)
+	// ScatterGather executes queries without shard key, or with keys of multiple shards, in all of them and merges results,
+	// orders and limits are applied in each shard
+	ScatterGather bool
+}
+
+// Tables returns shards of tables `<table>_<index>` with the default connection pool
+func Tables(table string, n int) []Shard {
+	shards := make([]Shard, n)
+	for i := range shards {
+		shards[i] = Shard{Table: fmt.Sprintf("%v_%d", table, i)}
+	}
+	return shards
+}
+
+// Hash returns resolver hashing keys to n shards, integers are routed by modulo, others by fnv hash of their string values
+func Hash(n int) func(key interface{}) (int, error) {
+	return func(key interface{}) (int, error) {
+		if i, ok := toInt64(key); ok {
+			if i < 0 {
+				i = -i
+			}
+			return int(i % int64(n)), nil
+		}
+
+		h := fnv.New32a()
+		h.Write([]byte(fmt.Sprint(key)))
+		return int(h.Sum32() % uint32(n)), nil
+	}
+}
+
+// Range returns resolver routing integer keys by ranges, bounds are exclusive upper bounds of shards in order,
+// keys greater than or equal to the last bound are routed to the last shard
+//    sharding.Range(1000000, 2000000) // [0, 1000000) => 0, [1000000, 2000000) => 1, [2000000, ...) => 2
+func Range(bounds ...int64) func(key interface{}) (int, error) {
+	return func(key interface{}) (int, error) {
+		i, ok := toInt64(key)
+		if !ok {
+			return 0, fmt.Errorf("%w: range of %v", ErrMissingShardKey, key)
+		}
+		return sort.Search(len(bounds), func(idx int) bool { return i < bounds[idx] }), nil
+	}
+}
+
+func toInt64(key interface{}) (int64, bool) {
+	rv := reflect.Indirect(reflect.ValueOf(key))
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(rv.Uint()), true
+	case reflect.String:
+		i, err := strconv.ParseInt(rv.String(), 10, 64)
+		return i, err == nil
+	}
+	return 0, false
+}
+
+// Sharding sharding plugin, implements gorm.Plugin
+type Sharding struct {
+	configs map[interface{}]Config // configs of models, parsed to tables when initializing
+	rules   map[string]*rule       // rules of tables
+}
+
+type rule struct {
+	Config
+	keyRegexp *regexp.Regexp
+}
+
+// New returns sharding plugin, register sharded models with Register
+func New() *Sharding {
+	return &Sharding{configs: map[interface{}]Config{}, rules: map[string]*rule{}}
+}
+
+// Register registers sharding config of models
+func (s *Sharding) Register(config Config, models ...interface{}) *Sharding {
+	for _, model := range models {
+		s.configs[model] = config
+	}
+	return s
+}
+
+// Name returns name of the plugin
+func (s *Sharding) Name() string {
+	return PluginName
+}
+
+// Initialize parses registered models and registers callbacks routing statements
+func (s *Sharding) Initialize(db *gorm.DB) error {
+	for model, config := range s.configs {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			return err
+		}
+
+		field := stmt.Schema.LookUpField(config.Key)
+		if field == nil {
+			return fmt.Errorf("%w: shard key %v of %v", gorm.ErrInvalidField, config.Key, stmt.Schema.Name)
+		}
+
+		config.Key = field.DBName
+		s.rules[stmt.Schema.Table] = &rule{
+			Config:    config,
+			keyRegexp: regexp.MustCompile(fmt.Sprintf("(?i)^\\s*[`\"\\[]?(?:\\w+[`\"\\]]?\\.[`\"\\[]?)?%v[`\"\\]]?\\s*(=|IN)\\s*\\(?\\s*\\?\\s*\\)?\\s*$", regexp.QuoteMeta(field.DBName))),
+		}
+	}
+
+	callbacks := db.Callback()
+	query := callbacks.Query().Get("gorm:query")
+	for _, err := range []error{
+		callbacks.Create().Before("gorm:begin_transaction").Register("sharding:route_create", s.route(true)),
+		callbacks.Update().Before("gorm:begin_transaction").Register("sharding:route_update", s.route(true)),
+		callbacks.Delete().Before("gorm:begin_transaction").Register("sharding:route_delete", s.route(true)),
+		callbacks.Row().Before("gorm:row").Register("sharding:route_row", s.route(false)),
+		callbacks.Query().Replace("gorm:query", s.query(query)),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// shards returns rule and indexes of shards of statement, shards is nil if statement is not sharded, values of records are
+// used if there is no condition of shard key in writes, e.g: creating records, updating with Model
+func (s *Sharding) shards(db *gorm.DB, write bool) (r *rule, shards []int, err error) {
+	stmt := db.Statement
+	if stmt.Schema == nil || stmt.SQL.Len() > 0 || stmt.Table != stmt.Schema.Table {
+		return nil, nil, nil
+	}
+
+	if r = s.rules[stmt.Schema.Table]; r == nil {
+		return nil, nil, nil
+	}
+
+	keys, ok := r.whereKeys(stmt)
+	if !ok && write {
+		keys = r.valueKeys(stmt)
+	}
+
+	routed := map[int]bool{}
+	for _, key := range keys {
+		idx, err := r.Resolve(key)
+		if err != nil {
+			return r, nil, err
+		}
+
+		if idx < 0 || idx >= len(r.Shards) {
+			return r, nil, fmt.Errorf("%w: shard %d of key %v", ErrMissingShardKey, idx, key)
+		}
+
+		if !routed[idx] {
+			routed[idx] = true
+			shards = append(shards, idx)
+		}
+	}
+	sort.Ints(shards)
+	return r, shards, nil
+}
+
+// whereKeys returns values of shard key in WHERE conditions, ok is false if there is no condition of shard key
+func (r *rule) whereKeys(stmt *gorm.Statement) (keys []interface{}, ok bool) {
+	c, exists := stmt.Clauses["WHERE"]
+	if !exists {
+		return nil, false
+	}
+
+	where, _ := c.Expression.(clause.Where)
+	for _, expr := range where.Exprs {
+		if values, matched := r.exprKeys(expr); matched {
+			keys, ok = append(keys, values...), true
+		}
+	}
+	return
+}
+
+func (r *rule) exprKeys(expr clause.Expression) ([]interface{}, bool) {
+	isKey := func(column interface{}) bool {
+		switch c := column.(type) {
+		case string:
+			return r.keyRegexp.MatchString(c + " = ?")
+		case clause.Column:
+			return c.Name == r.Key
+		}
+		return false
+	}
+
+	switch e := expr.(type) {
+	case clause.Eq:
+		if isKey(e.Column) {
+			return []interface{}{e.Value}, true
+		}
+	case clause.IN:
+		if isKey(e.Column) {
+			return e.Values, true
+		}
+	case clause.Expr:
+		if len(e.Vars) == 1 && r.keyRegexp.MatchString(e.SQL) {
+			if rv := reflect.ValueOf(e.Vars[0]); rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() != reflect.Uint8 {
+				values := make([]interface{}, rv.Len())
+				for i := range values {
+					values[i] = rv.Index(i).Interface()
+				}
+				return values, true
+			}
+			return e.Vars, true
+		}
+	case clause.AndConditions:
+		var (
+			keys    []interface{}
+			matched bool
+		)
+		for _, expr := range e.Exprs {
+			if values, ok := r.exprKeys(expr); ok {
+				keys, matched = append(keys, values...), true
+			}
+		}
+		return keys, matched
+	}
+	return nil, false
+}
+
+// valueKeys returns values of shard key of records of statement
+func (r *rule) valueKeys(stmt *gorm.Statement) (keys []interface{}) {
+	field := stmt.Schema.LookUpField(r.Key)
+	appendKey := func(rv reflect.Value) {
+		if value, zero := field.ValueOf(rv); !zero {
+			keys = append(keys, value)
+		}
+	}
+
+	switch dest := stmt.Dest.(type) {
+	case map[string]interface{}:
+		if value, ok := dest[field.DBName]; ok {
+			keys = append(keys, value)
+		} else if value, ok := dest[field.Name]; ok {
+			keys = append(keys, value)
+		}
+		return
+	}
+
+	switch rv := reflect.Indirect(stmt.ReflectValue); rv.Kind() {
+	case reflect.Struct:
+		if rv.Type() == stmt.Schema.ModelType {
+			appendKey(rv)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			if elem := reflect.Indirect(rv.Index(i)); elem.Type() == stmt.Schema.ModelType {
+				appendKey(elem)
+			}
+		}
+	}
+	return
+}
+
+// use switches table and connection pool of statement to shard
+func use(db *gorm.DB, shard Shard) {
+	if shard.ConnPool != nil && !db.Statement.InTransaction {
+		db.Statement.ConnPool = shard.ConnPool
+	}
+	db.Statement.Table, db.Statement.TableExpr = shard.Table, nil
+}
+
+// route routes creating, updating, deleting and rows queries to the shard of shard key
+func (s *Sharding) route(write bool) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		if db.Error == nil {
+			s.routeShard(db, write)
+		}
+	}
+}
+
+func (s *Sharding) routeShard(db *gorm.DB, write bool) {
+	r, shards, err := s.shards(db, write)
+	switch {
+	case err != nil:
+		db.AddError(err)
+	case r == nil:
+	case len(shards) == 1:
+		use(db, r.Shards[shards[0]])
+	case len(shards) == 0:
+		db.AddError(fmt.Errorf("%w: %v of %v", ErrMissingShardKey, r.Key, db.Statement.Schema.Table))
+	default:
+		db.AddError(fmt.Errorf("%w: %v of %v in shards %v", ErrCrossShard, r.Key, db.Statement.Schema.Table, shards))
+	}
+}
+
+// query routes queries to the shard of shard key, or scatters them to shards and gathers results with ScatterGather
+func (s *Sharding) query(query func(*gorm.DB)) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		if db.Error != nil {
+			return
+		}
+
+		r, shards, err := s.shards(db, false)
+		switch {
+		case err != nil:
+			db.AddError(err)
+			return
+		case r == nil:
+		case len(shards) == 1:
+			use(db, r.Shards[shards[0]])
+		case !r.ScatterGather && len(shards) == 0:
+			db.AddError(fmt.Errorf("%w: %v of %v", ErrMissingShardKey, r.Key, db.Statement.Schema.Table))
+			return
+		case !r.ScatterGather:
+			db.AddError(fmt.Errorf("%w: %v of %v in shards %v", ErrCrossShard, r.Key, db.Statement.Schema.Table, shards))
+			return
+		default:
+			if len(shards) == 0 {
+				for idx := range r.Shards {
+					shards = append(shards, idx)
+				}
+			}
+			scatter(db, query, r, shards)
+			return
+		}
+		query(db)
+	}
+}
+
+// scatter executes query in shards and gathers results into dest of statement, slices are appended, numbers are summed,
+// e.g: Count, and structs are assigned with the first found record
+func scatter(db *gorm.DB, query func(*gorm.DB), r *rule, shards []int) {
+	var (
+		stmt         = db.Statement
+		dest         = stmt.Dest
+		reflectValue = stmt.ReflectValue
+		connPool     = stmt.ConnPool
+		raiseError   = stmt.RaiseErrorOnNotFound
+		rowsAffected int64
+	)
+
+	defer func() {
+		stmt.Dest, stmt.ReflectValue, stmt.ConnPool, stmt.RaiseErrorOnNotFound = dest, reflectValue, connPool, raiseError
+		db.RowsAffected = rowsAffected
+	}()
+
+	switch reflectValue.Kind() {
+	case reflect.Slice, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+		reflectValue.Set(reflect.Zero(reflectValue.Type()))
+	}
+
+	stmt.RaiseErrorOnNotFound = false
+	for _, idx := range shards {
+		result := reflect.New(reflectValue.Type())
+		stmt.Dest, stmt.ReflectValue, stmt.ConnPool = result.Interface(), result.Elem(), connPool
+		stmt.SQL.Reset()
+		stmt.Vars = nil
+		use(db, r.Shards[idx])
+
+		db.RowsAffected = 0
+		if query(db); db.Error != nil {
+			return
+		}
+
+		switch reflectValue.Kind() {
+		case reflect.Slice:
+			reflectValue.Set(reflect.AppendSlice(reflectValue, result.Elem()))
+			rowsAffected += db.RowsAffected
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			reflectValue.SetInt(reflectValue.Int() + result.Elem().Int())
+			rowsAffected = 1
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			reflectValue.SetUint(reflectValue.Uint() + result.Elem().Uint())
+			rowsAffected = 1
+		case reflect.Float32, reflect.Float64:
+			reflectValue.SetFloat(reflectValue.Float() + result.Elem().Float())
+			rowsAffected = 1
+		default:
+			if db.RowsAffected > 0 {
+				reflectValue.Set(result.Elem())
+				rowsAffected = db.RowsAffected
+				return
+			}
+		}
+	}
+
+	if rowsAffected == 0 && raiseError {
+		db.AddError(gorm.ErrRecordNotFound)
+	}
+}
+
+// AutoMigrate migrates tables of shards of registered models
+func (s *Sharding) AutoMigrate(db *gorm.DB) error {
+	for model, config := range s.configs {
+		for _, shard := range config.Shards {
+			tx := db.Session(&gorm.Session{NewDB: true}).Table(shard.Table)
+			if shard.ConnPool != nil {
+				tx.Statement.ConnPool = shard.ConnPool
+			}
+
+			if err := tx.AutoMigrate(model); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
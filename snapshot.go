@@ -0,0 +1,173 @@
+package gorm
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+
+	"gorm.io/gorm/schema"
+)
+
+// snapshots values of columns of records snapshotted in session, keyed by addresses of records
+type snapshots struct {
+	mu     sync.Mutex
+	values map[uintptr]map[string]interface{}
+}
+
+// Snapshot returns session tracking changes of records of value since now, value should be pointer of struct or slice of structs,
+// changed columns are returned by Changes and updated by SaveChanges
+//    tx := db.Snapshot(&user)
+//    user.Name = "jinzhu"
+//    tx.Changes(&user) // []string{"name"}
+//    tx.SaveChanges(&user) // UPDATE users SET name = "jinzhu", updated_at = ... WHERE id = 1
+func (db *DB) Snapshot(value interface{}) (tx *DB) {
+	s, records, err := snapshotRecords(db, value)
+	if err != nil {
+		tx = db.getInstance()
+		tx.AddError(err)
+		return
+	}
+
+	tx = db
+	store, ok := db.snapshots()
+	if !ok {
+		tx = db.Session(&Session{})
+		store = &snapshots{values: map[uintptr]map[string]interface{}{}}
+		tx.Statement.Settings.Store("gorm:snapshots", store)
+	}
+
+	store.mu.Lock()
+	for _, record := range records {
+		store.values[record.UnsafeAddr()] = snapshotColumns(s, record)
+	}
+	store.mu.Unlock()
+	return tx
+}
+
+// Changes returns columns of records of value changed since they are snapshotted with Snapshot, in order of schema
+func (db *DB) Changes(value interface{}) []string {
+	store, ok := db.snapshots()
+	if !ok {
+		return nil
+	}
+
+	s, records, err := snapshotRecords(db, value)
+	if err != nil {
+		return nil
+	}
+
+	var (
+		changed = map[string]bool{}
+		columns []string
+	)
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	for _, record := range records {
+		for _, column := range changedColumns(s, store.values[record.UnsafeAddr()], record) {
+			changed[column] = true
+		}
+	}
+
+	for _, dbName := range s.DBNames {
+		if changed[dbName] {
+			columns = append(columns, dbName)
+		}
+	}
+	return columns
+}
+
+// SaveChanges updates changed columns of record value snapshotted with Snapshot, records without changes are not updated,
+// value is snapshotted again after it is updated
+func (db *DB) SaveChanges(value interface{}) (tx *DB) {
+	tx = db.getInstance()
+	store, ok := tx.snapshots()
+	if !ok {
+		tx.AddError(fmt.Errorf("%w: %T is not snapshotted", ErrInvalidData, value))
+		return
+	}
+
+	s, records, err := snapshotRecords(tx, value)
+	if err != nil {
+		tx.AddError(err)
+		return
+	} else if len(records) != 1 {
+		tx.AddError(fmt.Errorf("%w: save changes requires struct pointer, got %T", ErrInvalidData, value))
+		return
+	}
+
+	store.mu.Lock()
+	snapshot, ok := store.values[records[0].UnsafeAddr()]
+	store.mu.Unlock()
+	if !ok {
+		tx.AddError(fmt.Errorf("%w: %T is not snapshotted", ErrInvalidData, value))
+		return
+	}
+
+	if columns := changedColumns(s, snapshot, records[0]); len(columns) > 0 {
+		if tx = tx.Model(value).Select(columns).Updates(value); tx.Error == nil {
+			store.mu.Lock()
+			store.values[records[0].UnsafeAddr()] = snapshotColumns(s, records[0])
+			store.mu.Unlock()
+		}
+	}
+	return
+}
+
+// snapshots returns snapshots of session
+func (db *DB) snapshots() (*snapshots, bool) {
+	if v, ok := db.Statement.Settings.Load("gorm:snapshots"); ok {
+		return v.(*snapshots), true
+	}
+	return nil, false
+}
+
+// snapshotRecords returns schema and addressable records of value
+func snapshotRecords(db *DB, value interface{}) (*schema.Schema, []reflect.Value, error) {
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return nil, nil, fmt.Errorf("%w: snapshot requires pointer, got %T", ErrInvalidData, value)
+	}
+
+	s, err := schema.Parse(value, db.cacheStore, db.NamingStrategy)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var records []reflect.Value
+	switch rv = reflect.Indirect(rv); rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			if elem := reflect.Indirect(rv.Index(i)); elem.Kind() == reflect.Struct {
+				records = append(records, elem)
+			}
+		}
+	case reflect.Struct:
+		records = append(records, rv)
+	}
+	return s, records, nil
+}
+
+// snapshotColumns returns values of columns of record, pointers are dereferenced so changes of pointed values are tracked
+func snapshotColumns(s *schema.Schema, record reflect.Value) map[string]interface{} {
+	columns := make(map[string]interface{}, len(s.DBNames))
+	for _, dbName := range s.DBNames {
+		value, _ := s.FieldsByDBName[dbName].ValueOf(record)
+		if rv := reflect.ValueOf(value); rv.Kind() == reflect.Ptr && !rv.IsNil() {
+			value = rv.Elem().Interface()
+		}
+		columns[dbName] = value
+	}
+	return columns
+}
+
+// changedColumns returns columns of record changed since snapshot, in order of schema, all columns if snapshot is nil
+func changedColumns(s *schema.Schema, snapshot map[string]interface{}, record reflect.Value) (columns []string) {
+	current := snapshotColumns(s, record)
+	for _, dbName := range s.DBNames {
+		if old, ok := snapshot[dbName]; !ok || !reflect.DeepEqual(old, current[dbName]) {
+			columns = append(columns, dbName)
+		}
+	}
+	return
+}
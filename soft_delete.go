@@ -5,6 +5,8 @@ import (
 	"database/sql/driver"
 	"encoding/json"
 	"reflect"
+	"strings"
+	"time"
 
 	"gorm.io/gorm/clause"
 	"gorm.io/gorm/schema"
@@ -48,6 +50,51 @@ func (DeletedAt) QueryClauses(f *schema.Field) []clause.Interface {
 	return []clause.Interface{SoftDeleteQueryClause{Field: f}}
 }
 
+// DeletedFlag soft delete with a flag column, deleted records are marked as true (or 1 for integer columns)
+//    type User struct {
+//      ID        uint
+//      IsDeleted gorm.DeletedFlag `gorm:"not null;default:false"`
+//      DeletedAt *time.Time       `gorm:"softDelete:time"` // optional, filled with current time when soft deleting
+//      DeletedBy string           `gorm:"softDelete:by"`   // optional, filled with Config.ActorResolver when soft deleting
+//    }
+type DeletedFlag bool
+
+// Scan implements the Scanner interface.
+func (n *DeletedFlag) Scan(value interface{}) error {
+	var b sql.NullBool
+	err := b.Scan(value)
+	*n = DeletedFlag(b.Bool)
+	return err
+}
+
+// Value implements the driver Valuer interface.
+func (n DeletedFlag) Value() (driver.Value, error) {
+	return bool(n), nil
+}
+
+func (DeletedFlag) QueryClauses(f *schema.Field) []clause.Interface {
+	return []clause.Interface{SoftDeleteQueryClause{Field: f}}
+}
+
+func (DeletedFlag) DeleteClauses(f *schema.Field) []clause.Interface {
+	return []clause.Interface{SoftDeleteDeleteClause{Field: f}}
+}
+
+// softDeleteValue returns the soft delete field's value for deleted or not deleted records
+func softDeleteValue(field *schema.Field, deleted bool, curTime time.Time) interface{} {
+	if field.GORMDataType != schema.Bool {
+		if deleted {
+			return curTime
+		}
+		return nil
+	} else if field.DataType == schema.Bool {
+		return deleted
+	} else if deleted {
+		return 1
+	}
+	return 0
+}
+
 type SoftDeleteQueryClause struct {
 	Field *schema.Field
 }
@@ -78,7 +125,7 @@ func (sd SoftDeleteQueryClause) ModifyStatement(stmt *Statement) {
 		}
 
 		stmt.AddClause(clause.Where{Exprs: []clause.Expression{
-			clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: sd.Field.DBName}, Value: nil},
+			clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: sd.Field.DBName}, Value: softDeleteValue(sd.Field, false, time.Time{})},
 		}})
 		stmt.Clauses["soft_delete_enabled"] = clause.Clause{}
 	}
@@ -105,8 +152,36 @@ func (sd SoftDeleteDeleteClause) MergeClause(*clause.Clause) {
 func (sd SoftDeleteDeleteClause) ModifyStatement(stmt *Statement) {
 	if stmt.SQL.String() == "" {
 		curTime := stmt.DB.NowFunc()
-		stmt.AddClause(clause.Set{{Column: clause.Column{Name: sd.Field.DBName}, Value: curTime}})
-		stmt.SetColumn(sd.Field.DBName, curTime, true)
+		deletedValue := softDeleteValue(sd.Field, true, curTime)
+		set := clause.Set{{Column: clause.Column{Name: sd.Field.DBName}, Value: deletedValue}}
+		stmt.SetColumn(sd.Field.DBName, deletedValue, true)
+
+		if stmt.Schema != nil {
+			// paired columns of soft delete, like deleted time and deleted by
+			for _, field := range stmt.Schema.Fields {
+				if field.DBName == "" || field == sd.Field {
+					continue
+				}
+
+				var value interface{}
+				switch strings.ToUpper(field.TagSettings["SOFTDELETE"]) {
+				case "TIME":
+					value = curTime
+				case "BY":
+					actor, ok := stmt.Actor()
+					if !ok {
+						continue
+					}
+					value = actor
+				default:
+					continue
+				}
+
+				set = append(set, clause.Assignment{Column: clause.Column{Name: field.DBName}, Value: value})
+				stmt.SetColumn(field.DBName, value, true)
+			}
+		}
+		stmt.AddClause(set)
 
 		if stmt.Schema != nil {
 			_, queryValues := schema.GetIdentityFieldValuesMap(stmt.ReflectValue, stmt.Schema.PrimaryFields)
@@ -136,3 +211,71 @@ func (sd SoftDeleteDeleteClause) ModifyStatement(stmt *Statement) {
 		stmt.Build("UPDATE", "SET", "WHERE")
 	}
 }
+
+// PurgeSoftDeleted permanently delete records soft deleted before retention in batches, delete hooks will be called for every batch
+//     db.PurgeSoftDeleted(&Order{}, 90*24*time.Hour)
+//     db.Where("company_id = ?", 1).PurgeSoftDeleted(&Order{}, 30*24*time.Hour)
+func (db *DB) PurgeSoftDeleted(value interface{}, retention time.Duration) (tx *DB) {
+	tx = db.getInstance()
+	if err := tx.Statement.Parse(value); err != nil {
+		tx.AddError(err)
+		return
+	}
+
+	var deletedTimeField *schema.Field
+	for _, field := range tx.Statement.Schema.Fields {
+		if field.DBName == "" {
+			continue
+		}
+
+		if field.FieldType == reflect.TypeOf(DeletedAt{}) || strings.ToUpper(field.TagSettings["SOFTDELETE"]) == "TIME" {
+			deletedTimeField = field
+			break
+		}
+	}
+
+	if deletedTimeField == nil {
+		tx.AddError(ErrMissingSoftDeleteTime)
+		return
+	}
+
+	batchSize := tx.PurgeBatchSize
+	if batchSize <= 0 {
+		batchSize = 1000
+	}
+
+	var (
+		rowsAffected  int64
+		deletedBefore = tx.NowFunc().Add(-retention)
+		queryTx       = tx.Session(&Session{}).Unscoped().Where(clause.Lt{
+			Column: clause.Column{Table: clause.CurrentTable, Name: deletedTimeField.DBName}, Value: deletedBefore,
+		}).Session(&Session{})
+	)
+
+	for {
+		records := reflect.New(reflect.SliceOf(reflect.PtrTo(tx.Statement.Schema.ModelType)))
+		result := queryTx.Limit(batchSize).Find(records.Interface())
+		if result.Error != nil {
+			tx.AddError(result.Error)
+			break
+		}
+
+		if result.RowsAffected == 0 {
+			break
+		}
+
+		result = queryTx.Delete(records.Interface())
+		rowsAffected += result.RowsAffected
+		if result.Error != nil {
+			tx.AddError(result.Error)
+			break
+		}
+
+		if result.RowsAffected == 0 || records.Elem().Len() < batchSize {
+			break
+		}
+	}
+
+	tx.RowsAffected = rowsAffected
+	return
+}
@@ -0,0 +1,436 @@
+package gorm
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// DefaultSRID SRID used if geometry values or fields don't specify one, WGS 84
+const DefaultSRID = 4326
+
+const (
+	wkbPoint   uint32 = 1
+	wkbPolygon uint32 = 3
+	ewkbSRID   uint32 = 0x20000000
+)
+
+// Point spatial point, saved as geometry(Point) for postgres (PostGIS), POINT for mysql, and WKT text for others,
+// use tag `geography` to save it as geography for postgres and `srid` to change the SRID of column
+//    type Store struct {
+//      Location gorm.Point `gorm:"geography;srid:4326"`
+//    }
+type Point struct {
+	X, Y float64
+	SRID int
+}
+
+// Scan implements the Scanner interface, WKT, WKB, EWKB (hex or binary) and mysql internal format are supported
+func (p *Point) Scan(value interface{}) error {
+	g, err := scanGeometry(value)
+	if err != nil || g == nil {
+		*p = Point{}
+		return err
+	} else if g.typ != wkbPoint {
+		return fmt.Errorf("%w: %v is not a point", ErrInvalidGeometry, value)
+	}
+
+	*p = Point{SRID: g.srid}
+	if len(g.rings) > 0 && len(g.rings[0]) > 0 {
+		p.X, p.Y = g.rings[0][0][0], g.rings[0][0][1]
+	}
+	return nil
+}
+
+// Value implements the driver Valuer interface, returns WKT
+func (p Point) Value() (driver.Value, error) {
+	return p.String(), nil
+}
+
+// String returns WKT of point, e.g: POINT(1 2)
+func (p Point) String() string {
+	return "POINT(" + formatCoordinate(p.X, p.Y) + ")"
+}
+
+// GormDataType gorm common data type
+func (Point) GormDataType() string {
+	return "geometry"
+}
+
+// GormDBDataType gorm db data type
+func (Point) GormDBDataType(db *DB, field *schema.Field) string {
+	return spatialDBDataType(db, field, "Point")
+}
+
+// GormValue builds geometry value from WKT with dialect's function
+func (p Point) GormValue(ctx context.Context, db *DB) clause.Expr {
+	return spatialValue(db, p.String(), p.SRID)
+}
+
+// Polygon spatial polygon, the first ring is the exterior ring, others are interior rings (holes)
+type Polygon struct {
+	Rings [][]Point
+	SRID  int
+}
+
+// Scan implements the Scanner interface, WKT, WKB, EWKB (hex or binary) and mysql internal format are supported
+func (p *Polygon) Scan(value interface{}) error {
+	g, err := scanGeometry(value)
+	if err != nil || g == nil {
+		*p = Polygon{}
+		return err
+	} else if g.typ != wkbPolygon {
+		return fmt.Errorf("%w: %v is not a polygon", ErrInvalidGeometry, value)
+	}
+
+	*p = Polygon{SRID: g.srid, Rings: make([][]Point, len(g.rings))}
+	for idx, ring := range g.rings {
+		p.Rings[idx] = make([]Point, len(ring))
+		for i, coordinate := range ring {
+			p.Rings[idx][i] = Point{X: coordinate[0], Y: coordinate[1]}
+		}
+	}
+	return nil
+}
+
+// Value implements the driver Valuer interface, returns WKT
+func (p Polygon) Value() (driver.Value, error) {
+	return p.String(), nil
+}
+
+// String returns WKT of polygon, e.g: POLYGON((0 0,1 0,1 1,0 0))
+func (p Polygon) String() string {
+	var builder strings.Builder
+	builder.WriteString("POLYGON(")
+	for idx, ring := range p.Rings {
+		if idx > 0 {
+			builder.WriteByte(',')
+		}
+
+		builder.WriteByte('(')
+		for i, point := range ring {
+			if i > 0 {
+				builder.WriteByte(',')
+			}
+			builder.WriteString(formatCoordinate(point.X, point.Y))
+		}
+		builder.WriteByte(')')
+	}
+	builder.WriteByte(')')
+	return builder.String()
+}
+
+// GormDataType gorm common data type
+func (Polygon) GormDataType() string {
+	return "geometry"
+}
+
+// GormDBDataType gorm db data type
+func (Polygon) GormDBDataType(db *DB, field *schema.Field) string {
+	return spatialDBDataType(db, field, "Polygon")
+}
+
+// GormValue builds geometry value from WKT with dialect's function
+func (p Polygon) GormValue(ctx context.Context, db *DB) clause.Expr {
+	return spatialValue(db, p.String(), p.SRID)
+}
+
+// STDWithin query records whose geometry column is within distance of geometry, distance is in meters for geography
+// columns, uses ST_DWithin for postgres, and ST_Distance for others
+//    db.Where(gorm.STDWithin{Column: "location", Geometry: gorm.Point{X: 13.4, Y: 52.5}, Distance: 1000}).Find(&stores)
+type STDWithin struct {
+	Column   interface{}
+	Geometry interface{}
+	Distance float64
+}
+
+// Build build expression
+func (within STDWithin) Build(builder clause.Builder) {
+	if stmt, ok := builder.(*Statement); ok && stmt.Dialector.Name() == "postgres" {
+		builder.WriteString("ST_DWithin(")
+		builder.WriteQuoted(within.Column)
+		builder.WriteByte(',')
+		builder.AddVar(builder, within.Geometry, within.Distance)
+		builder.WriteByte(')')
+		return
+	}
+
+	STDistance{Column: within.Column, Geometry: within.Geometry}.Build(builder)
+	builder.WriteString(" <= ")
+	builder.AddVar(builder, within.Distance)
+}
+
+// NegationBuild build negation expression
+func (within STDWithin) NegationBuild(builder clause.Builder) {
+	builder.WriteString("NOT ")
+	within.Build(builder)
+}
+
+// STDistance distance between geometry column and geometry, could be used to order by distance
+//    db.Clauses(clause.OrderBy{Expression: gorm.STDistance{Column: "location", Geometry: point}}).Find(&stores)
+type STDistance struct {
+	Column   interface{}
+	Geometry interface{}
+}
+
+// Build build expression
+func (distance STDistance) Build(builder clause.Builder) {
+	builder.WriteString("ST_Distance(")
+	builder.WriteQuoted(distance.Column)
+	builder.WriteByte(',')
+	builder.AddVar(builder, distance.Geometry)
+	builder.WriteByte(')')
+}
+
+func spatialSRID(field *schema.Field) int {
+	if field != nil {
+		if v, err := strconv.Atoi(field.TagSettings["SRID"]); err == nil {
+			return v
+		}
+	}
+	return DefaultSRID
+}
+
+func spatialDBDataType(db *DB, field *schema.Field, geometryType string) string {
+	switch db.Dialector.Name() {
+	case "postgres":
+		if _, ok := field.TagSettings["GEOGRAPHY"]; ok {
+			return fmt.Sprintf("geography(%s,%d)", geometryType, spatialSRID(field))
+		}
+		return fmt.Sprintf("geometry(%s,%d)", geometryType, spatialSRID(field))
+	case "mysql":
+		return fmt.Sprintf("%s SRID %d", strings.ToUpper(geometryType), spatialSRID(field))
+	default:
+		return "text"
+	}
+}
+
+func spatialValue(db *DB, wkt string, srid int) clause.Expr {
+	if srid == 0 {
+		srid = DefaultSRID
+	}
+
+	switch db.Dialector.Name() {
+	case "postgres":
+		return clause.Expr{SQL: "ST_GeomFromText(?,?)", Vars: []interface{}{wkt, srid}}
+	case "mysql":
+		return clause.Expr{SQL: "ST_GeomFromText(?,?,'axis-order=long-lat')", Vars: []interface{}{wkt, srid}}
+	default:
+		return clause.Expr{SQL: "?", Vars: []interface{}{wkt}}
+	}
+}
+
+func formatCoordinate(x, y float64) string {
+	return strconv.FormatFloat(x, 'f', -1, 64) + " " + strconv.FormatFloat(y, 'f', -1, 64)
+}
+
+// geometry parsed geometry, points are saved as a ring with one coordinate
+type geometry struct {
+	typ   uint32
+	srid  int
+	rings [][][2]float64
+}
+
+func scanGeometry(value interface{}) (*geometry, error) {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return nil, fmt.Errorf("%w: %#v", ErrInvalidGeometry, value)
+	}
+
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	if text := bytes.TrimSpace(data); len(text) > 0 && (text[0] >= 'A' && text[0] <= 'Z' || text[0] >= 'a' && text[0] <= 'z') {
+		return parseWKT(string(text))
+	} else if decoded, err := hex.DecodeString(string(text)); err == nil && len(decoded) > 0 {
+		// hex encoded (E)WKB, e.g: returned by PostGIS
+		return parseWKB(decoded)
+	}
+
+	g, err := parseWKB(data)
+	if err != nil && len(data) > 4 {
+		// mysql internal format, 4 bytes little endian SRID followed by WKB
+		if g, err = parseWKB(data[4:]); err == nil {
+			g.srid = int(binary.LittleEndian.Uint32(data[:4]))
+		}
+	}
+	return g, err
+}
+
+func parseWKB(data []byte) (*geometry, error) {
+	var (
+		g      = &geometry{}
+		order  binary.ByteOrder
+		offset int
+	)
+
+	readUint32 := func() (uint32, error) {
+		if offset+4 > len(data) {
+			return 0, ErrInvalidGeometry
+		}
+		offset += 4
+		return order.Uint32(data[offset-4 : offset]), nil
+	}
+
+	readRing := func() ([][2]float64, error) {
+		count, err := readUint32()
+		if err != nil || offset+int(count)*16 > len(data) {
+			return nil, ErrInvalidGeometry
+		}
+
+		ring := make([][2]float64, count)
+		for i := range ring {
+			ring[i][0] = math.Float64frombits(order.Uint64(data[offset : offset+8]))
+			ring[i][1] = math.Float64frombits(order.Uint64(data[offset+8 : offset+16]))
+			offset += 16
+		}
+		return ring, nil
+	}
+
+	if len(data) < 5 {
+		return nil, ErrInvalidGeometry
+	} else if data[0] == 0 {
+		order = binary.BigEndian
+	} else if data[0] > 1 {
+		return nil, ErrInvalidGeometry
+	} else {
+		order = binary.LittleEndian
+	}
+	offset = 1
+
+	typ, err := readUint32()
+	if err != nil {
+		return nil, err
+	}
+
+	if typ&ewkbSRID != 0 {
+		srid, err := readUint32()
+		if err != nil {
+			return nil, err
+		}
+		g.srid = int(srid)
+	}
+
+	switch g.typ = typ & 0xffff; g.typ {
+	case wkbPoint:
+		if offset+16 > len(data) {
+			return nil, ErrInvalidGeometry
+		}
+
+		g.rings = [][][2]float64{{{
+			math.Float64frombits(order.Uint64(data[offset : offset+8])),
+			math.Float64frombits(order.Uint64(data[offset+8 : offset+16])),
+		}}}
+		offset += 16
+	case wkbPolygon:
+		count, err := readUint32()
+		if err != nil {
+			return nil, err
+		}
+
+		for i := uint32(0); i < count; i++ {
+			ring, err := readRing()
+			if err != nil {
+				return nil, err
+			}
+			g.rings = append(g.rings, ring)
+		}
+	default:
+		return nil, fmt.Errorf("%w: unsupported geometry type %d", ErrInvalidGeometry, g.typ)
+	}
+
+	if offset != len(data) {
+		return nil, ErrInvalidGeometry
+	}
+	return g, nil
+}
+
+// parseWKT parse WKT or EWKT, e.g: POINT(1 2), SRID=4326;POLYGON((0 0,1 0,1 1,0 0))
+func parseWKT(text string) (*geometry, error) {
+	g := &geometry{}
+	if strings.HasPrefix(strings.ToUpper(text), "SRID=") {
+		idx := strings.IndexByte(text, ';')
+		if idx < 0 {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidGeometry, text)
+		}
+
+		srid, err := strconv.Atoi(text[5:idx])
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidGeometry, text)
+		}
+		g.srid, text = srid, text[idx+1:]
+	}
+
+	idx := strings.IndexByte(text, '(')
+	if idx < 0 || !strings.HasSuffix(text, ")") {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidGeometry, text)
+	}
+
+	body := strings.TrimSpace(text[idx+1 : len(text)-1])
+	switch strings.ToUpper(strings.TrimSpace(text[:idx])) {
+	case "POINT":
+		g.typ = wkbPoint
+		coordinate, err := parseWKTCoordinate(body)
+		if err != nil {
+			return nil, err
+		}
+		g.rings = [][][2]float64{{coordinate}}
+	case "POLYGON":
+		g.typ = wkbPolygon
+		for body != "" {
+			if body[0] != '(' {
+				return nil, fmt.Errorf("%w: %v", ErrInvalidGeometry, text)
+			}
+
+			end := strings.IndexByte(body, ')')
+			if end < 0 {
+				return nil, fmt.Errorf("%w: %v", ErrInvalidGeometry, text)
+			}
+
+			var ring [][2]float64
+			for _, str := range strings.Split(body[1:end], ",") {
+				coordinate, err := parseWKTCoordinate(str)
+				if err != nil {
+					return nil, err
+				}
+				ring = append(ring, coordinate)
+			}
+			g.rings = append(g.rings, ring)
+			body = strings.TrimLeft(body[end+1:], ", ")
+		}
+	default:
+		return nil, fmt.Errorf("%w: unsupported geometry %v", ErrInvalidGeometry, text)
+	}
+	return g, nil
+}
+
+func parseWKTCoordinate(str string) (coordinate [2]float64, err error) {
+	values := strings.Fields(str)
+	if len(values) < 2 {
+		return coordinate, fmt.Errorf("%w: invalid coordinate %v", ErrInvalidGeometry, str)
+	}
+
+	for i := 0; i < 2; i++ {
+		if coordinate[i], err = strconv.ParseFloat(values[i], 64); err != nil {
+			return coordinate, fmt.Errorf("%w: invalid coordinate %v", ErrInvalidGeometry, str)
+		}
+	}
+	return coordinate, nil
+}
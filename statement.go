@@ -10,6 +10,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"gorm.io/gorm/clause"
 	"gorm.io/gorm/logger"
@@ -22,6 +23,7 @@ type Statement struct {
 	*DB
 	TableExpr            *clause.Expr
 	Table                string
+	Namespace            string // schema of table, e.g: `public` of `public.users`
 	Model                interface{}
 	Unscoped             bool
 	Dest                 interface{}
@@ -38,13 +40,57 @@ type Statement struct {
 	Context              context.Context
 	RaiseErrorOnNotFound bool
 	SkipHooks            bool
+	InTransaction        bool          // executed in transaction or dedicated connection, its ConnPool isn't switched to tenants or replicas
+	Timeout              time.Duration // timeout of statement, set with WithTimeout or StatementTimeout
 	SQL                  strings.Builder
 	Vars                 []interface{}
 	CurDestIndex         int
+	NullFields           [][]string // non-pointer fields scanned from NULL of every row, tracked with NullPolicy NullTrack
+	redaction            *redaction
+	varsBuffer           *[]interface{} // pooled backing array of Vars, released after executing
 	attrs                []interface{}
 	assigns              []interface{}
 }
 
+// maxPooledVars backing arrays of Vars of more vars are not pooled, e.g: batch creating
+const maxPooledVars = 256
+
+// varsPool backing arrays of Vars, statements are returned to callers and their clauses and SQL are referenced after
+// executing, e.g: by Statement.SQL of results and prepared statements cached by SQL, so Statement, its clauses and SQL
+// builder aren't pooled, only Vars, which are released after executing, are pooled
+var varsPool = sync.Pool{New: func() interface{} {
+	vars := make([]interface{}, 0, 8)
+	return &vars
+}}
+
+// acquireVars sets Vars of statement to backing array from pool
+func (stmt *Statement) acquireVars() {
+	stmt.varsBuffer = varsPool.Get().(*[]interface{})
+	stmt.Vars = (*stmt.varsBuffer)[:0]
+}
+
+// releaseVars puts backing array of Vars back to pool after executing, vars shouldn't be referenced after it
+func (stmt *Statement) releaseVars() {
+	if buf := stmt.varsBuffer; buf != nil && cap(stmt.Vars) <= maxPooledVars {
+		for idx := range stmt.Vars {
+			stmt.Vars[idx] = nil
+		}
+		*buf = stmt.Vars[:0]
+		varsPool.Put(buf)
+	}
+	stmt.varsBuffer, stmt.Vars = nil, nil
+}
+
+// releaseVarsHook resets SQL of statement and releases its vars after executing, statements of DryRun keep them for callers
+func releaseVarsHook(e *execution) {
+	e.next()
+
+	stmt := e.db.Statement
+	stmt.SQL.Reset()
+	stmt.releaseVars()
+	stmt.redaction = nil
+}
+
 type join struct {
 	Name  string
 	Conds []interface{}
@@ -65,6 +111,22 @@ func (stmt *Statement) WriteByte(c byte) error {
 	return stmt.SQL.WriteByte(c)
 }
 
+// RowValuesSubquery subquery is used for IN of row values if the database doesn't support lists of them, e.g: sqlite,
+// implements clause.RowValuesSubqueryBuilder
+func (stmt *Statement) RowValuesSubquery() bool {
+	return stmt.DB != nil && stmt.Dialector != nil && !stmt.DB.Capabilities().RowValues
+}
+
+// RowValuesExpansion IN of row values is expanded to ORs if the database supports neither lists of them nor VALUES
+// subqueries, e.g: sqlserver, implements clause.RowValuesExpansionBuilder
+func (stmt *Statement) RowValuesExpansion() bool {
+	if stmt.DB == nil || stmt.Dialector == nil {
+		return false
+	}
+	capabilities := stmt.DB.Capabilities()
+	return !capabilities.RowValues && !capabilities.ValuesSubquery
+}
+
 // WriteQuoted write quoted value
 func (stmt *Statement) WriteQuoted(value interface{}) {
 	stmt.QuoteTo(&stmt.SQL, value)
@@ -75,7 +137,9 @@ func (stmt *Statement) QuoteTo(writer clause.Writer, field interface{}) {
 	switch v := field.(type) {
 	case clause.Table:
 		if v.Name == clause.CurrentTable {
-			if stmt.TableExpr != nil {
+			if stmt.TableExpr != nil && len(stmt.TableExpr.Vars) == 0 {
+				writer.WriteString(stmt.TableExpr.SQL)
+			} else if stmt.TableExpr != nil {
 				stmt.TableExpr.Build(stmt)
 			} else {
 				stmt.DB.Dialector.QuoteTo(writer, stmt.Table)
@@ -157,6 +221,10 @@ func (stmt *Statement) AddVar(writer clause.Writer, vars ...interface{}) {
 			writer.WriteByte(',')
 		}
 
+		// values of sensitive columns are masked in logged SQL
+		w, _ := writer.(*Statement)
+		sensitive, start := w == stmt && stmt.redacting() && stmt.sensitiveVar(idx), len(stmt.Vars)
+
 		switch v := v.(type) {
 		case sql.NamedArg:
 			stmt.Vars = append(stmt.Vars, v.Value)
@@ -183,6 +251,7 @@ func (stmt *Statement) AddVar(writer clause.Writer, vars ...interface{}) {
 		case *DB:
 			subdb := v.Session(&Session{Logger: logger.Discard, DryRun: true}).getInstance()
 			subdb.Statement.Vars = append(subdb.Statement.Vars, stmt.Vars...)
+			subdb.InstanceSet("gorm:sub_query", true)
 			subdb.callbacks.Query().Execute(subdb)
 			writer.WriteString(subdb.Statement.SQL.String())
 			stmt.Vars = subdb.Statement.Vars
@@ -206,6 +275,13 @@ func (stmt *Statement) AddVar(writer clause.Writer, vars ...interface{}) {
 				stmt.DB.Dialector.BindVarTo(writer, stmt, v)
 			}
 		}
+
+		for i := start; sensitive && i < len(stmt.Vars); i++ {
+			if stmt.redaction.vars == nil {
+				stmt.redaction.vars = map[int]bool{}
+			}
+			stmt.redaction.vars[i] = true
+		}
 	}
 }
 
@@ -229,11 +305,31 @@ func (stmt *Statement) AddClauseIfNotExists(v clause.Interface) {
 	}
 }
 
+// isInteger returns true if s is integer parsed by strconv.Atoi, it doesn't allocate error for conditions like strconv.Atoi
+func isInteger(s string) bool {
+	digits := s
+	if len(digits) > 0 && (digits[0] == '-' || digits[0] == '+') {
+		digits = digits[1:]
+	}
+
+	if len(digits) == 0 || len(digits) > 18 {
+		_, err := strconv.Atoi(s)
+		return err == nil
+	}
+
+	for i := 0; i < len(digits); i++ {
+		if digits[i] < '0' || digits[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
 // BuildCondition build condition
 func (stmt *Statement) BuildCondition(query interface{}, args ...interface{}) []clause.Expression {
 	if s, ok := query.(string); ok {
 		// if it is a number, then treats it as primary key
-		if _, err := strconv.Atoi(s); err != nil {
+		if !isInteger(s) {
 			if s == "" && len(args) == 0 {
 				return nil
 			} else if len(args) == 0 || (len(args) > 0 && strings.Contains(s, "?")) {
@@ -389,6 +485,15 @@ func (stmt *Statement) BuildCondition(query interface{}, args ...interface{}) []
 func (stmt *Statement) Build(clauses ...string) {
 	var firstClauseWritten bool
 
+	if stmt.DB.PlanCache {
+		store, ok := stmt.buildFromPlan(clauses)
+		if ok {
+			return
+		} else if store != nil {
+			defer store()
+		}
+	}
+
 	for _, name := range clauses {
 		if c, ok := stmt.Clauses[name]; ok {
 			if firstClauseWritten {
@@ -407,13 +512,20 @@ func (stmt *Statement) Build(clauses ...string) {
 
 func (stmt *Statement) Parse(value interface{}) (err error) {
 	if stmt.Schema, err = schema.Parse(value, stmt.DB.cacheStore, stmt.DB.NamingStrategy); err == nil && stmt.Table == "" {
-		if tables := strings.Split(stmt.Schema.Table, "."); len(tables) == 2 {
+		if namespace, table, ok := strings.Cut(stmt.Schema.Table, "."); ok && !strings.Contains(table, ".") {
 			stmt.TableExpr = &clause.Expr{SQL: stmt.Quote(stmt.Schema.Table)}
-			stmt.Table = tables[1]
+			stmt.Table, stmt.Namespace = table, namespace
 			return
 		}
 
 		stmt.Table = stmt.Schema.Table
+		if stmt.Namespace = stmt.Schema.Namespace; stmt.Namespace == "" {
+			stmt.Namespace = stmt.DB.Namespace
+		}
+
+		if stmt.Namespace != "" {
+			stmt.TableExpr = &clause.Expr{SQL: stmt.Quote(stmt.Namespace + "." + stmt.Table)}
+		}
 	}
 	return err
 }
@@ -422,6 +534,7 @@ func (stmt *Statement) clone() *Statement {
 	newStmt := &Statement{
 		TableExpr:            stmt.TableExpr,
 		Table:                stmt.Table,
+		Namespace:            stmt.Namespace,
 		Model:                stmt.Model,
 		Unscoped:             stmt.Unscoped,
 		Dest:                 stmt.Dest,
@@ -436,6 +549,8 @@ func (stmt *Statement) clone() *Statement {
 		Context:              stmt.Context,
 		RaiseErrorOnNotFound: stmt.RaiseErrorOnNotFound,
 		SkipHooks:            stmt.SkipHooks,
+		InTransaction:        stmt.InTransaction,
+		Timeout:              stmt.Timeout,
 	}
 
 	for k, c := range stmt.Clauses {
@@ -563,6 +678,89 @@ func (stmt *Statement) Changed(fields ...string) bool {
 	return false
 }
 
+// FullSaveAssociation returns whether to upsert records of relationship name, FullSaveAssociations is used if it isn't
+// configured per call with
+//     db.Set("gorm:full_save_associations", map[string]bool{"Pets": true, "Pets.Toy": false, clause.Associations: false})
+func (stmt *Statement) FullSaveAssociation(name string) bool {
+	if v, ok := stmt.Settings.Load("gorm:full_save_associations"); ok {
+		switch value := v.(type) {
+		case bool:
+			return value
+		case map[string]bool:
+			if fullSave, ok := value[name]; ok {
+				return fullSave
+			} else if fullSave, ok := value[clause.Associations]; ok {
+				return fullSave
+			}
+		}
+	}
+	return stmt.DB.FullSaveAssociations
+}
+
+// NestedFullSaveAssociations returns `gorm:full_save_associations` for saving records of relationship name, settings
+// of nested associations are prefixed with the relationship name, e.g: "Pets.Toy" is "Toy" for records of "Pets"
+func (stmt *Statement) NestedFullSaveAssociations(name string) (nested map[string]bool, ok bool) {
+	v, _ := stmt.Settings.Load("gorm:full_save_associations")
+	value, ok := v.(map[string]bool)
+	if !ok {
+		return nil, false
+	}
+
+	nested = map[string]bool{}
+	if fullSave, ok := value[clause.Associations]; ok {
+		nested[clause.Associations] = fullSave
+	}
+
+	for key, fullSave := range value {
+		if strings.HasPrefix(key, name+".") {
+			nested[strings.TrimPrefix(key, name+".")] = fullSave
+		}
+	}
+	return nested, true
+}
+
+// SerializesField returns true if values of field are converted by SerializeValue when saving
+func (stmt *Statement) SerializesField(field *schema.Field) bool {
+	return field.Serializer != nil || field.GORMDataType == schema.Array || field.GORMDataType == schema.Interval || stmt.writeTimeZone(field) != nil
+}
+
+// SerializeValue converts value of field to database value when saving, times are converted to time zone of field first,
+// then values of fields with serializer are serialized, arrays and intervals are converted to database values
+func (stmt *Statement) SerializeValue(field *schema.Field, dst reflect.Value, value interface{}) (interface{}, error) {
+	// times are converted to time zone of field before serializing, e.g: timezone:UTC;serializer:json
+	if loc := stmt.writeTimeZone(field); loc != nil {
+		value = schema.InTimeZone(value, loc)
+	}
+
+	if field.Serializer != nil {
+		return field.Serializer.Value(stmt.Context, field, dst, value)
+	} else if field.GORMDataType == schema.Array {
+		return schema.ArrayValue(value, stmt.DB.Capabilities().NativeArray)
+	} else if field.GORMDataType == schema.Interval {
+		return schema.IntervalValue(value, stmt.DB.Capabilities().NativeArray)
+	}
+	return value, nil
+}
+
+// writeTimeZone returns time zone of time field used when saving
+func (stmt *Statement) writeTimeZone(field *schema.Field) *time.Location {
+	if field.TimeZone != nil {
+		return field.TimeZone
+	} else if field.GORMDataType == schema.Time && field.Serializer == nil {
+		return stmt.DB.TimeZone
+	}
+	return nil
+}
+
+// Actor returns actor of context of statement resolved with Config.ActorResolver, e.g: current user id, it is assigned to
+// fields tagged with `autoCreatedBy`, `autoUpdatedBy` and `softDelete:by`, and recorded by audit trails
+func (stmt *Statement) Actor() (interface{}, bool) {
+	if stmt.DB.ActorResolver != nil && stmt.Context != nil {
+		return stmt.DB.ActorResolver(stmt.Context)
+	}
+	return nil, false
+}
+
 // SelectAndOmitColumns get select and omit columns, select -> true, omit -> false
 func (stmt *Statement) SelectAndOmitColumns(requireCreate, requireUpdate bool) (map[string]bool, bool) {
 	results := map[string]bool{}
@@ -612,6 +810,8 @@ func (stmt *Statement) SelectAndOmitColumns(requireCreate, requireUpdate bool) (
 				results[name] = false
 			} else if requireUpdate && !field.Updatable {
 				results[name] = false
+			} else if (requireCreate || requireUpdate) && stmt.DB.RolePolicy != nil && !stmt.DB.RolePolicy.CanWrite(stmt.Context, field) {
+				results[name] = false
 			}
 		}
 	}
@@ -0,0 +1,87 @@
+package gorm
+
+import (
+	"context"
+	"database/sql"
+	"sync/atomic"
+	"time"
+)
+
+// Stats statistics of connection pool and gorm
+type Stats struct {
+	sql.DBStats
+	PreparedStmts      int   // statements cached with PrepareStmt
+	StatementsExecuted int64 // statements executed by callbacks, e.g: Find, Create, Exec
+	OpenTransactions   int64 // transactions begun and not committed or rolled back yet
+}
+
+// counters gorm level counters, shared by sessions of db
+type counters struct {
+	statementsExecuted int64
+	openTransactions   int64
+}
+
+// Stats returns statistics of connection pool and gorm, DBStats is zero if ConnPool is not *sql.DB
+func (db *DB) Stats() (stats Stats) {
+	if sqlDB, err := db.DB(); err == nil {
+		stats.DBStats = sqlDB.Stats()
+	}
+
+	if stmtDB, ok := db.ConnPool.(*PreparedStmtDB); ok {
+		stmtDB.Mux.RLock()
+		stats.PreparedStmts = len(stmtDB.Stmts)
+		stmtDB.Mux.RUnlock()
+	}
+
+	if db.counters != nil {
+		stats.StatementsExecuted = atomic.LoadInt64(&db.counters.statementsExecuted)
+		stats.OpenTransactions = atomic.LoadInt64(&db.counters.openTransactions)
+	}
+	return
+}
+
+// ReportStats reports statistics every interval until ctx is done, e.g: exporting them to monitoring systems
+//    db.ReportStats(ctx, time.Minute, func(stats gorm.Stats) {
+//      openConnections.Set(float64(stats.OpenConnections))
+//    })
+func (db *DB) ReportStats(ctx context.Context, interval time.Duration, report func(Stats)) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				report(db.Stats())
+			}
+		}
+	}()
+}
+
+// countStatementsEnabled returns true if executed statements are counted for Stats
+func countStatementsEnabled(e *execution) bool {
+	return !e.db.DryRun && e.db.Statement.DB.counters != nil
+}
+
+// countStatementsHook counts executed statement for StatementsExecuted of Stats
+func countStatementsHook(e *execution) {
+	e.next()
+
+	if stmt := e.db.Statement; stmt.SQL.Len() > 0 {
+		stmt.DB.counters.statementExecuted()
+	}
+}
+
+func (c *counters) statementExecuted() {
+	if c != nil {
+		atomic.AddInt64(&c.statementsExecuted, 1)
+	}
+}
+
+func (c *counters) transactionOpened(delta int64) {
+	if c != nil {
+		atomic.AddInt64(&c.openTransactions, delta)
+	}
+}
@@ -0,0 +1,177 @@
+package gorm
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm/clause"
+)
+
+// strictColumnsHook validates columns of statement built from model in StrictColumns mode before executing
+func strictColumnsHook(e *execution) {
+	if stmt := e.db.Statement; stmt.Schema != nil && stmt.SQL.Len() == 0 {
+		e.db.AddError(stmt.validateColumns())
+	}
+	e.next()
+}
+
+// validateColumns validates column names of Select, Omit, Order and map conditions against the parsed schema
+// in StrictColumns mode, columns listed in AllowedColumns are always valid
+func (stmt *Statement) validateColumns() error {
+	for _, name := range stmt.Selects {
+		for _, column := range strings.Split(name, ",") {
+			column = strings.TrimSpace(column)
+			if fields := strings.Fields(column); len(fields) == 3 && strings.EqualFold(fields[1], "AS") {
+				column = fields[0]
+			}
+
+			if column != "*" && !stmt.validColumn(column, true) {
+				return fmt.Errorf("%w: unknown column %v in select", ErrInvalidField, name)
+			}
+		}
+	}
+
+	for _, name := range stmt.Omits {
+		if !stmt.validColumn(name, true) {
+			return fmt.Errorf("%w: unknown column %v in omit", ErrInvalidField, name)
+		}
+	}
+
+	if c, ok := stmt.Clauses["ORDER BY"]; ok {
+		if orderBy, ok := c.Expression.(clause.OrderBy); ok {
+			for _, column := range orderBy.Columns {
+				if !column.Column.Raw {
+					if !stmt.validClauseColumn(column.Column) {
+						return fmt.Errorf("%w: unknown column %v in order", ErrInvalidField, column.Column.Name)
+					}
+					continue
+				}
+
+				for _, name := range strings.Split(column.Column.Name, ",") {
+					fields := strings.Fields(name)
+					if len(fields) == 2 && (strings.EqualFold(fields[1], "ASC") || strings.EqualFold(fields[1], "DESC")) {
+						fields = fields[:1]
+					}
+
+					if len(fields) != 1 || !stmt.validColumn(fields[0], false) {
+						return fmt.Errorf("%w: unknown column %v in order", ErrInvalidField, strings.TrimSpace(name))
+					}
+				}
+			}
+		}
+	}
+
+	if c, ok := stmt.Clauses["WHERE"]; ok {
+		if where, ok := c.Expression.(clause.Where); ok {
+			return stmt.validateConditions(where.Exprs)
+		}
+	}
+	return nil
+}
+
+// validateConditions validates columns of conditions built from maps or structs
+func (stmt *Statement) validateConditions(exprs []clause.Expression) error {
+	for _, expr := range exprs {
+		var column interface{}
+		switch v := expr.(type) {
+		case clause.AndConditions:
+			if err := stmt.validateConditions(v.Exprs); err != nil {
+				return err
+			}
+		case clause.OrConditions:
+			if err := stmt.validateConditions(v.Exprs); err != nil {
+				return err
+			}
+		case clause.NotConditions:
+			if err := stmt.validateConditions(v.Exprs); err != nil {
+				return err
+			}
+		case clause.Eq:
+			column = v.Column
+		case clause.Neq:
+			column = v.Column
+		case clause.IN:
+			column = v.Column
+		case clause.Gt:
+			column = v.Column
+		case clause.Gte:
+			column = v.Column
+		case clause.Lt:
+			column = v.Column
+		case clause.Lte:
+			column = v.Column
+		case clause.Like:
+			column = v.Column
+		}
+
+		switch v := column.(type) {
+		case string:
+			if !stmt.validColumn(v, false) {
+				return fmt.Errorf("%w: unknown column %v in conditions", ErrInvalidField, v)
+			}
+		case clause.Column:
+			if !stmt.validClauseColumn(v) {
+				return fmt.Errorf("%w: unknown column %v in conditions", ErrInvalidField, v.Name)
+			}
+		}
+	}
+	return nil
+}
+
+func (stmt *Statement) validClauseColumn(column clause.Column) bool {
+	if column.Raw {
+		return stmt.validColumn(column.Name, false)
+	}
+
+	if column.Name == clause.PrimaryKey || column.Table == "" || column.Table == clause.CurrentTable {
+		return column.Name == clause.PrimaryKey || stmt.validColumn(column.Name, false)
+	}
+	return stmt.validColumn(column.Table+"."+column.Name, false)
+}
+
+// validColumn returns whether name is a column of current table, a column of joined relations like `Company.name`,
+// or listed in AllowedColumns, relation names are valid if withRelations is true
+func (stmt *Statement) validColumn(name string, withRelations bool) bool {
+	for _, allowed := range stmt.DB.AllowedColumns {
+		if allowed == name {
+			return true
+		}
+	}
+
+	name = strings.Trim(name, "`\"")
+	sch := stmt.Schema
+	if idx := strings.LastIndexByte(name, '.'); idx > 0 {
+		prefix := strings.Trim(name[:idx], "`\"")
+		name = strings.Trim(name[idx+1:], "`\"")
+
+		if prefix != sch.Table {
+			sch = nil
+			for _, rel := range stmt.Schema.Relationships.Relations {
+				if rel.Name == prefix || rel.FieldSchema.Table == prefix {
+					sch = rel.FieldSchema
+					break
+				}
+			}
+
+			if sch == nil {
+				return false
+			} else if withRelations && name == "*" {
+				return true
+			}
+		}
+	}
+
+	if field := sch.LookUpField(name); field != nil && field.DBName != "" {
+		return true
+	}
+
+	if withRelations {
+		if name == clause.Associations {
+			return true
+		}
+		if _, ok := sch.Relationships.Relations[name]; ok {
+			return true
+		}
+	}
+	return false
+}
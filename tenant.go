@@ -0,0 +1,125 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm/clause"
+)
+
+// Tenant connection pool and namespace of tenant, statements of tenant are executed with its ConnPool, e.g: database per tenant,
+// and tables without namespace are qualified with its Namespace, e.g: schema of postgres or database of mysql, transactions of
+// postgres are started with search_path of Namespace, so tables of joins, preloads and join tables are looked up in it too,
+// only main tables are qualified for other databases and statements out of transactions
+type Tenant struct {
+	Name      string
+	ConnPool  ConnPool
+	Namespace string
+}
+
+// TenantResolver returns tenant of context, statements are executed with tenant of their contexts,
+// ok is false if there is no tenant in context, then the default connection pool and namespace are used
+//    db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+//      TenantResolver: func(ctx context.Context) (gorm.Tenant, bool) {
+//        if name, ok := ctx.Value(tenantKey{}).(string); ok {
+//          return gorm.Tenant{Name: name, Namespace: "tenant_" + name}, true
+//        }
+//        return gorm.Tenant{}, false
+//      },
+//    })
+//    db.WithContext(ctx).Find(&users) // SELECT * FROM "tenant_acme"."users"
+type TenantResolver func(ctx context.Context) (tenant Tenant, ok bool)
+
+// TenantScope scopes rows of models having tenant column to tenant of context, queries, updates and deletes of the models
+// are conditioned with the column, created records are assigned with the tenant, statements of the models without tenant
+// in context are rejected with ErrMissingTenant, use SkipTenantScope for admin jobs
+//    db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+//      TenantScope: &gorm.TenantScope{Column: "tenant_id", TenantID: func(ctx context.Context) (interface{}, bool) {
+//        id, ok := ctx.Value(tenantIDKey{}).(uint)
+//        return id, ok
+//      }},
+//    })
+type TenantScope struct {
+	Column   string
+	TenantID func(ctx context.Context) (id interface{}, ok bool)
+}
+
+// SkipTenantScope executes statements without TenantScope, e.g: admin jobs across tenants
+func (db *DB) SkipTenantScope() (tx *DB) {
+	tx = db.getInstance()
+	tx.Statement.Settings.Store("gorm:skip_tenant_scope", true)
+	return
+}
+
+// tenant returns tenant of statement, tenant set with ForTenant or resolved from context with TenantResolver
+func (db *DB) tenant(ctx context.Context) (Tenant, bool) {
+	if v, ok := db.Statement.Settings.Load("gorm:tenant"); ok {
+		return v.(Tenant), true
+	}
+
+	if db.TenantResolver != nil && ctx != nil {
+		return db.TenantResolver(ctx)
+	}
+	return Tenant{}, false
+}
+
+// ForTenant returns session of tenant, statements of it are executed with connection pool and namespace of tenant regardless
+// of TenantResolver, it could be used to migrate tables of tenant, e.g: db.ForTenant(tenant).AutoMigrate(&User{})
+func (db *DB) ForTenant(tenant Tenant) *DB {
+	tx := db.Session(&Session{Context: db.Statement.Context, Namespace: tenant.Namespace})
+	tx.Statement.Settings.Store("gorm:tenant", tenant)
+	if tenant.ConnPool != nil {
+		// replicas are of the default connection pool
+		tx.Statement.ConnPool, tx.Config.ConnPool, tx.Config.Replicas = tenant.ConnPool, tenant.ConnPool, nil
+	}
+	return tx
+}
+
+// MigrateTenants runs AutoMigrate of dst for tenants, stops at the first tenant failed to migrate
+func (db *DB) MigrateTenants(tenants []Tenant, dst ...interface{}) error {
+	for _, tenant := range tenants {
+		if err := db.ForTenant(tenant).AutoMigrate(dst...); err != nil {
+			return fmt.Errorf("failed to migrate tenant %v: %w", tenant.Name, err)
+		}
+	}
+	return nil
+}
+
+// tenantEnabled returns true if statement could be of tenant, set with ForTenant or resolved with TenantResolver
+func tenantEnabled(e *execution) bool {
+	if e.db.Statement.DB.TenantResolver != nil {
+		return true
+	}
+	_, ok := e.db.Statement.Settings.Load("gorm:tenant")
+	return ok
+}
+
+// tenantHook executes statement with connection pool and namespace of its tenant
+func tenantHook(e *execution) {
+	defer e.db.Statement.switchTenant()()
+	e.next()
+}
+
+// switchTenant switches connection pool and namespace of statement to tenant of its context, returns func to restore them
+func (stmt *Statement) switchTenant() (restore func()) {
+	tenant, ok := stmt.DB.tenant(stmt.Context)
+	if !ok {
+		return func() {}
+	}
+
+	connPool, namespace, tableExpr := stmt.ConnPool, stmt.Namespace, stmt.TableExpr
+	if tenant.ConnPool != nil && !stmt.InTransaction {
+		stmt.ConnPool = tenant.ConnPool
+	}
+
+	// tables qualified with Config.Namespace are switched to namespace of tenant
+	configured := stmt.TableExpr != nil && stmt.Namespace != "" && stmt.Namespace == stmt.DB.Namespace &&
+		(stmt.Schema == nil || stmt.Schema.Namespace == "")
+	if tenant.Namespace != "" && stmt.Table != "" && (stmt.TableExpr == nil || configured) {
+		stmt.Namespace, stmt.TableExpr = tenant.Namespace, &clause.Expr{SQL: stmt.Quote(tenant.Namespace + "." + stmt.Table)}
+	}
+
+	return func() {
+		stmt.ConnPool, stmt.Namespace, stmt.TableExpr = connPool, namespace, tableExpr
+	}
+}
@@ -0,0 +1,51 @@
+package tests_test
+
+import (
+	"reflect"
+	"testing"
+)
+
+type ArrayArticle struct {
+	ID     uint
+	Tags   []string
+	Scores []int64
+	Flags  *[]bool
+}
+
+func TestArrayColumns(t *testing.T) {
+	DB.Migrator().DropTable(&ArrayArticle{})
+	if err := DB.AutoMigrate(&ArrayArticle{}); err != nil {
+		t.Fatalf("failed to migrate array columns, got error %v", err)
+	}
+
+	flags := []bool{true, false}
+	article := ArrayArticle{Tags: []string{"a", `b "c"`}, Scores: []int64{1, 2, 3}, Flags: &flags}
+	if err := DB.Create(&article).Error; err != nil {
+		t.Fatalf("failed to create array article, got error %v", err)
+	}
+
+	var result ArrayArticle
+	if err := DB.First(&result, article.ID).Error; err != nil {
+		t.Fatalf("failed to query array article, got error %v", err)
+	}
+
+	if !reflect.DeepEqual(result, article) {
+		t.Errorf("array article should be equal, expects %#v, got %#v", article, result)
+	}
+
+	if err := DB.Model(&result).Update("Tags", []string{"x"}).Error; err != nil {
+		t.Fatalf("failed to update array column, got error %v", err)
+	}
+
+	var updated ArrayArticle
+	DB.First(&updated, article.ID)
+	if !reflect.DeepEqual(updated.Tags, []string{"x"}) || !reflect.DeepEqual(updated.Scores, article.Scores) {
+		t.Errorf("incorrect updated array article, got %#v", updated)
+	}
+
+	var empty ArrayArticle
+	DB.Create(&empty)
+	if err := DB.First(&empty, empty.ID).Error; err != nil || empty.Tags != nil || empty.Flags != nil {
+		t.Errorf("nil arrays should be scanned as nil, got %#v, error %v", empty, err)
+	}
+}
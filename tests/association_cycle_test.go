@@ -0,0 +1,103 @@
+package tests_test
+
+import (
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+	. "gorm.io/gorm/utils/tests"
+)
+
+type CycleOwner struct {
+	ID   uint
+	Name string
+	Pets []*CyclePet `gorm:"foreignKey:OwnerID"`
+}
+
+type CyclePet struct {
+	ID      uint
+	Name    string
+	OwnerID uint
+	Owner   *CycleOwner
+}
+
+type CycleA struct {
+	ID  uint
+	BID *uint
+	B   *CycleB
+}
+
+type CycleB struct {
+	ID  uint
+	AID *uint
+	A   *CycleA
+}
+
+func TestSaveCyclicAssociations(t *testing.T) {
+	DB.Migrator().DropTable(&CycleOwner{}, &CyclePet{}, &CycleA{}, &CycleB{})
+	if err := DB.AutoMigrate(&CycleOwner{}, &CyclePet{}, &CycleA{}, &CycleB{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	owner := &CycleOwner{Name: "cycle_owner"}
+	owner.Pets = []*CyclePet{{Name: "cycle_pet_1", Owner: owner}, {Name: "cycle_pet_2", Owner: owner}}
+	if err := DB.Create(owner).Error; err != nil {
+		t.Fatalf("failed to create owner with back references, got error %v", err)
+	}
+
+	var owners, pets int64
+	DB.Model(&CycleOwner{}).Count(&owners)
+	DB.Model(&CyclePet{}).Where("owner_id = ?", owner.ID).Count(&pets)
+	if owners != 1 || pets != 2 {
+		t.Errorf("should create 1 owner and 2 pets, but got %v, %v", owners, pets)
+	}
+
+	shared := &CycleOwner{Name: "cycle_shared"}
+	newPets := []CyclePet{{Name: "cycle_pet_3", Owner: shared}, {Name: "cycle_pet_4", Owner: shared}}
+	if err := DB.Create(&newPets).Error; err != nil {
+		t.Fatalf("failed to create pets with shared owner, got error %v", err)
+	}
+
+	DB.Model(&CycleOwner{}).Where("name = ?", "cycle_shared").Count(&owners)
+	if owners != 1 || newPets[0].OwnerID != shared.ID || newPets[1].OwnerID != shared.ID {
+		t.Errorf("shared owner should be created once, but got %v, %#v", owners, newPets)
+	}
+
+	a := &CycleA{}
+	a.B = &CycleB{A: a}
+	if err := DB.Create(a).Error; !errors.Is(err, gorm.ErrCyclicAssociation) {
+		t.Errorf("should returns cyclic association error, but got %v", err)
+	}
+}
+
+func TestSaveAssociationsBatchedByLevel(t *testing.T) {
+	db, _ := gorm.Open(DB.Dialector, &gorm.Config{})
+	inserts := map[string]int{}
+	db.Callback().Create().After("gorm:create").Register("test:count_inserts", func(tx *gorm.DB) {
+		inserts[tx.Statement.Table]++
+	})
+
+	users := []*User{GetUser("batch_level_1", Config{Pets: 2, Company: true, Account: true}), GetUser("batch_level_2", Config{Pets: 2, Account: true})}
+	for _, user := range users {
+		for _, pet := range user.Pets {
+			pet.Toy = Toy{Name: pet.Name + "_toy"}
+		}
+	}
+
+	if err := db.Create(&users).Error; err != nil {
+		t.Fatalf("failed to create users, got error %v", err)
+	}
+
+	// records of each relationship are saved with one statement for all records of the level
+	for _, table := range []string{"users", "companies", "accounts", "pets", "toys"} {
+		if inserts[table] != 1 {
+			t.Errorf("%v should be saved with one statement, but got %v", table, inserts[table])
+		}
+	}
+
+	var toys int64
+	db.Model(&Toy{}).Where("owner_type = ? AND name LIKE ?", "pets", "batch_level_%").Count(&toys)
+	if toys != 4 {
+		t.Errorf("should create 4 toys of pets, but got %v", toys)
+	}
+}
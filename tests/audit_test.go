@@ -0,0 +1,89 @@
+package tests_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/audit"
+)
+
+type AuditedAccount struct {
+	ID      uint
+	Name    string
+	Balance int
+}
+
+func (AuditedAccount) AuditTable() string {
+	return "audited_account_audits"
+}
+
+func TestAudit(t *testing.T) {
+	type actorKey struct{}
+	db, _ := gorm.Open(DB.Dialector, &gorm.Config{ActorResolver: func(ctx context.Context) (interface{}, bool) {
+		actor, ok := ctx.Value(actorKey{}).(string)
+		return actor, ok
+	}})
+	if err := db.Use(audit.New(audit.Config{})); err != nil {
+		t.Fatalf("failed to use plugin, got error %v", err)
+	}
+
+	db.Migrator().DropTable(&AuditedAccount{}, "audited_account_audits")
+	if err := db.AutoMigrate(&AuditedAccount{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	if err := audit.AutoMigrate(db, &AuditedAccount{}); err != nil {
+		t.Fatalf("failed to migrate audit table, got error %v", err)
+	}
+
+	var (
+		ctx     = context.WithValue(context.Background(), actorKey{}, "jinzhu")
+		tx      = db.WithContext(ctx)
+		account = AuditedAccount{Name: "audit", Balance: 10}
+	)
+
+	if err := tx.Create(&account).Error; err != nil {
+		t.Fatalf("failed to create account, got error %v", err)
+	}
+
+	if err := tx.Model(&account).Updates(map[string]interface{}{"balance": 20}).Error; err != nil {
+		t.Fatalf("failed to update account, got error %v", err)
+	}
+
+	tx.Transaction(func(tx *gorm.DB) error {
+		tx.Model(&account).Update("name", "rolled back")
+		return errors.New("rollback")
+	})
+
+	if err := db.Delete(&account).Error; err != nil {
+		t.Fatalf("failed to delete account, got error %v", err)
+	}
+
+	records, err := audit.History(db, &account)
+	if err != nil {
+		t.Fatalf("failed to read history, got error %v", err)
+	}
+
+	if len(records) != 3 {
+		t.Fatalf("should have 3 audit records, but got %#v", records)
+	}
+
+	if r := records[0]; r.Operation != "create" || r.Actor != "jinzhu" || r.Changes["name"].New != "audit" || r.Changes["name"].Old != nil {
+		t.Errorf("invalid create record %#v", r)
+	}
+
+	if r := records[1]; r.Operation != "update" || r.Actor != "jinzhu" || len(r.Changes) != 1 ||
+		r.Changes["balance"].Old != float64(10) || r.Changes["balance"].New != float64(20) {
+		t.Errorf("invalid update record %#v", r)
+	}
+
+	if r := records[2]; r.Operation != "delete" || r.Actor != "" || r.Changes["name"].Old != "audit" || r.Changes["name"].New != nil {
+		t.Errorf("invalid delete record %#v", r)
+	}
+
+	if _, err := audit.History(db, &AuditedAccount{}); !errors.Is(err, gorm.ErrPrimaryKeyRequired) {
+		t.Errorf("should returns primary key required, but got %v", err)
+	}
+}
@@ -0,0 +1,111 @@
+package tests_test
+
+import (
+	"context"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+type actorKey struct{}
+
+type ActorTrackedPost struct {
+	ID        uint
+	Title     string
+	CreatedBy string `gorm:"autoCreatedBy"`
+	UpdatedBy string `gorm:"autoUpdatedBy"`
+}
+
+func TestAutoActor(t *testing.T) {
+	db, _ := gorm.Open(DB.Dialector, &gorm.Config{
+		ActorResolver: func(ctx context.Context) (interface{}, bool) {
+			actor, ok := ctx.Value(actorKey{}).(string)
+			return actor, ok
+		},
+	})
+
+	db.Migrator().DropTable(&ActorTrackedPost{})
+	if err := db.AutoMigrate(&ActorTrackedPost{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	var (
+		jinzhu = db.WithContext(context.WithValue(context.Background(), actorKey{}, "jinzhu"))
+		admin  = db.WithContext(context.WithValue(context.Background(), actorKey{}, "admin"))
+		post   = ActorTrackedPost{Title: "actor"}
+		result ActorTrackedPost
+	)
+
+	assertActors := func(name, createdBy, updatedBy string) {
+		t.Helper()
+		db.First(&result, post.ID)
+		if result.CreatedBy != createdBy || result.UpdatedBy != updatedBy || post.CreatedBy != createdBy || post.UpdatedBy != updatedBy {
+			t.Errorf("%v: actors should be %v and %v, but got %#v, %#v", name, createdBy, updatedBy, result, post)
+		}
+	}
+
+	if err := jinzhu.Create(&post).Error; err != nil {
+		t.Fatalf("failed to create post, got error %v", err)
+	}
+	assertActors("create", "jinzhu", "jinzhu")
+
+	admin.Model(&post).Update("title", "actor updated")
+	assertActors("update", "jinzhu", "admin")
+
+	post.Title = "actor saved"
+	jinzhu.Save(&post)
+	assertActors("save", "jinzhu", "jinzhu")
+
+	db.Model(&post).Update("title", "no actor")
+	assertActors("update without actor", "jinzhu", "jinzhu")
+
+	posts := []ActorTrackedPost{{Title: "batch_1"}, {Title: "batch_2", CreatedBy: "importer"}}
+	admin.Create(&posts)
+	if posts[0].CreatedBy != "admin" || posts[1].CreatedBy != "importer" || posts[1].UpdatedBy != "admin" {
+		t.Errorf("actors should be assigned to zero fields of batch, but got %#v", posts)
+	}
+}
+
+type ActorTrackedComment struct {
+	ID        uint
+	Body      string
+	CreatedBy uint `gorm:"autoCreatedBy"`
+}
+
+func TestAutoActorTypeMismatch(t *testing.T) {
+	db, _ := gorm.Open(DB.Dialector, &gorm.Config{
+		ActorResolver: func(ctx context.Context) (interface{}, bool) {
+			return "jinzhu", true
+		},
+	})
+
+	db.Migrator().DropTable(&ActorTrackedComment{})
+	if err := db.AutoMigrate(&ActorTrackedComment{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	if err := db.Create(&ActorTrackedComment{Body: "mismatch"}).Error; err == nil {
+		t.Errorf("actor which can't be assigned to field should returns error")
+	}
+
+	if err := db.Create(&[]ActorTrackedComment{{Body: "mismatch_1"}, {Body: "mismatch_2"}}).Error; err == nil {
+		t.Errorf("actor which can't be assigned to fields of batch should returns error")
+	}
+
+	if err := db.CopyFrom(&[]ActorTrackedComment{{Body: "mismatch_3"}}).Error; err == nil {
+		t.Errorf("actor which can't be assigned to fields of copied records should returns error")
+	}
+
+	dialector := &bulkLoadingDialector{Dialector: DB.Dialector}
+	bulkDB, _ := gorm.Open(dialector, &gorm.Config{ActorResolver: func(ctx context.Context) (interface{}, bool) {
+		return "jinzhu", true
+	}})
+	if err := bulkDB.CopyFrom(&[]ActorTrackedComment{{Body: "mismatch_4"}}).Error; err == nil || len(dialector.rows) != 0 {
+		t.Errorf("actor which can't be assigned to fields of bulk loaded records should returns error, got %v, rows %v", err, dialector.rows)
+	}
+
+	var count int64
+	if db.Model(&ActorTrackedComment{}).Count(&count); count != 0 {
+		t.Errorf("records shouldn't be created with invalid actor, but got %v", count)
+	}
+}
@@ -3,6 +3,7 @@ package tests_test
 import (
 	"testing"
 
+	"gorm.io/gorm"
 	. "gorm.io/gorm/utils/tests"
 )
 
@@ -24,6 +25,26 @@ func BenchmarkFind(b *testing.B) {
 	}
 }
 
+func BenchmarkFindWithConditions(b *testing.B) {
+	var user = *GetUser("find", Config{})
+	DB.Create(&user)
+
+	for x := 0; x < b.N; x++ {
+		DB.Where("name = ?", user.Name).Where("age = ?", user.Age).Order("id").Find(&[]User{})
+	}
+}
+
+func BenchmarkFindWithPlanCache(b *testing.B) {
+	var user = *GetUser("find", Config{})
+	DB.Create(&user)
+
+	db := DB.Session(&gorm.Session{})
+	db.PlanCache = true
+	for x := 0; x < b.N; x++ {
+		db.Where("id = ?", user.ID).Order("id").Limit(1).Find(&User{})
+	}
+}
+
 func BenchmarkUpdate(b *testing.B) {
 	var user = *GetUser("find", Config{})
 	DB.Create(&user)
@@ -0,0 +1,138 @@
+package tests_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/gormtest"
+	. "gorm.io/gorm/utils/tests"
+)
+
+type capabilitiesDialector struct {
+	*gormtest.Stub
+	capabilities gorm.Capabilities
+}
+
+func (d capabilitiesDialector) Capabilities() gorm.Capabilities {
+	return d.capabilities
+}
+
+type namedDialector struct {
+	*gormtest.Stub
+	name string
+}
+
+func (d namedDialector) Name() string {
+	return d.name
+}
+
+func TestCapabilities(t *testing.T) {
+	if capabilities := DB.Capabilities(); DB.Dialector.Name() == "postgres" && (!capabilities.Returning || !capabilities.Lateral) {
+		t.Errorf("postgres should support RETURNING and LATERAL, got %+v", capabilities)
+	} else if !capabilities.SavePoint || !capabilities.OnConflict || capabilities.MaxPlaceholders <= 0 {
+		t.Errorf("should returns capabilities of known databases, got %+v", capabilities)
+	}
+
+	stub := gormtest.NewStub()
+	db, _ := gorm.Open(stub, &gorm.Config{SkipDefaultTransaction: true})
+	if capabilities := db.Capabilities(); !capabilities.SavePoint || !capabilities.NestedTransaction || !capabilities.OnConflict ||
+		!capabilities.ReleaseSavePoint || capabilities.TwoPhaseCommit || capabilities.MaxPlaceholders != 999 {
+		t.Errorf("should returns capabilities of unknown databases, got %+v", capabilities)
+	}
+
+	db.Save([]*User{{Name: "capabilities"}, {Name: "capabilities"}})
+	if statements := stub.Statements(); len(statements) != 1 || !strings.Contains(statements[0].SQL, "ON CONFLICT") {
+		t.Errorf("unknown databases should save records with ON CONFLICT as before, got %+v", statements)
+	}
+
+	stub.Reset()
+	db, _ = gorm.Open(capabilitiesDialector{Stub: stub}, &gorm.Config{SkipDefaultTransaction: true})
+	if capabilities := db.Capabilities(); capabilities.SavePoint || capabilities.OnConflict || capabilities.MaxPlaceholders != 999 {
+		t.Errorf("should returns capabilities of dialector, got %+v", capabilities)
+	}
+
+	db.Save([]*User{{Name: "capabilities"}, {Name: "capabilities"}})
+	for _, stmt := range stub.Statements() {
+		if strings.Contains(stmt.SQL, "ON CONFLICT") {
+			t.Errorf("should save records one by one without ON CONFLICT, got %v", stmt.SQL)
+		}
+	}
+
+	if statements := stub.Statements(); len(statements) != 2 {
+		t.Errorf("should save records one by one, got %+v", statements)
+	}
+
+	stub.Reset()
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		return tx.Transaction(func(tx *gorm.DB) error {
+			return tx.Create(&User{Name: "capabilities"}).Error
+		})
+	}); !errors.Is(err, gorm.ErrUnsupportedDriver) {
+		t.Errorf("nested transaction should be rejected without NestedTransaction, got %v", err)
+	}
+
+	for _, stmt := range stub.Statements() {
+		if strings.Contains(stmt.SQL, "SAVEPOINT") || strings.Contains(stmt.SQL, "INSERT") {
+			t.Errorf("nested transaction shouldn't run without NestedTransaction, got %v", stmt.SQL)
+		}
+	}
+
+	if err := db.Session(&gorm.Session{DisableNestedTransaction: true}).Transaction(func(tx *gorm.DB) error {
+		return tx.Transaction(func(tx *gorm.DB) error {
+			return tx.Create(&User{Name: "capabilities"}).Error
+		})
+	}); err != nil {
+		t.Errorf("nested transaction should run in the outer transaction with DisableNestedTransaction, got %v", err)
+	}
+
+	rowValues := clause.IN{Column: clause.Expr{SQL: "(name, age)"}, Values: []interface{}{[]interface{}{"capabilities", 1}, []interface{}{"capabilities", 2}}}
+	if sql := db.ToSQL(func(tx *gorm.DB) *gorm.DB { return tx.Where(rowValues).Find(&[]User{}) }); !strings.Contains(sql, "IN (VALUES") {
+		t.Errorf("row values should be queried with subquery without RowValues, got %v", sql)
+	}
+
+	columnValues := clause.IN{Column: []clause.Column{{Name: "name"}, {Name: "age"}}, Values: []interface{}{[]interface{}{"capabilities", 1}, []interface{}{"capabilities", 2}}}
+	if sql := db.ToSQL(func(tx *gorm.DB) *gorm.DB { return tx.Where(columnValues).Find(&[]User{}) }); !strings.Contains(sql, "((`name` = 'capabilities' AND `age` = 1) OR (`name` = 'capabilities' AND `age` = 2))") {
+		t.Errorf("row values should be expanded to ORs without RowValues and ValuesSubquery, got %v", sql)
+	}
+
+	if sql := db.ToSQL(func(tx *gorm.DB) *gorm.DB { return tx.Not(columnValues).Find(&[]User{}) }); !strings.Contains(sql, "NOT ((`name` = 'capabilities' AND `age` = 1) OR") {
+		t.Errorf("negated row values should be expanded to ORs without RowValues and ValuesSubquery, got %v", sql)
+	}
+
+	valuesDB, _ := gorm.Open(capabilitiesDialector{Stub: stub, capabilities: gorm.Capabilities{ValuesSubquery: true}}, &gorm.Config{SkipDefaultTransaction: true})
+	if sql := valuesDB.ToSQL(func(tx *gorm.DB) *gorm.DB { return tx.Where(columnValues).Find(&[]User{}) }); !strings.Contains(sql, "IN (VALUES") {
+		t.Errorf("row values should be queried with subquery with ValuesSubquery, got %v", sql)
+	}
+
+	stub.Reset()
+	syntaxDB, _ := gorm.Open(capabilitiesDialector{Stub: stub, capabilities: gorm.Capabilities{DateAdd: gorm.SyntaxMySQL, AdvisoryLock: gorm.SyntaxPostgres}}, &gorm.Config{SkipDefaultTransaction: true})
+	if sql := syntaxDB.ToSQL(func(tx *gorm.DB) *gorm.DB {
+		return tx.Where("? > ?", gorm.AddInterval{Time: "created_at", Interval: time.Hour}, 1).Find(&[]User{})
+	}); !strings.Contains(sql, "DATE_ADD(") {
+		t.Errorf("intervals should be added with syntax of DateAdd, got %v", sql)
+	}
+
+	if unlock, err := syntaxDB.Lock("capabilities"); err != nil {
+		t.Errorf("failed to lock with advisory lock of AdvisoryLock, got %v", err)
+	} else {
+		unlock()
+	}
+
+	if statements := stub.Statements(); len(statements) != 2 || !strings.Contains(statements[0].SQL, "pg_advisory_lock") {
+		t.Errorf("locks should be acquired with syntax of AdvisoryLock, got %+v", statements)
+	}
+
+	sqlserverDB, _ := gorm.Open(namedDialector{Stub: stub, name: "sqlserver"}, &gorm.Config{SkipDefaultTransaction: true})
+	if capabilities := sqlserverDB.Capabilities(); capabilities.Returning || capabilities.RowValues || capabilities.ValuesSubquery || !capabilities.OnConflict {
+		t.Errorf("sqlserver has neither RETURNING nor row values, got %+v", capabilities)
+	}
+
+	if err := gorm.NewCoordinator(db).Transaction(context.Background(), func([]*gorm.DB) error { return nil }); !errors.Is(err, gorm.ErrUnsupportedDriver) {
+		t.Errorf("two-phase commit should be rejected without TwoPhaseCommit, got %v", err)
+	}
+}
@@ -0,0 +1,88 @@
+package tests_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/cdc"
+	. "gorm.io/gorm/utils/tests"
+)
+
+func TestChangeDataCapture(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		changes []cdc.Change
+		db, _   = gorm.Open(DB.Dialector, &gorm.Config{})
+		user    = *GetUser("cdc", Config{})
+	)
+
+	capture := cdc.New().Subscribe(func(ctx context.Context, change cdc.Change) {
+		mu.Lock()
+		changes = append(changes, change)
+		mu.Unlock()
+	})
+
+	if err := db.Use(capture); err != nil {
+		t.Fatalf("failed to use plugin, got error %v", err)
+	}
+
+	assertChanges := func(name string, operations ...cdc.Operation) []cdc.Change {
+		t.Helper()
+		mu.Lock()
+		defer mu.Unlock()
+
+		result := changes
+		changes = nil
+		if len(result) != len(operations) {
+			t.Fatalf("%v: should capture %v changes, but got %#v", name, len(operations), result)
+		}
+
+		for idx, operation := range operations {
+			if result[idx].Operation != operation || result[idx].Table != "users" {
+				t.Errorf("%v: invalid change %#v", name, result[idx])
+			}
+		}
+		return result
+	}
+
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("failed to create user, got error %v", err)
+	}
+
+	if change := assertChanges("create", cdc.Create)[0]; change.Old != nil || change.New["name"] != "cdc" || change.PrimaryKey["id"] == nil {
+		t.Errorf("invalid created change %#v", change)
+	}
+
+	if err := db.Model(&user).Update("name", "cdc_updated").Error; err != nil {
+		t.Fatalf("failed to update user, got error %v", err)
+	}
+
+	if change := assertChanges("update", cdc.Update)[0]; change.Old["name"] != "cdc" || change.New["name"] != "cdc_updated" {
+		t.Errorf("invalid updated change %#v", change)
+	}
+
+	db.Transaction(func(tx *gorm.DB) error {
+		tx.Model(&User{}).Where("id = ?", user.ID).Update("age", 30)
+		assertChanges("update in transaction")
+		return errors.New("rollback")
+	})
+	assertChanges("rolled back")
+
+	db.Transaction(func(tx *gorm.DB) error {
+		tx.Model(&User{}).Where("id = ?", user.ID).Update("age", 40)
+		assertChanges("update in transaction")
+		return nil
+	})
+	assertChanges("committed", cdc.Update)
+
+	if err := db.Delete(&user).Error; err != nil {
+		t.Fatalf("failed to delete user, got error %v", err)
+	}
+
+	if change := assertChanges("delete", cdc.Delete)[0]; change.New != nil || change.Old["name"] != "cdc_updated" {
+		t.Errorf("invalid deleted change %#v", change)
+	}
+}
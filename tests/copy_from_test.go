@@ -0,0 +1,107 @@
+package tests_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+	. "gorm.io/gorm/utils/tests"
+)
+
+type bulkLoadingDialector struct {
+	gorm.Dialector
+	table   string
+	columns []string
+	rows    [][]interface{}
+}
+
+func (d *bulkLoadingDialector) BulkLoad(ctx context.Context, conn gorm.ConnPool, table string, columns []string, rows [][]interface{}) (int64, error) {
+	d.table, d.columns, d.rows = table, columns, rows
+	return int64(len(rows)), nil
+}
+
+func TestCopyFrom(t *testing.T) {
+	pets := make([]Pet, 5000)
+	for i := range pets {
+		pets[i].Name = "copy_from_" + strconv.Itoa(i)
+	}
+
+	result := DB.CopyFrom(&pets)
+	if result.Error != nil || result.RowsAffected != 5000 {
+		t.Fatalf("failed to copy pets, got %v, %v", result.Error, result.RowsAffected)
+	}
+
+	var count int64
+	DB.Model(&Pet{}).Where("name LIKE ?", "copy_from_%").Count(&count)
+	if count != 5000 || pets[4999].ID == 0 {
+		t.Errorf("all pets should be created, but got %v", count)
+	}
+
+	if err := DB.CopyFrom(Pet{}).Error; !errors.Is(err, gorm.ErrInvalidData) {
+		t.Errorf("should returns invalid data for non slice, but got %v", err)
+	}
+
+	dialector := &bulkLoadingDialector{Dialector: DB.Dialector}
+	db, _ := gorm.Open(dialector, &gorm.Config{})
+
+	languages := []Language{{Code: "copy_1", Name: "one"}, {Code: "copy_2", Name: "two"}}
+	if result := db.CopyFrom(&languages); result.Error != nil || result.RowsAffected != 2 {
+		t.Fatalf("failed to bulk load languages, got %v, %v", result.Error, result.RowsAffected)
+	}
+
+	if dialector.table != "languages" || !reflect.DeepEqual(dialector.columns, []string{"code", "name"}) ||
+		!reflect.DeepEqual(dialector.rows, [][]interface{}{{"copy_1", "one"}, {"copy_2", "two"}}) {
+		t.Errorf("rows should be loaded with bulk loader, but got %v %v %v", dialector.table, dialector.columns, dialector.rows)
+	}
+
+	pets = []Pet{{Name: "bulk_load"}}
+	db.CopyFrom(&pets)
+	if dialector.columns[0] == "id" || pets[0].CreatedAt.IsZero() {
+		t.Errorf("zero primary key should be omitted and created time should be assigned, but got %v, %v", dialector.columns, pets[0].CreatedAt)
+	}
+}
+
+type BulkLoadedCustomer struct {
+	ID        uint
+	SSN       string             `gorm:"serializer:copy_encrypted"`
+	Profile   *SerializerProfile `gorm:"serializer:json"`
+	CreatedBy string             `gorm:"autoCreatedBy"`
+}
+
+func TestCopyFromWithBulkLoaderSerializesValues(t *testing.T) {
+	schema.RegisterSerializer("copy_encrypted", schema.EncryptedSerializer{KeyProvider: &testKeyProvider{
+		current: "k1", keys: map[string][]byte{"k1": []byte("0123456789abcdef")},
+	}})
+
+	dialector := &bulkLoadingDialector{Dialector: DB.Dialector}
+	db, _ := gorm.Open(dialector, &gorm.Config{ActorResolver: func(ctx context.Context) (interface{}, bool) {
+		return "importer", true
+	}})
+
+	customers := []BulkLoadedCustomer{{SSN: "123-45-6789", Profile: &SerializerProfile{Phone: "555", Country: "HU"}}}
+	if err := db.CopyFrom(&customers).Error; err != nil {
+		t.Fatalf("failed to bulk load customers, got error %v", err)
+	}
+
+	if !reflect.DeepEqual(dialector.columns, []string{"ssn", "profile", "created_by"}) || len(dialector.rows) != 1 {
+		t.Fatalf("invalid bulk loaded columns, got %v, rows %v", dialector.columns, dialector.rows)
+	}
+
+	row := dialector.rows[0]
+	if ssn, ok := row[0].(string); !ok || !strings.HasPrefix(ssn, "k1:") {
+		t.Errorf("ssn should be encrypted with bulk loader, but got %#v", row[0])
+	}
+
+	if profile, ok := row[1].(string); !ok || profile != `{"Phone":"555","Country":"HU"}` {
+		t.Errorf("profile should be serialized with bulk loader, but got %#v", row[1])
+	}
+
+	if row[2] != "importer" || customers[0].CreatedBy != "importer" {
+		t.Errorf("created by should be assigned with actor, but got %#v, %v", row[2], customers[0].CreatedBy)
+	}
+}
@@ -122,3 +122,30 @@ func TestCount(t *testing.T) {
 
 	AssertEqual(t, users, expects)
 }
+
+func TestEstimatedCount(t *testing.T) {
+	users := []User{*GetUser("estimated_count", Config{}), *GetUser("estimated_count", Config{}), *GetUser("estimated_count", Config{})}
+	DB.Create(&users)
+
+	var count, exactCount int64
+	if err := DB.Model(&User{}).Where("name = ?", "estimated_count").EstimatedCount(&count).Error; err != nil || count != 3 {
+		t.Errorf("estimated count below threshold should use exact count, got %v, error %v", count, err)
+	}
+
+	DB.Model(&User{}).Count(&exactCount)
+	if err := DB.Model(&User{}).EstimatedCount(&count).Error; err != nil || count != exactCount {
+		t.Errorf("estimated count below threshold should use exact count, expects %v, got %v, error %v", exactCount, count, err)
+	}
+
+	tx := DB.Session(&gorm.Session{})
+	tx.EstimatedCountThreshold = 1
+	if err := tx.Model(&User{}).Where("name = ?", "estimated_count").EstimatedCount(&count).Error; err != nil || count <= 0 {
+		t.Errorf("estimated count should returns estimated rows, got %v, error %v", count, err)
+	}
+
+	var results []User
+	page, err := DB.Where("name = ?", "estimated_count").FindPage(&results, gorm.Page{Number: 1, Size: 2, Estimated: true})
+	if err != nil || page.Total != 3 || page.Pages != 2 || len(results) != 2 {
+		t.Errorf("failed to find page with estimated count, got page %+v, error %v", page, err)
+	}
+}
@@ -0,0 +1,73 @@
+package tests_test
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type DefaultOrderArticle struct {
+	ID        uint
+	Title     string
+	Rank      int       `gorm:"defaultOrder"`
+	CreatedAt time.Time `gorm:"defaultOrder:desc"`
+}
+
+type DefaultOrderEvent struct {
+	ID   uint
+	Name string
+	At   time.Time
+}
+
+func (DefaultOrderEvent) DefaultOrder() string {
+	return "at DESC"
+}
+
+func TestDefaultOrder(t *testing.T) {
+	DB.Migrator().DropTable(&DefaultOrderArticle{}, &DefaultOrderEvent{})
+	if err := DB.AutoMigrate(&DefaultOrderArticle{}, &DefaultOrderEvent{}); err != nil {
+		t.Fatalf("failed to migrate, got %v", err)
+	}
+
+	dryRun := DB.Session(&gorm.Session{DryRun: true})
+	for name, tc := range map[string]struct {
+		tx     *gorm.DB
+		expect string
+	}{
+		"find":      {dryRun.Find(&[]DefaultOrderArticle{}), `ORDER BY .default_order_articles.\..rank.,.default_order_articles.\..created_at. DESC$`},
+		"first":     {dryRun.First(&DefaultOrderArticle{}), `ORDER BY .default_order_articles.\..rank.,.default_order_articles.\..created_at. DESC,.default_order_articles.\..id. LIMIT`},
+		"last":      {dryRun.Last(&DefaultOrderArticle{}), `ORDER BY .default_order_articles.\..id. DESC LIMIT`},
+		"order":     {dryRun.Order("title").Find(&[]DefaultOrderArticle{}), `ORDER BY title$`},
+		"interface": {dryRun.Find(&[]DefaultOrderEvent{}), `ORDER BY at DESC$`},
+		"count":     {dryRun.Model(&DefaultOrderEvent{}).Count(new(int64)), `FROM .default_order_events.$`},
+		"pluck":     {dryRun.Model(&DefaultOrderEvent{}).Pluck("name", &[]string{}), `FROM .default_order_events.$`},
+		"group":     {dryRun.Model(&DefaultOrderEvent{}).Select("name").Group("name").Find(&[]DefaultOrderEvent{}), `GROUP BY .name.$`},
+		"sub query": {dryRun.Where("id IN (?)", DB.Model(&DefaultOrderEvent{}).Select("id")).Find(&[]DefaultOrderEvent{}), `IN \(SELECT .id. FROM .default_order_events.\) ORDER BY at DESC$`},
+		"distinct":  {dryRun.Distinct("name").Find(&[]DefaultOrderEvent{}), `FROM .default_order_events.$`},
+	} {
+		if sql := tc.tx.Statement.SQL.String(); !regexp.MustCompile(tc.expect).MatchString(sql) {
+			t.Errorf("%v: SQL should match %v, got %v", name, tc.expect, sql)
+		}
+	}
+
+	now := time.Now().Round(time.Second)
+	events := []DefaultOrderEvent{{Name: "first", At: now.Add(-time.Hour)}, {Name: "latest", At: now}, {Name: "second", At: now.Add(-time.Minute)}}
+	DB.Create(&events)
+
+	var results []DefaultOrderEvent
+	if err := DB.Find(&results).Error; err != nil || len(results) != 3 || results[0].Name != "latest" || results[2].Name != "first" {
+		t.Errorf("events should be ordered by default order, got %v, %v", err, results)
+	}
+
+	var result DefaultOrderEvent
+	if err := DB.First(&result).Error; err != nil || result.Name != "latest" {
+		t.Errorf("first event should be the latest, got %v, %v", err, result)
+	}
+
+	var ordered DefaultOrderEvent
+	if err := DB.Order("name").First(&ordered).Error; err != nil || ordered.Name != "first" {
+		t.Errorf("order of caller should skip default order, got %v, %v", err, ordered)
+	}
+}
@@ -37,3 +37,32 @@ func TestDefaultValue(t *testing.T) {
 		t.Fatalf("Failed to find created data with default data, got %+v", result)
 	}
 }
+
+func TestDefaultValueWithReturning(t *testing.T) {
+	type Harumph2 struct {
+		gorm.Model
+		Email string `gorm:"not null"`
+		Code  string `gorm:"default:(lower('ABC'));returning"`
+		Seq   int    `gorm:"default:(abs(-42));returning"`
+	}
+
+	DB.Migrator().DropTable(&Harumph2{})
+
+	if err := DB.AutoMigrate(&Harumph2{}); err != nil {
+		t.Fatalf("Failed to migrate with default value, got error: %v", err)
+	}
+
+	var harumph = Harumph2{Email: "hello@gorm.io"}
+	if err := DB.Create(&harumph).Error; err != nil {
+		t.Fatalf("Failed to create data with default value, got error: %v", err)
+	} else if harumph.Code != "abc" || harumph.Seq != 42 {
+		t.Fatalf("Failed to fetch database default values after create, got: %+v", harumph)
+	}
+
+	var harumphs = []Harumph2{{Email: "hello1@gorm.io"}, {Email: "hello2@gorm.io"}}
+	if err := DB.Create(&harumphs).Error; err != nil {
+		t.Fatalf("Failed to create data with default value, got error: %v", err)
+	} else if harumphs[0].Code != "abc" || harumphs[0].Seq != 42 || harumphs[1].Code != "abc" || harumphs[1].Seq != 42 {
+		t.Fatalf("Failed to fetch database default values after batch create, got: %+v", harumphs)
+	}
+}
@@ -0,0 +1,70 @@
+package tests_test
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type DumpCompany struct {
+	ID   uint
+	Name string `gorm:"size:100;not null;uniqueIndex"`
+}
+
+type DumpEmployee struct {
+	ID            uint
+	Name          string `gorm:"index:idx_dump_name_age,priority:1"`
+	Age           int    `gorm:"index:idx_dump_name_age,priority:2;default:18"`
+	Email         string `gorm:"unique;not null"`
+	Birthday      *time.Time
+	DumpCompanyID *uint
+	DumpCompany   DumpCompany
+	DeletedAt     gorm.DeletedAt
+}
+
+func TestDumpModels(t *testing.T) {
+	DB.Migrator().DropTable(&DumpEmployee{}, &DumpCompany{})
+	if err := DB.AutoMigrate(&DumpCompany{}, &DumpEmployee{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	if tables, err := DB.Migrator().(gorm.TableInspectorInterface).GetTables(); err != nil || !strings.Contains(strings.Join(tables, ","), "dump_employees") {
+		t.Errorf("failed to get tables, got %v, error %v", tables, err)
+	}
+
+	var buf bytes.Buffer
+	if err := DB.Migrator().(gorm.ModelDumperInterface).DumpModels(&buf, gorm.DumpOptions{Package: "dump", Tables: []string{"dump_companies", "dump_employees"}}); err != nil {
+		t.Fatalf("failed to dump models, got error %v", err)
+	}
+
+	source := buf.String()
+	if _, err := parser.ParseFile(token.NewFileSet(), "models.go", source, 0); err != nil {
+		t.Fatalf("generated source should be valid, got error %v\n%v", err, source)
+	}
+
+	for _, expected := range []string{
+		"package dump",
+		"type DumpCompany struct",
+		"type DumpEmployee struct",
+		"ID ",
+		"primaryKey",
+		"Birthday      *time.Time",
+		"DeletedAt     gorm.DeletedAt",
+		"index:idx_dump_name_age,priority:1",
+		"index:idx_dump_name_age,priority:2",
+		"DumpCompany   *DumpCompany `gorm:\"foreignKey:DumpCompanyID;references:ID\"`",
+	} {
+		if !strings.Contains(source, expected) {
+			t.Errorf("generated source should contains %q, got\n%v", expected, source)
+		}
+	}
+
+	if strings.Contains(source, "TableName()") {
+		t.Errorf("should not generate TableName method for tables matching naming strategy, got\n%v", source)
+	}
+}
@@ -0,0 +1,84 @@
+package tests_test
+
+import (
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ArticleState string
+
+func (ArticleState) EnumValues() []string {
+	return []string{"draft", "published", "archived"}
+}
+
+type EnumArticle struct {
+	ID         uint
+	Title      string
+	State      ArticleState `gorm:"default:draft"`
+	Visibility string       `gorm:"type:enum;values:public,private"`
+}
+
+func TestEnumField(t *testing.T) {
+	DB.Migrator().DropTable(&EnumArticle{})
+	if err := DB.AutoMigrate(&EnumArticle{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	article := EnumArticle{Title: "enum", Visibility: "public"}
+	if err := DB.Create(&article).Error; err != nil {
+		t.Fatalf("failed to create, got error %v", err)
+	}
+
+	var result EnumArticle
+	if err := DB.First(&result, article.ID).Error; err != nil || result.State != "draft" {
+		t.Errorf("enum default value should be saved, got %v, error %v", result.State, err)
+	}
+
+	if err := DB.Create(&EnumArticle{Title: "enum", State: "deleted", Visibility: "public"}).Error; !errors.Is(err, gorm.ErrInvalidEnumValue) {
+		t.Errorf("should returns ErrInvalidEnumValue when creating with invalid value, got %v", err)
+	}
+
+	if err := DB.Model(&EnumArticle{}).Create(&[]map[string]interface{}{{"Title": "enum", "State": "draft", "Visibility": "secret"}}).Error; !errors.Is(err, gorm.ErrInvalidEnumValue) {
+		t.Errorf("should returns ErrInvalidEnumValue when creating from map with invalid value, got %v", err)
+	}
+
+	if err := DB.Model(&article).Update("State", ArticleState("published")).Error; err != nil {
+		t.Errorf("failed to update with valid value, got error %v", err)
+	}
+
+	if err := DB.Model(&article).Updates(map[string]interface{}{"visibility": "secret"}).Error; !errors.Is(err, gorm.ErrInvalidEnumValue) {
+		t.Errorf("should returns ErrInvalidEnumValue when updating with invalid value, got %v", err)
+	}
+
+	if err := DB.Exec("UPDATE enum_articles SET visibility = ? WHERE id = ?", "secret", article.ID).Error; err == nil {
+		t.Errorf("check constraint should reject invalid value")
+	}
+
+	DB.First(&result, article.ID)
+	if result.State != "published" || result.Visibility != "public" {
+		t.Errorf("invalid values should not be saved, got %v, %v", result.State, result.Visibility)
+	}
+}
+
+type EnumReservedColumn struct {
+	ID    uint
+	Order string `gorm:"type:enum;values:asc,desc"`
+}
+
+func TestEnumFieldWithReservedColumn(t *testing.T) {
+	DB.Migrator().DropTable(&EnumReservedColumn{})
+	if err := DB.AutoMigrate(&EnumReservedColumn{}); err != nil {
+		t.Fatalf("failed to migrate enum field of reserved column, got error %v", err)
+	}
+
+	if err := DB.Create(&EnumReservedColumn{Order: "asc"}).Error; err != nil {
+		t.Errorf("failed to create, got error %v", err)
+	}
+
+	if err := DB.Exec("INSERT INTO enum_reserved_columns (?) VALUES (?)", clause.Column{Name: "order"}, "random").Error; err == nil {
+		t.Errorf("check constraint should reject invalid value")
+	}
+}
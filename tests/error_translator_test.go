@@ -0,0 +1,68 @@
+package tests_test
+
+import (
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+type TranslatedParent struct {
+	ID uint
+}
+
+type TranslatedChild struct {
+	ID       uint
+	Name     string `gorm:"unique;not null"`
+	Age      int    `gorm:"check:chk_translated_children_age,age > 0"`
+	ParentID uint
+	Parent   TranslatedParent
+}
+
+func TestTranslateError(t *testing.T) {
+	DB.Migrator().DropTable(&TranslatedChild{}, &TranslatedParent{})
+	if err := DB.AutoMigrate(&TranslatedParent{}, &TranslatedChild{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	config := *DB.Config
+	config.TranslateError = true
+	db := DB.Session(&gorm.Session{NewDB: true})
+	db.Config = &config
+
+	parent := TranslatedParent{}
+	db.Create(&parent)
+	if err := db.Create(&TranslatedChild{Name: "jinzhu", Age: 10, ParentID: parent.ID}).Error; err != nil {
+		t.Fatalf("failed to create, got error %v", err)
+	}
+
+	var translated *gorm.TranslatedError
+	err := db.Create(&TranslatedChild{Name: "jinzhu", Age: 10, ParentID: parent.ID}).Error
+	if !errors.Is(err, gorm.ErrDuplicatedKey) || !errors.As(err, &translated) || translated.Unwrap() == nil {
+		t.Errorf("should be duplicated key error, got %v", err)
+	} else if DB.Dialector.Name() == "sqlite" && (translated.Table != "translated_children" || translated.Column != "name") {
+		t.Errorf("invalid table or column, got %#v", translated)
+	}
+
+	if err := db.Exec("INSERT INTO translated_children (age, parent_id) VALUES (?, ?)", 10, parent.ID).Error; !errors.Is(err, gorm.ErrNotNullViolated) {
+		t.Errorf("should be not-null error, got %v", err)
+	}
+
+	err = db.Create(&TranslatedChild{Name: "jinzhu2", Age: -1, ParentID: parent.ID}).Error
+	if !errors.Is(err, gorm.ErrCheckConstraintViolated) || !errors.As(err, &translated) {
+		t.Errorf("should be check constraint error, got %v", err)
+	} else if DB.Dialector.Name() != "sqlite" && translated.Constraint != "chk_translated_children_age" {
+		t.Errorf("invalid constraint, got %#v", translated)
+	}
+
+	// foreign keys are not enforced by sqlite by default
+	if DB.Dialector.Name() != "sqlite" {
+		if err := db.Create(&TranslatedChild{Name: "jinzhu3", Age: 10, ParentID: parent.ID + 100}).Error; !errors.Is(err, gorm.ErrForeignKeyViolated) {
+			t.Errorf("should be foreign key error, got %v", err)
+		}
+	}
+
+	if err := DB.Create(&TranslatedChild{Name: "jinzhu", Age: 10, ParentID: parent.ID}).Error; err == nil || errors.Is(err, gorm.ErrDuplicatedKey) {
+		t.Errorf("errors should not be translated without TranslateError, got %v", err)
+	}
+}
@@ -0,0 +1,178 @@
+package tests_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+	. "gorm.io/gorm/utils/tests"
+)
+
+func TestWriteCSV(t *testing.T) {
+	users := []User{*GetUser("write_csv_1", Config{}), *GetUser("write_csv_2", Config{})}
+	DB.Create(&users)
+
+	var buf bytes.Buffer
+	result := DB.Model(&User{}).Select("id", "name", "age", "birthday", "company_id").Where("name LIKE ?", "write_csv_%").Order("id").WriteCSV(&buf)
+	if result.Error != nil || result.RowsAffected != 2 {
+		t.Fatalf("failed to write csv, got rows %v, error %v", result.RowsAffected, result.Error)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("failed to read csv, got error %v", err)
+	}
+
+	if len(records) != 3 || strings.Join(records[0], ",") != "id,name,age,birthday,company_id" {
+		t.Fatalf("invalid csv records, got %v", records)
+	}
+
+	if records[1][1] != users[0].Name || records[2][1] != users[1].Name || records[1][2] != "18" || records[1][4] != "" {
+		t.Errorf("invalid csv records, got %v", records)
+	}
+
+	if records[1][3] == "" || strings.HasPrefix(records[1][3], "0x") {
+		t.Errorf("invalid csv time value, got %v", records[1][3])
+	}
+}
+
+func TestWriteJSONLines(t *testing.T) {
+	users := []User{*GetUser("write_json_lines_1", Config{}), *GetUser("write_json_lines_2", Config{})}
+	DB.Create(&users)
+
+	var buf bytes.Buffer
+	result := DB.Model(&User{}).Select("id", "name", "age", "company_id").Where("name LIKE ?", "write_json_lines_%").Order("id").WriteJSONLines(&buf)
+	if result.Error != nil || result.RowsAffected != 2 {
+		t.Fatalf("failed to write json lines, got rows %v, error %v", result.RowsAffected, result.Error)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("should write two lines, but got %v", buf.String())
+	}
+
+	for idx, line := range lines {
+		var value map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &value); err != nil {
+			t.Fatalf("failed to unmarshal json line %v, got error %v", line, err)
+		}
+
+		if value["name"] != users[idx].Name || value["age"] != float64(18) || value["id"] != float64(users[idx].ID) || value["company_id"] != nil {
+			t.Errorf("invalid json line, got %v", line)
+		}
+	}
+
+	if !strings.HasPrefix(lines[0], `{"id":`) {
+		t.Errorf("json line should keep columns order, got %v", lines[0])
+	}
+}
+
+type ExportedOrder struct {
+	ID        uint
+	Name      string
+	Amount    float64
+	Note      *string
+	CreatedAt time.Time
+}
+
+func TestExportImport(t *testing.T) {
+	DB.Migrator().DropTable(&ExportedOrder{})
+	DB.AutoMigrate(&ExportedOrder{})
+
+	note := "note"
+	orders := make([]ExportedOrder, 2500)
+	for i := range orders {
+		orders[i] = ExportedOrder{Name: "order_" + strconv.Itoa(i), Amount: float64(i) + 0.5}
+	}
+	orders[1].Note = &note
+	DB.CopyFrom(&orders)
+
+	for _, format := range []gorm.ExportFormat{gorm.FormatCSV, gorm.FormatJSONLines} {
+		var buf bytes.Buffer
+		if result := DB.Export(&ExportedOrder{}, &buf, format); result.Error != nil || result.RowsAffected != 2500 {
+			t.Fatalf("failed to export %v, got rows %v, error %v", format, result.RowsAffected, result.Error)
+		}
+
+		if lines := strings.Count(buf.String(), "\n"); lines != 2501 {
+			t.Errorf("%v export should have header and 2500 rows, but got %v lines", format, lines)
+		}
+
+		if format == gorm.FormatJSONLines && !strings.HasPrefix(buf.String(), `{"table":"exported_orders","columns":["id",`) {
+			t.Errorf("json lines should start with schema header, but got %v", strings.SplitN(buf.String(), "\n", 2)[0])
+		}
+
+		DB.Where("1 = 1").Delete(&ExportedOrder{})
+		if result := DB.Import(&ExportedOrder{}, &buf, format); result.Error != nil || result.RowsAffected != 2500 {
+			t.Fatalf("failed to import %v, got rows %v, error %v", format, result.RowsAffected, result.Error)
+		}
+
+		var imported []ExportedOrder
+		DB.Order("id").Find(&imported)
+		if len(imported) != 2500 {
+			t.Fatalf("%v import should have 2500 rows, but got %v", format, len(imported))
+		}
+
+		for _, idx := range []int{0, 1, 2499} {
+			expected, got := orders[idx], imported[idx]
+			if got.ID != expected.ID || got.Name != expected.Name || got.Amount != expected.Amount ||
+				(got.Note == nil) != (expected.Note == nil) || !got.CreatedAt.Equal(expected.CreatedAt) {
+				t.Errorf("%v imported order should be %#v, but got %#v", format, expected, got)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if result := DB.Where("amount < ?", 10).Export(&ExportedOrder{}, &buf, gorm.FormatCSV); result.RowsAffected != 10 {
+		t.Errorf("export should apply conditions, but got %v rows", result.RowsAffected)
+	}
+}
+
+type ExportedCustomer struct {
+	ID      uint
+	Name    string
+	SSN     string             `gorm:"serializer:export_encrypted"`
+	Profile *SerializerProfile `gorm:"serializer:json"`
+}
+
+func TestExportImportWithSerializer(t *testing.T) {
+	schema.RegisterSerializer("export_encrypted", schema.EncryptedSerializer{KeyProvider: &testKeyProvider{
+		current: "k1", keys: map[string][]byte{"k1": []byte("0123456789abcdef")},
+	}})
+
+	DB.Migrator().DropTable(&ExportedCustomer{})
+	DB.AutoMigrate(&ExportedCustomer{})
+
+	customer := ExportedCustomer{Name: "exported", SSN: "123-45-6789", Profile: &SerializerProfile{Phone: "555", Country: "HU"}}
+	DB.Create(&customer)
+
+	for _, format := range []gorm.ExportFormat{gorm.FormatCSV, gorm.FormatJSONLines} {
+		var buf bytes.Buffer
+		if result := DB.Export(&ExportedCustomer{}, &buf, format); result.Error != nil || result.RowsAffected != 1 {
+			t.Fatalf("failed to export %v, got rows %v, error %v", format, result.RowsAffected, result.Error)
+		}
+
+		if strings.Contains(buf.String(), customer.SSN) {
+			t.Errorf("%v export should contain encrypted ssn, but got %v", format, buf.String())
+		}
+
+		DB.Where("1 = 1").Delete(&ExportedCustomer{})
+		if result := DB.Import(&ExportedCustomer{}, &buf, format); result.Error != nil || result.RowsAffected != 1 {
+			t.Fatalf("failed to import %v, got rows %v, error %v", format, result.RowsAffected, result.Error)
+		}
+
+		var imported ExportedCustomer
+		if err := DB.First(&imported, customer.ID).Error; err != nil {
+			t.Fatalf("failed to find imported customer, got error %v", err)
+		}
+
+		if imported.SSN != customer.SSN || imported.Profile == nil || *imported.Profile != *customer.Profile {
+			t.Errorf("%v imported customer should be %+v, but got %+v", format, customer, imported)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package tests_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm/field"
+	. "gorm.io/gorm/utils/tests"
+)
+
+func TestGenerateFields(t *testing.T) {
+	var buf bytes.Buffer
+	if err := DB.GenerateFields(&buf, "query", &User{}, &Pet{}); err != nil {
+		t.Fatalf("failed to generate fields, got error %v", err)
+	}
+
+	source := buf.String()
+	for _, expects := range []string{
+		"package query",
+		"var User = struct {",
+		"ID        field.Number[uint]",
+		"Name      field.String",
+		`Name:      field.NewString("users", "name"),`,
+		`Birthday:  field.NewTime("users", "birthday"),`,
+		`Active:    field.NewBool("users", "active"),`,
+		"var Pet = struct {",
+	} {
+		if !strings.Contains(source, expects) {
+			t.Errorf("generated source should contains %v, but got %v", expects, source)
+		}
+	}
+
+	user := *GetUser("generate_fields", Config{})
+	DB.Create(&user)
+
+	var (
+		result User
+		name   = field.NewString("users", "name")
+		age    = field.NewNumber[uint]("users", "age")
+	)
+
+	if err := DB.Where(name.Eq(user.Name)).Where(age.Gte(user.Age)).First(&result).Error; err != nil {
+		t.Fatalf("failed to query with typed fields, got error %v", err)
+	}
+	CheckUser(t, result, user)
+}
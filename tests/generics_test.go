@@ -0,0 +1,109 @@
+package tests_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+	. "gorm.io/gorm/utils/tests"
+)
+
+func TestGenerics(t *testing.T) {
+	ctx := context.Background()
+	users := []User{*GetUser("generics_1", Config{}), *GetUser("generics_2", Config{}), *GetUser("generics_3", Config{})}
+
+	if err := gorm.G[User](DB).CreateInBatches(ctx, &users, 2); err != nil {
+		t.Fatalf("failed to create users, got error %v", err)
+	}
+
+	user := *GetUser("generics_4", Config{Pets: 1})
+	if err := gorm.G[User](DB).Create(ctx, &user); err != nil {
+		t.Fatalf("failed to create user, got error %v", err)
+	}
+
+	query := gorm.G[User](DB).Where("name LIKE ?", "generics_%")
+	results, err := query.Order("id").Find(ctx)
+	if err != nil || len(results) != 4 {
+		t.Fatalf("failed to find users, got %v, error %v", len(results), err)
+	}
+	CheckUser(t, results[0], users[0])
+
+	if count, err := query.Count(ctx); err != nil || count != 4 {
+		t.Errorf("failed to count users, got %v, error %v", count, err)
+	}
+
+	result, err := gorm.G[User](DB).Preload("Pets").Where("name = ?", user.Name).First(ctx)
+	if err != nil {
+		t.Fatalf("failed to find user, got error %v", err)
+	}
+	CheckUser(t, result, user)
+
+	if last, err := query.Last(ctx); err != nil || last.ID != user.ID {
+		t.Errorf("failed to find last user, got %v, error %v", last.ID, err)
+	}
+
+	if rows, err := gorm.G[User](DB).Where("id = ?", users[0].ID).Update(ctx, "age", 100); err != nil || rows != 1 {
+		t.Errorf("failed to update user, got rows affected %v, error %v", rows, err)
+	}
+
+	if rows, err := gorm.G[User](DB).Where("id = ?", users[1].ID).Updates(ctx, User{Name: "generics_2_new"}); err != nil || rows != 1 {
+		t.Errorf("failed to updates user, got rows affected %v, error %v", rows, err)
+	}
+
+	if result, err := gorm.G[User](DB).Where("id = ?", users[0].ID).Take(ctx); err != nil || result.Age != 100 {
+		t.Errorf("failed to find updated user, got %+v, error %v", result, err)
+	}
+
+	var batches int
+	if err := query.FindInBatches(ctx, 3, func(results []User, batch int) error {
+		batches++
+		return nil
+	}); err != nil || batches != 2 {
+		t.Errorf("failed to find users in batches, got %v batches, error %v", batches, err)
+	}
+
+	if rows, err := gorm.G[User](DB).Where("id = ?", user.ID).Delete(ctx); err != nil || rows != 1 {
+		t.Errorf("failed to delete user, got rows affected %v, error %v", rows, err)
+	}
+
+	if _, err := gorm.G[User](DB).Where("id = ?", user.ID).First(ctx); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("should not find deleted user, got error %v", err)
+	}
+}
+
+func TestIterate(t *testing.T) {
+	users := []User{*GetUser("iterate_1", Config{}), *GetUser("iterate_2", Config{}), *GetUser("iterate_3", Config{})}
+	DB.Create(&users)
+
+	it, err := gorm.Iterate[User](DB.Where("name LIKE ?", "iterate_%").Order("id"))
+	if err != nil {
+		t.Fatalf("failed to iterate users, got error %v", err)
+	}
+	defer it.Close()
+
+	var idx int
+	for it.Next() {
+		CheckUser(t, it.Row(), users[idx])
+		idx++
+	}
+
+	if it.Err() != nil || idx != len(users) {
+		t.Errorf("failed to iterate all users, got %v, error %v", idx, it.Err())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	it, err = gorm.G[User](DB).Where("name LIKE ?", "iterate_%").Iterate(ctx)
+	if err != nil {
+		t.Fatalf("failed to iterate users, got error %v", err)
+	}
+
+	if !it.Next() {
+		t.Fatalf("should iterate first user, got error %v", it.Err())
+	}
+
+	cancel()
+	if it.Next() || !errors.Is(it.Err(), context.Canceled) {
+		t.Errorf("iterator should stop after context canceled, got error %v", it.Err())
+	}
+}
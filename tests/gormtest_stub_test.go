@@ -0,0 +1,62 @@
+package tests_test
+
+import (
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/gormtest"
+	. "gorm.io/gorm/utils/tests"
+)
+
+func TestGormTestStub(t *testing.T) {
+	stub := gormtest.NewStub()
+	db, err := gorm.Open(stub, &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open stub, got error %v", err)
+	}
+
+	stub.On("SELECT * FROM `users` WHERE name = ? AND `users`.`deleted_at` IS NULL", gormtest.Result{
+		Columns: []string{"id", "name", "age"},
+		Rows:    [][]interface{}{{1, "jinzhu", 18}, {2, "jinzhu", 20}},
+	})
+	stub.On("INSERT INTO `users` (`created_at`,`updated_at`,`deleted_at`,`name`,`age`,`birthday`,`company_id`,`manager_id`,`active`) VALUES (?,?,?,?,?,?,?,?,?)",
+		gormtest.Result{RowsAffected: 1, LastInsertID: 3}, gormtest.Result{Error: errors.New("duplicated")})
+
+	var users []User
+	if err := db.Where("name = ?", "jinzhu").Find(&users).Error; err != nil {
+		t.Fatalf("failed to query, got error %v", err)
+	}
+
+	if len(users) != 2 || users[0].ID != 1 || users[1].Age != 20 {
+		t.Errorf("should serve canned rows, got %+v", users)
+	}
+
+	user := User{Name: "stub"}
+	if err := db.Create(&user).Error; err != nil || user.ID != 3 {
+		t.Errorf("should serve canned result, got id %v, error %v", user.ID, err)
+	}
+
+	if err := db.Create(&User{Name: "stub"}).Error; err == nil || err.Error() != "duplicated" {
+		t.Errorf("should serve canned error, got %v", err)
+	}
+
+	var count int64
+	if err := db.Model(&User{}).Where("age > ?", 10).Count(&count).Error; err != nil || count != 0 {
+		t.Errorf("statements without results should return no rows, got %v, error %v", count, err)
+	}
+
+	statements := stub.Statements()
+	if len(statements) != 4 {
+		t.Fatalf("should record executed statements, got %+v", statements)
+	}
+
+	if statements[0].SQL != "SELECT * FROM `users` WHERE name = ? AND `users`.`deleted_at` IS NULL" || len(statements[0].Vars) != 1 || statements[0].Vars[0] != "jinzhu" {
+		t.Errorf("should record sql and vars, got %+v", statements[0])
+	}
+
+	stub.Reset()
+	if len(stub.Statements()) != 0 {
+		t.Errorf("should reset statements")
+	}
+}
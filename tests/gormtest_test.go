@@ -0,0 +1,69 @@
+package tests_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/gormtest"
+	. "gorm.io/gorm/utils/tests"
+)
+
+func TestGormTestBegin(t *testing.T) {
+	name := "gormtest_begin"
+
+	t.Run("rollback", func(t *testing.T) {
+		db := gormtest.Begin(t, DB)
+		clock := gormtest.NewClock(time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC))
+		db = clock.Use(db)
+
+		if err := db.Transaction(func(tx *gorm.DB) error {
+			return tx.Create(&User{Name: name}).Error
+		}); err != nil {
+			t.Fatalf("failed to create user, got error %v", err)
+		}
+
+		db.Transaction(func(tx *gorm.DB) error {
+			tx.Create(&User{Name: name + "_rollback"})
+			return errors.New("rollback")
+		})
+
+		nested := db.Begin()
+		nested.Create(&User{Name: name + "_nested"})
+		if err := nested.Rollback().Error; err != nil {
+			t.Fatalf("failed to rollback nested transaction, got error %v", err)
+		}
+
+		if err := db.Commit().Error; err != nil {
+			t.Fatalf("commit should be no-op, got error %v", err)
+		}
+
+		var users []User
+		db.Where("name LIKE ?", name+"%").Find(&users)
+		if len(users) != 1 || users[0].Name != name {
+			t.Fatalf("should find committed user in test transaction only, got %+v", users)
+		}
+
+		if !users[0].CreatedAt.Equal(clock.Now()) {
+			t.Errorf("should use fake clock, got %v", users[0].CreatedAt)
+		}
+
+		clock.Advance(time.Hour)
+		db.Model(&users[0]).Update("age", 18)
+		db.First(&users[0], users[0].ID)
+		if !users[0].UpdatedAt.Equal(time.Date(2021, 1, 2, 4, 4, 5, 0, time.UTC)) {
+			t.Errorf("should use advanced fake clock, got %v", users[0].UpdatedAt)
+		}
+	})
+
+	var count int64
+	DB.Model(&User{}).Where("name LIKE ?", name+"%").Count(&count)
+	if count != 0 {
+		t.Errorf("should rollback test transaction at cleanup, got %v users", count)
+	}
+
+	if err := DB.Where("name LIKE ?", name+"%").Delete(&User{}).Error; err != nil {
+		t.Errorf("test transaction should be finished at cleanup, got error %v", err)
+	}
+}
@@ -0,0 +1,89 @@
+package tests_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type TemporalAccount struct {
+	ID        uint
+	Name      string
+	Balance   int
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (TemporalAccount) HistoryTable() string {
+	return "temporal_accounts_history"
+}
+
+func TestAsOf(t *testing.T) {
+	DB.Migrator().DropTable(&TemporalAccount{}, "temporal_accounts_history")
+	for i := 0; i < 2; i++ {
+		if err := DB.AutoMigrate(&TemporalAccount{}); err != nil {
+			t.Fatalf("failed to migrate, got error %v", err)
+		}
+	}
+
+	if !DB.Migrator().HasTable("temporal_accounts_history") {
+		t.Fatalf("history table should be created")
+	}
+
+	tick := func() time.Time {
+		time.Sleep(20 * time.Millisecond)
+		now := time.Now()
+		time.Sleep(20 * time.Millisecond)
+		return now
+	}
+
+	beforeCreated := tick()
+	account := TemporalAccount{Name: "jinzhu", Balance: 10}
+	if err := DB.Create(&account).Error; err != nil {
+		t.Fatalf("failed to create, got error %v", err)
+	}
+
+	created := tick()
+	DB.Model(&account).Update("balance", 20)
+	updated := tick()
+	DB.Model(&account).Update("balance", 30)
+	updatedAgain := tick()
+	DB.Delete(&account)
+
+	var count int64
+	if DB.Table("temporal_accounts_history").Count(&count); count != 3 {
+		t.Errorf("history should have 3 row versions, got %v", count)
+	}
+
+	for at, balance := range map[time.Time]int{created: 10, updated: 20, updatedAgain: 30} {
+		var result TemporalAccount
+		if err := DB.AsOf(at).First(&result, account.ID).Error; err != nil || result.Balance != balance || result.Name != "jinzhu" {
+			t.Errorf("invalid state as of %v, expects balance %v, got %#v, error %v", at, balance, result, err)
+		}
+	}
+
+	if err := DB.AsOf(beforeCreated).First(&TemporalAccount{}, account.ID).Error; err != gorm.ErrRecordNotFound {
+		t.Errorf("account should not exist before created, got error %v", err)
+	}
+
+	if err := DB.AsOf(time.Now()).First(&TemporalAccount{}, account.ID).Error; err != gorm.ErrRecordNotFound {
+		t.Errorf("account should not exist after deleted, got error %v", err)
+	}
+
+	other := TemporalAccount{Name: "other", Balance: 5}
+	DB.Create(&other)
+
+	var results []TemporalAccount
+	if err := DB.AsOf(time.Now()).Find(&results).Error; err != nil || len(results) != 1 || results[0].ID != other.ID {
+		t.Errorf("unchanged rows should be read from table, got %#v, error %v", results, err)
+	}
+}
+
+func TestAsOfInvalidTime(t *testing.T) {
+	var results []TemporalAccount
+	if err := DB.Set("gorm:as_of", "yesterday").Find(&results).Error; !errors.Is(err, gorm.ErrInvalidData) {
+		t.Errorf("as of should be time, got error %v", err)
+	}
+}
@@ -0,0 +1,82 @@
+package tests_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	. "gorm.io/gorm/utils/tests"
+)
+
+type recordInterceptor struct {
+	name   string
+	calls  *[]string
+	before func(db *gorm.DB, operation string) error
+}
+
+func (i recordInterceptor) BeforeQuery(db *gorm.DB, operation string) error {
+	*i.calls = append(*i.calls, "before "+i.name+" "+operation)
+	if i.before != nil {
+		return i.before(db, operation)
+	}
+	return nil
+}
+
+func (i recordInterceptor) AfterQuery(db *gorm.DB, operation string) {
+	*i.calls = append(*i.calls, "after "+i.name+" "+operation)
+}
+
+func TestInterceptors(t *testing.T) {
+	user := *GetUser("interceptors", Config{})
+	DB.Create(&user)
+
+	var calls []string
+	db := DB.Intercept(recordInterceptor{name: "a", calls: &calls}, recordInterceptor{name: "b", calls: &calls})
+
+	var result User
+	if err := db.First(&result, user.ID).Error; err != nil {
+		t.Fatalf("failed to query, got %v", err)
+	}
+
+	if expects := "before a query,before b query,after b query,after a query"; strings.Join(calls, ",") != expects {
+		t.Errorf("interceptors should be called in order, expects %v, got %v", expects, calls)
+	}
+
+	calls = nil
+	DB.First(&result, user.ID)
+	if len(calls) != 0 {
+		t.Errorf("interceptors should be scoped to the session, got %v", calls)
+	}
+
+	errVetoed := errors.New("deleting is not allowed")
+	db = db.Intercept(recordInterceptor{name: "policy", calls: &calls, before: func(db *gorm.DB, operation string) error {
+		if operation == "delete" {
+			return errVetoed
+		}
+		return nil
+	}})
+
+	calls = nil
+	if err := db.Delete(&user).Error; !errors.Is(err, errVetoed) {
+		t.Errorf("delete should be vetoed, got %v", err)
+	}
+
+	if expects := "before a delete,before b delete,before policy delete,after b delete,after a delete"; strings.Join(calls, ",") != expects {
+		t.Errorf("interceptors after vetoing should be skipped, expects %v, got %v", expects, calls)
+	}
+
+	if err := DB.First(&result, user.ID).Error; err != nil {
+		t.Errorf("vetoed statement should not be executed, got %v", err)
+	}
+
+	var count int64
+	rewriter := recordInterceptor{name: "rewriter", calls: &calls, before: func(db *gorm.DB, operation string) error {
+		db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{clause.Eq{Column: "name", Value: "no such user"}}})
+		return nil
+	}}
+	if err := DB.Intercept(rewriter).Model(&User{}).Where("id = ?", user.ID).Count(&count).Error; err != nil || count != 0 {
+		t.Errorf("statement should be rewritten by interceptor, got %v, %v", err, count)
+	}
+}
@@ -0,0 +1,71 @@
+package tests_test
+
+import (
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type IntervalJob struct {
+	ID        uint
+	Name      string
+	Timeout   time.Duration  `gorm:"interval"`
+	Retry     *time.Duration `gorm:"interval"`
+	StartedAt time.Time
+}
+
+func TestIntervalColumns(t *testing.T) {
+	DB.Migrator().DropTable(&IntervalJob{})
+	if err := DB.AutoMigrate(&IntervalJob{}); err != nil {
+		t.Fatalf("failed to migrate interval job, got error %v", err)
+	}
+
+	retry := 1500 * time.Millisecond
+	now := time.Now().UTC().Truncate(time.Second)
+	jobs := []IntervalJob{
+		{Name: "expired", Timeout: time.Hour, Retry: &retry, StartedAt: now.Add(-2 * time.Hour)},
+		{Name: "running", Timeout: 3 * time.Hour, StartedAt: now.Add(-2 * time.Hour)},
+	}
+	if err := DB.Create(&jobs).Error; err != nil {
+		t.Fatalf("failed to create interval jobs, got error %v", err)
+	}
+
+	var raw int64
+	if DB.Dialector.Name() != "postgres" {
+		DB.Table("interval_jobs").Select("timeout").Where("id = ?", jobs[0].ID).Scan(&raw)
+		if raw != time.Hour.Microseconds() {
+			t.Errorf("interval should be saved as microseconds, got %v", raw)
+		}
+	}
+
+	var result IntervalJob
+	if err := DB.First(&result, jobs[0].ID).Error; err != nil {
+		t.Fatalf("failed to query interval job, got error %v", err)
+	}
+
+	if result.Timeout != time.Hour || result.Retry == nil || *result.Retry != retry {
+		t.Errorf("incorrect interval job, got %#v", result)
+	}
+
+	DB.Model(&result).Update("Timeout", 90*time.Minute)
+	DB.First(&result, jobs[0].ID)
+	if result.Timeout != 90*time.Minute {
+		t.Errorf("interval should be updated, got %v", result.Timeout)
+	}
+
+	var expired []IntervalJob
+	if err := DB.Where("? < ?", gorm.AddInterval{Time: "started_at", Interval: "timeout"}, now).Find(&expired).Error; err != nil {
+		t.Fatalf("failed to query with interval expression, got error %v", err)
+	}
+
+	if len(expired) != 1 || expired[0].Name != "expired" {
+		t.Errorf("should find expired job, got %#v", expired)
+	}
+
+	var recent []IntervalJob
+	DB.Where("? > ?", gorm.AddInterval{Time: "started_at", Interval: 150 * time.Minute}, now).Find(&recent)
+	if len(recent) != 2 {
+		t.Errorf("should find 2 jobs, got %v", len(recent))
+	}
+}
@@ -130,3 +130,50 @@ func TestJoinsWithSelect(t *testing.T) {
 		t.Errorf("Should find all two pets with Join select, got %+v", results)
 	}
 }
+
+func TestJoinsScanIntoNestedStruct(t *testing.T) {
+	type PetWithUser struct {
+		Pet
+		User User
+	}
+
+	user := *GetUser("joins_scan_nested", Config{Pets: 2})
+	DB.Save(&user)
+
+	var results []PetWithUser
+	if err := DB.Table("pets").Select("pets.*, users.*").Joins("left join users on users.id = pets.user_id").Where("users.name = ?", user.Name).Order("pets.id").Scan(&results).Error; err != nil {
+		t.Fatalf("failed to scan joined query, got error %v", err)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("should find two pets, but got %v", len(results))
+	}
+
+	for idx, result := range results {
+		if result.ID != user.Pets[idx].ID || result.Name != user.Pets[idx].Name {
+			t.Errorf("pet should be scanned, expects %v, got %+v", user.Pets[idx].Name, result.Pet)
+		}
+
+		if result.User.ID != user.ID || result.User.Name != user.Name || result.User.Age != user.Age {
+			t.Errorf("user should be scanned, expects %v, got %+v", user.Name, result.User)
+		}
+	}
+
+	var result PetWithUser
+	if err := DB.Table("pets").Select(`pets.id, pets.name, users.id AS "users.id", users.name AS "User__name"`).Joins("left join users on users.id = pets.user_id").Where("pets.id = ?", user.Pets[0].ID).Scan(&result).Error; err != nil {
+		t.Fatalf("failed to scan joined query, got error %v", err)
+	}
+
+	if result.ID != user.Pets[0].ID || result.Name != user.Pets[0].Name || result.User.ID != user.ID || result.User.Name != user.Name {
+		t.Errorf("should scan prefixed columns into nested struct, got %+v", result)
+	}
+
+	result = PetWithUser{}
+	if err := DB.Raw("SELECT pets.*, users.id FROM pets left join users on users.id = pets.user_id WHERE pets.id = ?", user.Pets[0].ID).Scan(&result).Error; err != nil {
+		t.Fatalf("failed to scan raw query, got error %v", err)
+	}
+
+	if result.ID != user.ID || result.Name != user.Pets[0].Name || result.User.ID != 0 {
+		t.Errorf("duplicated columns of raw query without joins should be scanned into main struct, got %+v", result)
+	}
+}
@@ -0,0 +1,65 @@
+package tests_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"gorm.io/gorm"
+	. "gorm.io/gorm/utils/tests"
+)
+
+func TestLoader(t *testing.T) {
+	var (
+		queries int32
+		db, _   = gorm.Open(DB.Dialector, &gorm.Config{})
+		users   = []User{*GetUser("loader_1", Config{}), *GetUser("loader_2", Config{}), *GetUser("loader_3", Config{})}
+		ctx     = context.Background()
+	)
+
+	db.Callback().Query().After("gorm:query").Register("count_queries", func(*gorm.DB) {
+		atomic.AddInt32(&queries, 1)
+	})
+
+	if err := db.Create(&users).Error; err != nil {
+		t.Fatalf("failed to create users, got error %v", err)
+	}
+
+	var (
+		wg      sync.WaitGroup
+		results = make([]User, 6)
+		errs    = make([]error, 6)
+		ids     = []interface{}{users[0].ID, users[1].ID, users[2].ID, users[0].ID, users[1].ID, users[2].ID}
+	)
+
+	for i := range ids {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = db.Loader(ctx).Find(&results[i], ids[i])
+		}(i)
+	}
+	wg.Wait()
+
+	for i, result := range results {
+		if errs[i] != nil {
+			t.Fatalf("failed to load user, got error %v", errs[i])
+		}
+		CheckUser(t, result, users[i%3])
+	}
+
+	if queries != 1 {
+		t.Errorf("lookups should be loaded in one query, but got %v", queries)
+	}
+
+	var user User
+	if err := db.Loader(ctx).Find(&user, 0); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("should returns record not found, but got %v", err)
+	}
+
+	if err := db.Loader(ctx).Find(user, users[0].ID); !errors.Is(err, gorm.ErrInvalidData) {
+		t.Errorf("should returns invalid data for non pointer, but got %v", err)
+	}
+}
@@ -0,0 +1,179 @@
+package tests_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/gormtest"
+)
+
+func TestLock(t *testing.T) {
+	var (
+		wg      sync.WaitGroup
+		running int32
+		overlap int32
+	)
+
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := DB.WithLock("lock_test", func(tx *gorm.DB) error {
+				if atomic.AddInt32(&running, 1) > 1 {
+					atomic.StoreInt32(&overlap, 1)
+				}
+				time.Sleep(50 * time.Millisecond)
+				atomic.AddInt32(&running, -1)
+				return nil
+			}); err != nil {
+				t.Errorf("failed to run with lock, got error %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if overlap != 0 {
+		t.Fatalf("functions with the same lock should not run at the same time")
+	}
+
+	unlock, err := DB.Lock("lock_test")
+	if err != nil {
+		t.Fatalf("failed to acquire lock, got error %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	if _, err := DB.WithContext(ctx).Lock("lock_test"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("should failed to acquire locked lock until context is done, got %v", err)
+	}
+
+	if err := unlock(); err != nil {
+		t.Fatalf("failed to release lock, got error %v", err)
+	}
+
+	if unlock, err := DB.Lock("lock_test"); err != nil {
+		t.Fatalf("failed to acquire released lock, got error %v", err)
+	} else {
+		unlock()
+	}
+}
+
+func TestLockTakeOverStaleLock(t *testing.T) {
+	if DB.Dialector.Name() == "postgres" || DB.Dialector.Name() == "mysql" {
+		t.Skip("advisory locks are released with connections")
+	}
+
+	unlock, err := DB.Lock("lock_stale_test")
+	if err != nil {
+		t.Fatalf("failed to acquire lock, got error %v", err)
+	}
+	defer unlock()
+
+	if err := DB.Table(gorm.LocksTable).Where("name = ?", "lock_stale_test").Update("locked_at", time.Now().Add(-gorm.LockTTL-time.Minute)).Error; err != nil {
+		t.Fatalf("failed to make lock stale, got error %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	if unlock, err := DB.WithContext(ctx).Lock("lock_stale_test"); err != nil {
+		t.Fatalf("should take over stale lock, got error %v", err)
+	} else {
+		unlock()
+	}
+}
+
+func TestLockHeartbeat(t *testing.T) {
+	if DB.Dialector.Name() == "postgres" || DB.Dialector.Name() == "mysql" {
+		t.Skip("advisory locks are released with connections")
+	}
+
+	defer func(ttl time.Duration) { gorm.LockTTL = ttl }(gorm.LockTTL)
+	gorm.LockTTL = 300 * time.Millisecond
+
+	unlock, err := DB.Lock("lock_heartbeat_test")
+	if err != nil {
+		t.Fatalf("failed to acquire lock, got error %v", err)
+	}
+	defer unlock()
+
+	// the lock would be stale after LockTTL without heartbeats of the running holder
+	time.Sleep(500 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	if unlock, err := DB.WithContext(ctx).Lock("lock_heartbeat_test"); err == nil {
+		unlock()
+		t.Errorf("lock of running holder should not be taken over")
+	}
+}
+
+func TestLockInTransaction(t *testing.T) {
+	if DB.Dialector.Name() == "postgres" || DB.Dialector.Name() == "mysql" {
+		t.Skip("advisory locks are acquired with connection of transaction")
+	}
+
+	if err := DB.Transaction(func(tx *gorm.DB) error {
+		unlock, err := tx.Lock("lock_transaction_test")
+		if err != nil {
+			return err
+		}
+		defer unlock()
+
+		// the lock is inserted with dedicated connection, so it is visible to others before the transaction is committed
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		defer cancel()
+		if unlock, err := DB.WithContext(ctx).Lock("lock_transaction_test"); err == nil {
+			unlock()
+			t.Errorf("lock acquired in transaction should be held")
+		}
+		return nil
+	}); err != nil {
+		t.Errorf("failed to lock in transaction, got error %v", err)
+	}
+}
+
+func TestLockInsertError(t *testing.T) {
+	stub := gormtest.NewStub()
+	db, _ := gorm.Open(capabilitiesDialector{Stub: stub}, &gorm.Config{SkipDefaultTransaction: true})
+	stub.On("INSERT INTO `gorm_locks` (`name`,`locked_at`) VALUES (?,?)", gormtest.Result{Error: errors.New("permission denied")})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if _, err := db.WithContext(ctx).Lock("lock_insert_error_test"); err == nil || errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("errors other than duplicated key should be returned without waiting, got %v", err)
+	}
+}
+
+func TestAutoMigrateWithLock(t *testing.T) {
+	if DB.Dialector.Name() == "postgres" || DB.Dialector.Name() == "mysql" {
+		t.Skip("advisory locks don't use locks table")
+	}
+
+	type LockMigrateUser struct {
+		ID   uint
+		Name string
+	}
+
+	DB.Migrator().DropTable(gorm.LocksTable, &LockMigrateUser{})
+	if err := DB.AutoMigrate(&LockMigrateUser{}); err != nil {
+		t.Fatalf("failed to auto migrate, got error %v", err)
+	}
+
+	if DB.Migrator().HasTable(gorm.LocksTable) {
+		t.Fatalf("AutoMigrate should not create locks table without MigrateWithLock")
+	}
+
+	db, _ := gorm.Open(DB.Dialector, &gorm.Config{MigrateWithLock: true})
+	if err := db.AutoMigrate(&LockMigrateUser{}); err != nil {
+		t.Fatalf("failed to auto migrate with lock, got error %v", err)
+	}
+
+	if !DB.Migrator().HasTable(gorm.LocksTable) {
+		t.Fatalf("AutoMigrate should create locks table with MigrateWithLock")
+	}
+}
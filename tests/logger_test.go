@@ -0,0 +1,46 @@
+package tests_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	. "gorm.io/gorm/utils/tests"
+)
+
+type requestIDKey struct{}
+
+func TestLogLevelWithTraceID(t *testing.T) {
+	var (
+		writer = &sqlWriter{}
+		config = *DB.Config
+	)
+
+	config.Logger = logger.New(writer, logger.Config{LogLevel: logger.Warn, TraceIDKey: requestIDKey{}})
+	db := DB.Session(&gorm.Session{NewDB: true})
+	db.Config = &config
+
+	ctx := context.WithValue(context.Background(), requestIDKey{}, "req-1")
+	db.WithContext(ctx).Where("name = ?", "log_level").Find(&[]User{})
+	if len(writer.logs) != 0 {
+		t.Fatalf("queries should not be logged with warn level, got %v", writer.logs)
+	}
+
+	db.WithContext(ctx).LogLevel(logger.Info).Where("name = ?", "log_level").Find(&[]User{})
+	if len(writer.logs) != 1 || !strings.Contains(writer.logs[0], "[trace_id:req-1]") || !strings.Contains(writer.logs[0], "log_level") {
+		t.Fatalf("query should be logged with trace id, got %v", writer.logs)
+	}
+
+	db.WithContext(ctx).Where("name = ?", "log_level").Find(&[]User{})
+	if len(writer.logs) != 1 {
+		t.Errorf("log level should only be changed for the instance, got %v", writer.logs)
+	}
+
+	db.Logger = logger.New(writer, logger.Config{LogLevel: logger.Info})
+	db.WithContext(logger.WithTraceID(context.Background(), "trace-2")).Find(&[]User{})
+	if len(writer.logs) != 2 || !strings.Contains(writer.logs[1], "[trace_id:trace-2]") {
+		t.Errorf("trace id set with WithTraceID should be logged, got %v", writer.logs)
+	}
+}
@@ -0,0 +1,48 @@
+package tests_test
+
+import (
+	"context"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	. "gorm.io/gorm/utils/tests"
+)
+
+func TestQueryFingerprint(t *testing.T) {
+	var (
+		metrics []gorm.QueryMetrics
+		config  = *DB.Config
+	)
+
+	config.QueryFingerprint = true
+	config.MetricsCallback = func(ctx context.Context, m gorm.QueryMetrics) {
+		if logger.FingerprintFromContext(ctx) != m.Fingerprint {
+			t.Errorf("fingerprint of context should be passed to metrics callback")
+		}
+		metrics = append(metrics, m)
+	}
+
+	db := DB.Session(&gorm.Session{NewDB: true})
+	db.Config = &config
+
+	db.Where("name IN ?", []string{"fingerprint1", "fingerprint2"}).Find(&[]User{})
+	db.Where("name IN ?", []string{"fingerprint3"}).Find(&[]User{})
+	db.Where("age = ?", 18).Find(&[]User{})
+
+	if len(metrics) != 3 {
+		t.Fatalf("metrics callback should be called for each statement, got %v", len(metrics))
+	}
+
+	if metrics[0].Fingerprint == "" || metrics[0].Fingerprint != metrics[1].Fingerprint {
+		t.Errorf("statements of the same shape should have the same fingerprint, got %v, %v", metrics[0].Fingerprint, metrics[1].Fingerprint)
+	}
+
+	if metrics[0].Fingerprint == metrics[2].Fingerprint {
+		t.Errorf("statements of different shapes should have different fingerprints")
+	}
+
+	if metrics[2].Error != nil || metrics[2].Elapsed <= 0 || metrics[2].SQL == "" {
+		t.Errorf("invalid metrics, got %#v", metrics[2])
+	}
+}
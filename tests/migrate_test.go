@@ -1,12 +1,19 @@
 package tests_test
 
 import (
+	"context"
+	"errors"
+	"io"
 	"math/rand"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/gormtest"
+	"gorm.io/gorm/migrator"
+	"gorm.io/gorm/schema"
 	. "gorm.io/gorm/utils/tests"
 )
 
@@ -353,3 +360,956 @@ func TestMigrateConstraint(t *testing.T) {
 		}
 	}
 }
+
+func TestMigrateGeneratedColumns(t *testing.T) {
+	type GeneratedItem struct {
+		ID       uint
+		Price    float64
+		Quantity int
+		Total    float64 `gorm:"->;generatedAs:price*quantity;stored"`
+		Double   int     `gorm:"generatedAs:quantity*2"`
+	}
+
+	DB.Migrator().DropTable(&GeneratedItem{})
+	if err := DB.AutoMigrate(&GeneratedItem{}); err != nil {
+		t.Fatalf("failed to migrate generated columns, got error %v", err)
+	}
+
+	item := GeneratedItem{Price: 2.5, Quantity: 4, Total: 100, Double: 100}
+	if err := DB.Select("*").Create(&item).Error; err != nil {
+		t.Fatalf("generated columns should be excluded when creating, got error %v", err)
+	}
+
+	var result GeneratedItem
+	if err := DB.First(&result, item.ID).Error; err != nil || result.Total != 10 || result.Double != 8 {
+		t.Errorf("generated columns should be computed, got %+v, error %v", result, err)
+	}
+
+	if err := DB.Model(&result).Updates(map[string]interface{}{"quantity": 2, "total": 1}).Error; err != nil {
+		t.Fatalf("generated columns should be excluded when updating, got error %v", err)
+	}
+
+	DB.First(&result, item.ID)
+	if result.Total != 5 || result.Double != 4 {
+		t.Errorf("generated columns should be recomputed after updating, got %+v", result)
+	}
+}
+
+func TestMigrateCollation(t *testing.T) {
+	if DB.Dialector.Name() != "sqlite" {
+		t.Skip("skip collation test for " + DB.Dialector.Name())
+	}
+
+	type CollationUser struct {
+		ID    uint
+		Email string `gorm:"size:100;uniqueIndex"`
+	}
+
+	type CollationUserWithCollate struct {
+		ID    uint
+		Email string `gorm:"size:100;uniqueIndex;collate:NOCASE"`
+	}
+
+	DB.Migrator().DropTable(&CollationUser{})
+	if err := DB.AutoMigrate(&CollationUser{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	stmt := &gorm.Statement{DB: DB}
+	if err := stmt.Parse(&CollationUserWithCollate{}); err != nil {
+		t.Fatalf("failed to parse, got error %v", err)
+	}
+
+	if sql := DB.Migrator().FullDataTypeOf(stmt.Schema.LookUpField("Email")).SQL; sql != "text COLLATE NOCASE" {
+		t.Errorf("full data type should contains collation, got %v", sql)
+	}
+
+	if err := DB.Table("collation_users").AutoMigrate(&CollationUserWithCollate{}); err != nil {
+		t.Fatalf("failed to migrate collation changes, got error %v", err)
+	}
+
+	type ColumnCollation interface {
+		ColumnCollation(value interface{}, column string) (charset string, collation string, ok bool)
+	}
+
+	if m, ok := DB.Migrator().(ColumnCollation); ok {
+		if _, collation, ok := m.ColumnCollation(&CollationUser{}, "email"); !ok || collation != "NOCASE" {
+			t.Errorf("column collation should be changed to NOCASE, got %v", collation)
+		}
+	}
+
+	if err := DB.Table("collation_users").Create(&CollationUserWithCollate{Email: "jinzhu@example.org"}).Error; err != nil {
+		t.Fatalf("failed to create, got error %v", err)
+	}
+
+	if err := DB.Table("collation_users").Create(&CollationUserWithCollate{Email: "JINZHU@example.org"}).Error; err == nil {
+		t.Errorf("unique email should be case insensitive with collation NOCASE")
+	}
+
+	type CollationMember struct {
+		ID    uint
+		Email string `gorm:"collate:NOCASE"`
+		Name  string `gorm:"collate:NOCASE"`
+	}
+
+	db, _ := gorm.Open(DB.Dialector, &gorm.Config{})
+	db.Migrator().DropTable(&CollationMember{})
+	if err := db.AutoMigrate(&CollationMember{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	var lookups int
+	db.Callback().Row().After("gorm:row").Register("test:count_collation_lookups", func(tx *gorm.DB) {
+		if strings.HasPrefix(tx.Statement.SQL.String(), "SELECT sql FROM sqlite_master") {
+			lookups++
+		}
+	})
+
+	if err := db.AutoMigrate(&CollationMember{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	if lookups != 1 {
+		t.Errorf("collations of columns should be looked up once for table, got %v", lookups)
+	}
+}
+
+func TestMigrateColumnConversion(t *testing.T) {
+	type ConversionUser struct {
+		ID  uint
+		Age string
+	}
+
+	type ConversionUserWithIntAge struct {
+		ID  uint
+		Age int `gorm:"type:integer;using:age::integer"`
+	}
+
+	stmt := &gorm.Statement{DB: DB}
+	if err := stmt.Parse(&ConversionUserWithIntAge{}); err != nil {
+		t.Fatalf("failed to parse, got error %v", err)
+	}
+
+	if using := stmt.Schema.LookUpField("Age").Using; using != "age::integer" {
+		t.Errorf("conversion expression should be parsed, got %v", using)
+	}
+
+	if DB.Dialector.Name() != "postgres" {
+		t.Skip("skip column conversion test for " + DB.Dialector.Name())
+	}
+
+	DB.Migrator().DropTable(&ConversionUser{})
+	if err := DB.AutoMigrate(&ConversionUser{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	if err := DB.Create(&ConversionUser{Age: "18"}).Error; err != nil {
+		t.Fatalf("failed to create, got error %v", err)
+	}
+
+	if err := DB.Table("conversion_users").AutoMigrate(&ConversionUserWithIntAge{}); !errors.Is(err, gorm.ErrColumnConversionNotConfirmed) {
+		t.Fatalf("column conversion should be confirmed, got error %v", err)
+	}
+
+	if err := DB.Session(&gorm.Session{ConfirmColumnConversion: true}).Table("conversion_users").AutoMigrate(&ConversionUserWithIntAge{}); err != nil {
+		t.Fatalf("failed to convert column, got error %v", err)
+	}
+
+	var user ConversionUserWithIntAge
+	if err := DB.Table("conversion_users").First(&user).Error; err != nil || user.Age != 18 {
+		t.Errorf("age should be converted, got %v, error %v", user.Age, err)
+	}
+}
+
+type CommentedUser struct {
+	ID   uint
+	Name string `gorm:"size:100;index:,comment:lookup by name"`
+}
+
+func (CommentedUser) TableComment() string {
+	return "users of app"
+}
+
+type CommentedUser2 struct {
+	ID   uint
+	Name string `gorm:"size:100;index:idx_commented_users_name,comment:lookup by name"`
+}
+
+func (CommentedUser2) TableName() string {
+	return "commented_users"
+}
+
+func (CommentedUser2) TableComment() string {
+	return "users of app, it's changed"
+}
+
+func TestMigrateComments(t *testing.T) {
+	stmt := &gorm.Statement{DB: DB}
+	if err := stmt.Parse(&CommentedUser{}); err != nil || stmt.Schema.Comment != "users of app" {
+		t.Fatalf("table comment should be parsed, got %v, error %v", stmt.Schema.Comment, err)
+	}
+
+	if DB.Dialector.Name() != "mysql" && DB.Dialector.Name() != "postgres" {
+		t.Skip("skip comments test for " + DB.Dialector.Name())
+	}
+
+	DB.Migrator().DropTable(&CommentedUser{})
+	if err := DB.AutoMigrate(&CommentedUser{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	if tableType, err := DB.Migrator().(gorm.TableInspectorInterface).TableType(&CommentedUser{}); err != nil {
+		t.Fatalf("failed to get table type, got error %v", err)
+	} else if comment, _ := tableType.Comment(); comment != "users of app" {
+		t.Errorf("table comment should be created, got %v", comment)
+	}
+
+	if err := DB.AutoMigrate(&CommentedUser2{}); err != nil {
+		t.Fatalf("failed to migrate comment changes, got error %v", err)
+	}
+
+	if tableType, err := DB.Migrator().(gorm.TableInspectorInterface).TableType(&CommentedUser{}); err != nil {
+		t.Fatalf("failed to get table type, got error %v", err)
+	} else if comment, _ := tableType.Comment(); comment != "users of app, it's changed" {
+		t.Errorf("table comment should be changed, got %v", comment)
+	}
+
+	type UncommentedUser struct {
+		ID   uint
+		Name string `gorm:"size:100"`
+	}
+
+	if err := DB.Table("commented_users").AutoMigrate(&UncommentedUser{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	if tableType, err := DB.Migrator().(gorm.TableInspectorInterface).TableType(&CommentedUser{}); err != nil {
+		t.Fatalf("failed to get table type, got error %v", err)
+	} else if comment, _ := tableType.Comment(); comment != "users of app, it's changed" {
+		t.Errorf("table comment should be kept for models without TableComment, got %v", comment)
+	}
+}
+
+func TestMigrateColumnComments(t *testing.T) {
+	type CommentedColumn struct {
+		ID    uint
+		Name  string `gorm:"comment:name of column"`
+		Email string `gorm:"comment:email of column"`
+	}
+
+	stub := gormtest.NewStub()
+	stub.On("SELECT count(*) FROM information_schema.tables WHERE table_schema = ? AND table_name = ? AND table_type = ?", gormtest.Result{Columns: []string{"count"}, Rows: [][]interface{}{{1}}})
+	stub.On("SELECT * FROM `commented_columns` LIMIT 1", gormtest.Result{Columns: []string{"id", "name", "email"}})
+	stub.On("SELECT column_name, column_comment FROM information_schema.columns WHERE table_schema = ? AND table_name = ?", gormtest.Result{
+		Columns: []string{"column_name", "column_comment"},
+		Rows:    [][]interface{}{{"id", ""}, {"name", "name of column"}, {"email", "old comment"}},
+	})
+
+	db, _ := gorm.Open(capabilitiesDialector{Stub: stub, capabilities: gorm.Capabilities{Comment: gorm.SyntaxMySQL}}, &gorm.Config{SkipDefaultTransaction: true})
+	if err := db.AutoMigrate(&CommentedColumn{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	var queries, alters []string
+	for _, stmt := range stub.Statements() {
+		if strings.Contains(stmt.SQL, "column_comment") {
+			queries = append(queries, stmt.SQL)
+		} else if strings.HasPrefix(stmt.SQL, "ALTER TABLE") {
+			alters = append(alters, stmt.SQL)
+		}
+	}
+
+	if len(queries) != 1 {
+		t.Errorf("comments of columns should be loaded once for table, got %v", queries)
+	}
+
+	if len(alters) != 1 || !strings.Contains(alters[0], "`email`") {
+		t.Errorf("only column with changed comment should be altered, got %v", alters)
+	}
+}
+
+func TestMigratePlan(t *testing.T) {
+	type PlanUser struct {
+		ID   uint
+		Name string `gorm:"index"`
+	}
+
+	type PlanUserWithAge struct {
+		ID   uint
+		Name string `gorm:"index"`
+		Age  int
+	}
+
+	DB.Migrator().DropTable(&PlanUser{})
+	plan, err := DB.Migrator().(gorm.MigrationPlannerInterface).Plan(&PlanUser{})
+	if err != nil {
+		t.Fatalf("failed to plan, got error %v", err)
+	}
+
+	if DB.Migrator().HasTable(&PlanUser{}) {
+		t.Fatalf("table should not be created when planning")
+	}
+
+	if len(plan.Statements) == 0 || !strings.HasPrefix(plan.Statements[0], "CREATE TABLE") || !strings.HasPrefix(plan.Diff(), "+ CREATE TABLE") {
+		t.Errorf("plan should create table, got %v", plan.Diff())
+	}
+
+	if err := DB.AutoMigrate(&PlanUser{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	if plan, err := DB.Migrator().(gorm.MigrationPlannerInterface).Plan(&PlanUser{}); err != nil || len(plan.Statements) != 0 {
+		t.Errorf("plan should be empty for migrated model, got %v, error %v", plan.Statements, err)
+	}
+
+	plan, err = DB.Table("plan_users").Migrator().(gorm.MigrationPlannerInterface).Plan(&PlanUserWithAge{})
+	if err != nil || len(plan.Statements) == 0 || !strings.Contains(plan.Diff(), "age") {
+		t.Fatalf("plan should add column age, got %v, error %v", plan.Statements, err)
+	}
+
+	if DB.Migrator().HasColumn(&PlanUser{}, "age") {
+		t.Errorf("column should not be added when planning")
+	}
+
+	var script strings.Builder
+	if _, err := plan.WriteTo(&script); err != nil || !strings.HasSuffix(script.String(), ";\n") {
+		t.Errorf("failed to write plan as script, got %v, error %v", script.String(), err)
+	}
+}
+
+func TestMigrateScriptTo(t *testing.T) {
+	type ScriptCompany struct {
+		ID   uint
+		Name string
+	}
+
+	type ScriptUser struct {
+		ID        uint
+		Name      string `gorm:"index"`
+		CompanyID uint
+		Company   ScriptCompany
+	}
+
+	DB.Migrator().DropTable(&ScriptUser{}, &ScriptCompany{})
+
+	var script strings.Builder
+	if err := DB.Migrator().(gorm.MigrationPlannerInterface).ScriptTo(&script, &ScriptUser{}, &ScriptCompany{}); err != nil {
+		t.Fatalf("failed to write script, got error %v", err)
+	}
+
+	if DB.Migrator().HasTable(&ScriptUser{}) || DB.Migrator().HasTable(&ScriptCompany{}) {
+		t.Fatalf("tables should not be created when writing script")
+	}
+
+	sql := script.String()
+	companies, users, index := strings.Index(sql, "script_companies"), strings.Index(sql, "CREATE TABLE `script_users`"), strings.Index(sql, "idx_script_users_name")
+	if DB.Dialector.Name() != "sqlite" {
+		users = strings.Index(sql, "script_users")
+	}
+
+	if companies == -1 || users == -1 || index == -1 || companies > users {
+		t.Fatalf("script should create companies, users tables and index in order, got %v", sql)
+	}
+
+	if err := DB.Exec(sql).Error; err != nil && DB.Dialector.Name() == "sqlite" {
+		t.Fatalf("failed to run script, got error %v", err)
+	}
+}
+
+type migrationAuditHook struct {
+	before, after []string
+}
+
+func (hook *migrationAuditHook) BeforeMigrate(ctx context.Context, sql string) error {
+	hook.before = append(hook.before, sql)
+	if strings.HasPrefix(sql, "DROP TABLE") {
+		return errors.New("dropping tables is not allowed")
+	}
+	return nil
+}
+
+func (hook *migrationAuditHook) AfterMigrate(ctx context.Context, sql string, elapsed time.Duration, err error) {
+	hook.after = append(hook.after, sql)
+}
+
+func TestMigrationHook(t *testing.T) {
+	type HookUser struct {
+		ID   uint
+		Name string `gorm:"index"`
+	}
+
+	DB.Migrator().DropTable(&HookUser{})
+
+	hook := &migrationAuditHook{}
+	tx, _ := gorm.Open(DB.Dialector, &gorm.Config{MigrationHook: hook})
+
+	if err := tx.AutoMigrate(&HookUser{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	if len(hook.before) == 0 || !strings.HasPrefix(hook.before[0], "CREATE TABLE") || !reflect.DeepEqual(hook.before, hook.after) {
+		t.Errorf("hooks should be fired around DDL statements, got %v, %v", hook.before, hook.after)
+	}
+
+	if err := tx.Migrator().DropTable(&HookUser{}); err == nil || !DB.Migrator().HasTable(&HookUser{}) {
+		t.Errorf("dropping table should be blocked, got error %v", err)
+	}
+
+	if len(hook.after) != len(hook.before)-1 {
+		t.Errorf("after hook should not be fired for blocked statement, got %v", hook.after)
+	}
+
+	if err := tx.Create(&HookUser{Name: "hook"}).Error; err != nil || len(hook.after) != len(hook.before)-1 {
+		t.Errorf("hooks should not be fired for statements out of Migrator, got %v, error %v", hook.before, err)
+	}
+
+	type HookPlanUser struct {
+		ID   uint
+		Name string `gorm:"index"`
+	}
+
+	hook.before, hook.after = nil, nil
+	if plan, err := tx.Migrator().(gorm.MigrationPlannerInterface).Plan(&HookPlanUser{}); err != nil || len(plan.Statements) == 0 {
+		t.Fatalf("failed to plan, got %v, error %v", plan.Statements, err)
+	}
+
+	if err := tx.Migrator().(gorm.MigrationPlannerInterface).ScriptTo(io.Discard, &HookPlanUser{}); err != nil {
+		t.Fatalf("failed to write script, got error %v", err)
+	}
+
+	if len(hook.before) != 0 || len(hook.after) != 0 {
+		t.Errorf("hooks should not be fired for recorded statements, got %v, %v", hook.before, hook.after)
+	}
+}
+
+func TestMigrateDropUnused(t *testing.T) {
+	type UnusedUser struct {
+		ID       uint
+		Name     string `gorm:"index"`
+		Email    string `gorm:"unique"`
+		Nickname string `gorm:"index"`
+		Age      int
+	}
+
+	type UnusedUserWithoutNickname struct {
+		ID    uint
+		Name  string `gorm:"index:idx_unused_users_name"`
+		Email string `gorm:"unique"`
+	}
+
+	DB.Migrator().DropTable(&UnusedUser{})
+	if err := DB.AutoMigrate(&UnusedUser{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	if err := DB.Table("unused_users").AutoMigrate(&UnusedUserWithoutNickname{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	if !DB.Migrator().HasColumn(&UnusedUser{}, "nickname") {
+		t.Fatalf("unused columns should not be dropped by default")
+	}
+
+	tx := DB.Session(&gorm.Session{DropUnusedWhenMigrating: true})
+	plan, err := tx.Table("unused_users").Migrator().(gorm.MigrationPlannerInterface).Plan(&UnusedUserWithoutNickname{})
+	if err != nil {
+		t.Fatalf("failed to plan, got error %v", err)
+	}
+
+	if diff := plan.Diff(); !strings.Contains(diff, "idx_unused_users_nickname") || !strings.Contains(diff, "age") {
+		t.Errorf("plan should drop unused index and columns, got %v", diff)
+	}
+
+	if !DB.Migrator().HasColumn(&UnusedUser{}, "age") {
+		t.Fatalf("columns should not be dropped when planning")
+	}
+
+	if err := DB.Create(&UnusedUser{Name: "jinzhu", Email: "jinzhu@example.org", Nickname: "jz", Age: 18}).Error; err != nil {
+		t.Fatalf("failed to create, got error %v", err)
+	}
+
+	if err := tx.Table("unused_users").AutoMigrate(&UnusedUserWithoutNickname{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	for _, column := range []string{"nickname", "age"} {
+		if DB.Migrator().HasColumn(&UnusedUser{}, column) {
+			t.Errorf("unused column %v should be dropped", column)
+		}
+	}
+
+	if DB.Migrator().HasIndex(&UnusedUser{}, "idx_unused_users_nickname") {
+		t.Errorf("unused index should be dropped")
+	}
+
+	if !DB.Migrator().HasIndex(&UnusedUser{}, "idx_unused_users_name") || !DB.Migrator().HasColumn(&UnusedUser{}, "email") {
+		t.Errorf("used indexes and columns should be kept")
+	}
+
+	var result UnusedUserWithoutNickname
+	if err := DB.Table("unused_users").First(&result).Error; err != nil || result.Name != "jinzhu" {
+		t.Errorf("data should be kept, got %+v, error %v", result, err)
+	}
+
+	if plan, err := tx.Table("unused_users").Migrator().(gorm.MigrationPlannerInterface).Plan(&UnusedUserWithoutNickname{}); err != nil || len(plan.Statements) != 0 {
+		t.Errorf("plan should be empty after dropping unused objects, got %v, error %v", plan.Statements, err)
+	}
+}
+
+type MigrateUserStat struct {
+	Name  string `gorm:"uniqueIndex:idx_migrate_user_stats_name"`
+	Total int64
+}
+
+func (MigrateUserStat) MaterializedView() bool {
+	return true
+}
+
+func TestMigrateViews(t *testing.T) {
+	DB.Migrator().DropView("migrate_user_stats")
+	DB.Migrator().DropView("migrate_user_names")
+
+	users := []User{*GetUser("migrate_views", Config{}), *GetUser("migrate_views", Config{}), *GetUser("migrate_views_2", Config{})}
+	DB.Create(&users)
+
+	query := DB.Model(&User{}).Select("name, count(*) AS total").Where("name LIKE ?", "migrate_views%").Group("name")
+	if err := DB.Migrator().CreateView("migrate_user_names", gorm.ViewOption{Query: query}); err != nil {
+		t.Fatalf("failed to create view, got error %v", err)
+	}
+
+	var stats []MigrateUserStat
+	if err := DB.Table("migrate_user_names").Order("name").Find(&stats).Error; err != nil || len(stats) != 2 || stats[0].Total != 2 {
+		t.Fatalf("failed to query view, got %v, error %v", stats, err)
+	}
+
+	if err := DB.Migrator().DropView("migrate_user_names"); err != nil {
+		t.Fatalf("failed to drop view, got error %v", err)
+	}
+
+	if err := DB.Migrator().CreateView("migrate_user_names", gorm.ViewOption{}); !errors.Is(err, gorm.ErrSubQueryRequired) {
+		t.Fatalf("should returns ErrSubQueryRequired without query, got %v", err)
+	}
+
+	err := DB.Migrator().CreateView("migrate_user_stats", gorm.ViewOption{Query: query, Materialized: true})
+	if DB.Dialector.Name() != "postgres" {
+		if !errors.Is(err, gorm.ErrNotImplemented) {
+			t.Fatalf("materialized view should be not implemented, got %v", err)
+		}
+	} else if err != nil {
+		t.Fatalf("failed to create materialized view, got error %v", err)
+	}
+
+	if err := DB.AutoMigrate(&MigrateUserStat{}); err != nil {
+		t.Fatalf("failed to migrate materialized view model, got error %v", err)
+	}
+
+	if DB.Migrator().HasTable(&MigrateUserStat{}) {
+		t.Fatalf("should not create table for materialized view model")
+	}
+
+	if err := DB.Create(&MigrateUserStat{Name: "migrate_views_3"}).Error; !errors.Is(err, gorm.ErrReadOnly) {
+		t.Fatalf("should not create materialized view model, got %v", err)
+	}
+
+	if err := DB.Where("name = ?", "migrate_views").Delete(&MigrateUserStat{}).Error; !errors.Is(err, gorm.ErrReadOnly) {
+		t.Fatalf("should not delete materialized view model, got %v", err)
+	}
+
+	if DB.Dialector.Name() == "postgres" {
+		if !DB.Migrator().HasIndex(&MigrateUserStat{}, "idx_migrate_user_stats_name") {
+			t.Fatalf("should create index of materialized view")
+		}
+
+		DB.Create(&[]User{*GetUser("migrate_views_3", Config{})})
+		if err := DB.Migrator().(gorm.ViewRefresherInterface).RefreshView("migrate_user_stats", true); err != nil {
+			t.Fatalf("failed to refresh materialized view, got error %v", err)
+		}
+
+		if err := DB.Find(&stats).Error; err != nil || len(stats) != 3 {
+			t.Fatalf("failed to query refreshed materialized view, got %v, error %v", stats, err)
+		}
+
+		if err := DB.Migrator().DropView("migrate_user_stats"); err != nil {
+			t.Fatalf("failed to drop materialized view, got error %v", err)
+		}
+	}
+}
+
+type MigrateFKCompany struct {
+	ID int
+}
+
+type MigrateFKUser2 struct {
+	ID        int
+	CompanyID *int
+	Company   MigrateFKCompany `gorm:"constraint:OnDelete:SET NULL,OnUpdate:CASCADE"`
+}
+
+func (MigrateFKUser2) TableName() string {
+	return "migrate_fk_users"
+}
+
+func TestMigrateForeignKeyActions(t *testing.T) {
+	type MigrateFKUser struct {
+		ID        int
+		CompanyID *int
+		Company   MigrateFKCompany `gorm:"constraint:OnDelete:CASCADE"`
+	}
+
+	type MigrateFKInvalidUser struct {
+		ID        int
+		CompanyID int              `gorm:"not null"`
+		Company   MigrateFKCompany `gorm:"constraint:OnDelete:SET NULL"`
+	}
+
+	DB.Migrator().DropTable("migrate_fk_users", "migrate_fk_invalid_users", &MigrateFKCompany{})
+	if err := DB.AutoMigrate(&MigrateFKUser{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	if err := DB.AutoMigrate(&MigrateFKInvalidUser{}); !errors.Is(err, schema.ErrInvalidConstraint) {
+		t.Fatalf("should not set not null foreign key to NULL, got %v", err)
+	}
+
+	if onDelete, _, ok := DB.Migrator().(interface {
+		ForeignKeyActions(interface{}, string) (string, string, bool)
+	}).ForeignKeyActions(&MigrateFKUser{}, "Company"); !ok || onDelete != "CASCADE" {
+		t.Fatalf("OnDelete action should be CASCADE, got %v %v", onDelete, ok)
+	}
+
+	if DB.Dialector.Name() == "sqlite" {
+		return
+	}
+
+	if err := DB.AutoMigrate(&MigrateFKUser2{}); err != nil {
+		t.Fatalf("failed to migrate foreign key actions, got error %v", err)
+	}
+
+	if onDelete, onUpdate, ok := DB.Migrator().(interface {
+		ForeignKeyActions(interface{}, string) (string, string, bool)
+	}).ForeignKeyActions(&MigrateFKUser{}, "Company"); !ok || onDelete != "SET NULL" || onUpdate != "CASCADE" {
+		t.Fatalf("foreign key actions should be changed, got %v %v %v", onDelete, onUpdate, ok)
+	}
+}
+
+func TestMigrateGetConstraints(t *testing.T) {
+	type ConstraintCompany struct {
+		ID   int
+		Name string
+	}
+
+	type ConstraintUser struct {
+		ID        int
+		Email     string `gorm:"size:100;unique"`
+		Age       int    `gorm:"check:age_checker,age > 0"`
+		CompanyID *int
+		Company   ConstraintCompany `gorm:"constraint:OnDelete:CASCADE"`
+	}
+
+	DB.Migrator().DropTable(&ConstraintUser{}, &ConstraintCompany{})
+	if err := DB.AutoMigrate(&ConstraintUser{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	constraints, err := DB.Migrator().(gorm.ConstraintInspectorInterface).GetConstraints(&ConstraintUser{})
+	if err != nil {
+		t.Fatalf("failed to get constraints, got error %v", err)
+	}
+
+	types := map[string][]string{}
+	for _, constraint := range constraints {
+		types[constraint.Type] = append(types[constraint.Type], constraint.Name)
+		if constraint.Type != "CHECK" && len(constraint.Columns) != 1 {
+			t.Errorf("constraint %v should have one column, got %v", constraint.Name, constraint.Columns)
+		}
+	}
+
+	if len(types["PRIMARY KEY"]) != 1 || len(types["UNIQUE"]) != 1 || !reflect.DeepEqual(types["FOREIGN KEY"], []string{"fk_constraint_users_company"}) {
+		t.Errorf("invalid constraints, got %#v", constraints)
+	}
+
+	if DB.Dialector.Name() != "mysql" && !reflect.DeepEqual(types["CHECK"], []string{"age_checker"}) {
+		t.Errorf("check constraint should be found, got %#v", constraints)
+	}
+
+	foreignKeys, err := DB.Migrator().(gorm.ConstraintInspectorInterface).GetForeignKeys(&ConstraintUser{})
+	if err != nil || len(foreignKeys) != 1 {
+		t.Fatalf("failed to get foreign keys, got %#v, error %v", foreignKeys, err)
+	}
+
+	expected := gorm.ForeignKey{
+		Name: "fk_constraint_users_company", Columns: []string{"company_id"}, ReferencedTable: "constraint_companies",
+		ReferencedColumns: []string{"id"}, OnDelete: "CASCADE", OnUpdate: "NO ACTION",
+	}
+	if !reflect.DeepEqual(foreignKeys[0], expected) {
+		t.Errorf("invalid foreign key, expects %#v, got %#v", expected, foreignKeys[0])
+	}
+}
+
+type MigrateIndexedUser struct {
+	ID        int
+	Email     string `gorm:"index:idx_migrate_indexed_users_email,where:deleted_at IS NULL"`
+	Name      string `gorm:"index:,expression:lower(name)"`
+	DeletedAt gorm.DeletedAt
+}
+
+type MigrateIndexedUser2 struct {
+	ID        int
+	Email     string `gorm:"index:idx_migrate_indexed_users_email,where:deleted_at IS NOT NULL"`
+	Name      string `gorm:"index:idx_migrate_indexed_users_name,expression:upper(name)"`
+	DeletedAt gorm.DeletedAt
+}
+
+func (MigrateIndexedUser2) TableName() string {
+	return "migrate_indexed_users"
+}
+
+func TestMigratePartialAndExpressionIndexes(t *testing.T) {
+	DB.Migrator().DropTable(&MigrateIndexedUser{})
+	if err := DB.AutoMigrate(&MigrateIndexedUser{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	if !DB.Migrator().HasIndex(&MigrateIndexedUser{}, "idx_migrate_indexed_users_email") || !DB.Migrator().HasIndex(&MigrateIndexedUser{}, "idx_migrate_indexed_users_name") {
+		t.Fatalf("should create partial and expression indexes")
+	}
+
+	if plan, err := DB.Migrator().(gorm.MigrationPlannerInterface).Plan(&MigrateIndexedUser{}); err != nil || len(plan.Statements) != 0 {
+		t.Fatalf("should not recreate unchanged indexes, got %v, error %v", plan.Statements, err)
+	}
+
+	plan, err := DB.Migrator().(gorm.MigrationPlannerInterface).Plan(&MigrateIndexedUser2{})
+	if err != nil || len(plan.Statements) != 4 {
+		t.Fatalf("should recreate changed indexes, got %v, error %v", plan.Statements, err)
+	}
+
+	if err := DB.AutoMigrate(&MigrateIndexedUser2{}); err != nil {
+		t.Fatalf("failed to migrate changed indexes, got error %v", err)
+	}
+
+	if plan, err := DB.Migrator().(gorm.MigrationPlannerInterface).Plan(&MigrateIndexedUser2{}); err != nil || len(plan.Statements) != 0 {
+		t.Fatalf("should not recreate migrated indexes, got %v, error %v", plan.Statements, err)
+	}
+}
+
+type MigrateCoveringIndex struct {
+	ID    int
+	Email string `gorm:"index:idx_migrate_covering_email,include:name,with:fillfactor=70"`
+	Name  string
+}
+
+func TestMigrateCoveringIndexes(t *testing.T) {
+	if DB.Dialector.Name() != "postgres" && DB.Dialector.Name() != "sqlserver" {
+		t.Skip("INCLUDE columns are only supported by postgres and sqlserver")
+	}
+
+	DB.Migrator().DropTable(&MigrateCoveringIndex{})
+	if err := DB.AutoMigrate(&MigrateCoveringIndex{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	if !DB.Migrator().HasIndex(&MigrateCoveringIndex{}, "idx_migrate_covering_email") {
+		t.Fatalf("should create covering index")
+	}
+
+	if DB.Dialector.Name() == "postgres" {
+		if plan, err := DB.Migrator().(gorm.MigrationPlannerInterface).Plan(&MigrateCoveringIndex{}); err != nil || len(plan.Statements) != 0 {
+			t.Fatalf("should not recreate unchanged covering index, got %v, error %v", plan.Statements, err)
+		}
+	}
+}
+
+type MigrateConcurrentIndex struct {
+	ID   int
+	Name string `gorm:"index"`
+	Age  int    `gorm:"index:idx_migrate_concurrent_age,where:age > 0"`
+}
+
+func TestMigrateConcurrentIndexes(t *testing.T) {
+	tx := DB.Session(&gorm.Session{ConcurrentIndexes: true})
+	tx.Migrator().DropTable(&MigrateConcurrentIndex{})
+
+	if err := tx.AutoMigrate(&MigrateConcurrentIndex{}); err != nil {
+		t.Fatalf("failed to migrate with concurrent indexes, got error %v", err)
+	}
+
+	if !tx.Migrator().HasIndex(&MigrateConcurrentIndex{}, "Name") || !tx.Migrator().HasIndex(&MigrateConcurrentIndex{}, "idx_migrate_concurrent_age") {
+		t.Fatalf("should create indexes concurrently")
+	}
+
+	if DB.Dialector.Name() == "postgres" {
+		tx.Migrator().DropIndex(&MigrateConcurrentIndex{}, "Name")
+		err := tx.Transaction(func(tx *gorm.DB) error {
+			return tx.Migrator().AutoMigrate(&MigrateConcurrentIndex{})
+		})
+
+		if !errors.Is(err, gorm.ErrInvalidTransaction) {
+			t.Fatalf("should not create indexes concurrently in transaction, got %v", err)
+		}
+	}
+}
+
+type indexRecordingDialector struct {
+	gorm.Dialector
+	indexes *[]string
+}
+
+func (d indexRecordingDialector) Migrator(db *gorm.DB) gorm.Migrator {
+	return indexRecordingMigrator{Migrator: d.Dialector.Migrator(db), indexes: d.indexes}
+}
+
+type indexRecordingMigrator struct {
+	gorm.Migrator
+	indexes *[]string
+}
+
+func (m indexRecordingMigrator) BuildIndexOptions(opts []schema.IndexOption, stmt *gorm.Statement) []interface{} {
+	return m.Migrator.(migrator.BuildIndexOptionsInterface).BuildIndexOptions(opts, stmt)
+}
+
+func (m indexRecordingMigrator) CreateIndex(value interface{}, name string) error {
+	*m.indexes = append(*m.indexes, "create:"+name)
+	return m.Migrator.CreateIndex(value, name)
+}
+
+func (m indexRecordingMigrator) DropIndex(value interface{}, name string) error {
+	*m.indexes = append(*m.indexes, "drop:"+name)
+	return m.Migrator.DropIndex(value, name)
+}
+
+func TestMigrateIndexesWithDialectorMigrator(t *testing.T) {
+	var indexes []string
+	db, _ := gorm.Open(indexRecordingDialector{Dialector: DB.Dialector, indexes: &indexes}, &gorm.Config{ConcurrentIndexes: true})
+	db.Migrator().DropTable(&MigrateConcurrentIndex{})
+	db.Exec("CREATE TABLE migrate_concurrent_indices (id integer, name varchar(100), age integer)")
+	db.Exec("CREATE INDEX idx_migrate_concurrent_age ON migrate_concurrent_indices (age)")
+
+	if err := db.AutoMigrate(&MigrateConcurrentIndex{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	// index with changed predicate is recreated, indexes of schema are migrated in any order
+	var ageIndexes, nameIndexes []string
+	for _, index := range indexes {
+		if strings.HasSuffix(index, "_age") {
+			ageIndexes = append(ageIndexes, index)
+		} else {
+			nameIndexes = append(nameIndexes, index)
+		}
+	}
+
+	if !reflect.DeepEqual(ageIndexes, []string{"drop:idx_migrate_concurrent_age", "create:idx_migrate_concurrent_age"}) ||
+		!reflect.DeepEqual(nameIndexes, []string{"create:idx_migrate_concurrent_indices_name"}) {
+		t.Errorf("indexes should be created and dropped with migrator of dialector, got %v", indexes)
+	}
+}
+
+type MigrateTriggerUser struct {
+	ID   int
+	Name string
+}
+
+type MigrateTriggerAudit struct {
+	ID   int
+	Name string
+}
+
+func (MigrateTriggerUser) Triggers() []gorm.Trigger {
+	return []gorm.Trigger{{
+		Name:   "trg_migrate_trigger_users_audit",
+		Timing: "AFTER",
+		Event:  "INSERT",
+		Body:   "INSERT INTO migrate_trigger_audits (name) VALUES (NEW.name);",
+		Bodies: map[string]string{"sqlserver": "INSERT INTO migrate_trigger_audits (name) SELECT name FROM inserted;"},
+	}}
+}
+
+func TestMigrateTriggers(t *testing.T) {
+	DB.Migrator().DropTable(&MigrateTriggerUser{}, &MigrateTriggerAudit{})
+	if err := DB.AutoMigrate(&MigrateTriggerAudit{}, &MigrateTriggerUser{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	if !DB.Migrator().(gorm.TriggerMigratorInterface).HasTrigger(&MigrateTriggerUser{}, "trg_migrate_trigger_users_audit") {
+		t.Fatalf("should create triggers of model")
+	}
+
+	if err := DB.AutoMigrate(&MigrateTriggerUser{}); err != nil {
+		t.Fatalf("should not create existing triggers, got error %v", err)
+	}
+
+	DB.Create(&MigrateTriggerUser{Name: "trigger"})
+	var audit MigrateTriggerAudit
+	if err := DB.First(&audit, "name = ?", "trigger").Error; err != nil {
+		t.Fatalf("trigger should be executed, got error %v", err)
+	}
+
+	if err := DB.Migrator().(gorm.TriggerMigratorInterface).DropTrigger(&MigrateTriggerUser{}, "trg_migrate_trigger_users_audit"); err != nil {
+		t.Fatalf("failed to drop trigger, got error %v", err)
+	}
+
+	if DB.Migrator().(gorm.TriggerMigratorInterface).HasTrigger(&MigrateTriggerUser{}, "trg_migrate_trigger_users_audit") {
+		t.Fatalf("trigger should be dropped")
+	}
+
+	if err := DB.Migrator().(gorm.TriggerMigratorInterface).CreateTrigger(&MigrateTriggerUser{}, gorm.Trigger{
+		Name: "trg_migrate_trigger_users_delete", Timing: "AFTER", Event: "DELETE",
+		Body:   "DELETE FROM migrate_trigger_audits WHERE name = OLD.name;",
+		Bodies: map[string]string{"sqlserver": "DELETE FROM migrate_trigger_audits WHERE name IN (SELECT name FROM deleted);"},
+	}); err != nil {
+		t.Fatalf("failed to create trigger, got error %v", err)
+	}
+
+	DB.Where("name = ?", "trigger").Delete(&MigrateTriggerUser{})
+	if err := DB.First(&audit, "name = ?", "trigger").Error; !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("trigger of delete should be executed, got %v", err)
+	}
+}
+
+type UnusedParent struct {
+	ID       uint
+	Name     string
+	Children []UnusedChild `gorm:"foreignKey:ParentID"`
+}
+
+type UnusedChild struct {
+	ID       uint
+	ParentID uint
+	Name     string
+	Age      int
+}
+
+type UnusedChildWithoutAge struct {
+	ID       uint
+	ParentID uint
+	Name     string
+}
+
+func (UnusedChildWithoutAge) TableName() string {
+	return "unused_children"
+}
+
+func TestMigrateDropUnusedParentChild(t *testing.T) {
+	DB.Migrator().DropTable(&UnusedChild{}, &UnusedParent{})
+	if err := DB.AutoMigrate(&UnusedParent{}, &UnusedChild{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	if !DB.Migrator().HasConstraint("unused_children", "fk_unused_parents_children") {
+		t.Fatalf("foreign key of has-many relationship should be created")
+	}
+
+	// schema of parent is not parsed by new db, the foreign key is not declared by the child
+	db, _ := gorm.Open(DB.Dialector, &gorm.Config{DropUnusedWhenMigrating: true})
+	if err := db.AutoMigrate(&UnusedChildWithoutAge{}); err != nil {
+		t.Fatalf("failed to migrate child, got error %v", err)
+	}
+
+	if DB.Migrator().HasColumn(&UnusedChild{}, "age") {
+		t.Errorf("unused column of child should be dropped")
+	}
+
+	if !DB.Migrator().HasConstraint("unused_children", "fk_unused_parents_children") {
+		t.Errorf("foreign key declared by parent should not be dropped when migrating child")
+	}
+}
@@ -0,0 +1,109 @@
+package tests_test
+
+import (
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func TestMigrations(t *testing.T) {
+	type MigrationUser struct {
+		ID   uint
+		Name string
+	}
+
+	DB.Migrator().DropTable(&MigrationUser{}, gorm.DefaultMigrationsTable)
+
+	migrations := DB.Migrations(&gorm.Migration{
+		ID:      "202101031000_add_migration_users_name_index",
+		UpSQL:   "CREATE INDEX idx_migration_users_name ON migration_users(name)",
+		DownSQL: "DROP INDEX idx_migration_users_name",
+	}, &gorm.Migration{
+		ID:   "202101021504_create_migration_users",
+		Up:   func(tx *gorm.DB) error { return tx.Migrator().CreateTable(&MigrationUser{}) },
+		Down: func(tx *gorm.DB) error { return tx.Migrator().DropTable(&MigrationUser{}) },
+	})
+
+	if err := migrations.Register(&gorm.Migration{ID: "202101021504_create_migration_users"}).Error; !errors.Is(err, gorm.ErrRegistered) {
+		t.Fatalf("should returns ErrRegistered for duplicated migration, got %v", err)
+	}
+	migrations.Error = nil
+
+	if err := migrations.Register(&gorm.Migration{ID: "202101040000_empty", UpSQL: " "}).Error; !errors.Is(err, gorm.ErrInvalidData) {
+		t.Fatalf("should returns ErrInvalidData for migration without Up or UpSQL, got %v", err)
+	}
+	migrations.Error = nil
+
+	if len(migrations.Registered()) != 2 {
+		t.Fatalf("migration without Up or UpSQL should not be registered, got %v", len(migrations.Registered()))
+	}
+
+	if err := migrations.To("202101021504_create_migration_users"); err != nil {
+		t.Fatalf("failed to migrate to version, got error %v", err)
+	}
+
+	if !DB.Migrator().HasTable(&MigrationUser{}) || DB.Migrator().HasIndex(&MigrationUser{}, "idx_migration_users_name") {
+		t.Fatalf("should only apply migrations until version")
+	}
+
+	if err := migrations.Up(); err != nil {
+		t.Fatalf("failed to migrate up, got error %v", err)
+	}
+
+	if !DB.Migrator().HasIndex(&MigrationUser{}, "idx_migration_users_name") {
+		t.Fatalf("should apply pending migrations")
+	}
+
+	applied, err := migrations.Applied()
+	if err != nil || len(applied) != 2 || applied[0].ID != "202101021504_create_migration_users" || applied[1].AppliedAt.IsZero() {
+		t.Fatalf("failed to get applied migrations, got %+v, error %v", applied, err)
+	}
+
+	if pending, err := migrations.Pending(); err != nil || len(pending) != 0 {
+		t.Errorf("should have no pending migrations, got %+v, error %v", pending, err)
+	}
+
+	if err := migrations.Down(); err != nil {
+		t.Fatalf("failed to migrate down, got error %v", err)
+	}
+
+	if DB.Migrator().HasIndex(&MigrationUser{}, "idx_migration_users_name") {
+		t.Errorf("last migration should be rolled back")
+	}
+
+	failed := DB.Migrations(append(migrations.Registered(), &gorm.Migration{
+		ID: "202101041000_failed",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.Exec("INSERT INTO migration_users (name) VALUES (?)", "jinzhu").Error; err != nil {
+				return err
+			}
+			return errors.New("failed")
+		},
+	})...)
+
+	if err := failed.Up(); err == nil {
+		t.Fatalf("should returns error of failed migration")
+	}
+
+	var count int64
+	if DB.Model(&MigrationUser{}).Count(&count); count != 0 {
+		t.Errorf("failed migration should be rolled back, got %v records", count)
+	}
+
+	if applied, _ := failed.Applied(); len(applied) != 2 {
+		t.Errorf("migrations before failed one should be applied, got %+v", applied)
+	}
+
+	if err := failed.To("202101051000_unknown"); !errors.Is(err, gorm.ErrMigrationNotFound) {
+		t.Errorf("should returns ErrMigrationNotFound for unknown version, got %v", err)
+	}
+
+	if err := failed.To(""); err != nil {
+		t.Fatalf("failed to roll back all migrations, got error %v", err)
+	}
+
+	if DB.Migrator().HasTable(&MigrationUser{}) {
+		t.Errorf("all migrations should be rolled back")
+	}
+}
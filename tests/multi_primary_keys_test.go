@@ -2,10 +2,12 @@ package tests_test
 
 import (
 	"reflect"
+	"regexp"
 	"sort"
 	"testing"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	. "gorm.io/gorm/utils/tests"
 )
 
@@ -446,3 +448,136 @@ func TestCompositePrimaryKeysAssociations(t *testing.T) {
 
 	AssertEqual(t, book, result)
 }
+
+type TenantAuthor struct {
+	TenantID uint   `gorm:"primaryKey;autoIncrement:false"`
+	Code     string `gorm:"primaryKey"`
+	Name     string
+	Books    []TenantBook `gorm:"foreignKey:TenantID,AuthorCode;references:TenantID,Code"`
+	Tags     []TenantTag  `gorm:"many2many:tenant_author_tags"`
+}
+
+type TenantBook struct {
+	TenantID   uint   `gorm:"primaryKey;autoIncrement:false"`
+	ISBN       string `gorm:"primaryKey"`
+	AuthorCode *string
+	Title      string
+}
+
+type TenantTag struct {
+	TenantID uint   `gorm:"primaryKey;autoIncrement:false"`
+	Name     string `gorm:"primaryKey"`
+}
+
+func TestCompositePrimaryKeysAssociationMode(t *testing.T) {
+	DB.Migrator().DropTable(&TenantAuthor{}, &TenantBook{}, &TenantTag{}, "tenant_author_tags")
+	if err := DB.AutoMigrate(&TenantAuthor{}, &TenantBook{}, &TenantTag{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	authors := []TenantAuthor{
+		{TenantID: 1, Code: "jinzhu", Books: []TenantBook{{TenantID: 1, ISBN: "1"}}, Tags: []TenantTag{{TenantID: 1, Name: "go"}}},
+		{TenantID: 2, Code: "jinzhu", Books: []TenantBook{{TenantID: 2, ISBN: "1"}}, Tags: []TenantTag{{TenantID: 2, Name: "go"}}},
+	}
+	if err := DB.Create(&authors).Error; err != nil {
+		t.Fatalf("failed to create, got error %v", err)
+	}
+
+	author := authors[0]
+	if err := DB.Model(&author).Association("Books").Append(&TenantBook{TenantID: 1, ISBN: "2"}); err != nil {
+		t.Fatalf("failed to append books, got error %v", err)
+	}
+
+	if count := DB.Model(&author).Association("Books").Count(); count != 2 {
+		t.Errorf("should have 2 books, got %v", count)
+	}
+
+	if err := DB.Model(&author).Association("Books").Delete(&TenantBook{TenantID: 1, ISBN: "1"}); err != nil {
+		t.Fatalf("failed to delete book, got error %v", err)
+	}
+
+	var book TenantBook
+	if err := DB.First(&book, "tenant_id = ? AND isbn = ?", 1, "1").Error; err != nil || book.AuthorCode != nil {
+		t.Errorf("deleted book's author code should be cleared while tenant is kept, got %+v, error %v", book, err)
+	}
+
+	if err := DB.Model(&author).Association("Books").Replace(&TenantBook{TenantID: 1, ISBN: "3"}); err != nil {
+		t.Fatalf("failed to replace books, got error %v", err)
+	}
+
+	var books []TenantBook
+	DB.Model(&author).Association("Books").Find(&books)
+	if len(books) != 1 || books[0].ISBN != "3" {
+		t.Errorf("should have replaced books, got %+v", books)
+	}
+
+	if count := DB.Model(&authors[1]).Association("Books").Count(); count != 1 {
+		t.Errorf("books of other tenant should not be changed, got %v", count)
+	}
+
+	if err := DB.Model(&author).Association("Tags").Append(&TenantTag{TenantID: 1, Name: "sql"}); err != nil {
+		t.Fatalf("failed to append tags, got error %v", err)
+	}
+
+	if err := DB.Model(&author).Association("Tags").Delete(&TenantTag{TenantID: 1, Name: "go"}); err != nil {
+		t.Fatalf("failed to delete tags, got error %v", err)
+	}
+
+	if count := DB.Model(&authors).Association("Tags").Count(); count != 2 {
+		t.Errorf("should have 2 tags for all authors, got %v", count)
+	}
+
+	if err := DB.Model(&author).Association("Tags").Replace(&TenantTag{TenantID: 1, Name: "orm"}); err != nil {
+		t.Fatalf("failed to replace tags, got error %v", err)
+	}
+
+	var tags []TenantTag
+	DB.Model(&author).Association("Tags").Find(&tags)
+	if len(tags) != 1 || tags[0].Name != "orm" {
+		t.Errorf("should have replaced tags, got %+v", tags)
+	}
+
+	if count := DB.Model(&authors[1]).Association("Tags").Count(); count != 1 {
+		t.Errorf("tags of other tenant should not be changed, got %v", count)
+	}
+}
+
+func TestCompositePrimaryKeysUpsert(t *testing.T) {
+	DB.Migrator().DropTable("tenant_author_tags", &TenantTag{})
+	if err := DB.AutoMigrate(&TenantTag{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	tags := []TenantTag{{TenantID: 1, Name: "go"}, {TenantID: 2, Name: "go"}}
+	if err := DB.Create(&tags).Error; err != nil {
+		t.Fatalf("failed to create, got error %v", err)
+	}
+
+	stmt := DB.Session(&gorm.Session{DryRun: true}).Clauses(clause.OnConflict{UpdateAll: true}).Create(&TenantAuthor{TenantID: 1, Code: "jinzhu", Name: "jinzhu"}).Statement
+	if sql := stmt.SQL.String(); !regexp.MustCompile("ON CONFLICT \\(.tenant_id.,.code.\\) DO UPDATE SET .name.").MatchString(sql) {
+		t.Errorf("conflict target should be composite primary keys, got %v", sql)
+	}
+
+	var tag TenantTag
+	if err := DB.FirstOrCreate(&tag, TenantTag{TenantID: 2, Name: "go"}).Error; err != nil {
+		t.Fatalf("failed to first or create, got error %v", err)
+	}
+
+	var tag2 TenantTag
+	if err := DB.FirstOrCreate(&tag2, TenantTag{TenantID: 3, Name: "go"}).Error; err != nil || tag2.TenantID != 3 {
+		t.Fatalf("failed to first or create, got %+v, error %v", tag2, err)
+	}
+
+	var count int64
+	if DB.Model(&TenantTag{}).Count(&count); count != 3 {
+		t.Errorf("should have 3 tags, got %v", count)
+	}
+
+	if err := DB.Delete(&tags).Error; err != nil {
+		t.Fatalf("failed to delete with composite primary keys, got error %v", err)
+	}
+
+	if DB.Model(&TenantTag{}).Count(&count); count != 1 {
+		t.Errorf("should have 1 tag after deleting, got %v", count)
+	}
+}
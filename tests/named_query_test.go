@@ -0,0 +1,111 @@
+package tests_test
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"gorm.io/gorm"
+	. "gorm.io/gorm/utils/tests"
+)
+
+var namedQueriesFS = fstest.MapFS{
+	"queries/users.sql": {Data: []byte(`
+-- name: find_users_older_than :many
+-- users older than age
+-- ordered by age
+SELECT * FROM users
+-- @admin users are not excluded
+WHERE name LIKE @prefix AND age > @age AND name <> 'x@example.com'
+ORDER BY age;
+
+-- name: find_user :one
+SELECT * FROM users WHERE name = @name AND deleted_at IS NULL
+
+-- name: set_user_age :exec
+UPDATE users SET age = @age WHERE name = @name;
+`)},
+	"queries/count.sql": {Data: []byte(`
+-- name: count_users
+SELECT count(*) FROM users WHERE name LIKE ?
+`)},
+}
+
+func TestNamedQueries(t *testing.T) {
+	queries, err := gorm.LoadQueries(namedQueriesFS, "queries/*.sql")
+	if err != nil {
+		t.Fatalf("failed to load queries, got %v", err)
+	}
+
+	var unloaded *gorm.Queries
+	if names := unloaded.Names(); len(names) != 0 {
+		t.Errorf("queries should be empty if not loaded, got %v", names)
+	}
+
+	if names := queries.Names(); len(names) != 4 || names[0] != "count_users" || names[3] != "set_user_age" {
+		t.Errorf("queries should be loaded, got %v", names)
+	}
+
+	if query := queries.Get("find_users_older_than"); query == nil || query.Doc != "users older than age\nordered by age" ||
+		query.Result != gorm.QueryMany || len(query.Params) != 2 || query.Params[0] != "prefix" || query.Params[1] != "age" || query.File != "queries/users.sql" {
+		t.Errorf("failed to parse query, got %#v", query)
+	}
+
+	if query := queries.Get("count_users"); query == nil || query.Result != gorm.QueryMany || len(query.Params) != 0 {
+		t.Errorf("failed to parse query without result, got %#v", query)
+	}
+
+	db, _ := gorm.Open(DB.Dialector, &gorm.Config{Queries: queries})
+	users := []*User{GetUser("named_query_1", Config{}), GetUser("named_query_2", Config{}), GetUser("named_query_3", Config{})}
+	users[0].Age, users[1].Age, users[2].Age = 30, 10, 20
+	db.Create(&users)
+
+	var results []User
+	if err := db.NamedQuery("find_users_older_than", map[string]interface{}{"prefix": "named_query%", "age": 15}).Find(&results).Error; err != nil ||
+		len(results) != 2 || results[0].Name != "named_query_3" || results[1].Name != "named_query_1" {
+		t.Errorf("failed to find with named query, got %v, %v", err, results)
+	}
+
+	var result User
+	if err := db.NamedQuery("find_user", sql.Named("name", "named_query_2")).Scan(&result).Error; err != nil || result.ID != users[1].ID {
+		t.Errorf("failed to scan with named query, got %v, %v", err, result)
+	}
+
+	if err := db.NamedQuery("find_user", sql.Named("name", "no such user")).Find(&User{}).Error; !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("query of one should return ErrRecordNotFound, got %v", err)
+	}
+
+	if tx := db.NamedQuery("set_user_age", map[string]interface{}{"name": "named_query_2", "age": 40}); tx.Error != nil || tx.RowsAffected != 1 {
+		t.Errorf("failed to execute named query, got %v, %v", tx.Error, tx.RowsAffected)
+	}
+
+	var count int64
+	if err := db.NamedQuery("count_users", "named_query%").Scan(&count).Error; err != nil || count != 3 {
+		t.Errorf("failed to count with named query, got %v, %v", err, count)
+	}
+
+	if err := db.NamedQuery("find_users_older_than", map[string]interface{}{"age": 15}).Find(&results).Error; !errors.Is(err, gorm.ErrInvalidData) {
+		t.Errorf("missing params should return ErrInvalidData, got %v", err)
+	}
+
+	if err := db.NamedQuery("set_user_age", sql.Named("age", 1)).Error; !errors.Is(err, gorm.ErrInvalidData) {
+		t.Errorf("statement with missing params should not be executed, got %v", err)
+	}
+
+	if err := db.NamedQuery("unknown").Find(&results).Error; !errors.Is(err, gorm.ErrNamedQueryNotFound) {
+		t.Errorf("should return ErrNamedQueryNotFound, got %v", err)
+	}
+
+	if db.Where("name = ?", "named_query_2").First(&result); result.Age != 40 {
+		t.Errorf("age should be updated by named query only, got %v", result.Age)
+	}
+
+	if _, err := gorm.LoadQueries(fstest.MapFS{"a.sql": {Data: []byte("-- name: a\nSELECT 1\n-- name: a\nSELECT 2")}}, "*.sql"); !errors.Is(err, gorm.ErrRegistered) {
+		t.Errorf("duplicated queries should return ErrRegistered, got %v", err)
+	}
+
+	if _, err := gorm.LoadQueries(fstest.MapFS{"a.sql": {Data: []byte("-- name: a :all\nSELECT 1")}}, "*.sql"); !errors.Is(err, gorm.ErrInvalidData) {
+		t.Errorf("invalid result should return ErrInvalidData, got %v", err)
+	}
+}
@@ -0,0 +1,135 @@
+package tests_test
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+type NamespacedAccount struct {
+	ID   uint
+	Name string `gorm:"index"`
+}
+
+func (NamespacedAccount) TableNamespace() string {
+	return "ns"
+}
+
+type NamespacedCustomer struct {
+	ID   uint
+	Name string `gorm:"index"`
+}
+
+func TestParseNamespace(t *testing.T) {
+	s, err := schema.Parse(&NamespacedAccount{}, &sync.Map{}, schema.NamingStrategy{})
+	if err != nil {
+		t.Fatalf("failed to parse schema, got error %v", err)
+	}
+
+	if s.Namespace != "ns" || s.Table != "namespaced_accounts" {
+		t.Errorf("invalid namespace or table, got %v.%v", s.Namespace, s.Table)
+	}
+
+	stmt := &gorm.Statement{DB: DB}
+	if err := stmt.Parse(&NamespacedAccount{}); err != nil {
+		t.Fatalf("failed to parse statement, got error %v", err)
+	}
+
+	if quoted := stmt.Quote(clause.Table{Name: clause.CurrentTable}); quoted != stmt.Quote("ns.namespaced_accounts") {
+		t.Errorf("current table should be qualified with namespace, got %v", quoted)
+	}
+
+	result := DB.Session(&gorm.Session{DryRun: true, Namespace: "tenant1"}).Where("name = ?", "jinzhu").Find(&[]NamespacedCustomer{})
+	if expected := DB.Statement.Quote("tenant1.namespaced_customers"); !strings.Contains(result.Statement.SQL.String(), "FROM "+expected) {
+		t.Errorf("table should be qualified with session namespace, got %v", result.Statement.SQL.String())
+	}
+}
+
+func TestMigrateNamespace(t *testing.T) {
+	if DB.Dialector.Name() != "sqlite" {
+		t.Skip("schemas are attached databases for sqlite")
+	}
+
+	sqlDB, err := sql.Open("sqlite3", filepath.Join(os.TempDir(), "gorm.db"))
+	if err != nil {
+		t.Fatalf("failed to open database, got error %v", err)
+	}
+	defer sqlDB.Close()
+
+	// databases are attached to connections
+	conn, err := sqlDB.Conn(context.Background())
+	if err != nil {
+		t.Fatalf("failed to get connection, got error %v", err)
+	}
+	defer conn.Close()
+
+	db, err := gorm.Open(sqlite.Dialector{Conn: conn}, &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open database, got error %v", err)
+	}
+
+	file := filepath.Join(os.TempDir(), "gorm_ns.db")
+	os.Remove(file)
+	defer os.Remove(file)
+
+	if err := db.Exec("ATTACH DATABASE ? AS ns", file).Error; err != nil {
+		t.Fatalf("failed to attach database, got error %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := db.AutoMigrate(&NamespacedAccount{}); err != nil {
+			t.Fatalf("failed to migrate, got error %v", err)
+		}
+
+		if err := db.Session(&gorm.Session{Namespace: "ns"}).AutoMigrate(&NamespacedCustomer{}); err != nil {
+			t.Fatalf("failed to migrate with session namespace, got error %v", err)
+		}
+	}
+
+	if db.Migrator().HasTable(&NamespacedCustomer{}) {
+		t.Errorf("table should be created in namespace ns")
+	}
+
+	if tableType, err := db.Migrator().(gorm.TableInspectorInterface).TableType("ns.namespaced_customers"); err != nil || tableType.Schema() != "ns" {
+		t.Errorf("table ns.namespaced_customers should exist, got error %v", err)
+	}
+
+	tableType, err := db.Migrator().(gorm.TableInspectorInterface).TableType(&NamespacedAccount{})
+	if err != nil || tableType.Schema() != "ns" || tableType.Name() != "namespaced_accounts" || tableType.Type() != "BASE TABLE" {
+		t.Errorf("invalid table type, got %#v, error %v", tableType, err)
+	}
+
+	tables, err := db.Session(&gorm.Session{Namespace: "ns"}).Migrator().(gorm.TableInspectorInterface).GetTables()
+	if err != nil || len(tables) != 2 || tables[0] != "namespaced_accounts" || tables[1] != "namespaced_customers" {
+		t.Errorf("invalid tables of namespace, got %v, error %v", tables, err)
+	}
+
+	db.Exec("CREATE TABLE IF NOT EXISTS namespaced_members (id integer, account_id integer)")
+	db.Exec("CREATE TABLE ns.namespaced_members (id integer, account_id integer, CONSTRAINT fk_namespaced_members_account FOREIGN KEY (account_id) REFERENCES namespaced_accounts(id))")
+	if foreignKeys, err := db.Migrator().(gorm.ConstraintInspectorInterface).GetForeignKeys("ns.namespaced_members"); err != nil || len(foreignKeys) != 1 || foreignKeys[0].Name != "fk_namespaced_members_account" {
+		t.Errorf("foreign keys should be looked up in namespace, got %#v, error %v", foreignKeys, err)
+	}
+
+	if foreignKeys, err := db.Migrator().(gorm.ConstraintInspectorInterface).GetForeignKeys("namespaced_members"); err != nil || len(foreignKeys) != 0 {
+		t.Errorf("foreign keys of namespace should not be found in main database, got %#v, error %v", foreignKeys, err)
+	}
+
+	account := NamespacedAccount{Name: "jinzhu"}
+	if err := db.Create(&account).Error; err != nil {
+		t.Fatalf("failed to create, got error %v", err)
+	}
+
+	var result NamespacedAccount
+	if err := db.Where("name = ?", "jinzhu").First(&result).Error; err != nil || result.ID != account.ID {
+		t.Errorf("failed to find, got %#v, error %v", result, err)
+	}
+}
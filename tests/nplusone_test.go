@@ -0,0 +1,54 @@
+package tests_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/nplusone"
+	. "gorm.io/gorm/utils/tests"
+)
+
+func TestNPlusOneDetector(t *testing.T) {
+	db, err := gorm.Open(DB.Dialector, &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open database, got error %v", err)
+	}
+
+	var reports []nplusone.Report
+	if err := db.Use(nplusone.New(nplusone.Config{Threshold: 3, Reporter: func(ctx context.Context, report nplusone.Report) {
+		reports = append(reports, report)
+	}})); err != nil {
+		t.Fatalf("failed to use plugin, got error %v", err)
+	}
+
+	users := []User{*GetUser("nplusone_1", Config{}), *GetUser("nplusone_2", Config{}), *GetUser("nplusone_3", Config{}),
+		*GetUser("nplusone_4", Config{}), *GetUser("nplusone_5", Config{})}
+	db.Create(&users)
+
+	for _, user := range users {
+		db.First(&User{}, user.ID)
+	}
+
+	if len(reports) != 0 {
+		t.Fatalf("queries without scope should not be tracked, got %v", reports)
+	}
+
+	ctx := nplusone.WithScope(context.Background())
+	for i := 0; i < 3; i++ {
+		db.WithContext(ctx).First(&User{}, users[0].ID)
+	}
+
+	if len(reports) != 0 {
+		t.Fatalf("queries with the same value should not be reported, got %v", reports)
+	}
+
+	for _, user := range users {
+		db.WithContext(ctx).First(&User{}, user.ID)
+	}
+
+	if len(reports) != 1 || reports[0].Count != 4 || len(reports[0].CallSites) != 2 || !strings.Contains(reports[0].CallSites[1], "nplusone_test.go") {
+		t.Fatalf("N+1 query should be reported once, got %#v", reports)
+	}
+}
@@ -0,0 +1,92 @@
+package tests_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+	. "gorm.io/gorm/utils/tests"
+)
+
+type PartitionEvent struct {
+	ID        int       `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"index"`
+	CreatedAt time.Time `gorm:"primaryKey;partition:range"`
+}
+
+func TestPartitionSpec(t *testing.T) {
+	s, err := schema.Parse(&PartitionEvent{}, &sync.Map{}, schema.NamingStrategy{})
+	if err != nil {
+		t.Fatalf("failed to parse partitioned model, got error %v", err)
+	}
+
+	if s.Partition == nil || s.Partition.Type != schema.PartitionRange || len(s.Partition.Columns) != 1 || s.Partition.Columns[0] != "created_at" {
+		t.Fatalf("invalid partition spec, got %+v", s.Partition)
+	}
+
+	if s, err := schema.Parse(&User{}, &sync.Map{}, schema.NamingStrategy{}); err != nil || s.Partition != nil {
+		t.Fatalf("user should not be partitioned, got %+v, error %v", s.Partition, err)
+	}
+}
+
+func TestPartitions(t *testing.T) {
+	january := time.Date(2021, 1, 15, 0, 0, 0, 0, time.UTC)
+	february := time.Date(2021, 2, 15, 0, 0, 0, 0, time.UTC)
+	DB.Migrator().(gorm.PartitionMigratorInterface).DropPartition(&PartitionEvent{}, "partition_events_2021_01")
+	DB.Migrator().(gorm.PartitionMigratorInterface).DropPartition(&PartitionEvent{}, "partition_events_2021_02")
+	DB.Migrator().DropTable(&PartitionEvent{})
+
+	if err := DB.AutoMigrate(&PartitionEvent{}); err != nil {
+		t.Fatalf("failed to migrate partitioned table, got error %v", err)
+	}
+
+	if partition := gorm.MonthlyPartition("partition_events", january); partition.Name != "partition_events_2021_01" ||
+		partition.To.(time.Time).Sub(partition.From.(time.Time)) != 31*24*time.Hour {
+		t.Fatalf("invalid monthly partition, got %+v", partition)
+	}
+
+	if err := DB.Migrator().(gorm.PartitionMigratorInterface).CreatePartition(&PartitionEvent{}, gorm.MonthlyPartition("partition_events", february)); err != nil {
+		t.Fatalf("failed to create partition, got error %v", err)
+	}
+
+	if !DB.Migrator().(gorm.PartitionMigratorInterface).HasPartition(&PartitionEvent{}, "partition_events_2021_02") || DB.Migrator().(gorm.PartitionMigratorInterface).HasPartition(&PartitionEvent{}, "partition_events_2021_01") {
+		t.Fatalf("should only create partition of february")
+	}
+
+	tx := DB.Session(&gorm.Session{PartitionRouter: &gorm.MonthlyPartitionRouter{Field: "CreatedAt", AutoCreate: true}})
+	events := []PartitionEvent{{ID: 1, Name: "january", CreatedAt: january}, {ID: 2, Name: "january", CreatedAt: january}}
+	if err := tx.Create(&events).Error; err != nil {
+		t.Fatalf("failed to create events, got error %v", err)
+	}
+
+	if err := tx.Create(&PartitionEvent{ID: 3, Name: "february", CreatedAt: february}).Error; err != nil {
+		t.Fatalf("failed to create event, got error %v", err)
+	}
+
+	if !DB.Migrator().(gorm.PartitionMigratorInterface).HasPartition(&PartitionEvent{}, "partition_events_2021_01") {
+		t.Fatalf("partition should be created on demand")
+	}
+
+	var count int64
+	if DB.Table("partition_events_2021_01").Count(&count); count != 2 {
+		t.Fatalf("events should be routed to partition of january, got %v", count)
+	}
+
+	if DB.Table("partition_events_2021_02").Where("name = ?", "february").Count(&count); count != 1 {
+		t.Fatalf("event should be routed to partition of february, got %v", count)
+	}
+
+	if err := tx.Delete(&events[0]).Error; err != nil {
+		t.Fatalf("failed to delete event, got error %v", err)
+	}
+
+	if DB.Table("partition_events_2021_01").Count(&count); count != 1 {
+		t.Fatalf("event should be deleted from partition, got %v", count)
+	}
+
+	if err := DB.Migrator().(gorm.PartitionMigratorInterface).DropPartition(&PartitionEvent{}, "partition_events_2021_02"); err != nil || DB.Migrator().(gorm.PartitionMigratorInterface).HasPartition(&PartitionEvent{}, "partition_events_2021_02") {
+		t.Fatalf("failed to drop partition, got error %v", err)
+	}
+}
@@ -0,0 +1,59 @@
+package tests_test
+
+import (
+	"testing"
+
+	"gorm.io/gorm"
+	. "gorm.io/gorm/utils/tests"
+)
+
+func TestPlanCache(t *testing.T) {
+	db, _ := gorm.Open(DB.Dialector, &gorm.Config{PlanCache: true})
+
+	users := []User{*GetUser("plan_cache_1", Config{}), *GetUser("plan_cache_2", Config{}), *GetUser("plan_cache_3", Config{})}
+	users[1].Age, users[2].Age = 20, 30
+	db.Create(&users)
+	db.Delete(&users[2])
+
+	for _, user := range users[:2] {
+		for i := 0; i < 2; i++ {
+			var result User
+			if err := db.Where("name = ?", user.Name).Where(&User{Age: user.Age}).Order("id").Limit(1).Find(&result).Error; err != nil || result.ID != user.ID {
+				t.Errorf("should find user %v with cached plan, got %v, error %v", user.Name, result.ID, err)
+			}
+		}
+	}
+
+	for _, ids := range [][]uint{{users[0].ID}, {users[0].ID, users[1].ID}, {users[0].ID, users[1].ID, users[2].ID}} {
+		var results []User
+		db.Where("id IN ?", ids).Find(&results)
+		expected := len(ids)
+		if expected == 3 {
+			expected = 2 // soft deleted
+		}
+
+		if len(results) != expected {
+			t.Errorf("should find %v users of %v, got %v", expected, ids, len(results))
+		}
+	}
+
+	var count int64
+	db.Unscoped().Model(&User{}).Where("name LIKE ?", "plan_cache_%").Count(&count)
+	if count != 3 {
+		t.Errorf("should find deleted users when unscoped, got %v", count)
+	}
+
+	db.Model(&User{}).Where("name LIKE ?", "plan_cache_%").Count(&count)
+	if count != 2 {
+		t.Errorf("should not find deleted users when scoped, got %v", count)
+	}
+
+	dryRun := db.Session(&gorm.Session{DryRun: true})
+	for i := 0; i < 2; i++ {
+		stmt := dryRun.Where("name = ?", "jinzhu").Where("age > ?", i).Offset(i).Find(&User{}).Statement
+		expected := DB.Session(&gorm.Session{DryRun: true}).Where("name = ?", "jinzhu").Where("age > ?", i).Offset(i).Find(&User{}).Statement
+		if stmt.SQL.String() != expected.SQL.String() || len(stmt.Vars) != 2 || stmt.Vars[1] != i {
+			t.Errorf("cached plan should build the same SQL, expects %v, got %v %v", expected.SQL.String(), stmt.SQL.String(), stmt.Vars)
+		}
+	}
+}
@@ -10,6 +10,7 @@ import (
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
 	. "gorm.io/gorm/utils/tests"
 )
 
@@ -231,3 +232,43 @@ func TestPreloadGoroutine(t *testing.T) {
 	}
 	wg.Wait()
 }
+
+func TestPreloadWithDefinedRelationships(t *testing.T) {
+	type DefinedNote struct {
+		ID      uint
+		Content string
+		Writer  uint
+	}
+
+	type DefinedAuthor struct {
+		ID    uint
+		Name  string
+		Notes []DefinedNote
+	}
+
+	db, _ := OpenTestConnection()
+	if err := db.DefineRelationships(&DefinedAuthor{}, func(s *schema.Schema) {
+		s.DefineRelationship("Notes", "foreignKey:Writer")
+	}); err != nil {
+		t.Fatalf("failed to define relationships, got error %v", err)
+	}
+
+	db.Migrator().DropTable(&DefinedNote{}, &DefinedAuthor{})
+	if err := db.AutoMigrate(&DefinedAuthor{}, &DefinedNote{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	author := DefinedAuthor{Name: "defined", Notes: []DefinedNote{{Content: "note1"}, {Content: "note2"}}}
+	if err := db.Create(&author).Error; err != nil {
+		t.Fatalf("failed to create, got error %v", err)
+	}
+
+	var result DefinedAuthor
+	if err := db.Preload("Notes").First(&result, author.ID).Error; err != nil || len(result.Notes) != 2 || result.Notes[0].Writer != author.ID {
+		t.Errorf("failed to preload defined relationships, got %+v, error %v", result, err)
+	}
+
+	if count := db.Model(&result).Association("Notes").Count(); count != 2 {
+		t.Errorf("failed to count defined relationships, got %v", count)
+	}
+}
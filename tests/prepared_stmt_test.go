@@ -88,3 +88,40 @@ func TestPreparedStmtFromTransaction(t *testing.T) {
 	}
 	tx2.Commit()
 }
+
+func TestPreparedChain(t *testing.T) {
+	user := *GetUser("prepared_chain", Config{})
+	DB.Create(&user)
+
+	stmts := DB.Session(&gorm.Session{PrepareStmt: true}).ConnPool.(*gorm.PreparedStmtDB).Stmts
+	isPrepared := func(sql string) bool {
+		_, ok := stmts[sql]
+		return ok
+	}
+
+	var result User
+	if err := DB.Prepared(true).Where("name = ? AND age = ?", user.Name, user.Age).First(&result).Error; err != nil || result.ID != user.ID {
+		t.Fatalf("failed to query with prepared statement, got %v, error %v", result.ID, err)
+	}
+
+	stmt := DB.Session(&gorm.Session{DryRun: true}).Where("name = ? AND age = ?", user.Name, user.Age).First(&User{}).Statement
+	if !isPrepared(stmt.SQL.String()) {
+		t.Errorf("statement should be prepared with Prepared(true)")
+	}
+
+	prepareDB := DB.Session(&gorm.Session{PrepareStmt: true})
+	if err := prepareDB.Prepared(false).Where("name = ? AND birthday = ?", user.Name, user.Birthday).Find(&[]User{}).Error; err != nil {
+		t.Fatalf("failed to query without prepared statement, got error %v", err)
+	}
+
+	stmt = DB.Session(&gorm.Session{DryRun: true}).Where("name = ? AND birthday = ?", user.Name, user.Birthday).Find(&[]User{}).Statement
+	if isPrepared(stmt.SQL.String()) {
+		t.Errorf("statement shouldn't be prepared with Prepared(false)")
+	}
+
+	if err := DB.Transaction(func(tx *gorm.DB) error {
+		return tx.Prepared(true).Model(&user).Update("age", 30).Error
+	}); err != nil {
+		t.Fatalf("failed to update with prepared statement in transaction, got error %v", err)
+	}
+}
@@ -0,0 +1,40 @@
+package tests_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+	. "gorm.io/gorm/utils/tests"
+)
+
+func TestQueryError(t *testing.T) {
+	config := *DB.Config
+	config.WrapQueryErrors = true
+	db := DB.Session(&gorm.Session{NewDB: true})
+	db.Config = &config
+
+	var queryErr *gorm.QueryError
+	err := db.Table("non_existing_query_errors").Where("name = ?", "jinzhu").Find(&[]User{}).Error
+	if !errors.As(err, &queryErr) {
+		t.Fatalf("error should be wrapped with QueryError, got %v", err)
+	}
+
+	if queryErr.Operation != "query" || queryErr.Table != "non_existing_query_errors" || !strings.Contains(queryErr.SQL, "non_existing_query_errors") ||
+		strings.Contains(queryErr.SQL, "jinzhu") || queryErr.Error() != queryErr.Err.Error() {
+		t.Errorf("invalid query error, got %#v", queryErr)
+	}
+
+	if err := db.Exec("UPDATE non_existing_query_errors SET name = ?", "jinzhu").Error; !errors.As(err, &queryErr) || queryErr.Operation != "raw" {
+		t.Errorf("error of raw sql should be wrapped with QueryError, got %#v", err)
+	}
+
+	if err := db.Where("name = ?", "non_existing_query_error_user").First(&User{}).Error; err != gorm.ErrRecordNotFound {
+		t.Errorf("ErrRecordNotFound should not be wrapped, got %#v", err)
+	}
+
+	if err := DB.Table("non_existing_query_errors").Find(&[]User{}).Error; err == nil || errors.As(err, &queryErr) {
+		t.Errorf("errors should not be wrapped without WrapQueryErrors, got %#v", err)
+	}
+}
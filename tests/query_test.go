@@ -2,6 +2,7 @@ package tests_test
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"reflect"
 	"regexp"
@@ -292,6 +293,208 @@ func TestFindInBatches(t *testing.T) {
 	}
 }
 
+func TestFindInBatchesWithOrder(t *testing.T) {
+	var users = []User{
+		*GetUser("find_in_batches_order", Config{}),
+		*GetUser("find_in_batches_order", Config{}),
+		*GetUser("find_in_batches_order", Config{}),
+		*GetUser("find_in_batches_order", Config{}),
+		*GetUser("find_in_batches_order", Config{}),
+	}
+
+	for idx := range users {
+		users[idx].Age = uint(idx % 2)
+	}
+
+	DB.Create(&users)
+
+	var (
+		results []User
+		found   []User
+	)
+
+	if result := DB.Where("name = ?", users[0].Name).Order("age desc").FindInBatches(&results, 2, func(tx *gorm.DB, batch int) error {
+		// records inserted during batches should not make found records shift
+		DB.Create(GetUser("find_in_batches_order", Config{}))
+		found = append(found, results...)
+		return nil
+	}); result.Error != nil {
+		t.Errorf("Failed to batch find, got error %v", result.Error)
+	}
+
+	var expects []User
+	DB.Where("name = ? AND id <= ?", users[0].Name, users[len(users)-1].ID).Order("age desc, id").Find(&expects)
+	if len(found) < len(expects) {
+		t.Fatalf("incorrect records length, expects at least: %v, got %v", len(expects), len(found))
+	}
+
+	seen := map[uint]bool{}
+	for idx, user := range found {
+		if seen[user.ID] {
+			t.Errorf("record %v found more than once", user.ID)
+		}
+		seen[user.ID] = true
+
+		if idx < len(expects) && user.ID != expects[idx].ID {
+			t.Errorf("incorrect record order at %v, expects: %v, got %v", idx, expects[idx].ID, user.ID)
+		}
+	}
+
+}
+
+func TestFindInBatchesWithRawOrder(t *testing.T) {
+	users := []User{
+		*GetUser("find_in_batches_raw_C", Config{}),
+		*GetUser("find_in_batches_raw_a", Config{}),
+		*GetUser("find_in_batches_raw_B", Config{}),
+		*GetUser("find_in_batches_raw_d", Config{}),
+		*GetUser("find_in_batches_raw_E", Config{}),
+	}
+	DB.Create(&users)
+
+	for _, order := range []string{"LOWER(name)", "LOWER(name) desc, id"} {
+		var (
+			results []User
+			found   []string
+			batches int
+		)
+
+		if result := DB.Where("name LIKE ?", "find_in_batches_raw_%").Order(order).FindInBatches(&results, 2, func(tx *gorm.DB, batch int) error {
+			batches = batch
+			for _, user := range results {
+				found = append(found, user.Name)
+			}
+			return nil
+		}); result.Error != nil || result.RowsAffected != 5 {
+			t.Errorf("raw order %v should be batched with offset, got error %v, rows affected %v", order, result.Error, result.RowsAffected)
+		}
+
+		var expects []string
+		DB.Model(&User{}).Where("name LIKE ?", "find_in_batches_raw_%").Order(order).Pluck("name", &expects)
+		if batches != 3 || !reflect.DeepEqual(found, expects) {
+			t.Errorf("raw order %v should find records in order %v, got %v in %v batches", order, expects, found, batches)
+		}
+	}
+}
+
+func TestFindInBatchesWithoutPrimaryKey(t *testing.T) {
+	type BatchedTag struct {
+		Name string
+	}
+
+	DB.Migrator().DropTable(&BatchedTag{})
+	if err := DB.AutoMigrate(&BatchedTag{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+	DB.Create(&[]BatchedTag{{Name: "tag_a"}, {Name: "tag_b"}, {Name: "tag_c"}})
+
+	var (
+		results []BatchedTag
+		found   []string
+	)
+
+	if result := DB.Order("name").FindInBatches(&results, 2, func(tx *gorm.DB, batch int) error {
+		for _, tag := range results {
+			found = append(found, tag.Name)
+		}
+		return nil
+	}); result.Error != nil || result.RowsAffected != 3 {
+		t.Errorf("records without primary keys should be batched with offset, got error %v, rows affected %v", result.Error, result.RowsAffected)
+	}
+	AssertEqual(t, found, []string{"tag_a", "tag_b", "tag_c"})
+}
+
+func TestFindPage(t *testing.T) {
+	var users = []User{
+		*GetUser("find_page", Config{}),
+		*GetUser("find_page", Config{}),
+		*GetUser("find_page", Config{}),
+		*GetUser("find_page", Config{}),
+		*GetUser("find_page", Config{}),
+	}
+
+	DB.Create(&users)
+
+	var results []User
+	page, err := DB.Where("name = ?", "find_page").Order("id desc").FindPage(&results, gorm.Page{Number: 2, Size: 2})
+	if err != nil {
+		t.Fatalf("failed to find page, got error %v", err)
+	}
+
+	if page.Number != 2 || page.Size != 2 || page.Total != 5 || page.Pages != 3 {
+		t.Errorf("incorrect page, got %+v", page)
+	}
+
+	if len(results) != 2 || results[0].ID != users[2].ID || results[1].ID != users[1].ID {
+		t.Errorf("incorrect page records, got %+v", results)
+	}
+
+	page, err = DB.Model(&User{}).Where("name = ?", "find_page").FindPage(&results, gorm.Page{Number: 4, Size: 2})
+	if err != nil || page.Total != 5 || len(results) != 0 {
+		t.Errorf("page out of range should returns no records, got %v records, page %+v, error %v", len(results), page, err)
+	}
+
+	if _, err := DB.Where("name = ?", "find_page").FindPage(&results, gorm.Page{Number: 1}); !errors.Is(err, gorm.ErrInvalidPage) {
+		t.Errorf("should returns ErrInvalidPage when page size is zero, got %v", err)
+	}
+}
+
+func TestFindInto(t *testing.T) {
+	var users = []User{
+		*GetUser("find_into", Config{}),
+		*GetUser("find_into", Config{}),
+		*GetUser("find_into", Config{}),
+	}
+
+	DB.Create(&users)
+
+	var (
+		ch     = make(chan User)
+		result *gorm.DB
+		done   = make(chan struct{})
+	)
+
+	go func() {
+		result = DB.Where("name = ?", users[0].Name).Order("id").FindInto(ch)
+		close(done)
+	}()
+
+	var results []User
+	for user := range ch {
+		results = append(results, user)
+	}
+	<-done
+
+	if result.Error != nil || result.RowsAffected != 3 || len(results) != 3 {
+		t.Fatalf("failed to find into channel, got %v records, error %v", len(results), result.Error)
+	}
+
+	for idx, user := range results {
+		CheckUser(t, user, users[idx])
+	}
+
+	ptrCh := make(chan *User, 3)
+	if err := DB.Where("name = ?", users[0].Name).FindInto(ptrCh).Error; err != nil {
+		t.Fatalf("failed to find into channel, got error %v", err)
+	}
+
+	var count int
+	for user := range ptrCh {
+		if user.Name != users[0].Name {
+			t.Errorf("invalid user name, got %v", user.Name)
+		}
+		count++
+	}
+
+	if count != 3 {
+		t.Errorf("should find 3 records, but got %v", count)
+	}
+
+	if err := DB.FindInto(&results).Error; !errors.Is(err, gorm.ErrInvalidData) {
+		t.Errorf("should return ErrInvalidData for non channel value, but got %v", err)
+	}
+}
+
 func TestFillSmallerStruct(t *testing.T) {
 	user := User{Name: "SmallerUser", Age: 100}
 	DB.Save(&user)
@@ -701,6 +904,36 @@ func TestPluckWithSelect(t *testing.T) {
 	AssertEqual(t, userAges, []int{26, 27})
 }
 
+func TestPluckRows(t *testing.T) {
+	users := []User{
+		{Name: "pluck_rows_1", Age: 25},
+		{Name: "pluck_rows_2", Age: 26},
+	}
+
+	DB.Create(&users)
+
+	var options []struct {
+		ID   uint
+		Name string
+	}
+	if err := DB.Model(&User{}).Where("name like ?", "pluck_rows%").Order("id").PluckRows(&options, "ID", "name").Error; err != nil {
+		t.Fatalf("got error when pluck rows: %v", err)
+	}
+
+	if len(options) != 2 || options[0].ID != users[0].ID || options[0].Name != users[0].Name || options[1].Name != users[1].Name {
+		t.Errorf("failed to pluck rows into struct, got %+v", options)
+	}
+
+	var results []map[string]interface{}
+	if err := DB.Table("users").Where("name like ?", "pluck_rows%").Order("id").PluckRows(&results, "name", "age + 1 AS next_age").Error; err != nil {
+		t.Fatalf("got error when pluck rows: %v", err)
+	}
+
+	if len(results) != 2 || results[0]["name"] != users[0].Name || fmt.Sprint(results[1]["next_age"]) != "27" {
+		t.Errorf("failed to pluck rows into map, got %+v", results)
+	}
+}
+
 func TestSelectWithVariables(t *testing.T) {
 	DB.Save(&User{Name: "select_with_variables"})
 
@@ -1090,3 +1323,23 @@ func TestQueryWithTableAndConditionsAndAllFields(t *testing.T) {
 		t.Errorf("invalid query SQL, got %v", result.Statement.SQL.String())
 	}
 }
+
+func TestAllowEmpty(t *testing.T) {
+	var user User
+	if err := DB.First(&user, "name = ?", "allow_empty_not_found").Error; !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("should returns ErrRecordNotFound, but got %v", err)
+	}
+
+	result := DB.AllowEmpty().First(&user, "name = ?", "allow_empty_not_found")
+	if result.Error != nil || result.RowsAffected != 0 {
+		t.Errorf("should returns no error with empty result, but got %v, rows affected %v", result.Error, result.RowsAffected)
+	}
+
+	if err := DB.Session(&gorm.Session{AllowEmptyResult: true}).Last(&user, "name = ?", "allow_empty_not_found").Error; err != nil {
+		t.Errorf("should returns no error with empty result, but got %v", err)
+	}
+
+	if err := DB.Take(&user, "name = ?", "allow_empty_not_found").Error; !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("AllowEmpty should not affect other sessions, but got %v", err)
+	}
+}
@@ -0,0 +1,65 @@
+package tests_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type SensitiveAccount struct {
+	ID       uint
+	Name     string
+	Password string `gorm:"sensitive"`
+	Token    string
+}
+
+type sqlWriter struct {
+	logs []string
+}
+
+func (w *sqlWriter) Printf(format string, args ...interface{}) {
+	w.logs = append(w.logs, fmt.Sprintf(format, args...))
+}
+
+func TestSensitiveRedaction(t *testing.T) {
+	DB.Migrator().DropTable(&SensitiveAccount{})
+	DB.AutoMigrate(&SensitiveAccount{})
+
+	var (
+		writer = &sqlWriter{}
+		config = *DB.Config
+	)
+
+	config.SensitiveColumns = []string{"token"}
+	config.Logger = logger.New(writer, logger.Config{LogLevel: logger.Info})
+	db := DB.Session(&gorm.Session{NewDB: true})
+	db.Config = &config
+
+	accounts := []SensitiveAccount{{Name: "jinzhu", Password: "secret1", Token: "token1"}, {Name: "jinzhu2", Password: "secret2", Token: "token2"}}
+	db.Create(&accounts)
+	db.Model(&accounts[0]).Updates(map[string]interface{}{"password": "secret3", "name": "jinzhu3"})
+	db.Where("password = ? AND name = ?", "secret3", "jinzhu3").First(&SensitiveAccount{})
+	db.Where(&SensitiveAccount{Password: "secret2"}).Find(&[]SensitiveAccount{})
+	db.Where("token IN ?", []string{"token1", "token2"}).Find(&[]SensitiveAccount{})
+
+	logs := strings.Join(writer.logs, "\n")
+	for _, value := range []string{"secret1", "secret2", "secret3", "token1", "token2"} {
+		if strings.Contains(logs, value) {
+			t.Errorf("sensitive value %v should be redacted, got %v", value, logs)
+		}
+	}
+
+	for _, value := range []string{"jinzhu2", "jinzhu3", gorm.RedactedValue} {
+		if !strings.Contains(logs, value) {
+			t.Errorf("value %v should be logged, got %v", value, logs)
+		}
+	}
+
+	var result SensitiveAccount
+	if err := DB.Where("password = ?", "secret3").First(&result).Error; err != nil || result.Name != "jinzhu3" {
+		t.Errorf("values should not be redacted in database, got %#v, error %v", result, err)
+	}
+}
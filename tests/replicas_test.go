@@ -0,0 +1,164 @@
+package tests_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	. "gorm.io/gorm/utils/tests"
+)
+
+type replicaConnPool struct {
+	gorm.ConnPool
+	queries int
+}
+
+func (pool *replicaConnPool) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	pool.queries++
+	return pool.ConnPool.QueryContext(ctx, query, args...)
+}
+
+func (pool *replicaConnPool) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	pool.queries++
+	return pool.ConnPool.QueryRowContext(ctx, query, args...)
+}
+
+func TestReplicas(t *testing.T) {
+	var (
+		replica = &replicaConnPool{ConnPool: DB.ConnPool}
+		lag     time.Duration
+		db, _   = gorm.Open(DB.Dialector, &gorm.Config{Replicas: &gorm.Replicas{
+			ConnPools:        []gorm.ConnPool{replica},
+			LagCheckInterval: time.Nanosecond,
+			LagCheck: func(ctx context.Context, pool gorm.ConnPool) (time.Duration, error) {
+				return lag, nil
+			},
+		}})
+		user = *GetUser("replicas", Config{})
+	)
+
+	assertQueries := func(name string, expects int) {
+		t.Helper()
+		if replica.queries != expects {
+			t.Errorf("%v: replica should be queried %v times, but got %v", name, expects, replica.queries)
+		}
+		replica.queries = 0
+	}
+
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("failed to create user, got error %v", err)
+	}
+	assertQueries("create", 0)
+
+	var result User
+	db.First(&result, user.ID)
+	assertQueries("query", 1)
+
+	var count int64
+	db.Model(&User{}).Where("name = ?", user.Name).Count(&count)
+	db.Raw("SELECT name FROM users WHERE id = ?", user.ID).Scan(&result.Name)
+	assertQueries("count and raw query", 2)
+
+	db.Primary().First(&result, user.ID)
+	assertQueries("primary", 0)
+
+	db.Prepared(true).First(&result, user.ID)
+	db.Prepared(true).Prepared(false).First(&result, user.ID)
+	assertQueries("prepared", 2)
+
+	db.Prepared(true).Transaction(func(tx *gorm.DB) error {
+		return tx.Prepared(false).First(&result, user.ID).Error
+	})
+	assertQueries("prepared transaction", 0)
+
+	db.Transaction(func(tx *gorm.DB) error {
+		return tx.First(&result, user.ID).Error
+	})
+	assertQueries("transaction", 0)
+
+	ctx := gorm.WithReadYourWrites(context.Background())
+	db.WithContext(ctx).First(&result, user.ID)
+	assertQueries("read your writes before write", 1)
+
+	db.WithContext(ctx).Model(&user).Update("age", 20)
+	db.WithContext(ctx).First(&result, user.ID)
+	assertQueries("read your writes after write", 0)
+
+	lag = time.Hour
+	db.First(&result, user.ID)
+	assertQueries("lagged replica", 0)
+
+	lag = 0
+	db.First(&result, user.ID)
+	assertQueries("caught up replica", 1)
+
+	if err := db.First(&result, user.ID).Error; err != nil || result.Age != 20 {
+		t.Errorf("failed to query from replica, got %v, error %v", result.Age, err)
+	}
+	assertQueries("replica", 1)
+
+	db.Config.Replicas.LagCheck = func(ctx context.Context, pool gorm.ConnPool) (time.Duration, error) {
+		return 0, errors.New("failed to check lag")
+	}
+	db.First(&result, user.ID)
+	assertQueries("failed lag check", 0)
+}
+
+func TestReadOnly(t *testing.T) {
+	var (
+		replica = &replicaConnPool{ConnPool: DB.ConnPool}
+		db, _   = gorm.Open(DB.Dialector, &gorm.Config{Replicas: &gorm.Replicas{ConnPools: []gorm.ConnPool{replica}}})
+		user    = *GetUser("read_only", Config{})
+	)
+
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("failed to create user, got error %v", err)
+	}
+
+	reports := db.ReadOnly()
+
+	var result User
+	if err := reports.First(&result, user.ID).Error; err != nil || result.Name != user.Name {
+		t.Fatalf("failed to query with read-only session, got %v, %#v", err, result)
+	}
+
+	var count int64
+	if err := reports.Raw("SELECT count(*) FROM users WHERE id = ?", user.ID).Scan(&count).Error; err != nil || count != 1 {
+		t.Fatalf("failed to raw query with read-only session, got %v, %v", err, count)
+	}
+
+	if replica.queries != 2 {
+		t.Errorf("queries of read-only session should be routed to replica, but got %v", replica.queries)
+	}
+
+	for name, tx := range map[string]*gorm.DB{
+		"create":     reports.Create(GetUser("read_only_create", Config{})),
+		"update":     reports.Model(&user).Update("age", 99),
+		"delete":     reports.Delete(&user),
+		"exec":       reports.Exec("DELETE FROM users WHERE id = ?", user.ID),
+		"raw":        reports.Raw("DELETE FROM users WHERE id = ?", user.ID).Scan(&result),
+		"for update": reports.Clauses(clause.Locking{Strength: "UPDATE"}).First(&result, user.ID),
+	} {
+		if !errors.Is(tx.Error, gorm.ErrReadOnlySession) {
+			t.Errorf("%v should be rejected with ErrReadOnlySession, but got %v", name, tx.Error)
+		}
+	}
+
+	if err := reports.Transaction(func(tx *gorm.DB) error {
+		return tx.Model(&user).Update("age", 99).Error
+	}); !errors.Is(err, gorm.ErrReadOnlySession) {
+		t.Errorf("updates in transaction of read-only session should be rejected, but got %v", err)
+	}
+
+	if err := db.First(&result, user.ID).Error; err != nil || result.Age != user.Age {
+		t.Errorf("user should not be changed by read-only session, got %v, %#v", err, result)
+	}
+
+	if err := db.Model(&user).Update("age", 30).Error; err != nil {
+		t.Errorf("original db should be writable, got %v", err)
+	}
+}
@@ -0,0 +1,81 @@
+package tests_test
+
+import (
+	"context"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+type RoleEmployee struct {
+	ID     uint
+	Name   string
+	Salary int
+}
+
+type roleContextKey struct{}
+
+type salaryPolicy struct{}
+
+func (salaryPolicy) CanRead(ctx context.Context, field *schema.Field) bool {
+	return field.Name != "Salary" || ctx.Value(roleContextKey{}) != nil
+}
+
+func (salaryPolicy) CanWrite(ctx context.Context, field *schema.Field) bool {
+	return field.Name != "Salary" || ctx.Value(roleContextKey{}) == "admin"
+}
+
+func TestRolePolicy(t *testing.T) {
+	DB.Migrator().DropTable(&RoleEmployee{})
+	DB.AutoMigrate(&RoleEmployee{})
+
+	config := *DB.Config
+	config.RolePolicy = salaryPolicy{}
+	db := DB.Session(&gorm.Session{NewDB: true})
+	db.Config = &config
+
+	admin := db.WithContext(context.WithValue(context.Background(), roleContextKey{}, "admin"))
+	manager := db.WithContext(context.WithValue(context.Background(), roleContextKey{}, "manager"))
+	guest := db.WithContext(context.Background())
+
+	employee := RoleEmployee{Name: "jinzhu", Salary: 100}
+	if err := admin.Create(&employee).Error; err != nil {
+		t.Fatalf("failed to create employee, got error %v", err)
+	}
+
+	guestEmployee := RoleEmployee{Name: "guest", Salary: 100}
+	guest.Create(&guestEmployee)
+
+	var result RoleEmployee
+	admin.First(&result, guestEmployee.ID)
+	if result.Salary != 0 {
+		t.Errorf("guest should not be able to write salary, got %v", result.Salary)
+	}
+
+	var results []RoleEmployee
+	guest.Order("id").Find(&results)
+	if len(results) != 2 || results[0].Name != "jinzhu" || results[0].Salary != 0 {
+		t.Errorf("guest should not be able to read salary, got %#v", results)
+	}
+
+	result = RoleEmployee{}
+	manager.First(&result, employee.ID)
+	if result.Salary != 100 {
+		t.Errorf("manager should be able to read salary, got %v", result.Salary)
+	}
+
+	manager.Model(&result).Updates(map[string]interface{}{"name": "jinzhu2", "salary": 200})
+	manager.Model(&result).Select("*").Updates(RoleEmployee{Name: "jinzhu3", Salary: 300})
+
+	admin.First(&result, employee.ID)
+	if result.Name != "jinzhu3" || result.Salary != 100 {
+		t.Errorf("manager should only be able to update name, got %#v", result)
+	}
+
+	admin.Model(&result).Update("salary", 200)
+	admin.First(&result, employee.ID)
+	if result.Salary != 200 {
+		t.Errorf("admin should be able to update salary, got %v", result.Salary)
+	}
+}
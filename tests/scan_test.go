@@ -1,10 +1,12 @@
 package tests_test
 
 import (
+	"errors"
 	"reflect"
 	"sort"
 	"strings"
 	"testing"
+	"time"
 
 	"gorm.io/gorm"
 	. "gorm.io/gorm/utils/tests"
@@ -108,3 +110,194 @@ func TestScanRows(t *testing.T) {
 		t.Fatalf("failed to scan ages, got error %v, ages: %v", err, name)
 	}
 }
+
+func TestScanWithColumnMapper(t *testing.T) {
+	user := *GetUser("ScanWithColumnMapper", Config{Company: true})
+	DB.Create(&user)
+
+	tx := DB.Session(&gorm.Session{ColumnMapper: func(column string) string {
+		switch column {
+		case "usr_nm":
+			return "Name"
+		case "usr.age":
+			return "age"
+		case "cmp_nm":
+			return "Company.Name"
+		}
+		return ""
+	}})
+
+	var result User
+	if err := tx.Raw(`SELECT users.id, users.name AS usr_nm, users.age AS "usr.age", companies.name AS cmp_nm FROM users LEFT JOIN companies ON companies.id = users.company_id WHERE users.id = ?`, user.ID).Scan(&result).Error; err != nil {
+		t.Fatalf("failed to scan with column mapper, got error %v", err)
+	}
+
+	if result.ID != user.ID || result.Name != user.Name || result.Age != user.Age || result.Company.Name != user.Company.Name {
+		t.Errorf("failed to scan with column mapper, got %+v", result)
+	}
+
+	var results []User
+	if err := tx.Raw(`SELECT users.id, users.name AS usr_nm, companies.name AS cmp_nm FROM users LEFT JOIN companies ON companies.id = users.company_id WHERE users.id = ?`, user.ID).Scan(&results).Error; err != nil {
+		t.Fatalf("failed to scan with column mapper, got error %v", err)
+	}
+
+	if len(results) != 1 || results[0].Name != user.Name || results[0].Company.Name != user.Company.Name {
+		t.Errorf("failed to scan with column mapper, got %+v", results)
+	}
+
+	rows, err := tx.Raw(`SELECT users.id, users.name AS usr_nm FROM users WHERE users.id IN ?`, []uint{user.ID, user.ID + 1}).Rows()
+	if err != nil {
+		t.Fatalf("failed to query with column mapper, got error %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row User
+		if err := tx.ScanRows(rows, &row); err != nil || row.Name != user.Name {
+			t.Errorf("failed to scan rows with column mapper, got %+v, error %v", row, err)
+		}
+
+		if columns, _ := rows.Columns(); !reflect.DeepEqual(columns, []string{"id", "usr_nm"}) {
+			t.Errorf("columns of rows should not be changed by column mapper, got %v", columns)
+		}
+	}
+}
+
+func TestScanWithNullPolicy(t *testing.T) {
+	users := []User{{Name: "ScanWithNullPolicy1", Age: 10}, {Name: "ScanWithNullPolicy2", Age: 20}}
+	DB.Create(&users)
+	DB.Model(&users[1]).Update("age", nil)
+
+	var results []User
+	if err := DB.Where("name LIKE ?", "ScanWithNullPolicy%").Order("id").Find(&results).Error; err != nil || len(results) != 2 || results[1].Age != 0 {
+		t.Fatalf("NULL should be scanned as zero value by default, got %+v, error %v", results, err)
+	}
+
+	tx := DB.Session(&gorm.Session{NullPolicy: gorm.NullAsError})
+	if err := tx.Where("name LIKE ?", "ScanWithNullPolicy%").Order("id").Find(&results).Error; !errors.Is(err, gorm.ErrNullValue) {
+		t.Errorf("should return ErrNullValue, but got %v", err)
+	}
+
+	tx = DB.Session(&gorm.Session{NullPolicy: gorm.NullTrack})
+	result := tx.Select("id", "name", "age").Where("name LIKE ?", "ScanWithNullPolicy%").Order("id").Find(&results)
+	if result.Error != nil {
+		t.Fatalf("failed to find users, got error %v", result.Error)
+	}
+
+	if !reflect.DeepEqual(result.Statement.NullFields, [][]string{nil, {"Age"}}) {
+		t.Errorf("should track NULL fields of every row, but got %#v", result.Statement.NullFields)
+	}
+
+	type NullPolicyUser struct {
+		ID   uint
+		Name string
+		Age  uint `gorm:"null:error"`
+	}
+
+	var user NullPolicyUser
+	if err := DB.Table("users").Where("id = ?", users[1].ID).Take(&user).Error; !errors.Is(err, gorm.ErrNullValue) {
+		t.Errorf("should return ErrNullValue with field tag, but got %v", err)
+	}
+}
+
+// ProtoTimestamp mocks protobuf generated *timestamppb.Timestamp
+type ProtoTimestamp struct {
+	sizeCache int32
+	Seconds   int64 `protobuf:"varint,1,opt,name=seconds,proto3" json:"seconds,omitempty"`
+	Nanos     int32 `protobuf:"varint,2,opt,name=nanos,proto3" json:"nanos,omitempty"`
+}
+
+func (x *ProtoTimestamp) AsTime() time.Time {
+	if x == nil {
+		return time.Unix(0, 0).UTC()
+	}
+	return time.Unix(x.Seconds, int64(x.Nanos)).UTC()
+}
+
+// ProtoUser mocks protobuf generated message
+type ProtoUser struct {
+	sizeCache int32
+	Id        uint64          `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserName  string          `protobuf:"bytes,2,opt,name=user_name,json=userName,proto3" json:"user_name,omitempty"`
+	CreatedAt *ProtoTimestamp `protobuf:"bytes,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	DeletedAt *ProtoTimestamp `protobuf:"bytes,4,opt,name=deleted_at,json=deletedAt,proto3" json:"deleted_at,omitempty"`
+}
+
+func TestScanIntoProtobufMessage(t *testing.T) {
+	DB.Migrator().DropTable(&ProtoUser{})
+	if err := DB.AutoMigrate(&ProtoUser{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	if !DB.Migrator().HasColumn(&ProtoUser{}, "user_name") || !DB.Migrator().HasColumn(&ProtoUser{}, "created_at") {
+		t.Fatalf("should use protobuf names as columns")
+	}
+
+	now := time.Now().Round(time.Second).UTC()
+	user := ProtoUser{UserName: "proto_user", CreatedAt: &ProtoTimestamp{Seconds: now.Unix()}}
+	if err := DB.Create(&user).Error; err != nil {
+		t.Fatalf("failed to create protobuf message, got error %v", err)
+	}
+
+	var result ProtoUser
+	if err := DB.First(&result, "user_name = ?", user.UserName).Error; err != nil {
+		t.Fatalf("failed to find protobuf message, got error %v", err)
+	}
+
+	if result.Id != user.Id || result.UserName != user.UserName || result.CreatedAt == nil || !result.CreatedAt.AsTime().Equal(now) || result.DeletedAt != nil {
+		t.Errorf("failed to scan into protobuf message, got %+v", result)
+	}
+
+	var results []ProtoUser
+	if err := DB.Raw("SELECT id, user_name AS userName, created_at AS createdAt FROM proto_users WHERE id = ?", user.Id).Scan(&results).Error; err != nil {
+		t.Fatalf("failed to scan protobuf message, got error %v", err)
+	}
+
+	if len(results) != 1 || results[0].UserName != user.UserName || results[0].CreatedAt == nil || !results[0].CreatedAt.AsTime().Equal(now) {
+		t.Errorf("failed to scan protobuf json names, got %+v", results)
+	}
+}
+
+func TestScanReusesBuffers(t *testing.T) {
+	users := []User{*GetUser("scan_buffers_1", Config{}), *GetUser("scan_buffers_2", Config{})}
+	*users[1].Birthday = users[1].Birthday.Add(time.Hour)
+	DB.Create(&users)
+
+	var first, second User
+	DB.First(&first, users[0].ID)
+	birthday := *first.Birthday
+	DB.First(&second, users[1].ID)
+
+	if !first.Birthday.Equal(birthday) || first.Birthday == second.Birthday || first.Name != users[0].Name {
+		t.Errorf("scanned values shouldn't be changed by later queries, got %v, %v", first.Birthday, second.Birthday)
+	}
+
+	var results []User
+	DB.Where("name LIKE ?", "scan_buffers_%").Order("id").Find(&results)
+	if len(results) != 2 || results[0].Birthday == results[1].Birthday || !results[1].Birthday.Equal(*second.Birthday) {
+		t.Errorf("rows should be scanned into their own values, got %+v", results)
+	}
+}
+
+func TestStatementReusesVars(t *testing.T) {
+	users := []User{*GetUser("statement_vars_1", Config{}), *GetUser("statement_vars_2", Config{})}
+	DB.Create(&users)
+
+	tx := DB.Where("name = ?", users[0].Name)
+	for i := 0; i < 2; i++ {
+		var results []User
+		if err := tx.Find(&results).Error; err != nil || len(results) != 1 || results[0].Name != users[0].Name {
+			t.Fatalf("reused statement should be executed with its vars, got %v, %+v", err, results)
+		}
+	}
+
+	var results []User
+	subQuery := DB.Model(&User{}).Select("id").Where("name = ?", users[1].Name)
+	if err := DB.Where("id IN (?) AND age = ?", subQuery, users[1].Age).Find(&results).Error; err != nil || len(results) != 1 || results[0].ID != users[1].ID {
+		t.Errorf("statement with vars of sub query should be executed with its vars, got %v, %+v", err, results)
+	}
+
+	if err := DB.Find(&results, "name IN ?", []string{users[0].Name, users[1].Name}).Error; err != nil || len(results) != 2 {
+		t.Errorf("vars released by previous statements shouldn't be referenced, got %v, %+v", err, results)
+	}
+}
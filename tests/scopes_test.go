@@ -1,6 +1,8 @@
 package tests_test
 
 import (
+	"errors"
+	"reflect"
 	"testing"
 
 	"gorm.io/gorm"
@@ -46,3 +48,72 @@ func TestScopes(t *testing.T) {
 		t.Errorf("Should found two users's name in 1, 3, but got %v", len(users3))
 	}
 }
+
+func TestRegisteredScopes(t *testing.T) {
+	db, _ := gorm.Open(DB.Dialector, &gorm.Config{})
+	users := []*User{
+		GetUser("RegisteredScopeUser1", Config{}),
+		GetUser("RegisteredScopeUser2", Config{}),
+		GetUser("RegisteredScopeUser3", Config{}),
+	}
+	users[0].Age, users[1].Age, users[2].Age = 10, 20, 30
+	db.Create(&users)
+
+	if err := db.RegisterScope("registered", func(db *gorm.DB) *gorm.DB {
+		return db.Where("name LIKE ?", "RegisteredScopeUser%")
+	}); err != nil {
+		t.Fatalf("failed to register scope, got %v", err)
+	}
+
+	if err := db.RegisterScope("older_than", func(age uint) func(*gorm.DB) *gorm.DB {
+		return func(db *gorm.DB) *gorm.DB {
+			return db.Where("age > ?", age)
+		}
+	}); err != nil {
+		t.Fatalf("failed to register scope, got %v", err)
+	}
+
+	if err := db.Session(&gorm.Session{}).RegisterScope("named", func(names ...string) func(*gorm.DB) *gorm.DB {
+		return NameIn(names)
+	}); err != nil {
+		t.Fatalf("failed to register scope with session, got %v", err)
+	}
+
+	var result []User
+	if err := db.Scope("registered").Scope("older_than", 15).Order("age").Find(&result).Error; err != nil || len(result) != 2 || result[0].Name != users[1].Name {
+		t.Errorf("failed to query with registered scopes, got %v, %v", err, result)
+	}
+
+	if err := db.Scope("named", users[0].Name, users[2].Name).Find(&result).Error; err != nil || len(result) != 2 {
+		t.Errorf("failed to query with variadic scope, got %v, %v", err, result)
+	}
+
+	if err := db.Scope("unknown").Find(&result).Error; !errors.Is(err, gorm.ErrScopeNotFound) {
+		t.Errorf("should return ErrScopeNotFound, got %v", err)
+	}
+
+	if err := db.Scope("older_than", "15").Find(&result).Error; !errors.Is(err, gorm.ErrInvalidData) {
+		t.Errorf("should return ErrInvalidData for invalid arguments, got %v", err)
+	}
+
+	if err := db.Scope("older_than").Find(&result).Error; !errors.Is(err, gorm.ErrInvalidData) {
+		t.Errorf("should return ErrInvalidData for missing arguments, got %v", err)
+	}
+
+	if err := db.RegisterScope("registered", NameIn1And2); !errors.Is(err, gorm.ErrRegistered) {
+		t.Errorf("should return ErrRegistered for registered scope, got %v", err)
+	}
+
+	if err := db.RegisterScope("invalid", func(age int) int { return age }); !errors.Is(err, gorm.ErrInvalidData) {
+		t.Errorf("should return ErrInvalidData for invalid scope, got %v", err)
+	}
+
+	scopes := db.RegisteredScopes()
+	if len(scopes) != 3 || scopes[0].Name != "named" || scopes[1].Name != "older_than" || scopes[2].Name != "registered" {
+		t.Fatalf("registered scopes should be sorted by name, got %v", scopes)
+	}
+
+	if len(scopes[1].Args) != 1 || scopes[1].Args[0].Kind() != reflect.Uint || len(scopes[2].Args) != 0 {
+		t.Errorf("arguments of registered scopes are not correct, got %v", scopes)
+	}
+}
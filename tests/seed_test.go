@@ -0,0 +1,82 @@
+package tests_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"gorm.io/gorm"
+)
+
+func TestSeed(t *testing.T) {
+	type SeedLanguage struct {
+		Code string `gorm:"primaryKey"`
+		Name string
+	}
+
+	type SeedUser struct {
+		ID       uint
+		Name     string
+		Language string
+	}
+
+	DB.Migrator().DropTable(&SeedLanguage{}, &SeedUser{}, gorm.DefaultSeedsTable)
+	if err := DB.AutoMigrate(&SeedLanguage{}, &SeedUser{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	var runs int
+	seeds := gorm.NewSeeds(&gorm.Seed{
+		Name:      "demo",
+		DependsOn: []string{"base"},
+		Run: func(tx *gorm.DB) error {
+			runs++
+			return tx.Create(&SeedUser{Name: "seed", Language: "en"}).Error
+		},
+	}, &gorm.Seed{
+		Name:     "base",
+		Fixtures: []gorm.Fixture{{Model: &SeedLanguage{}, Path: "languages.json"}},
+	}, &gorm.Seed{
+		Name:         "staging",
+		Environments: []string{"staging"},
+		Run:          func(tx *gorm.DB) error { return errors.New("should not run in development") },
+	})
+	seeds.Environment = "development"
+	seeds.FS = fstest.MapFS{"languages.json": {Data: []byte(`[{"Code": "en", "name": "English"}, {"code": "zh", "Name": "Chinese"}]`)}}
+
+	if err := seeds.Register(&gorm.Seed{Name: "base"}).Error; !errors.Is(err, gorm.ErrRegistered) {
+		t.Fatalf("should returns ErrRegistered for duplicated seed, got %v", err)
+	}
+	seeds.Error = nil
+
+	db, _ := gorm.Open(DB.Dialector, &gorm.Config{Seeds: seeds})
+
+	if err := db.Seed(context.Background(), "unknown"); !errors.Is(err, gorm.ErrSeedNotFound) {
+		t.Fatalf("should returns ErrSeedNotFound, got %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := db.Seed(context.Background(), "demo", "staging"); err != nil {
+			t.Fatalf("failed to seed, got error %v", err)
+		}
+	}
+
+	var languages []SeedLanguage
+	db.Order("code").Find(&languages)
+	if len(languages) != 2 || languages[0].Name != "English" || languages[1].Name != "Chinese" {
+		t.Errorf("should load fixtures of seed dependencies, got %+v", languages)
+	}
+
+	var users int64
+	db.Model(&SeedUser{}).Count(&users)
+	if runs != 1 || users != 1 {
+		t.Errorf("should apply seed once, got runs %v, users %v", runs, users)
+	}
+
+	var records []gorm.SeedRecord
+	db.Table(gorm.DefaultSeedsTable).Order("applied_at, key").Find(&records)
+	if len(records) != 2 || records[0].Key != "base" || records[1].Key != "demo" {
+		t.Errorf("should record applied seeds in dependency order, got %+v", records)
+	}
+}
@@ -0,0 +1,257 @@
+package tests_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+type testKeyProvider struct {
+	current string
+	keys    map[string][]byte
+}
+
+func (p *testKeyProvider) CurrentKey(ctx context.Context) (string, []byte, error) {
+	return p.current, p.keys[p.current], nil
+}
+
+func (p *testKeyProvider) Key(ctx context.Context, keyID string) ([]byte, error) {
+	if key, ok := p.keys[keyID]; ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown key %v", keyID)
+}
+
+type SerializerProfile struct {
+	Phone   string
+	Country string
+}
+
+type SerializerUser struct {
+	ID       uint
+	Name     string
+	SSN      string             `gorm:"serializer:test_encrypted"`
+	Secret   []byte             `gorm:"serializer:test_encrypted"`
+	Profile  *SerializerProfile `gorm:"serializer:test_encrypted"`
+	Settings map[string]string  `gorm:"serializer:json"`
+}
+
+func TestEncryptedSerializer(t *testing.T) {
+	provider := &testKeyProvider{current: "k1", keys: map[string][]byte{
+		"k1": []byte("0123456789abcdef"), "k2": []byte("fedcba9876543210fedcba9876543210"),
+	}}
+	schema.RegisterSerializer("test_encrypted", schema.EncryptedSerializer{KeyProvider: provider})
+
+	DB.Migrator().DropTable(&SerializerUser{})
+	if err := DB.AutoMigrate(&SerializerUser{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	users := []SerializerUser{
+		{Name: "serializer1", SSN: "123-45-6789", Secret: []byte("secret1"), Profile: &SerializerProfile{Phone: "555", Country: "HU"}, Settings: map[string]string{"theme": "dark"}},
+		{Name: "serializer2", SSN: "987-65-4321"},
+	}
+	if err := DB.Create(&users).Error; err != nil {
+		t.Fatalf("failed to create, got error %v", err)
+	}
+
+	raw := map[string]interface{}{}
+	DB.Table("serializer_users").Where("id = ?", users[0].ID).Take(&raw)
+	if ssn := fmt.Sprint(raw["ssn"]); !strings.HasPrefix(ssn, "k1:") || strings.Contains(ssn, "123-45-6789") {
+		t.Errorf("ssn should be encrypted with key k1, got %v", ssn)
+	}
+
+	if settings := fmt.Sprint(raw["settings"]); settings != `{"theme":"dark"}` {
+		t.Errorf("settings should be saved as json, got %v", settings)
+	}
+
+	var results []SerializerUser
+	if err := DB.Order("id").Find(&results, "name LIKE ?", "serializer%").Error; err != nil || len(results) != 2 {
+		t.Fatalf("failed to find, got %v records, error %v", len(results), err)
+	}
+
+	if results[0].SSN != "123-45-6789" || string(results[0].Secret) != "secret1" || results[0].Profile == nil ||
+		results[0].Profile.Country != "HU" || results[0].Settings["theme"] != "dark" {
+		t.Errorf("failed to decrypt values, got %+v", results[0])
+	}
+
+	if results[1].Profile != nil || results[1].Settings != nil || results[1].SSN != "987-65-4321" {
+		t.Errorf("failed to decrypt zero values, got %+v", results[1])
+	}
+
+	if err := DB.Model(&results[1]).Update("SSN", "111-11-1111").Error; err != nil {
+		t.Fatalf("failed to update, got error %v", err)
+	}
+
+	provider.current = "k2"
+	if err := DB.Where("name LIKE ?", "serializer%").ReencryptInBatches(&[]SerializerUser{}, 1).Error; err != nil {
+		t.Fatalf("failed to re-encrypt, got error %v", err)
+	}
+
+	var rows []map[string]interface{}
+	DB.Table("serializer_users").Find(&rows)
+	for _, row := range rows {
+		if ssn := fmt.Sprint(row["ssn"]); !strings.HasPrefix(ssn, "k2:") {
+			t.Errorf("ssn should be re-encrypted with key k2, got %v", ssn)
+		}
+	}
+
+	delete(provider.keys, "k1")
+	if err := DB.Order("id").Find(&results, "name LIKE ?", "serializer%").Error; err != nil || results[1].SSN != "111-11-1111" || results[0].Profile.Phone != "555" {
+		t.Errorf("failed to find after rotating keys, got %+v, error %v", results, err)
+	}
+
+	DB.Exec("UPDATE serializer_users SET ssn = ? WHERE id = ?", "k3:invalid", users[0].ID)
+	if err := DB.First(&SerializerUser{}, users[0].ID).Error; err == nil {
+		t.Errorf("should returns error when decrypting with unknown key")
+	}
+
+	if _, err := (schema.EncryptedSerializer{}).Value(context.Background(), nil, reflect.Value{}, "1"); !errors.Is(err, schema.ErrMissingKeyProvider) {
+		t.Errorf("should returns ErrMissingKeyProvider, got %v", err)
+	}
+}
+
+func TestEncryptedSerializerRegisteredAfterParsing(t *testing.T) {
+	type LateEncryptedUser struct {
+		ID  uint
+		SSN string `gorm:"serializer:encrypted"`
+	}
+
+	DB.Migrator().DropTable(&LateEncryptedUser{})
+	if err := DB.AutoMigrate(&LateEncryptedUser{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	if err := DB.Create(&LateEncryptedUser{SSN: "123-45-6789"}).Error; !errors.Is(err, schema.ErrMissingKeyProvider) {
+		t.Errorf("should returns ErrMissingKeyProvider before registering key provider, got %v", err)
+	}
+
+	provider := &testKeyProvider{current: "k1", keys: map[string][]byte{"k1": []byte("0123456789abcdef")}}
+	schema.RegisterSerializer("encrypted", schema.EncryptedSerializer{KeyProvider: provider})
+	defer schema.RegisterSerializer("encrypted", schema.EncryptedSerializer{})
+
+	user := LateEncryptedUser{SSN: "123-45-6789"}
+	if err := DB.Create(&user).Error; err != nil {
+		t.Fatalf("fields parsed before registering key provider should be encrypted with it, got error %v", err)
+	}
+
+	var result LateEncryptedUser
+	if err := DB.First(&result, user.ID).Error; err != nil || result.SSN != user.SSN {
+		t.Errorf("failed to decrypt field, got %+v, error %v", result, err)
+	}
+}
+
+type SerializerLabels map[string]string
+
+type SerializerHost struct {
+	ID     uint
+	Name   string
+	IP     net.IP
+	Backup *net.IP
+	Labels SerializerLabels
+	Notes  SerializerLabels `gorm:"serializer:text"`
+}
+
+func TestTypeSerializer(t *testing.T) {
+	schema.RegisterTypeSerializer(reflect.TypeOf(SerializerLabels{}), schema.JSONSerializer{})
+	schema.RegisterTypeSerializer(reflect.TypeOf(net.IP{}), schema.TextSerializer{})
+
+	DB.Migrator().DropTable(&SerializerHost{})
+	if err := DB.AutoMigrate(&SerializerHost{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	backup := net.ParseIP("10.0.0.2")
+	host := SerializerHost{Name: "host", IP: net.ParseIP("10.0.0.1"), Backup: &backup, Labels: SerializerLabels{"env": "prod"}}
+	if err := DB.Create(&host).Error; err != nil {
+		t.Fatalf("failed to create, got error %v", err)
+	}
+
+	raw := map[string]interface{}{}
+	DB.Table("serializer_hosts").Where("id = ?", host.ID).Take(&raw)
+	if fmt.Sprint(raw["ip"]) != "10.0.0.1" || fmt.Sprint(raw["backup"]) != "10.0.0.2" || fmt.Sprint(raw["labels"]) != `{"env":"prod"}` {
+		t.Errorf("values should be saved with type serializers, got %v", raw)
+	}
+
+	var result SerializerHost
+	if err := DB.First(&result, host.ID).Error; err != nil {
+		t.Fatalf("failed to find, got error %v", err)
+	}
+
+	if !result.IP.Equal(host.IP) || result.Backup == nil || !result.Backup.Equal(backup) || result.Labels["env"] != "prod" {
+		t.Errorf("failed to scan with type serializers, got %+v", result)
+	}
+
+	if err := DB.Create(&SerializerHost{Name: "invalid", Notes: SerializerLabels{"a": "b"}}).Error; err != nil {
+		t.Fatalf("failed to create, got error %v", err)
+	}
+
+	if err := DB.Where("name = ?", "invalid").First(&result).Error; err == nil {
+		t.Errorf("serializer tag should take precedence over type serializer")
+	}
+}
+
+type SerializerDocument struct {
+	ID    uint
+	Name  string
+	Attrs map[string]interface{} `gorm:"serializer:json;jsonPatch"`
+}
+
+func TestJSONPatchSerializer(t *testing.T) {
+	DB.Migrator().DropTable(&SerializerDocument{})
+	if err := DB.AutoMigrate(&SerializerDocument{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	doc := SerializerDocument{Name: "doc", Attrs: map[string]interface{}{
+		"color": "red", "size": float64(1), "removed": true, "nested": map[string]interface{}{"a": "a", "b": "b"},
+	}}
+	if err := DB.Create(&doc).Error; err != nil {
+		t.Fatalf("failed to create document, got error %v", err)
+	}
+
+	var loaded SerializerDocument
+	DB.First(&loaded, doc.ID)
+
+	attrs := map[string]interface{}{
+		"color": "red", "size": float64(2), "added": "new", "nested": map[string]interface{}{"a": "a", "b": "c"},
+	}
+
+	stmt := DB.Session(&gorm.Session{DryRun: true}).Model(&SerializerDocument{ID: loaded.ID, Attrs: loaded.Attrs}).Updates(SerializerDocument{Attrs: attrs}).Statement
+	if sql := strings.ToLower(stmt.SQL.String()); !strings.Contains(sql, "json") || strings.Contains(sql, "color") {
+		t.Errorf("should only update changed keys, got %v", sql)
+	}
+
+	for _, v := range stmt.Vars {
+		if s, ok := v.(string); ok && strings.Contains(s, "color") {
+			t.Errorf("should not save whole document, got vars %v", stmt.Vars)
+		}
+	}
+
+	if DB.Exec("SELECT json_set('{}', '$.a', 1)").Error != nil {
+		t.Skip("json functions are not supported")
+	}
+
+	if err := DB.Model(&loaded).Updates(SerializerDocument{Attrs: attrs}).Error; err != nil {
+		t.Fatalf("failed to update document, got error %v", err)
+	}
+
+	var result SerializerDocument
+	DB.First(&result, doc.ID)
+	if !reflect.DeepEqual(result.Attrs, attrs) {
+		t.Errorf("document attrs should be patched, expects %#v, got %#v", attrs, result.Attrs)
+	}
+
+	DB.Model(&result).Update("Attrs", map[string]interface{}{"color": "blue"})
+	DB.First(&result, doc.ID)
+	if !reflect.DeepEqual(result.Attrs, map[string]interface{}{"color": "blue"}) {
+		t.Errorf("document attrs should be patched, got %#v", result.Attrs)
+	}
+}
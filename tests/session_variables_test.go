@@ -0,0 +1,155 @@
+package tests_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/gormtest"
+	. "gorm.io/gorm/utils/tests"
+)
+
+type currentUserKey struct{}
+
+func TestSessionVariables(t *testing.T) {
+	db, _ := gorm.Open(DB.Dialector, &gorm.Config{SessionVariables: func(ctx context.Context) map[string]string {
+		if user, ok := ctx.Value(currentUserKey{}).(string); ok {
+			return map[string]string{"app.current_user": user}
+		}
+		return nil
+	}})
+
+	ctx := context.WithValue(context.Background(), currentUserKey{}, "jinzhu")
+	user := *GetUser("session_variables", Config{})
+	if err := db.WithContext(ctx).Create(&user).Error; err != nil {
+		t.Fatalf("failed to create user with session variables, got error %v", err)
+	}
+
+	var result User
+	if err := db.WithContext(ctx).First(&result, user.ID).Error; err != nil {
+		t.Errorf("failed to query user with session variables, got error %v", err)
+	}
+
+	if DB.Dialector.Name() != "postgres" {
+		return
+	}
+
+	var currentUser string
+	db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return tx.Raw("SELECT current_setting('app.current_user', true)").Scan(&currentUser).Error
+	})
+	AssertEqual(t, currentUser, "jinzhu")
+
+	currentUser = ""
+	db.WithContext(ctx).Raw("SELECT current_setting('app.current_user', true)").Scan(&currentUser)
+	AssertEqual(t, currentUser, "jinzhu")
+
+	currentUser = "none"
+	db.Raw("SELECT COALESCE(current_setting('app.current_user', true), '')").Scan(&currentUser)
+	AssertEqual(t, currentUser, "")
+
+	if _, err := db.WithContext(ctx).Raw("SELECT 1").Rows(); err == nil {
+		t.Errorf("Rows out of transaction with session variables should returns error")
+	}
+}
+
+func TestSessionVariablesTransaction(t *testing.T) {
+	stub := gormtest.NewStub()
+	db, _ := gorm.Open(capabilitiesDialector{Stub: stub, capabilities: gorm.Capabilities{SessionVariables: true}}, &gorm.Config{
+		SkipDefaultTransaction: true,
+		SessionVariables: func(ctx context.Context) map[string]string {
+			if user, ok := ctx.Value(currentUserKey{}).(string); ok {
+				return map[string]string{"app.current_user": user}
+			}
+			return nil
+		},
+	})
+
+	var committed, failed bool
+	db.Callback().Create().After("gorm:create").Register("test:after_commit", func(tx *gorm.DB) {
+		tx.AfterCommit(func(context.Context) { committed = true })
+		if failed {
+			tx.AddError(errors.New("failed"))
+		}
+	})
+
+	ctx := context.WithValue(context.Background(), currentUserKey{}, "jinzhu")
+	failed = true
+	if err := db.WithContext(ctx).Create(&User{Name: "session_variables"}).Error; err == nil || committed {
+		t.Errorf("after commit hooks should be discarded when the transaction is rolled back, got error %v, committed %v", err, committed)
+	}
+
+	failed = false
+	if err := db.WithContext(ctx).Create(&User{Name: "session_variables"}).Error; err != nil || !committed {
+		t.Errorf("after commit hooks should run after the transaction is committed, got error %v, committed %v", err, committed)
+	}
+
+	if _, err := db.WithContext(ctx).Raw("SELECT 1").Rows(); !errors.Is(err, gorm.ErrInvalidTransaction) {
+		t.Errorf("Rows out of transaction with session variables should returns ErrInvalidTransaction, got %v", err)
+	}
+
+	if tx := db.WithContext(ctx).Raw("SELECT 1"); tx.Row() != nil || !errors.Is(tx.Error, gorm.ErrInvalidTransaction) {
+		t.Errorf("Row out of transaction with session variables should returns ErrInvalidTransaction, got %v", tx.Error)
+	}
+
+	stub.Reset()
+	stub.On("SELECT * FROM `users` WHERE name = ? AND `users`.`deleted_at` IS NULL", gormtest.Result{Columns: []string{"id", "name"}, Rows: [][]interface{}{{1, "jinzhu"}, {2, "jinzhu"}}})
+	it, err := gorm.Iterate[User](db.WithContext(ctx).Where("name = ?", "jinzhu"))
+	if err != nil {
+		t.Fatalf("Iterate out of transaction with session variables should be allowed, got %v", err)
+	}
+
+	var count int
+	for it.Next() {
+		count++
+	}
+	if err := it.Err(); err != nil || count != 2 {
+		t.Errorf("failed to iterate rows in transaction with session variables, got %v, count %v", err, count)
+	}
+
+	if statements := stub.Statements(); len(statements) != 2 || !strings.Contains(statements[0].SQL, "set_config") {
+		t.Errorf("session variables should be set before Iterate, got %+v", statements)
+	}
+
+	stub.Reset()
+	stub.On("SELECT * FROM `users` WHERE name = ? AND `users`.`deleted_at` IS NULL", gormtest.Result{Columns: []string{"id", "name"}, Rows: [][]interface{}{{1, "jinzhu"}, {2, "jinzhu"}}})
+	var buf bytes.Buffer
+	if result := db.WithContext(ctx).Model(&User{}).Where("name = ?", "jinzhu").WriteCSV(&buf); result.Error != nil || result.RowsAffected != 2 {
+		t.Errorf("WriteCSV out of transaction with session variables should be allowed, got %v, rows %v", result.Error, result.RowsAffected)
+	}
+
+	ch := make(chan User, 2)
+	if result := db.WithContext(ctx).Where("name = ?", "jinzhu").FindInto(ch); result.Error != nil || result.RowsAffected != 2 || len(ch) != 2 {
+		t.Errorf("FindInto out of transaction with session variables should be allowed, got %v, rows %v", result.Error, result.RowsAffected)
+	}
+
+	if statements := stub.Statements(); len(statements) != 4 || !strings.Contains(statements[2].SQL, "set_config") {
+		t.Errorf("session variables should be set before WriteCSV and FindInto, got %+v", statements)
+	}
+
+	stub.Reset()
+	stub.On("SELECT * FROM `users` WHERE name = ?", gormtest.Result{Columns: []string{"id", "name"}, Rows: [][]interface{}{{1, "jinzhu"}, {2, "jinzhu"}}})
+	if err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		rows, err := tx.Table("users").Where("name = ?", "jinzhu").Rows()
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		var ids []int
+		for rows.Next() {
+			var user User
+			if err := tx.ScanRows(rows, &user); err != nil {
+				return err
+			}
+			ids = append(ids, int(user.ID))
+		}
+		AssertEqual(t, ids, []int{1, 2})
+		return rows.Err()
+	}); err != nil {
+		t.Errorf("Rows in transaction with session variables should be allowed, got %v", err)
+	}
+}
@@ -0,0 +1,127 @@
+package tests_test
+
+import (
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/sharding"
+)
+
+type ShardedOrder struct {
+	ID     uint
+	UserID uint
+	Amount int
+}
+
+func TestSharding(t *testing.T) {
+	plugin := sharding.New().Register(sharding.Config{
+		Key:     "UserID",
+		Shards:  sharding.Tables("sharded_orders", 2),
+		Resolve: sharding.Hash(2),
+	}, &ShardedOrder{})
+
+	db, _ := gorm.Open(DB.Dialector, &gorm.Config{})
+	if err := db.Use(plugin); err != nil {
+		t.Fatalf("failed to use sharding plugin, got error %v", err)
+	}
+
+	for _, table := range []string{"sharded_orders_0", "sharded_orders_1"} {
+		DB.Migrator().DropTable(table)
+	}
+
+	if err := plugin.AutoMigrate(db); err != nil {
+		t.Fatalf("failed to migrate shards, got error %v", err)
+	}
+
+	if !DB.Migrator().HasTable("sharded_orders_0") || !DB.Migrator().HasTable("sharded_orders_1") {
+		t.Fatalf("tables of shards should be created")
+	}
+
+	if err := db.Create(&ShardedOrder{UserID: 1, Amount: 10}).Error; err != nil {
+		t.Fatalf("failed to create order, got error %v", err)
+	}
+
+	if err := db.Create(&[]ShardedOrder{{UserID: 2, Amount: 20}, {UserID: 4, Amount: 40}}).Error; err != nil {
+		t.Fatalf("failed to create orders, got error %v", err)
+	}
+
+	if err := db.Create(&[]ShardedOrder{{UserID: 1}, {UserID: 2}}).Error; !errors.Is(err, sharding.ErrCrossShard) {
+		t.Errorf("should returns ErrCrossShard when creating orders of different shards, but got %v", err)
+	}
+
+	var count int64
+	if DB.Table("sharded_orders_0").Count(&count); count != 2 {
+		t.Errorf("orders of even users should be created in shard 0, but got %v", count)
+	}
+
+	if DB.Table("sharded_orders_1").Count(&count); count != 1 {
+		t.Errorf("orders of odd users should be created in shard 1, but got %v", count)
+	}
+
+	var orders []ShardedOrder
+	if err := db.Where("user_id = ?", 2).Find(&orders).Error; err != nil || len(orders) != 1 || orders[0].Amount != 20 {
+		t.Errorf("should find orders in shard of user, got %#v, error %v", orders, err)
+	}
+
+	var order ShardedOrder
+	if err := db.Where(&ShardedOrder{UserID: 1}).First(&order).Error; err != nil || order.Amount != 10 {
+		t.Errorf("should find order in shard of user, got %#v, error %v", order, err)
+	}
+
+	if err := db.Model(&ShardedOrder{}).Where("user_id = ?", 4).Update("amount", 400).Error; err != nil {
+		t.Errorf("failed to update order in shard, got error %v", err)
+	}
+
+	if err := db.Find(&orders).Error; !errors.Is(err, sharding.ErrMissingShardKey) {
+		t.Errorf("should returns ErrMissingShardKey for query without shard key, but got %v", err)
+	}
+
+	if err := db.Where("amount > ?", 0).Delete(&ShardedOrder{}).Error; !errors.Is(err, sharding.ErrMissingShardKey) {
+		t.Errorf("should returns ErrMissingShardKey for delete without shard key, but got %v", err)
+	}
+
+	scatter, _ := gorm.Open(DB.Dialector, &gorm.Config{})
+	scatter.Use(sharding.New().Register(sharding.Config{
+		Key:           "user_id",
+		Shards:        sharding.Tables("sharded_orders", 2),
+		Resolve:       sharding.Hash(2),
+		ScatterGather: true,
+	}, &ShardedOrder{}))
+
+	orders = nil
+	if err := scatter.Order("user_id").Find(&orders).Error; err != nil || len(orders) != 3 {
+		t.Errorf("should find orders of all shards, got %#v, error %v", orders, err)
+	}
+
+	if err := scatter.Model(&ShardedOrder{}).Where("user_id IN ?", []uint{1, 4}).Count(&count).Error; err != nil || count != 2 {
+		t.Errorf("should count orders of shards, got %v, error %v", count, err)
+	}
+
+	order = ShardedOrder{}
+	if err := scatter.Where("amount = ?", 400).First(&order).Error; err != nil || order.UserID != 4 {
+		t.Errorf("should find order in shards, got %#v, error %v", order, err)
+	}
+
+	if err := scatter.Where("amount = ?", 1000).First(&ShardedOrder{}).Error; !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("should returns ErrRecordNotFound if not found in shards, but got %v", err)
+	}
+}
+
+func TestShardingResolvers(t *testing.T) {
+	ranges := sharding.Range(100, 200)
+	for key, expects := range map[interface{}]int{1: 0, uint(99): 0, int64(100): 1, "150": 1, 200: 2, 1000: 2} {
+		if idx, err := ranges(key); err != nil || idx != expects {
+			t.Errorf("range shard of %v should be %v, but got %v, error %v", key, expects, idx, err)
+		}
+	}
+
+	hash := sharding.Hash(4)
+	if idx, _ := hash(6); idx != 2 {
+		t.Errorf("hash shard of integer should be modulo, but got %v", idx)
+	}
+
+	if idx1, _ := hash("jinzhu"); idx1 < 0 || idx1 >= 4 {
+		t.Errorf("hash shard of string should be in shards, but got %v", idx1)
+	}
+}
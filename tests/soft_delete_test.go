@@ -1,11 +1,13 @@
 package tests_test
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"errors"
 	"regexp"
 	"testing"
+	"time"
 
 	"gorm.io/gorm"
 	. "gorm.io/gorm/utils/tests"
@@ -83,3 +85,80 @@ func TestDeletedAtUnMarshal(t *testing.T) {
 		t.Errorf("Failed, result.DeletedAt: %v is not same as expected.DeletedAt: %v", result.DeletedAt, expected.DeletedAt)
 	}
 }
+
+type FlagSoftDeleteRecord struct {
+	ID        uint
+	Name      string
+	IsDeleted gorm.DeletedFlag `gorm:"not null;default:false"`
+	DeletedAt *time.Time       `gorm:"softDelete:time"`
+	DeletedBy string           `gorm:"softDelete:by"`
+}
+
+func TestSoftDeleteWithFlag(t *testing.T) {
+	DB.Migrator().DropTable(&FlagSoftDeleteRecord{})
+	if err := DB.AutoMigrate(&FlagSoftDeleteRecord{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	record := FlagSoftDeleteRecord{Name: "flag_soft_delete"}
+	DB.Save(&record)
+
+	tx, _ := gorm.Open(DB.Dialector, &gorm.Config{ActorResolver: func(ctx context.Context) (interface{}, bool) {
+		operator := ctx.Value("operator")
+		return operator, operator != nil
+	}})
+
+	if err := tx.WithContext(context.WithValue(context.Background(), "operator", "jinzhu")).Delete(&record).Error; err != nil {
+		t.Fatalf("failed to soft delete, got error %v", err)
+	}
+
+	if !record.IsDeleted || record.DeletedAt == nil || record.DeletedBy != "jinzhu" {
+		t.Errorf("soft delete columns should be set, but got %+v", record)
+	}
+
+	if err := DB.First(&FlagSoftDeleteRecord{}, record.ID).Error; !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("should not find soft deleted record, but got %v", err)
+	}
+
+	var result FlagSoftDeleteRecord
+	if err := DB.Unscoped().First(&result, record.ID).Error; err != nil {
+		t.Fatalf("should find soft deleted record with Unscoped, but got %v", err)
+	}
+
+	if !result.IsDeleted || result.DeletedAt == nil || result.DeletedBy != "jinzhu" {
+		t.Errorf("soft delete columns should be saved, but got %+v", result)
+	}
+}
+
+func TestPurgeSoftDeleted(t *testing.T) {
+	users := []User{*GetUser("purge_soft_deleted_1", Config{}), *GetUser("purge_soft_deleted_2", Config{}), *GetUser("purge_soft_deleted_3", Config{})}
+	DB.Create(&users)
+	DB.Delete(&users[0])
+	DB.Delete(&users[1])
+	DB.Unscoped().Model(&users[0]).Update("deleted_at", time.Now().Add(-48*time.Hour))
+
+	if err := DB.PurgeSoftDeleted(&User{}, 24*time.Hour).Error; err != nil {
+		t.Fatalf("failed to purge soft deleted records, got error %v", err)
+	}
+
+	var count int64
+	DB.Unscoped().Model(&User{}).Where("name LIKE ?", "purge_soft_deleted_%").Count(&count)
+	if count != 2 {
+		t.Errorf("should only purge expired soft deleted records, but got %v records left", count)
+	}
+
+	if err := DB.Unscoped().First(&User{}, users[0].ID).Error; !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expired soft deleted record should be purged, but got %v", err)
+	}
+
+	tx := DB.Session(&gorm.Session{NewDB: true})
+	tx.Config.PurgeBatchSize = 1
+	DB.Unscoped().Model(&User{}).Where("name LIKE ?", "purge_soft_deleted_%").Update("deleted_at", time.Now().Add(-48*time.Hour))
+	if result := tx.Where("name LIKE ?", "purge_soft_deleted_%").PurgeSoftDeleted(&User{}, 24*time.Hour); result.Error != nil || result.RowsAffected != 2 {
+		t.Errorf("failed to purge soft deleted records in batches, got error %v, rows affected %v", result.Error, result.RowsAffected)
+	}
+
+	if err := DB.PurgeSoftDeleted(&Company{}, time.Hour).Error; !errors.Is(err, gorm.ErrMissingSoftDeleteTime) {
+		t.Errorf("should return ErrMissingSoftDeleteTime for models without soft delete, but got %v", err)
+	}
+}
@@ -0,0 +1,91 @@
+package tests_test
+
+import (
+	"encoding/hex"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SpatialStore struct {
+	ID       uint
+	Name     string
+	Location gorm.Point   `gorm:"geography"`
+	Area     gorm.Polygon `gorm:"srid:3857"`
+}
+
+func TestSpatialTypes(t *testing.T) {
+	DB.Migrator().DropTable(&SpatialStore{})
+	if err := DB.AutoMigrate(&SpatialStore{}); err != nil {
+		t.Fatalf("failed to migrate spatial store, got error %v", err)
+	}
+
+	store := SpatialStore{
+		Name:     "store",
+		Location: gorm.Point{X: 13.405, Y: 52.52},
+		Area:     gorm.Polygon{Rings: [][]gorm.Point{{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 1, Y: 1}, {X: 0, Y: 0}}}},
+	}
+	if err := DB.Create(&store).Error; err != nil {
+		t.Fatalf("failed to create spatial store, got error %v", err)
+	}
+
+	var result SpatialStore
+	if err := DB.First(&result, store.ID).Error; err != nil {
+		t.Fatalf("failed to query spatial store, got error %v", err)
+	}
+
+	if !reflect.DeepEqual(result.Location, store.Location) || !reflect.DeepEqual(result.Area, store.Area) {
+		t.Errorf("spatial values should be equal, expects %#v, got %#v", store, result)
+	}
+
+	stmt := DB.Session(&gorm.Session{DryRun: true}).Where(gorm.STDWithin{Column: "location", Geometry: store.Location, Distance: 100}).
+		Clauses(clause.OrderBy{Expression: gorm.STDistance{Column: "location", Geometry: store.Location}}).Find(&[]SpatialStore{}).Statement
+	if DB.Dialector.Name() == "postgres" {
+		if !strings.Contains(stmt.SQL.String(), "ST_DWithin(") {
+			t.Errorf("should use ST_DWithin, got %v", stmt.SQL.String())
+		}
+	} else if !strings.Contains(stmt.SQL.String(), "ST_Distance(") || !strings.Contains(stmt.SQL.String(), "<= ?") {
+		t.Errorf("should use ST_Distance, got %v", stmt.SQL.String())
+	}
+
+	if !strings.Contains(stmt.SQL.String(), "ORDER BY ST_Distance(") {
+		t.Errorf("should order by distance, got %v", stmt.SQL.String())
+	}
+}
+
+func TestScanSpatialTypes(t *testing.T) {
+	// EWKB of SRID=4326;POINT(1 2), as returned by PostGIS
+	ewkb := "0101000020E6100000000000000000F03F0000000000000040"
+	wkb, _ := hex.DecodeString("0101000000000000000000F03F0000000000000040")
+
+	for _, value := range []interface{}{ewkb, []byte(ewkb), "SRID=4326;POINT(1 2)", wkb, append([]byte{0xE6, 0x10, 0, 0}, wkb...)} {
+		var point gorm.Point
+		if err := point.Scan(value); err != nil || point.X != 1 || point.Y != 2 {
+			t.Errorf("failed to scan point %v, got %#v, error %v", value, point, err)
+		}
+
+		if _, ok := value.([]byte); ok && len(value.([]byte)) == len(wkb) {
+			continue
+		} else if point.SRID != 4326 {
+			t.Errorf("failed to scan SRID of %v, got %v", value, point.SRID)
+		}
+	}
+
+	var polygon gorm.Polygon
+	if err := polygon.Scan("POLYGON((0 0,4 0,4 4,0 0),(1 1, 2 1, 2 2, 1 1))"); err != nil || len(polygon.Rings) != 2 || polygon.Rings[1][1] != (gorm.Point{X: 2, Y: 1}) {
+		t.Errorf("failed to scan polygon, got %#v, error %v", polygon, err)
+	}
+
+	if err := polygon.Scan("POINT(1 2)"); !errors.Is(err, gorm.ErrInvalidGeometry) {
+		t.Errorf("should returns ErrInvalidGeometry when scanning point into polygon, got %v", err)
+	}
+
+	var point gorm.Point
+	if err := point.Scan(nil); err != nil || point != (gorm.Point{}) {
+		t.Errorf("NULL should be scanned as zero point, got %#v, error %v", point, err)
+	}
+}
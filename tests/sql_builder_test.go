@@ -1,11 +1,13 @@
 package tests_test
 
 import (
+	"errors"
 	"regexp"
 	"strings"
 	"testing"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	. "gorm.io/gorm/utils/tests"
 )
 
@@ -242,3 +244,68 @@ func TestCombineStringConditions(t *testing.T) {
 		t.Fatalf("invalid sql generated, got %v", sql)
 	}
 }
+
+func TestToSQL(t *testing.T) {
+	var count int64
+	DB.Model(&User{}).Where("name LIKE ?", "to_sql%").Count(&count)
+
+	sql := DB.ToSQL(func(tx *gorm.DB) *gorm.DB {
+		return tx.Model(&User{}).Where("id = ?", 100).Limit(10).Order("age desc").Find(&[]User{})
+	})
+	if !regexp.MustCompile(`^SELECT \* FROM .users. WHERE id = 100 AND .users.\..deleted_at. IS NULL ORDER BY age desc LIMIT 10$`).MatchString(sql) {
+		t.Errorf("invalid SQL of query, got %v", sql)
+	}
+
+	user := *GetUser("to_sql", Config{Company: true, Pets: 2})
+	statements, err := DB.ToSQLs(func(tx *gorm.DB) *gorm.DB {
+		return tx.Create(&user)
+	})
+	if err != nil || len(statements) != 3 {
+		t.Fatalf("create should generate 3 statements, got %v, %v", err, statements)
+	}
+
+	for idx, table := range []string{"companies", "users", "pets"} {
+		if !regexp.MustCompile("^INSERT INTO ." + table + ". ").MatchString(statements[idx]) {
+			t.Errorf("statement %d should insert into %v, got %v", idx, table, statements[idx])
+		}
+	}
+
+	users := []User{*GetUser("to_sql_batch1", Config{}), *GetUser("to_sql_batch2", Config{}), *GetUser("to_sql_batch3", Config{})}
+	if statements, err = DB.ToSQLs(func(tx *gorm.DB) *gorm.DB {
+		return tx.Omit(clause.Associations).CreateInBatches(&users, 2)
+	}); err != nil || len(statements) != 2 || !strings.Contains(statements[1], "to_sql_batch3") {
+		t.Errorf("create in batches should generate statement for each batch, got %v, %v", err, statements)
+	}
+
+	sql = DB.ToSQL(func(tx *gorm.DB) *gorm.DB {
+		return tx.Clauses(clause.OnConflict{UpdateAll: true}).Omit(clause.Associations).Create(&users[0])
+	})
+	if !strings.Contains(sql, "ON CONFLICT") {
+		t.Errorf("upsert should generate ON CONFLICT, got %v", sql)
+	}
+
+	sql = DB.ToSQL(func(tx *gorm.DB) *gorm.DB {
+		return tx.Model(&User{}).Where("name = ?", "to_sql").Update("age", 18)
+	})
+	if !regexp.MustCompile(`^UPDATE .users. SET .age.=18,.updated_at.=".+" WHERE name = "to_sql"$`).MatchString(sql) {
+		t.Errorf("invalid SQL of update, got %v", sql)
+	}
+
+	sql = DB.ToSQL(func(tx *gorm.DB) *gorm.DB {
+		return tx.Unscoped().Where("id IN (?)", tx.Model(&User{}).Select("id").Where("name = ?", "to_sql")).Delete(&User{})
+	})
+	if !regexp.MustCompile(`^DELETE FROM .users. WHERE id IN \(SELECT .id. FROM .users. WHERE name = "to_sql" AND .users.\..deleted_at. IS NULL\)$`).MatchString(sql) {
+		t.Errorf("invalid SQL of delete with sub query, got %v", sql)
+	}
+
+	if _, err = DB.ToSQLs(func(tx *gorm.DB) *gorm.DB {
+		return tx.Delete(&User{})
+	}); !errors.Is(err, gorm.ErrMissingWhereClause) {
+		t.Errorf("should return error of generating statements, got %v", err)
+	}
+
+	var newCount int64
+	if DB.Model(&User{}).Where("name LIKE ?", "to_sql%").Count(&newCount); newCount != count {
+		t.Errorf("statements should not be executed, users %v, got %v", count, newCount)
+	}
+}
@@ -0,0 +1,105 @@
+package tests_test
+
+import (
+	"errors"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+type OrderStatus string
+
+func (OrderStatus) StateTransitions() map[string][]string {
+	return map[string][]string{
+		"draft": {"paid", "cancelled"},
+		"paid":  {"shipped", "cancelled"},
+	}
+}
+
+type StateOrder struct {
+	ID          uint
+	Status      OrderStatus
+	Transitions []string `gorm:"-"`
+}
+
+func (o *StateOrder) BeforeTransition(tx *gorm.DB, field, from, to string) error {
+	if to == "cancelled" && from == "paid" {
+		return errors.New("paid orders should be refunded")
+	}
+	o.Transitions = append(o.Transitions, "before:"+field+":"+from+">"+to)
+	return nil
+}
+
+func (o *StateOrder) AfterTransition(tx *gorm.DB, field, from, to string) error {
+	o.Transitions = append(o.Transitions, "after:"+field+":"+from+">"+to)
+	return nil
+}
+
+func TestStateMachine(t *testing.T) {
+	DB.Migrator().DropTable(&StateOrder{})
+	if err := DB.AutoMigrate(&StateOrder{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	order := StateOrder{Status: "draft"}
+	DB.Create(&order)
+
+	if err := DB.Model(&order).Update("status", "paid").Error; err != nil {
+		t.Fatalf("failed to transition order, got error %v", err)
+	}
+
+	if len(order.Transitions) != 2 || order.Transitions[0] != "before:Status:draft>paid" || order.Transitions[1] != "after:Status:draft>paid" {
+		t.Errorf("transition hooks should be called, but got %v", order.Transitions)
+	}
+
+	var transitionErr *gorm.TransitionError
+	if err := DB.Model(&order).Update("status", "draft").Error; !errors.As(err, &transitionErr) ||
+		!errors.Is(err, gorm.ErrInvalidTransition) || transitionErr.From != "paid" || transitionErr.To != "draft" {
+		t.Errorf("should returns invalid transition error, but got %v", err)
+	}
+
+	if order.Status != "paid" {
+		t.Errorf("status of rejected transition should be restored, but got %v", order.Status)
+	}
+
+	if err := DB.Model(&order).Update("status", "cancelled").Error; err == nil || err.Error() != "paid orders should be refunded" {
+		t.Errorf("transition should be rejected by hook, but got %v", err)
+	}
+
+	// rows in states not allowed to transition from are not updated
+	stale := StateOrder{ID: order.ID}
+	DB.Model(&StateOrder{}).Where("id = ?", order.ID).Update("status", "shipped")
+	if result := DB.Model(&stale).Update("status", "paid"); result.Error != nil || result.RowsAffected != 0 {
+		t.Errorf("shipped order should not be updated to paid, but got %v, %v", result.Error, result.RowsAffected)
+	}
+
+	var result StateOrder
+	DB.First(&result, order.ID)
+	if result.Status != "shipped" {
+		t.Errorf("status should be shipped, but got %v", result.Status)
+	}
+
+	// rows changed to other states concurrently are not updated, and the transition is rejected
+	concurrent := StateOrder{Status: "draft"}
+	DB.Create(&concurrent)
+	DB.Model(&StateOrder{}).Where("id = ?", concurrent.ID).Update("status", "cancelled")
+	if err := DB.Model(&concurrent).Update("status", "paid").Error; !errors.As(err, &transitionErr) || transitionErr.From != "draft" || transitionErr.To != "paid" {
+		t.Errorf("transition of order changed concurrently should be rejected, but got %v", err)
+	}
+
+	if concurrent.Status != "draft" || len(concurrent.Transitions) != 1 {
+		t.Errorf("status of rejected transition should be restored without after hooks, but got %v, %v", concurrent.Status, concurrent.Transitions)
+	}
+
+	stmt := DB.Session(&gorm.Session{DryRun: true}).Model(&StateOrder{}).Where("id = ?", order.ID).Update("status", "shipped").Statement
+	if !regexp.MustCompile(`.status. IN \(.+,.+\)`).MatchString(stmt.SQL.String()) {
+		t.Errorf("updating should be guarded with states allowed to transition from, but got %v", stmt.SQL.String())
+	}
+
+	stmt = DB.Session(&gorm.Session{DryRun: true}).Model(&StateOrder{}).Where("id = ?", order.ID).Update("status", "cancelled").Statement
+	if vars := stmt.Vars[len(stmt.Vars)-3:]; !reflect.DeepEqual(vars, []interface{}{"cancelled", "draft", "paid"}) {
+		t.Errorf("states allowed to transition from should be sorted, but got %v", vars)
+	}
+}
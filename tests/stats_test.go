@@ -0,0 +1,56 @@
+package tests_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+	. "gorm.io/gorm/utils/tests"
+)
+
+func TestStats(t *testing.T) {
+	db, err := gorm.Open(DB.Dialector, &gorm.Config{PrepareStmt: true})
+	if err != nil {
+		t.Fatalf("failed to open database, got error %v", err)
+	}
+
+	db.Where("name = ?", "stats").Find(&[]User{})
+	db.Where("age = ?", 18).Find(&[]User{})
+
+	stats := db.Stats()
+	if stats.StatementsExecuted != 2 || stats.PreparedStmts != 2 || stats.OpenTransactions != 0 || stats.OpenConnections == 0 {
+		t.Errorf("invalid stats, got %#v", stats)
+	}
+
+	tx := db.Begin()
+	if stats := db.Stats(); stats.OpenTransactions != 1 {
+		t.Errorf("transaction should be open, got %v", stats.OpenTransactions)
+	}
+
+	tx.Rollback()
+	tx.Rollback()
+	if stats := db.Stats(); stats.OpenTransactions != 0 {
+		t.Errorf("transaction should be closed, got %v", stats.OpenTransactions)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	reports := make(chan gorm.Stats, 1)
+	db.ReportStats(ctx, 10*time.Millisecond, func(stats gorm.Stats) {
+		select {
+		case reports <- stats:
+		default:
+		}
+	})
+
+	select {
+	case stats := <-reports:
+		if stats.StatementsExecuted != 2 {
+			t.Errorf("invalid reported stats, got %#v", stats)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("stats should be reported")
+	}
+}
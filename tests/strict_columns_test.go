@@ -0,0 +1,49 @@
+package tests_test
+
+import (
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+	. "gorm.io/gorm/utils/tests"
+)
+
+func TestStrictColumns(t *testing.T) {
+	user := *GetUser("strict_columns", Config{Account: true, Pets: 2})
+	DB.Create(&user)
+
+	tx := DB.Session(&gorm.Session{StrictColumns: true, AllowedColumns: []string{"count(*)"}})
+
+	var users []User
+	if err := tx.Select("id", "name AS user_name").Where(map[string]interface{}{"name": user.Name}).Order("age desc, users.id").Find(&users).Error; err != nil || len(users) != 1 {
+		t.Errorf("known columns should be allowed, got %v records, error %v", len(users), err)
+	}
+
+	if err := tx.Where("name = ?", user.Name).Preload("Pets").Preload("Account").Find(&users).Error; err != nil {
+		t.Errorf("preload should work in strict mode, got error %v", err)
+	}
+
+	var results []map[string]interface{}
+	if err := tx.Model(&User{}).Select("name", "count(*) AS total").Where(map[string]interface{}{"name": user.Name}).Group("name").Find(&results).Error; err != nil {
+		t.Errorf("allowed columns should be valid, got error %v", err)
+	}
+
+	tests := map[string]*gorm.DB{
+		"select":     tx.Select("name", "password").Find(&users),
+		"omit":       tx.Omit("unknown").Find(&users),
+		"order":      tx.Order("name; DROP TABLE users").Find(&users),
+		"order desc": tx.Order("unknown desc").Find(&users),
+		"conditions": tx.Where(map[string]interface{}{"unknown": 1}).Find(&users),
+		"not":        tx.Not(map[string]interface{}{"unknown": []int{1, 2}}).Find(&users),
+	}
+
+	for name, result := range tests {
+		if !errors.Is(result.Error, gorm.ErrInvalidField) {
+			t.Errorf("%v with unknown columns should returns ErrInvalidField, got %v", name, result.Error)
+		}
+	}
+
+	if err := DB.Order("age desc, id").Find(&users).Error; err != nil {
+		t.Errorf("columns should not be validated without strict mode, got %v", err)
+	}
+}
@@ -0,0 +1,261 @@
+package tests_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/gormtest"
+	. "gorm.io/gorm/utils/tests"
+)
+
+type tenantKey struct{}
+
+func tenantResolver(tenants map[string]gorm.Tenant) gorm.TenantResolver {
+	return func(ctx context.Context) (gorm.Tenant, bool) {
+		if name, ok := ctx.Value(tenantKey{}).(string); ok {
+			tenant, ok := tenants[name]
+			return tenant, ok
+		}
+		return gorm.Tenant{}, false
+	}
+}
+
+func TestTenantNamespace(t *testing.T) {
+	db, _ := gorm.Open(DB.Dialector, &gorm.Config{
+		DryRun:         true,
+		TenantResolver: tenantResolver(map[string]gorm.Tenant{"acme": {Name: "acme", Namespace: "tenant_acme"}}),
+	})
+
+	ctx := context.WithValue(context.Background(), tenantKey{}, "acme")
+	stmt := db.WithContext(ctx).Find(&User{}).Statement
+	if !regexp.MustCompile(`FROM .tenant_acme.\..users.`).MatchString(stmt.SQL.String()) {
+		t.Errorf("table should be qualified with namespace of tenant, but got %v", stmt.SQL.String())
+	}
+
+	stmt = db.WithContext(ctx).Table("users").Where("id = ?", 1).Delete(&User{}).Statement
+	if !regexp.MustCompile(`UPDATE .tenant_acme.\..users.`).MatchString(stmt.SQL.String()) {
+		t.Errorf("table should be qualified with namespace of tenant, but got %v", stmt.SQL.String())
+	}
+
+	stmt = db.Find(&User{}).Statement
+	if regexp.MustCompile(`tenant_acme`).MatchString(stmt.SQL.String()) {
+		t.Errorf("table should not be qualified without tenant, but got %v", stmt.SQL.String())
+	}
+
+	stmt = db.ForTenant(gorm.Tenant{Name: "beta", Namespace: "tenant_beta"}).Find(&User{}).Statement
+	if !regexp.MustCompile(`FROM .tenant_beta.\..users.`).MatchString(stmt.SQL.String()) {
+		t.Errorf("table should be qualified with namespace of tenant, but got %v", stmt.SQL.String())
+	}
+
+	namespacedDB, _ := gorm.Open(DB.Dialector, &gorm.Config{
+		DryRun:         true,
+		Namespace:      "public",
+		TenantResolver: tenantResolver(map[string]gorm.Tenant{"acme": {Name: "acme", Namespace: "tenant_acme"}}),
+	})
+
+	stmt = namespacedDB.WithContext(ctx).Find(&User{}).Statement
+	if !regexp.MustCompile(`FROM .tenant_acme.\..users.`).MatchString(stmt.SQL.String()) {
+		t.Errorf("namespace of config should be switched to namespace of tenant, but got %v", stmt.SQL.String())
+	}
+}
+
+func TestTenantSearchPath(t *testing.T) {
+	stub := gormtest.NewStub()
+	db, _ := gorm.Open(capabilitiesDialector{Stub: stub, capabilities: gorm.Capabilities{SessionVariables: true}}, &gorm.Config{
+		SkipDefaultTransaction: true,
+		TenantResolver:         tenantResolver(map[string]gorm.Tenant{"acme": {Name: "acme", Namespace: "tenant_acme"}}),
+	})
+
+	ctx := context.WithValue(context.Background(), tenantKey{}, "acme")
+	if err := db.WithContext(ctx).Joins("Company").Find(&[]User{}).Error; err != nil {
+		t.Fatalf("failed to query users of tenant, got error %v", err)
+	}
+
+	if statements := stub.Statements(); len(statements) != 1 || !strings.Contains(statements[0].SQL, "FROM `tenant_acme.users`") {
+		t.Errorf("statement out of transaction should be executed with qualified table without search_path, got %+v", statements)
+	}
+
+	stub.Reset()
+	if err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return tx.Joins("Company").Find(&[]User{}).Error
+	}); err != nil {
+		t.Fatalf("failed to query users of tenant in transaction, got error %v", err)
+	}
+
+	if statements := stub.Statements(); len(statements) != 2 || !strings.Contains(statements[0].SQL, "set_config") ||
+		!reflect.DeepEqual(statements[0].Vars, []interface{}{"search_path", "`tenant_acme`"}) {
+		t.Errorf("search_path should be set to namespace of tenant for joins, got %+v", statements)
+	}
+
+	stub.Reset()
+	if rows, err := db.WithContext(ctx).Model(&User{}).Rows(); err != nil {
+		t.Errorf("Rows of tenant should be executed with qualified table, got error %v", err)
+	} else {
+		rows.Close()
+	}
+
+	if statements := stub.Statements(); len(statements) != 1 || !strings.Contains(statements[0].SQL, "FROM `tenant_acme.users`") {
+		t.Errorf("table of Rows should be qualified with namespace of tenant, got %+v", statements)
+	}
+}
+
+func TestTenantConnPool(t *testing.T) {
+	if DB.Dialector.Name() != "sqlite" {
+		t.Skip("database per tenant is tested with sqlite")
+	}
+
+	file := filepath.Join(os.TempDir(), "gorm_tenant_acme.db")
+	defer os.Remove(file)
+
+	tenantDB, err := gorm.Open(sqlite.Open(file), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open database of tenant, got error %v", err)
+	}
+
+	tenant := gorm.Tenant{Name: "acme", ConnPool: tenantDB.ConnPool}
+	db, _ := gorm.Open(DB.Dialector, &gorm.Config{TenantResolver: tenantResolver(map[string]gorm.Tenant{"acme": tenant})})
+	if err := db.MigrateTenants([]gorm.Tenant{tenant}, &User{}); err != nil {
+		t.Fatalf("failed to migrate tenants, got error %v", err)
+	}
+
+	if !tenantDB.Migrator().HasTable(&User{}) {
+		t.Fatalf("table should be created in database of tenant")
+	}
+
+	ctx := context.WithValue(context.Background(), tenantKey{}, "acme")
+	user := *GetUser("tenant_conn_pool", Config{})
+	if err := db.WithContext(ctx).Create(&user).Error; err != nil {
+		t.Fatalf("failed to create user of tenant, got error %v", err)
+	}
+
+	var count int64
+	tenantDB.Model(&User{}).Where("name = ?", user.Name).Count(&count)
+	if count != 1 {
+		t.Errorf("user should be created in database of tenant, but got %v", count)
+	}
+
+	DB.Model(&User{}).Where("name = ?", user.Name).Count(&count)
+	if count != 0 {
+		t.Errorf("user should not be created in default database, but got %v", count)
+	}
+
+	db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return tx.Model(&User{}).Where("name = ?", user.Name).Count(&count).Error
+	})
+	if count != 1 {
+		t.Errorf("transaction should be began in database of tenant, but got %v", count)
+	}
+
+	db.WithContext(ctx).Prepared(true).Model(&User{}).Where("name = ?", user.Name).Count(&count)
+	if count != 1 {
+		t.Errorf("prepared statements should be executed in database of tenant, but got %v", count)
+	}
+
+	preparedDB, _ := gorm.Open(DB.Dialector, &gorm.Config{PrepareStmt: true, TenantResolver: tenantResolver(map[string]gorm.Tenant{"acme": tenant})})
+	preparedDB.WithContext(ctx).Model(&User{}).Where("name = ?", user.Name).Count(&count)
+	if count != 1 {
+		t.Errorf("statements of PrepareStmt should be executed in database of tenant, but got %v", count)
+	}
+
+	preparedDB.WithContext(ctx).Prepared(false).Model(&User{}).Where("name = ?", user.Name).Count(&count)
+	if count != 1 {
+		t.Errorf("statements without prepared statements should be executed in database of tenant, but got %v", count)
+	}
+
+	preparedDB.WithContext(ctx).Prepared(false).Transaction(func(tx *gorm.DB) error {
+		return tx.Prepared(true).Model(&User{}).Where("name = ?", user.Name).Count(&count).Error
+	})
+	if count != 1 {
+		t.Errorf("prepared statements in transaction should be executed in database of tenant, but got %v", count)
+	}
+}
+
+type TenantProject struct {
+	ID       uint
+	TenantID uint
+	Name     string
+}
+
+type tenantIDKey struct{}
+
+func TestTenantScope(t *testing.T) {
+	DB.Migrator().DropTable(&TenantProject{})
+	DB.AutoMigrate(&TenantProject{})
+
+	db, _ := gorm.Open(DB.Dialector, &gorm.Config{TenantScope: &gorm.TenantScope{
+		Column: "tenant_id",
+		TenantID: func(ctx context.Context) (interface{}, bool) {
+			id, ok := ctx.Value(tenantIDKey{}).(uint)
+			return id, ok
+		},
+	}})
+
+	tenant1 := db.WithContext(context.WithValue(context.Background(), tenantIDKey{}, uint(1)))
+	tenant2 := db.WithContext(context.WithValue(context.Background(), tenantIDKey{}, uint(2)))
+
+	project := TenantProject{Name: "project1"}
+	if err := tenant1.Create(&project).Error; err != nil || project.TenantID != 1 {
+		t.Fatalf("tenant should be assigned to created record, got %v, error %v", project.TenantID, err)
+	}
+
+	projects := []TenantProject{{Name: "project2"}, {Name: "project3"}}
+	if err := tenant2.Create(&projects).Error; err != nil || projects[0].TenantID != 2 || projects[1].TenantID != 2 {
+		t.Fatalf("tenant should be assigned to created records, got %#v, error %v", projects, err)
+	}
+
+	if err := tenant2.Model(&TenantProject{}).Create(map[string]interface{}{"name": "project4"}).Error; err != nil {
+		t.Fatalf("failed to create with map, got error %v", err)
+	}
+
+	if err := tenant1.Create(&TenantProject{Name: "other", TenantID: 2}).Error; !errors.Is(err, gorm.ErrTenantMismatch) {
+		t.Errorf("should returns ErrTenantMismatch when creating record of other tenant, but got %v", err)
+	}
+
+	var results []TenantProject
+	tenant2.Order("id").Find(&results)
+	if len(results) != 3 || results[2].Name != "project4" || results[2].TenantID != 2 {
+		t.Errorf("should only find records of tenant, but got %#v", results)
+	}
+
+	var result TenantProject
+	if err := tenant2.First(&result, project.ID).Error; !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("should not find record of other tenant, but got %v", err)
+	}
+
+	var count int64
+	if tenant1.Model(&TenantProject{}).Count(&count); count != 1 {
+		t.Errorf("should count records of tenant, but got %v", count)
+	}
+
+	if rows := tenant2.Model(&TenantProject{}).Where("1 = 1").Update("name", "updated").RowsAffected; rows != 3 {
+		t.Errorf("should update records of tenant, but got %v", rows)
+	}
+
+	if rows := tenant2.Where("1 = 1").Delete(&TenantProject{}).RowsAffected; rows != 3 {
+		t.Errorf("should delete records of tenant, but got %v", rows)
+	}
+
+	if err := db.Model(&TenantProject{}).Where("1 = 1").Update("name", "updated").Error; !errors.Is(err, gorm.ErrMissingTenant) {
+		t.Errorf("should returns ErrMissingTenant without tenant, but got %v", err)
+	}
+
+	if err := db.Where("1 = 1").Delete(&TenantProject{}).Error; !errors.Is(err, gorm.ErrMissingTenant) {
+		t.Errorf("should returns ErrMissingTenant without tenant, but got %v", err)
+	}
+
+	if err := db.SkipTenantScope().First(&result, project.ID).Error; err != nil || result.Name != "project1" {
+		t.Errorf("should find record with SkipTenantScope, but got %#v, error %v", result, err)
+	}
+
+	if err := db.Find(&[]User{}).Error; err != nil {
+		t.Errorf("models without tenant column should not be scoped, but got error %v", err)
+	}
+}
@@ -0,0 +1,147 @@
+package tests_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/gormtest"
+	. "gorm.io/gorm/utils/tests"
+)
+
+func slowSQL() string {
+	switch DB.Dialector.Name() {
+	case "postgres":
+		return "SELECT 1 FROM pg_sleep(1)"
+	case "mysql":
+		return "SELECT SLEEP(1)"
+	case "sqlserver":
+		return "WAITFOR DELAY '00:00:01'; SELECT 1"
+	default:
+		return "WITH RECURSIVE c(x) AS (SELECT 1 UNION ALL SELECT x + 1 FROM c WHERE x < 100000000) SELECT count(*) FROM c"
+	}
+}
+
+func TestWithTimeout(t *testing.T) {
+	var result int64
+	if err := DB.WithTimeout(50 * time.Millisecond).Raw(slowSQL()).Scan(&result).Error; !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("slow statement should be canceled after timeout, but got %v", err)
+	}
+
+	if err := DB.Session(&gorm.Session{StatementTimeout: 50 * time.Millisecond}).Raw(slowSQL()).Scan(&result).Error; err == nil {
+		t.Errorf("slow statement should be canceled after timeout of session")
+	}
+
+	user := *GetUser("with_timeout", Config{})
+	if err := DB.WithTimeout(time.Second).Create(&user).Error; err != nil {
+		t.Fatalf("failed to create user with timeout, got error %v", err)
+	}
+
+	var result2 User
+	tx := DB.WithTimeout(time.Second)
+	if err := tx.First(&result2, user.ID).Error; err != nil {
+		t.Errorf("failed to query user with timeout, got error %v", err)
+	}
+
+	if _, ok := tx.Statement.Context.Deadline(); ok {
+		t.Errorf("context of statement should be restored after executing")
+	}
+
+	rows, err := DB.WithTimeout(time.Second).Model(&User{}).Where("id = ?", user.ID).Rows()
+	if err != nil {
+		t.Fatalf("failed to query rows with timeout, got error %v", err)
+	}
+
+	var count int
+	for rows.Next() {
+		count++
+	}
+	rows.Close()
+
+	if rows.Err() != nil || count != 1 {
+		t.Errorf("rows should be readable after executing, got count %v, error %v", count, rows.Err())
+	}
+
+	if DB.Dialector.Name() == "mysql" {
+		stmt := DB.Session(&gorm.Session{DryRun: true}).WithTimeout(200 * time.Millisecond).Find(&User{}).Statement
+		if !strings.HasPrefix(stmt.SQL.String(), "SELECT /*+ MAX_EXECUTION_TIME(200) */ ") {
+			t.Errorf("query should be hinted with MAX_EXECUTION_TIME, but got %v", stmt.SQL.String())
+		}
+	}
+}
+
+func TestStatementTimeout(t *testing.T) {
+	stub := gormtest.NewStub()
+	db, _ := gorm.Open(capabilitiesDialector{Stub: stub, capabilities: gorm.Capabilities{SessionVariables: true, StatementTimeout: true}}, &gorm.Config{
+		SkipDefaultTransaction: true,
+		StatementTimeout:       time.Second,
+	})
+
+	db.WithTimeout(200 * time.Millisecond).Find(&[]User{})
+	if statements := stub.Statements(); len(statements) != 1 {
+		t.Errorf("statement out of transaction should be executed without transaction and statement_timeout, got %+v", statements)
+	}
+
+	stub.Reset()
+	db.WithTimeout(200 * time.Millisecond).Transaction(func(tx *gorm.DB) error {
+		return tx.Find(&[]User{}).Error
+	})
+	if statements := stub.Statements(); len(statements) != 2 || !reflect.DeepEqual(statements[0].Vars, []interface{}{"statement_timeout", "200"}) {
+		t.Errorf("statement_timeout should be set to timeout of statement, got %+v", statements)
+	}
+
+	stub.Reset()
+	db.Transaction(func(tx *gorm.DB) error {
+		return tx.Find(&[]User{}).Error
+	})
+	if statements := stub.Statements(); len(statements) != 2 || !reflect.DeepEqual(statements[0].Vars, []interface{}{"statement_timeout", "1000"}) {
+		t.Errorf("statement_timeout should be set to timeout of config, got %+v", statements)
+	}
+
+	stub.Reset()
+	db.Session(&gorm.Session{StatementTimeout: -1}).Transaction(func(tx *gorm.DB) error {
+		return tx.Find(&[]User{}).Error
+	})
+	if statements := stub.Statements(); len(statements) != 1 {
+		t.Errorf("statement_timeout should not be set if timeout is turned off, got %+v", statements)
+	}
+
+	stub.Reset()
+	db.WithTimeout(-1).Transaction(func(tx *gorm.DB) error {
+		return tx.Find(&[]User{}).Error
+	})
+	if statements := stub.Statements(); len(statements) != 1 {
+		t.Errorf("statement_timeout should not be set if timeout of statement is turned off, got %+v", statements)
+	}
+
+	stub.Reset()
+	if rows, err := db.Model(&User{}).Rows(); err != nil {
+		t.Errorf("Rows with timeout should be executed without transaction, got error %v", err)
+	} else {
+		rows.Close()
+	}
+
+	if statements := stub.Statements(); len(statements) != 1 {
+		t.Errorf("statement_timeout should not be set for Rows, got %+v", statements)
+	}
+
+	var deadlines []bool
+	db.Callback().Row().Before("gorm:row").Register("test:deadline", func(tx *gorm.DB) {
+		_, ok := tx.Statement.Context.Deadline()
+		deadlines = append(deadlines, ok)
+	})
+
+	if rows, err := db.WithTimeout(time.Second).Model(&User{}).Rows(); err == nil {
+		rows.Close()
+	}
+
+	var count int64
+	db.WithTimeout(time.Second).Raw("SELECT count(*) FROM users").Scan(&count)
+	if !reflect.DeepEqual(deadlines, []bool{false, true}) {
+		t.Errorf("Rows should ignore timeout and Scan should read rows with timeout, got %v", deadlines)
+	}
+}
@@ -0,0 +1,74 @@
+package tests_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type TimeZoneEvent struct {
+	ID         uint
+	Name       string
+	StartAt    time.Time  `gorm:"timezone:UTC"`
+	EndAt      *time.Time `gorm:"timezone:UTC;readTimezone:Asia/Shanghai"`
+	ReportedAt time.Time
+	NotifyAt   time.Time `gorm:"timezone:UTC;serializer:json"`
+}
+
+func TestTimeZoneFields(t *testing.T) {
+	DB.Migrator().DropTable(&TimeZoneEvent{})
+	if err := DB.AutoMigrate(&TimeZoneEvent{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+	shanghai, _ := time.LoadLocation("Asia/Shanghai")
+	startAt := time.Date(2021, 1, 2, 9, 0, 0, 0, tokyo)
+	endAt := startAt.Add(time.Hour)
+
+	event := TimeZoneEvent{Name: "event", StartAt: startAt, EndAt: &endAt, ReportedAt: startAt, NotifyAt: startAt}
+	if err := DB.Create(&event).Error; err != nil {
+		t.Fatalf("failed to create event, got error %v", err)
+	}
+
+	if DB.Dialector.Name() == "sqlite" {
+		var raw string
+		DB.Table("time_zone_events").Select("start_at").Where("id = ?", event.ID).Scan(&raw)
+		if !strings.HasPrefix(raw, "2021-01-02") || !strings.Contains(raw, "00:00:00") {
+			t.Errorf("start at should be saved in UTC, got %v", raw)
+		}
+	}
+
+	var notifyAt string
+	DB.Table("time_zone_events").Select("notify_at").Where("id = ?", event.ID).Scan(&notifyAt)
+	if notifyAt != `"2021-01-02T00:00:00Z"` {
+		t.Errorf("notify at should be converted to UTC and serialized, got %v", notifyAt)
+	}
+
+	var result TimeZoneEvent
+	DB.First(&result, event.ID)
+	if result.StartAt.Location() != time.UTC || !result.StartAt.Equal(startAt) {
+		t.Errorf("start at should be read in UTC, got %v", result.StartAt)
+	}
+
+	if result.EndAt == nil || result.EndAt.Location().String() != shanghai.String() || !result.EndAt.Equal(endAt) {
+		t.Errorf("end at should be read in Asia/Shanghai, got %v", result.EndAt)
+	}
+
+	DB.Model(&result).Update("StartAt", startAt.Add(time.Hour))
+	DB.First(&result, event.ID)
+	if result.StartAt.Location() != time.UTC || !result.StartAt.Equal(startAt.Add(time.Hour)) {
+		t.Errorf("start at should be updated in UTC, got %v", result.StartAt)
+	}
+
+	tx := DB.Session(&gorm.Session{NewDB: true})
+	config := *DB.Config
+	config.TimeZone = shanghai
+	tx.Config = &config
+	tx.First(&result, event.ID)
+	if result.ReportedAt.Location().String() != shanghai.String() || !result.ReportedAt.Equal(startAt) || result.StartAt.Location() != time.UTC {
+		t.Errorf("reported at should be read in time zone of config, got %v, %v", result.ReportedAt, result.StartAt)
+	}
+}
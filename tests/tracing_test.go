@@ -0,0 +1,89 @@
+package tests_test
+
+import (
+	"context"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/tracing"
+	. "gorm.io/gorm/utils/tests"
+)
+
+type spanKey struct{}
+
+type testSpan struct {
+	name  string
+	attrs map[string]interface{}
+	err   error
+	ended bool
+}
+
+func (s *testSpan) SetAttributes(attrs ...tracing.Attribute) {
+	for _, attr := range attrs {
+		s.attrs[attr.Key] = attr.Value
+	}
+}
+
+func (s *testSpan) RecordError(err error) { s.err = err }
+
+func (s *testSpan) End() { s.ended = true }
+
+type testTracer struct {
+	spans     []*testSpan
+	durations []float64
+}
+
+func (t *testTracer) Start(ctx context.Context, name string) (context.Context, tracing.Span) {
+	span := &testSpan{name: name, attrs: map[string]interface{}{}}
+	t.spans = append(t.spans, span)
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+func (t *testTracer) Record(ctx context.Context, value float64, attrs ...tracing.Attribute) {
+	t.durations = append(t.durations, value)
+}
+
+func TestTracing(t *testing.T) {
+	db, err := gorm.Open(DB.Dialector, &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open database, got error %v", err)
+	}
+
+	tracer := &testTracer{}
+	if err := db.Use(tracing.New(tracing.Config{Tracer: tracer, Duration: tracer})); err != nil {
+		t.Fatalf("failed to use plugin, got error %v", err)
+	}
+
+	var spanInCallback interface{}
+	db.Callback().Create().Before("gorm:create").Register("test:span_context", func(tx *gorm.DB) {
+		spanInCallback = tx.Statement.Context.Value(spanKey{})
+	})
+
+	ctx := context.Background()
+	user := *GetUser("tracing", Config{})
+	db.WithContext(ctx).Create(&user)
+	db.WithContext(ctx).Where("name = ?", "tracing").First(&User{})
+	db.Table("non_existing_tracing_table").Find(&[]User{})
+
+	if len(tracer.spans) != 3 || len(tracer.durations) != 3 {
+		t.Fatalf("should have 3 spans and durations, got %v, %v", len(tracer.spans), len(tracer.durations))
+	}
+
+	create, query, failed := tracer.spans[0], tracer.spans[1], tracer.spans[2]
+	if create.name != "gorm.create" || !create.ended || create.attrs[tracing.AttributeTable] != "users" ||
+		create.attrs[tracing.AttributeRowsAffected] != int64(1) || create.attrs[tracing.AttributeSystem] != DB.Dialector.Name() {
+		t.Errorf("invalid create span, got %#v", create)
+	}
+
+	if spanInCallback != create {
+		t.Errorf("span context should be propagated to statement")
+	}
+
+	if query.name != "gorm.query" || query.attrs[tracing.AttributeOperation] != "query" || query.attrs[tracing.AttributeStatement] == nil || query.err != nil {
+		t.Errorf("invalid query span, got %#v", query)
+	}
+
+	if failed.err == nil {
+		t.Errorf("error should be recorded in span")
+	}
+}
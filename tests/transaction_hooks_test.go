@@ -0,0 +1,100 @@
+package tests_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+	. "gorm.io/gorm/utils/tests"
+)
+
+type CommitHookProduct struct {
+	gorm.Model
+	Name string
+}
+
+var commitHookEvents []string
+
+func (p *CommitHookProduct) AfterCommit(ctx context.Context) {
+	commitHookEvents = append(commitHookEvents, "commit:"+p.Name)
+}
+
+func (p *CommitHookProduct) AfterRollback(ctx context.Context) {
+	commitHookEvents = append(commitHookEvents, "rollback:"+p.Name)
+}
+
+func TestAfterCommitAndAfterRollback(t *testing.T) {
+	var events []string
+	errRollback := errors.New("rollback")
+
+	DB.Transaction(func(tx *gorm.DB) error {
+		tx.AfterCommit(func(ctx context.Context) { events = append(events, "commit") })
+		tx.AfterRollback(func(ctx context.Context) { events = append(events, "rollback") })
+
+		tx.Transaction(func(tx2 *gorm.DB) error {
+			tx2.AfterCommit(func(ctx context.Context) { events = append(events, "nested_rollbacked_commit") })
+			return errRollback
+		})
+
+		tx.Transaction(func(tx2 *gorm.DB) error {
+			tx2.AfterCommit(func(ctx context.Context) { events = append(events, "nested_commit") })
+			return nil
+		})
+
+		if len(events) != 0 {
+			t.Errorf("hooks should not run before transaction resolved, but got %v", events)
+		}
+		return nil
+	})
+
+	AssertEqual(t, events, []string{"commit", "nested_commit"})
+
+	events = nil
+	DB.Transaction(func(tx *gorm.DB) error {
+		tx.AfterCommit(func(ctx context.Context) { events = append(events, "commit") })
+		tx.AfterRollback(func(ctx context.Context) { events = append(events, "rollback") })
+		return errRollback
+	})
+
+	AssertEqual(t, events, []string{"rollback"})
+
+	events = nil
+	tx := DB.Begin()
+	tx.Session(&gorm.Session{}).AfterCommit(func(ctx context.Context) { events = append(events, "commit") })
+	tx.Commit()
+	tx.Rollback()
+	AssertEqual(t, events, []string{"commit"})
+
+	events = nil
+	DB.AfterCommit(func(ctx context.Context) { events = append(events, "no_transaction") })
+	AssertEqual(t, events, []string{"no_transaction"})
+}
+
+func TestModelAfterCommitAndAfterRollback(t *testing.T) {
+	DB.Migrator().DropTable(&CommitHookProduct{})
+	if err := DB.AutoMigrate(&CommitHookProduct{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	commitHookEvents = nil
+	DB.Create(&CommitHookProduct{Name: "default_transaction"})
+	AssertEqual(t, commitHookEvents, []string{"commit:default_transaction"})
+
+	commitHookEvents = nil
+	DB.Transaction(func(tx *gorm.DB) error {
+		tx.Create(&[]CommitHookProduct{{Name: "product1"}, {Name: "product2"}})
+		if len(commitHookEvents) != 0 {
+			t.Errorf("hooks of models should not run before transaction committed, but got %v", commitHookEvents)
+		}
+		return nil
+	})
+	AssertEqual(t, commitHookEvents, []string{"commit:product1", "commit:product2"})
+
+	commitHookEvents = nil
+	DB.Transaction(func(tx *gorm.DB) error {
+		tx.Create(&CommitHookProduct{Name: "rollbacked"})
+		return errors.New("rollback")
+	})
+	AssertEqual(t, commitHookEvents, []string{"rollback:rollbacked"})
+}
@@ -2,10 +2,13 @@ package tests_test
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"testing"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/gormtest"
 	. "gorm.io/gorm/utils/tests"
 )
 
@@ -366,3 +369,158 @@ func TestTransactionOnClosedConn(t *testing.T) {
 		t.Errorf("should returns error when commit with closed conn, got error %v", err)
 	}
 }
+
+func TestTransactionWithOptions(t *testing.T) {
+	user := *GetUser("transaction_with_options", Config{})
+	DB.Create(&user)
+
+	if err := DB.Transaction(func(tx *gorm.DB) error {
+		var result User
+		return tx.First(&result, user.ID).Error
+	}, &sql.TxOptions{ReadOnly: true}); err != nil {
+		t.Errorf("should be able to query in read-only transaction, but got %v", err)
+	}
+
+	if err := DB.TransactionWithOptions(func(tx *gorm.DB) error {
+		var result User
+		return tx.First(&result, user.ID).Error
+	}, gorm.TxOptions{Isolation: sql.LevelSerializable, ReadOnly: true, Deferrable: true}); err != nil {
+		t.Errorf("should be able to query in read-only deferrable transaction, but got %v", err)
+	}
+
+	if DB.Dialector.Name() == "postgres" || DB.Dialector.Name() == "mysql" {
+		if err := DB.TransactionWithOptions(func(tx *gorm.DB) error {
+			return tx.Model(&user).Update("name", "transaction_with_options_updated").Error
+		}, gorm.TxOptions{ReadOnly: true}); err == nil {
+			t.Errorf("should failed to update in read-only transaction")
+		}
+	}
+
+	tx := DB.BeginWithOptions(gorm.TxOptions{ReadOnly: true})
+	if tx.Error != nil {
+		t.Fatalf("failed to begin transaction with options, got error %v", tx.Error)
+	}
+	tx.Rollback()
+
+	var begin func(...*sql.TxOptions) *gorm.DB = DB.Begin
+	if tx := begin(); tx.Error != nil {
+		t.Errorf("Begin should accept *sql.TxOptions, got error %v", tx.Error)
+	} else {
+		tx.Rollback()
+	}
+}
+
+func TestTransactionRetry(t *testing.T) {
+	errConflict := errors.New("conflict")
+	db, _ := gorm.Open(DB.Dialector, &gorm.Config{TransactionRetry: &gorm.RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   time.Millisecond,
+		Retryable:   func(err error) bool { return errors.Is(err, errConflict) },
+	}})
+
+	var attempts int
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		attempts++
+		if err := tx.Create(GetUser("transaction_retry", Config{})).Error; err != nil {
+			return err
+		}
+
+		if attempts < 2 {
+			return errConflict
+		}
+		return nil
+	}); err != nil || attempts != 2 {
+		t.Errorf("transaction should be retried once, but got error %v, attempts %v", err, attempts)
+	}
+
+	var count int64
+	if DB.Model(&User{}).Where("name = ?", "transaction_retry").Count(&count); count != 1 {
+		t.Errorf("changes of failed attempts should be rollbacked, but got %v records", count)
+	}
+
+	attempts = 0
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		attempts++
+		return errConflict
+	}); !errors.Is(err, errConflict) || attempts != 3 {
+		t.Errorf("transaction should be retried until max attempts, but got error %v, attempts %v", err, attempts)
+	}
+
+	attempts = 0
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		attempts++
+		return errors.New("not retryable")
+	}); err == nil || attempts != 1 {
+		t.Errorf("transaction should not be retried for other errors, but got error %v, attempts %v", err, attempts)
+	}
+}
+
+func TestSavePointAuto(t *testing.T) {
+	if err := DB.Transaction(func(tx *gorm.DB) error {
+		if depth := tx.SavePointDepth(); depth != 0 {
+			t.Errorf("savepoint depth should be 0, but got %v", depth)
+		}
+
+		user := *GetUser("savepoint_auto", Config{})
+		name, release, _ := tx.SavePointAuto()
+		if name == "" || tx.SavePointDepth() != 1 {
+			t.Fatalf("failed to create savepoint, name %v, depth %v", name, tx.SavePointDepth())
+		}
+
+		if err := tx.Create(&user).Error; err != nil {
+			t.Fatalf("failed to create user, got error %v", err)
+		}
+
+		name2, _, rollback2 := tx.SavePointAuto()
+		if name2 == name || tx.SavePointDepth() != 2 {
+			t.Errorf("nested savepoint should have different name, got %v, %v, depth %v", name, name2, tx.SavePointDepth())
+		}
+
+		if err := tx.Model(&user).Update("name", "savepoint_auto_updated").Error; err != nil {
+			t.Fatalf("failed to update user, got error %v", err)
+		}
+
+		if err := rollback2(); err != nil || tx.SavePointDepth() != 2 {
+			t.Errorf("failed to rollback to savepoint, got error %v, depth %v", err, tx.SavePointDepth())
+		}
+
+		if err := tx.Transaction(func(tx2 *gorm.DB) error {
+			if depth := tx2.SavePointDepth(); depth != 3 {
+				t.Errorf("savepoint depth of nested transaction should be 3, but got %v", depth)
+			}
+			return nil
+		}); err != nil {
+			t.Errorf("failed to run nested transaction, got error %v", err)
+		}
+
+		if err := release(); err != nil || tx.SavePointDepth() != 0 {
+			t.Errorf("failed to release savepoint, got error %v, depth %v", err, tx.SavePointDepth())
+		}
+
+		var result User
+		if err := tx.First(&result, user.ID).Error; err != nil || result.Name != "savepoint_auto" {
+			t.Errorf("update should be rollbacked, got %v, error %v", result.Name, err)
+		}
+
+		return nil
+	}); err != nil {
+		t.Errorf("failed to run transaction, got error %v", err)
+	}
+
+	if depth := DB.SavePointDepth(); depth != 0 {
+		t.Errorf("savepoint depth out of transaction should be 0, but got %v", depth)
+	}
+}
+
+func TestReleaseSavePointQuoted(t *testing.T) {
+	stub := gormtest.NewStub()
+	db, _ := gorm.Open(stub, &gorm.Config{SkipDefaultTransaction: true})
+
+	if err := db.ReleaseSavePoint("sp; DROP TABLE users").Error; err != nil {
+		t.Fatalf("failed to release savepoint, got error %v", err)
+	}
+
+	if statements := stub.Statements(); len(statements) != 1 || statements[0].SQL != "RELEASE SAVEPOINT `sp; DROP TABLE users`" {
+		t.Errorf("name of savepoint should be quoted, got %+v", statements)
+	}
+}
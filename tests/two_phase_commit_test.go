@@ -0,0 +1,78 @@
+package tests_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/gormtest"
+	. "gorm.io/gorm/utils/tests"
+)
+
+func TestCoordinatorTransaction(t *testing.T) {
+	db2 := DB.Session(&gorm.Session{})
+	coordinator := gorm.NewCoordinator(DB, db2)
+
+	if name := DB.Dialector.Name(); name != "mysql" {
+		var called bool
+		err := coordinator.Transaction(context.Background(), func(txs []*gorm.DB) error {
+			called = true
+			return nil
+		})
+
+		if name == "postgres" {
+			t.Skip("two-phase commit of postgres requires max_prepared_transactions")
+		}
+
+		if !errors.Is(err, gorm.ErrUnsupportedDriver) || called {
+			t.Errorf("should returns ErrUnsupportedDriver for %v, but got %v", name, err)
+		}
+		return
+	}
+
+	users := []User{*GetUser("two_phase_commit_1", Config{}), *GetUser("two_phase_commit_2", Config{})}
+	if err := coordinator.Transaction(context.Background(), func(txs []*gorm.DB) error {
+		for idx, tx := range txs {
+			if err := tx.Create(&users[idx]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		t.Fatalf("failed to commit with two-phase commit, got error %v", err)
+	}
+
+	var count int64
+	if DB.Model(&User{}).Where("name IN ?", []string{"two_phase_commit_1", "two_phase_commit_2"}).Count(&count); count != 2 {
+		t.Errorf("users should be committed, but got %v", count)
+	}
+
+	if err := coordinator.Transaction(context.Background(), func(txs []*gorm.DB) error {
+		txs[0].Create(GetUser("two_phase_rollback", Config{}))
+		return errors.New("rollback")
+	}); err == nil {
+		t.Errorf("should returns error of function")
+	}
+
+	if DB.Model(&User{}).Where("name = ?", "two_phase_rollback").Count(&count); count != 0 {
+		t.Errorf("users should be rollbacked, but got %v", count)
+	}
+}
+
+func TestCoordinatorRollbackCanceledContext(t *testing.T) {
+	stub := gormtest.NewStub()
+	db, _ := gorm.Open(capabilitiesDialector{Stub: stub, capabilities: gorm.Capabilities{TwoPhaseCommit: true}}, &gorm.Config{SkipDefaultTransaction: true})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := gorm.NewCoordinator(db).Transaction(ctx, func(txs []*gorm.DB) error {
+		cancel()
+		return context.Canceled
+	}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("should returns error of function, got %v", err)
+	}
+
+	if statements := stub.Statements(); len(statements) != 2 || statements[0].SQL != "BEGIN" || statements[1].SQL != "ROLLBACK" {
+		t.Errorf("should rollback transaction after context canceled, got %+v", statements)
+	}
+}
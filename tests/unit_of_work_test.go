@@ -0,0 +1,136 @@
+package tests_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+type UowCompany struct {
+	ID   uint
+	Name string
+}
+
+type UowEmployee struct {
+	ID        uint
+	Name      string
+	CompanyID *uint
+	Company   *UowCompany
+	Tasks     []*UowTask
+}
+
+type UowTask struct {
+	ID            uint
+	UowEmployeeID uint
+	Title         string
+}
+
+func TestUnitOfWork(t *testing.T) {
+	DB.Migrator().DropTable(&UowCompany{}, &UowEmployee{}, &UowTask{})
+	if err := DB.AutoMigrate(&UowCompany{}, &UowEmployee{}, &UowTask{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	employee := UowEmployee{Name: "uow"}
+	DB.Create(&employee)
+
+	var (
+		uow           = DB.UnitOfWork()
+		loaded, again *UowEmployee
+	)
+
+	if err := uow.Find(&loaded, employee.ID); err != nil {
+		t.Fatalf("failed to find employee, got error %v", err)
+	}
+
+	if err := uow.Find(&again, employee.ID); err != nil || again != loaded {
+		t.Fatalf("should load the same pointer, but got %p, %p, error %v", loaded, again, err)
+	}
+
+	if typed, err := gorm.Load[UowEmployee](uow, employee.ID); err != nil || typed != loaded {
+		t.Fatalf("should load the same pointer with Load, but got %p, %p, error %v", loaded, typed, err)
+	}
+
+	if dirty := uow.Dirty(loaded); len(dirty) != 0 {
+		t.Errorf("loaded employee should not be dirty, but got %v", dirty)
+	}
+
+	var (
+		company = &UowCompany{Name: "uow_company"}
+		task    = &UowTask{Title: "uow_task"}
+	)
+
+	loaded.Name = "uow_renamed"
+	loaded.Company = company
+	loaded.Tasks = append(loaded.Tasks, task)
+
+	if dirty := uow.Dirty(loaded); !reflect.DeepEqual(dirty, []string{"name"}) {
+		t.Errorf("dirty columns should be name, but got %v", dirty)
+	}
+
+	// task is added before company, the company is created before employee belongs to it
+	uow.Add(task)
+	uow.Add(company)
+
+	if err := uow.Commit(); err != nil {
+		t.Fatalf("failed to commit, got error %v", err)
+	}
+
+	var result UowEmployee
+	DB.Preload("Company").Preload("Tasks").First(&result, employee.ID)
+	if result.Name != "uow_renamed" || result.Company == nil || result.Company.Name != "uow_company" ||
+		len(result.Tasks) != 1 || result.Tasks[0].Title != "uow_task" {
+		t.Errorf("changes should be flushed, but got %#v", result)
+	}
+
+	if dirty := uow.Dirty(loaded); len(dirty) != 0 {
+		t.Errorf("committed employee should not be dirty, but got %v", dirty)
+	}
+
+	var loadedCompany *UowCompany
+	if err := uow.Find(&loadedCompany, company.ID); err != nil || loadedCompany != company {
+		t.Errorf("created company should be tracked, but got %p, %p, error %v", company, loadedCompany, err)
+	}
+
+	uow.Remove(task)
+	if err := uow.Commit(); err != nil {
+		t.Fatalf("failed to commit, got error %v", err)
+	}
+
+	var count int64
+	DB.Model(&UowTask{}).Where("id = ?", task.ID).Count(&count)
+	if count != 0 {
+		t.Errorf("removed task should be deleted")
+	}
+
+	var removed *UowTask
+	if err := uow.Find(&removed, task.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("removed task should not be found, but got %v", err)
+	}
+
+	if err := uow.Remove(&UowTask{}); !errors.Is(err, gorm.ErrInvalidData) {
+		t.Errorf("should returns invalid data for untracked record, but got %v", err)
+	}
+
+	// added records with primary key are found without querying
+	added := &UowCompany{ID: company.ID + 100, Name: "uow_added"}
+	if err := uow.Add(added); err != nil {
+		t.Fatalf("failed to add company, got error %v", err)
+	}
+
+	var addedCompany *UowCompany
+	if err := uow.Find(&addedCompany, added.ID); err != nil || addedCompany != added {
+		t.Errorf("added company should be found without querying, but got %p, %p, error %v", added, addedCompany, err)
+	}
+
+	if err := uow.Add(&UowCompany{ID: added.ID}); !errors.Is(err, gorm.ErrInvalidData) {
+		t.Errorf("should returns invalid data for record with tracked primary key, but got %v", err)
+	}
+
+	uow.Remove(added)
+	if err := uow.Find(&addedCompany, added.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("removed added company should not be found, but got %v", err)
+	}
+}
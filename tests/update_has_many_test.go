@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	. "gorm.io/gorm/utils/tests"
 )
 
@@ -79,4 +80,69 @@ func TestUpdateHasManyAssociations(t *testing.T) {
 		DB.Preload("Toys").Find(&user4, "id = ?", user.ID)
 		CheckUser(t, user4, user)
 	})
+	t.Run("PerRelation", func(t *testing.T) {
+		var user = *GetUser("update-has-many-per-relation", Config{Pets: 1, Toys: 1})
+		user.Pets[0].Toy = Toy{Name: "pet-toy"}
+
+		if err := DB.Create(&user).Error; err != nil {
+			t.Fatalf("errors happened when create: %v", err)
+		}
+
+		user.Pets[0].Name += "new"
+		user.Pets[0].Toy.Name += "new"
+		user.Toys[0].Name += "new"
+
+		if err := DB.Set("gorm:full_save_associations", map[string]bool{"Pets": true, "Pets.Toy": false}).Save(&user).Error; err != nil {
+			t.Fatalf("errors happened when update: %v", err)
+		}
+
+		var user2 User
+		DB.Preload("Pets.Toy").Preload("Toys").Find(&user2, "id = ?", user.ID)
+		if user2.Pets[0].Name != user.Pets[0].Name {
+			t.Errorf("pets should be updated, expects %v, got %v", user.Pets[0].Name, user2.Pets[0].Name)
+		}
+
+		if user2.Pets[0].Toy.Name != "pet-toy" {
+			t.Errorf("pet's toy should not be updated, but got %v", user2.Pets[0].Toy.Name)
+		}
+
+		if user2.Toys[0].Name == user.Toys[0].Name {
+			t.Errorf("toys should not be updated, but got %v", user2.Toys[0].Name)
+		}
+
+		if err := DB.Set("gorm:full_save_associations", map[string]bool{clause.Associations: true}).Save(&user).Error; err != nil {
+			t.Fatalf("errors happened when update: %v", err)
+		}
+
+		var user3 User
+		DB.Preload("Pets.Toy").Preload("Toys").Find(&user3, "id = ?", user.ID)
+		if user3.Pets[0].Toy.Name != user.Pets[0].Toy.Name || user3.Toys[0].Name != user.Toys[0].Name {
+			t.Errorf("all associations should be updated, but got %+v, %+v", user3.Pets[0].Toy, user3.Toys[0])
+		}
+	})
+	t.Run("PerRelationAssociation", func(t *testing.T) {
+		var user = *GetUser("update-has-many-per-relation-association", Config{Pets: 1})
+		user.Pets[0].Toy = Toy{Name: "pet-toy"}
+
+		if err := DB.Create(&user).Error; err != nil {
+			t.Fatalf("errors happened when create: %v", err)
+		}
+
+		user.Pets[0].Name += "new"
+		user.Pets[0].Toy.Name += "new"
+
+		if err := DB.Set("gorm:full_save_associations", map[string]bool{"Pets": true, "Pets.Toy": false}).Model(&user).Association("Pets").Replace(user.Pets); err != nil {
+			t.Fatalf("errors happened when replace: %v", err)
+		}
+
+		var user2 User
+		DB.Preload("Pets.Toy").Find(&user2, "id = ?", user.ID)
+		if len(user2.Pets) != 1 || user2.Pets[0].Name != user.Pets[0].Name {
+			t.Fatalf("pets should be updated, expects %v, got %+v", user.Pets[0].Name, user2.Pets)
+		}
+
+		if user2.Pets[0].Toy.Name != "pet-toy" {
+			t.Errorf("pet's toy should not be updated, but got %v", user2.Pets[0].Toy.Name)
+		}
+	})
 }
@@ -2,6 +2,7 @@ package tests_test
 
 import (
 	"errors"
+	"reflect"
 	"regexp"
 	"sort"
 	"strings"
@@ -685,3 +686,54 @@ func TestSaveWithPrimaryValue(t *testing.T) {
 		t.Errorf("failed to find created record, got error: %v, result: %+v", err, result4)
 	}
 }
+
+func TestSaveWithPrimaryValueAllowEmpty(t *testing.T) {
+	lang := Language{Code: "save_allow_empty", Name: "save_allow_empty"}
+	if result := DB.AllowEmpty().Save(&lang); result.Error != nil || result.RowsAffected != 1 {
+		t.Errorf("should create language, got error %v, rows affected: %v", result.Error, result.RowsAffected)
+	}
+
+	var result Language
+	if err := DB.First(&result, "code = ?", lang.Code).Error; err != nil {
+		t.Fatalf("failed to find created record, got error %v", err)
+	}
+	AssertEqual(t, result, lang)
+}
+
+func TestSnapshotChanges(t *testing.T) {
+	user := *GetUser("snapshot", Config{})
+	DB.Create(&user)
+
+	var result User
+	tx := DB.First(&result, user.ID).Snapshot(&result)
+	if changes := tx.Changes(&result); len(changes) != 0 {
+		t.Errorf("snapshotted user should not be changed, but got %v", changes)
+	}
+
+	result.Name = "snapshot_changed"
+	result.Age = 0
+	if changes := tx.Changes(&result); !reflect.DeepEqual(changes, []string{"name", "age"}) {
+		t.Errorf("changes should be name and age, but got %v", changes)
+	}
+
+	user.Name = "snapshot_concurrently_changed"
+	DB.Model(&user).Update("active", true)
+
+	if err := tx.SaveChanges(&result).Error; err != nil {
+		t.Fatalf("failed to save changes, got error %v", err)
+	}
+
+	var updated User
+	DB.First(&updated, user.ID)
+	if updated.Name != "snapshot_changed" || updated.Age != 0 || !updated.Active {
+		t.Errorf("only changed columns should be updated, but got %#v", updated)
+	}
+
+	if changes := tx.Changes(&result); len(changes) != 0 {
+		t.Errorf("saved user should be snapshotted again, but got %v", changes)
+	}
+
+	if err := DB.SaveChanges(&result).Error; !errors.Is(err, gorm.ErrInvalidData) {
+		t.Errorf("should returns invalid data for records not snapshotted, but got %v", err)
+	}
+}
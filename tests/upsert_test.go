@@ -1,10 +1,14 @@
 package tests_test
 
 import (
+	"errors"
+	"strings"
 	"testing"
 	"time"
 
+	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
+	"gorm.io/gorm/gormtest"
 	. "gorm.io/gorm/utils/tests"
 )
 
@@ -274,3 +278,172 @@ func TestFindOrCreate(t *testing.T) {
 		t.Errorf("belongs to association should be saved")
 	}
 }
+
+func TestFirstOrCreateAtomic(t *testing.T) {
+	var lang1, lang2, lang3 Language
+	if err := DB.Where(Language{Code: "first-or-create-atomic"}).Attrs(Language{Name: "Atomic"}).FirstOrCreateAtomic(&lang1).Error; err != nil {
+		t.Fatalf("no error should happen when FirstOrCreateAtomic, but got %v", err)
+	}
+
+	if lang1.Code != "first-or-create-atomic" || lang1.Name != "Atomic" {
+		t.Errorf("language should be created with search value and attrs, but got %+v", lang1)
+	}
+
+	if err := DB.Where(Language{Code: "first-or-create-atomic"}).Attrs(Language{Name: "Atomic-New"}).FirstOrCreateAtomic(&lang2).Error; err != nil {
+		t.Fatalf("no error should happen when FirstOrCreateAtomic, but got %v", err)
+	}
+
+	if lang2.Name != "Atomic" {
+		t.Errorf("existing language should be found and not initialized by Attrs, but got %+v", lang2)
+	}
+
+	if err := DB.Assign(Language{Name: "Atomic-Assigned"}).FirstOrCreateAtomic(&lang3, map[string]interface{}{"code": "first-or-create-atomic"}).Error; err != nil {
+		t.Fatalf("no error should happen when FirstOrCreateAtomic, but got %v", err)
+	}
+
+	var langs []Language
+	if err := DB.Find(&langs, "code = ?", "first-or-create-atomic").Error; err != nil {
+		t.Errorf("no error should happen when find languages with code, but got %v", err)
+	} else if len(langs) != 1 || langs[0].Name != "Atomic-Assigned" || lang3.Name != "Atomic-Assigned" {
+		t.Errorf("should only find one language updated with assigned attrs, but got %+v", langs)
+	}
+
+	// name and age aren't an unique key, they can't be the conflict target
+	var user User
+	if err := DB.Where(User{Name: "first-or-create-atomic", Age: 18}).FirstOrCreateAtomic(&user).Error; !errors.Is(err, gorm.ErrUniqueKeyRequired) {
+		t.Fatalf("FirstOrCreateAtomic without unique key should returns ErrUniqueKeyRequired, but got %v", err)
+	}
+
+	var count int64
+	if DB.Model(&User{}).Where("name = ?", "first-or-create-atomic").Count(&count); count != 0 || user.ID != 0 {
+		t.Errorf("user shouldn't be created without unique key, but got %v, %+v", count, user)
+	}
+}
+
+func TestFirstOrCreateAtomicWithReturning(t *testing.T) {
+	stub := gormtest.NewStub()
+	db, _ := gorm.Open(capabilitiesDialector{Stub: stub, capabilities: gorm.Capabilities{Returning: true, OnConflict: true}}, &gorm.Config{SkipDefaultTransaction: true})
+
+	sql := "INSERT INTO `languages` (`code`,`name`) VALUES (?,?) ON CONFLICT (`code`) DO UPDATE SET `code`=`excluded`.`code` RETURNING *"
+	stub.On(sql, gormtest.Result{Columns: []string{"code", "name"}, Rows: [][]interface{}{{"atomic-returning", "Atomic"}}})
+
+	var lang Language
+	if err := db.Where(Language{Code: "atomic-returning"}).Attrs(Language{Name: "Atomic-New"}).FirstOrCreateAtomic(&lang).Error; err != nil {
+		t.Fatalf("no error should happen when FirstOrCreateAtomic, but got %v", err)
+	}
+
+	if lang.Code != "atomic-returning" || lang.Name != "Atomic" {
+		t.Errorf("existing language should be returned by RETURNING, but got %+v", lang)
+	}
+
+	if statements := stub.Statements(); len(statements) != 1 || statements[0].SQL != sql {
+		t.Errorf("should find or create language with one statement, got %+v", statements)
+	}
+}
+
+func TestCreateWithReturning(t *testing.T) {
+	stub := gormtest.NewStub()
+	db, _ := gorm.Open(capabilitiesDialector{Stub: stub, capabilities: gorm.Capabilities{Returning: true}}, &gorm.Config{SkipDefaultTransaction: true})
+
+	sql := "INSERT INTO `languages` (`code`,`name`) VALUES (?,?),(?,?) RETURNING *"
+	stub.On(sql, gormtest.Result{Columns: []string{"code", "name"}, Rows: [][]interface{}{{"returning-1", "Returned-1"}, {"returning-2", "Returned-2"}}})
+
+	lang1, lang2 := &Language{Code: "returning-1"}, &Language{Code: "returning-2"}
+	if err := db.Clauses(clause.Returning{}).Create(&[]*Language{lang1, lang2}).Error; err != nil {
+		t.Fatalf("no error should happen when creating with RETURNING, but got %v", err)
+	}
+
+	if lang1.Name != "Returned-1" || lang2.Name != "Returned-2" {
+		t.Errorf("returned values should be scanned into created records, got %+v, %+v", lang1, lang2)
+	}
+
+	langs := [2]Language{{Code: "returning-1"}, {Code: "returning-2"}}
+	if err := db.Clauses(clause.Returning{}).Create(&langs).Error; err != nil {
+		t.Fatalf("no error should happen when creating array with RETURNING, but got %v", err)
+	}
+
+	if langs[0].Name != "Returned-1" || langs[1].Name != "Returned-2" {
+		t.Errorf("returned values should be scanned into created array, got %+v", langs)
+	}
+
+	stub.Reset()
+	db, _ = gorm.Open(capabilitiesDialector{Stub: stub, capabilities: gorm.Capabilities{}}, &gorm.Config{SkipDefaultTransaction: true})
+	if err := db.Clauses(clause.Returning{}).Create(&Language{Code: "returning-3"}).Error; err != nil {
+		t.Fatalf("no error should happen when creating without RETURNING support, but got %v", err)
+	}
+
+	if statements := stub.Statements(); len(statements) != 1 || strings.Contains(statements[0].SQL, "RETURNING") {
+		t.Errorf("RETURNING should be ignored if it isn't supported, got %+v", statements)
+	}
+}
+
+func TestUpdateOrCreate(t *testing.T) {
+	var lang Language
+	if err := DB.UpdateOrCreate(&lang, Language{Code: "update-or-create"}, Language{Name: "Update-Or-Create"}).Error; err != nil {
+		t.Fatalf("no error should happen when UpdateOrCreate, but got %v", err)
+	}
+
+	if lang.Code != "update-or-create" || lang.Name != "Update-Or-Create" {
+		t.Errorf("language should be created with attrs and values, but got %+v", lang)
+	}
+
+	var lang2 Language
+	if err := DB.UpdateOrCreate(&lang2, map[string]interface{}{"code": "update-or-create"}, map[string]interface{}{"name": "Update-Or-Create-New"}).Error; err != nil {
+		t.Fatalf("no error should happen when UpdateOrCreate, but got %v", err)
+	}
+
+	var langs []Language
+	if err := DB.Find(&langs, "code = ?", "update-or-create").Error; err != nil {
+		t.Errorf("no error should happen when find languages with code, but got %v", err)
+	} else if len(langs) != 1 || langs[0].Name != "Update-Or-Create-New" || lang2.Name != "Update-Or-Create-New" {
+		t.Errorf("should only find one language updated with values, but got %+v", langs)
+	}
+
+	var user1, user2 User
+	if err := DB.UpdateOrCreate(&user1, User{Name: "update or create"}, User{Age: 20}).Error; err != nil {
+		t.Fatalf("no error should happen when UpdateOrCreate, but got %v", err)
+	}
+
+	if user1.ID == 0 || user1.Name != "update or create" || user1.Age != 20 {
+		t.Errorf("user should be created with attrs and values, but got %+v", user1)
+	}
+
+	if err := DB.UpdateOrCreate(&user2, User{Name: "update or create"}, User{Age: 30}).Error; err != nil {
+		t.Fatalf("no error should happen when UpdateOrCreate, but got %v", err)
+	}
+
+	if user2.ID != user1.ID || user2.Age != 30 {
+		t.Errorf("user should be found and updated with values, but got %+v", user2)
+	}
+
+	var result User
+	if err := DB.First(&result, user1.ID).Error; err != nil || result.Age != 30 {
+		t.Errorf("user should be updated with values, but got %+v, error %v", result, err)
+	}
+}
+
+func TestUpdateOrCreateSoftDeleted(t *testing.T) {
+	type SoftDeletedLanguage struct {
+		Code      string `gorm:"primarykey"`
+		Name      string
+		DeletedAt gorm.DeletedAt
+	}
+
+	DB.Migrator().DropTable(&SoftDeletedLanguage{})
+	if err := DB.AutoMigrate(&SoftDeletedLanguage{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	lang := SoftDeletedLanguage{Code: "soft-deleted", Name: "Soft-Deleted"}
+	DB.Create(&lang)
+	DB.Delete(&lang)
+
+	var result SoftDeletedLanguage
+	if err := DB.UpdateOrCreate(&result, SoftDeletedLanguage{Code: "soft-deleted"}, SoftDeletedLanguage{Name: "Soft-Deleted-New"}).Error; err != nil {
+		t.Fatalf("should reload soft deleted record after upsert, but got %v", err)
+	}
+
+	if result.Code != "soft-deleted" || result.Name != "Soft-Deleted-New" || !result.DeletedAt.Valid {
+		t.Errorf("conflicted soft deleted record should be reloaded, but got %+v", result)
+	}
+}
@@ -0,0 +1,81 @@
+package tests_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+type ValidatedAccount struct {
+	ID       uint
+	Name     string `gorm:"validate:required,max=10"`
+	Email    string `gorm:"validate:email"`
+	Role     string `gorm:"validate:oneof=admin member"`
+	Age      int    `gorm:"validate:min=18"`
+	Disabled bool
+}
+
+func (account *ValidatedAccount) Validate(ctx context.Context) error {
+	if account.Disabled && account.Role == "admin" {
+		return gorm.ValidationErrors{{Field: "Disabled", Message: "admin can't be disabled"}}
+	}
+	return nil
+}
+
+func TestValidation(t *testing.T) {
+	DB.Migrator().DropTable(&ValidatedAccount{})
+	if err := DB.AutoMigrate(&ValidatedAccount{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	account := ValidatedAccount{Name: "jinzhu", Email: "jinzhu@example.org", Role: "admin", Age: 20}
+	if err := DB.Create(&account).Error; err != nil {
+		t.Fatalf("failed to create valid account, got error %v", err)
+	}
+
+	err := DB.Create(&ValidatedAccount{Name: "a-very-long-name", Email: "invalid", Role: "guest", Age: 10}).Error
+	var errs gorm.ValidationErrors
+	if !errors.As(err, &errs) || !errors.Is(err, gorm.ErrValidationFailed) {
+		t.Fatalf("should returns ValidationErrors, got %v", err)
+	}
+
+	rules := map[string]string{}
+	for _, e := range errs {
+		rules[e.Field] = e.Rule
+	}
+	if len(errs) != 4 || rules["Name"] != "max=10" || rules["Email"] != "email" || rules["Role"] != "oneof=admin member" || rules["Age"] != "min=18" {
+		t.Errorf("invalid validation errors, got %v", errs)
+	}
+
+	if err := DB.Create(&[]ValidatedAccount{{Name: "ok", Role: "member", Age: 30}, {Role: "member", Age: 30}}).Error; !errors.As(err, &errs) || len(errs) != 1 || errs[0].Field != "Name" || errs[0].Rule != "required" {
+		t.Errorf("should validate records in slice, got %v", err)
+	}
+
+	if err := DB.Model(&account).Update("Age", 17).Error; !errors.As(err, &errs) || len(errs) != 1 || errs[0].Field != "Age" {
+		t.Errorf("should validate updated column, got %v", err)
+	}
+
+	if err := DB.Model(&account).Updates(map[string]interface{}{"name": "", "email": "jinzhu@example.com"}).Error; !errors.As(err, &errs) || len(errs) != 1 || errs[0].Rule != "required" {
+		t.Errorf("should validate updated map, got %v", err)
+	}
+
+	if err := DB.Model(&account).Update("Age", gorm.Expr("age + ?", 1)).Error; err != nil {
+		t.Errorf("expressions should not be validated, got %v", err)
+	}
+
+	if err := DB.First(&account, account.ID).Error; err != nil {
+		t.Fatalf("failed to find account, got error %v", err)
+	}
+
+	account.Disabled = true
+	if err := DB.Save(&account).Error; !errors.As(err, &errs) || len(errs) != 1 || errs[0].Field != "Disabled" {
+		t.Errorf("should call Validate of model, got %v", err)
+	}
+
+	var result ValidatedAccount
+	if err := DB.First(&result, account.ID).Error; err != nil || result.Name != "jinzhu" || result.Age != 21 || result.Disabled {
+		t.Errorf("invalid values should not be saved, got %+v, error %v", result, err)
+	}
+}
@@ -0,0 +1,65 @@
+package gorm
+
+import "strings"
+
+// ToSQL returns SQL of statements generated by queryFn without executing them, statements are separated by `;`,
+// check ToSQLs for details
+//    sql := db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+//      return tx.Model(&User{}).Where("id = ?", 100).Limit(10).Order("age desc").Find(&[]User{})
+//    })
+//    // SELECT * FROM "users" WHERE id = 100 AND "users"."deleted_at" IS NULL ORDER BY age desc LIMIT 10
+func (db *DB) ToSQL(queryFn func(tx *DB) *DB) string {
+	statements, _ := db.ToSQLs(queryFn)
+	return strings.Join(statements, ";\n")
+}
+
+// ToSQLs returns SQL of statements generated by queryFn in order without executing them, e.g: statements of
+// batches of CreateInBatches, saving associations, preloading, values of sensitive fields are masked
+//    statements, err := db.ToSQLs(func(tx *gorm.DB) *gorm.DB {
+//      return tx.Create(&user) // INSERT INTO "companies" ..., INSERT INTO "users" ..., INSERT INTO "pets" ...
+//    })
+func (db *DB) ToSQLs(queryFn func(tx *DB) *DB) ([]string, error) {
+	recorder := &sqlRecorder{}
+	tx := queryFn(db.Session(&Session{DryRun: true, SkipDefaultTransaction: true}).Intercept(recorder))
+	return recorder.statements, tx.Error
+}
+
+// sqlRecorder records SQL of statements in order they would be executed, statements are recorded when they are
+// finished or when statements nested in them start after their SQL is built, e.g: saving has many associations
+type sqlRecorder struct {
+	frames     []*sqlRecorderFrame
+	statements []string
+}
+
+type sqlRecorderFrame struct {
+	stmt     *Statement
+	skipped  bool
+	recorded bool
+}
+
+func (recorder *sqlRecorder) BeforeQuery(db *DB, operation string) error {
+	_, subQuery := db.InstanceGet("gorm:sub_query")
+	if !subQuery {
+		for _, frame := range recorder.frames {
+			recorder.record(frame)
+		}
+	}
+
+	recorder.frames = append(recorder.frames, &sqlRecorderFrame{stmt: db.Statement, skipped: subQuery})
+	return nil
+}
+
+func (recorder *sqlRecorder) AfterQuery(db *DB, operation string) {
+	if len(recorder.frames) > 0 {
+		frame := recorder.frames[len(recorder.frames)-1]
+		recorder.frames = recorder.frames[:len(recorder.frames)-1]
+		recorder.record(frame)
+	}
+}
+
+func (recorder *sqlRecorder) record(frame *sqlRecorderFrame) {
+	if !frame.skipped && !frame.recorded && frame.stmt.SQL.Len() > 0 {
+		frame.recorded = true
+		recorder.statements = append(recorder.statements, frame.stmt.DB.Dialector.Explain(frame.stmt.SQL.String(), frame.stmt.redactedVars()...))
+	}
+}
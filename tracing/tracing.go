@@ -0,0 +1,149 @@
+// Package tracing instruments gorm operations with spans and duration histograms, Tracer, Span and Histogram are
+// satisfied by thin adapters of OpenTelemetry tracers and meters, e.g: adapters wrapping trace.Tracer and metric.Float64Histogram
+//    db.Use(tracing.New(tracing.Config{Tracer: tracer, Duration: histogram}))
+package tracing
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PluginName name of the plugin, its callbacks are registered as `tracing:before_<operation>` and `tracing:after_<operation>`
+const PluginName = "gorm:tracing"
+
+// attribute keys of spans and measurements, follow OpenTelemetry semantic conventions of database clients
+const (
+	AttributeSystem       = "db.system"
+	AttributeStatement    = "db.statement"
+	AttributeOperation    = "db.operation"
+	AttributeTable        = "db.sql.table"
+	AttributeRowsAffected = "db.rows_affected"
+)
+
+// Attribute key value of span or measurement
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// Tracer starts spans, the returned context carries the span and is passed to the database driver
+type Tracer interface {
+	Start(ctx context.Context, spanName string) (context.Context, Span)
+}
+
+// Span span of operation
+type Span interface {
+	SetAttributes(attrs ...Attribute)
+	RecordError(err error)
+	End()
+}
+
+// Histogram records durations of operations in milliseconds
+type Histogram interface {
+	Record(ctx context.Context, value float64, attrs ...Attribute)
+}
+
+// Config config of the plugin, Tracer and Duration are optional
+type Config struct {
+	Tracer   Tracer
+	Duration Histogram
+	// ExcludeStatement don't record SQL in spans, e.g: for statements might contain sensitive data
+	ExcludeStatement bool
+}
+
+// Plugin creates a span for each gorm operation and records its duration, implements gorm.Plugin
+type Plugin struct {
+	Config
+}
+
+// New returns tracing plugin with config
+func New(config Config) *Plugin {
+	return &Plugin{Config: config}
+}
+
+// Name returns name of the plugin
+func (p *Plugin) Name() string {
+	return PluginName
+}
+
+// operation span of gorm operation in progress
+type operation struct {
+	ctx   context.Context // context before the span started
+	span  Span
+	begin time.Time
+}
+
+// Initialize registers callbacks around all operations
+func (p *Plugin) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	for _, err := range []error{
+		callbacks.Create().Before("*").Register("tracing:before_create", p.before("create")),
+		callbacks.Create().After("*").Register("tracing:after_create", p.after("create")),
+		callbacks.Query().Before("*").Register("tracing:before_query", p.before("query")),
+		callbacks.Query().After("*").Register("tracing:after_query", p.after("query")),
+		callbacks.Update().Before("*").Register("tracing:before_update", p.before("update")),
+		callbacks.Update().After("*").Register("tracing:after_update", p.after("update")),
+		callbacks.Delete().Before("*").Register("tracing:before_delete", p.before("delete")),
+		callbacks.Delete().After("*").Register("tracing:after_delete", p.after("delete")),
+		callbacks.Row().Before("*").Register("tracing:before_row", p.before("row")),
+		callbacks.Row().After("*").Register("tracing:after_row", p.after("row")),
+		callbacks.Raw().Before("*").Register("tracing:before_raw", p.before("raw")),
+		callbacks.Raw().After("*").Register("tracing:after_raw", p.after("raw")),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// before starts span of operation, context of statement is replaced with the span context, so it is propagated to the driver
+func (p *Plugin) before(name string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		op := &operation{ctx: db.Statement.Context, begin: time.Now()}
+		if p.Tracer != nil && db.Statement.Context != nil {
+			db.Statement.Context, op.span = p.Tracer.Start(db.Statement.Context, "gorm."+name)
+		}
+		db.InstanceSet(PluginName, op)
+	}
+}
+
+// after ends span of operation with attributes, and records duration of it
+func (p *Plugin) after(name string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		value, ok := db.InstanceGet(PluginName)
+		if !ok {
+			return
+		}
+
+		op := value.(*operation)
+		attrs := []Attribute{{Key: AttributeOperation, Value: name}}
+		if db.Dialector != nil {
+			attrs = append(attrs, Attribute{Key: AttributeSystem, Value: db.Dialector.Name()})
+		}
+
+		if db.Statement.Table != "" {
+			attrs = append(attrs, Attribute{Key: AttributeTable, Value: db.Statement.Table})
+		}
+
+		if p.Duration != nil {
+			p.Duration.Record(op.ctx, float64(time.Since(op.begin).Nanoseconds())/1e6, attrs...)
+		}
+
+		if op.span != nil {
+			if !p.ExcludeStatement && db.Statement.SQL.Len() > 0 {
+				attrs = append(attrs, Attribute{Key: AttributeStatement, Value: db.Statement.SQL.String()})
+			}
+
+			op.span.SetAttributes(append(attrs, Attribute{Key: AttributeRowsAffected, Value: db.RowsAffected})...)
+			if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+				op.span.RecordError(db.Error)
+			}
+			op.span.End()
+			db.Statement.Context = op.ctx
+		}
+	}
+}
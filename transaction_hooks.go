@@ -0,0 +1,106 @@
+package gorm
+
+import (
+	"context"
+	"sync"
+)
+
+// txHooks hooks registered in transaction, shared by sessions of the transaction
+type txHooks struct {
+	mu            sync.Mutex
+	afterCommit   []func(context.Context)
+	afterRollback []func(context.Context)
+	resolved      bool
+}
+
+// txHooks returns hooks of current transaction, nil if db is not in transaction
+func (db *DB) txHooks() *txHooks {
+	if value, ok := db.Statement.Settings.Load("gorm:tx_hooks"); ok {
+		return value.(*txHooks)
+	}
+	return nil
+}
+
+// AfterCommit registers fc to run once after the outermost transaction is committed, fc is discarded if the transaction
+// or the nested transaction registered it is rolled back, fc runs immediately if db is not in transaction
+//    db.Transaction(func(tx *gorm.DB) error {
+//      tx.Create(&order)
+//      tx.AfterCommit(func(ctx context.Context) { publishOrderCreated(ctx, order) })
+//      return nil
+//    })
+func (db *DB) AfterCommit(fc func(ctx context.Context)) *DB {
+	if hooks := db.txHooks(); hooks != nil {
+		hooks.mu.Lock()
+		if !hooks.resolved {
+			hooks.afterCommit = append(hooks.afterCommit, fc)
+			hooks.mu.Unlock()
+			return db
+		}
+		hooks.mu.Unlock()
+	}
+
+	fc(db.Statement.Context)
+	return db
+}
+
+// AfterRollback registers fc to run once after the outermost transaction is rolled back, it is ignored if db is not in transaction
+func (db *DB) AfterRollback(fc func(ctx context.Context)) *DB {
+	if hooks := db.txHooks(); hooks != nil {
+		hooks.mu.Lock()
+		if !hooks.resolved {
+			hooks.afterRollback = append(hooks.afterRollback, fc)
+		}
+		hooks.mu.Unlock()
+	}
+	return db
+}
+
+// mark returns number of after commit hooks registered, used to discard hooks of rolled back nested transaction
+func (hooks *txHooks) mark() int {
+	if hooks == nil {
+		return 0
+	}
+
+	hooks.mu.Lock()
+	defer hooks.mu.Unlock()
+	return len(hooks.afterCommit)
+}
+
+// discard discards after commit hooks registered after mark
+func (hooks *txHooks) discard(mark int) {
+	if hooks != nil {
+		hooks.mu.Lock()
+		defer hooks.mu.Unlock()
+		if mark < len(hooks.afterCommit) {
+			hooks.afterCommit = hooks.afterCommit[:mark]
+		}
+	}
+}
+
+// resolve runs after commit hooks if committed, otherwise after rollback hooks, hooks run only once
+func (hooks *txHooks) resolve(ctx context.Context, committed bool) {
+	if hooks == nil {
+		return
+	}
+
+	hooks.mu.Lock()
+	if hooks.resolved {
+		hooks.mu.Unlock()
+		return
+	}
+
+	fcs := hooks.afterRollback
+	if committed {
+		fcs = hooks.afterCommit
+	}
+	hooks.resolved, hooks.afterCommit, hooks.afterRollback = true, nil, nil
+	hooks.mu.Unlock()
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	for _, fc := range fcs {
+		fc(ctx)
+	}
+}
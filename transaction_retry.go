@@ -0,0 +1,82 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+)
+
+// RetryPolicy retries transactions failed with serialization failures or deadlocks, the function of transaction
+// is run again in a new transaction, so it should not have side effects out of the database
+//    db, err := gorm.Open(sqlite.Open("gorm.db"), &gorm.Config{
+//      TransactionRetry: &gorm.RetryPolicy{MaxAttempts: 3, BaseDelay: 10 * time.Millisecond},
+//    })
+type RetryPolicy struct {
+	// MaxAttempts max attempts of running transaction, including the first one, default 3
+	MaxAttempts int
+	// BaseDelay delay before the first retry, it is doubled for each retry with jitter, default 10ms
+	BaseDelay time.Duration
+	// MaxDelay max delay between attempts, default 1s
+	MaxDelay time.Duration
+	// Retryable returns true if err should be retried, default is errors of ErrSerializationFailure or ErrDeadlock
+	Retryable func(err error) bool
+}
+
+// retryable returns true if err of attempt should be retried
+func (policy *RetryPolicy) retryable(db *DB, err error, attempt int) bool {
+	if policy == nil || err == nil {
+		return false
+	}
+
+	maxAttempts := policy.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 3
+	}
+
+	if attempt >= maxAttempts {
+		return false
+	}
+
+	if policy.Retryable != nil {
+		return policy.Retryable(err)
+	}
+
+	err = db.translateError(err)
+	return errors.Is(err, ErrSerializationFailure) || errors.Is(err, ErrDeadlock)
+}
+
+// delay returns delay before running attempt+1 with exponential backoff and jitter
+func (policy *RetryPolicy) delay(attempt int) time.Duration {
+	base, max := policy.BaseDelay, policy.MaxDelay
+	if base <= 0 {
+		base = 10 * time.Millisecond
+	}
+
+	if max <= 0 {
+		max = time.Second
+	}
+
+	delay := base << uint(attempt-1)
+	if delay <= 0 || delay > max {
+		delay = max
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// wait waits delay before running attempt+1, returns false if ctx is done
+func (policy *RetryPolicy) wait(ctx context.Context, attempt int) bool {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	timer := time.NewTimer(policy.delay(attempt))
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -0,0 +1,198 @@
+package gorm
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Coordinator commits transactions of multiple databases atomically with two-phase commit, with PREPARE TRANSACTION for postgres
+// and XA transactions for mysql, postgres requires max_prepared_transactions greater than zero
+//    err := gorm.NewCoordinator(ordersDB, paymentsDB).Transaction(ctx, func(txs []*gorm.DB) error {
+//      if err := txs[0].Create(&order).Error; err != nil {
+//        return err
+//      }
+//      return txs[1].Create(&payment).Error
+//    })
+type Coordinator struct {
+	DBs []*DB
+	// XID returns id of global transaction, id of transaction of each database is `<xid>_<index>`, default random id prefixed with `gorm_`
+	XID func() string
+}
+
+// NewCoordinator returns coordinator of two-phase commit for dbs
+func NewCoordinator(dbs ...*DB) *Coordinator {
+	return &Coordinator{DBs: dbs}
+}
+
+// participantConn connection of participant, it can't begin transactions, and as a TxCommitter, nested transactions
+// are created with savepoints, its Commit and Rollback return ErrInvalidTransaction as they are managed by Coordinator
+type participantConn struct {
+	conn *sql.Conn
+}
+
+func (pc participantConn) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return pc.conn.PrepareContext(ctx, query)
+}
+
+func (pc participantConn) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return pc.conn.ExecContext(ctx, query, args...)
+}
+
+func (pc participantConn) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return pc.conn.QueryContext(ctx, query, args...)
+}
+
+func (pc participantConn) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return pc.conn.QueryRowContext(ctx, query, args...)
+}
+
+func (pc participantConn) Commit() error {
+	return ErrInvalidTransaction
+}
+
+func (pc participantConn) Rollback() error {
+	return ErrInvalidTransaction
+}
+
+// participant transaction of database in two-phase commit
+type participant struct {
+	tx       *DB
+	conn     *sql.Conn
+	xid      string
+	prepared bool
+}
+
+// exec executes sqls on connection of participant
+func (p *participant) exec(sqls ...string) error {
+	for _, sql := range sqls {
+		if err := p.tx.Exec(sql).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Transaction begins transactions of dbs, runs fc with them in order of dbs, then prepares and commits all of them,
+// transactions are rolled back if fc returns error or any of them fails to prepare, returns error of ErrTransactionInDoubt
+// if some prepared transactions failed to commit, ErrUnsupportedDriver if any database doesn't support two-phase commit
+func (c *Coordinator) Transaction(ctx context.Context, fc func(txs []*DB) error) (err error) {
+	for _, db := range c.DBs {
+		if !db.Capabilities().TwoPhaseCommit {
+			return fmt.Errorf("%w: two-phase commit is not supported by %v", ErrUnsupportedDriver, db.Dialector.Name())
+		}
+	}
+
+	xid := c.xid()
+	participants := make([]*participant, 0, len(c.DBs))
+	defer func() {
+		for _, p := range participants {
+			if err != nil && !errors.Is(err, ErrTransactionInDoubt) {
+				if p.rollback() != nil {
+					// discard connection instead of returning it to the pool with an open transaction
+					p.conn.Raw(func(interface{}) error { return driver.ErrBadConn })
+				}
+			}
+			p.conn.Close()
+		}
+	}()
+
+	txs := make([]*DB, 0, len(c.DBs))
+	for idx, db := range c.DBs {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return err
+		}
+
+		conn, err := sqlDB.Conn(ctx)
+		if err != nil {
+			return err
+		}
+
+		p := &participant{tx: db.Session(&Session{Context: ctx}), conn: conn, xid: quoteXID(fmt.Sprintf("%v_%d", xid, idx))}
+		p.tx.Statement.ConnPool, p.tx.Statement.InTransaction = participantConn{conn: conn}, true
+		participants = append(participants, p)
+
+		if p.tx.Capabilities().XATransaction {
+			err = p.exec("XA START " + p.xid)
+		} else {
+			err = p.exec("BEGIN")
+		}
+
+		if err != nil {
+			return err
+		}
+		txs = append(txs, p.tx)
+	}
+
+	if err = fc(txs); err != nil {
+		return err
+	}
+
+	for _, p := range participants {
+		if p.tx.Capabilities().XATransaction {
+			err = p.exec("XA END "+p.xid, "XA PREPARE "+p.xid)
+		} else {
+			err = p.exec("PREPARE TRANSACTION " + p.xid)
+		}
+
+		if err != nil {
+			return err
+		}
+		p.prepared = true
+	}
+
+	for idx, p := range participants {
+		if p.tx.Capabilities().XATransaction {
+			err = p.exec("XA COMMIT " + p.xid)
+		} else {
+			err = p.exec("COMMIT PREPARED " + p.xid)
+		}
+
+		if err != nil {
+			if idx == 0 {
+				// nothing committed yet, all prepared transactions could be rolled back
+				return err
+			}
+			return fmt.Errorf("%w: failed to commit prepared transaction %v, got error %v", ErrTransactionInDoubt, p.xid, err)
+		}
+	}
+	return nil
+}
+
+// rollback rolls back transaction of participant without context of caller as it might be canceled already,
+// XA END is allowed to fail as the transaction might be ended
+func (p *participant) rollback() error {
+	tx := p.tx.WithContext(context.Background())
+	switch {
+	case p.tx.Capabilities().XATransaction && p.prepared:
+		return tx.Exec("XA ROLLBACK " + p.xid).Error
+	case p.tx.Capabilities().XATransaction:
+		tx.Exec("XA END " + p.xid)
+		return tx.Exec("XA ROLLBACK " + p.xid).Error
+	case p.prepared:
+		return tx.Exec("ROLLBACK PREPARED " + p.xid).Error
+	default:
+		return tx.Exec("ROLLBACK").Error
+	}
+}
+
+func (c *Coordinator) xid() string {
+	if c.XID != nil {
+		return c.XID()
+	}
+
+	bytes := make([]byte, 8)
+	rand.Read(bytes)
+	return "gorm_" + hex.EncodeToString(bytes)
+}
+
+// quoteXID quotes xid as string literal
+func quoteXID(xid string) string {
+	return "'" + strings.ReplaceAll(xid, "'", "''") + "'"
+}
@@ -0,0 +1,385 @@
+package gorm
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+	"gorm.io/gorm/utils"
+)
+
+// UnitOfWork session keeping identity map of loaded records, records of the same table and primary key are loaded
+// into the same struct pointer, changes of them are tracked and flushed in one transaction by Commit
+//    uow := db.UnitOfWork()
+//    var user *User
+//    uow.Find(&user, 1)
+//    user.Name = "jinzhu"
+//    pet := &Pet{Name: "pet"}
+//    user.Pets = append(user.Pets, pet)
+//    uow.Add(pet)
+//    err := uow.Commit()
+type UnitOfWork struct {
+	db         *DB
+	mu         sync.Mutex
+	identities map[identityKey]*identityEntry
+	entries    []*identityEntry
+}
+
+type identityKey struct {
+	table string
+	key   string
+}
+
+type identityState int
+
+const (
+	identityNew identityState = iota
+	identityLoaded
+	identityRemoved
+)
+
+// identityEntry record tracked by unit of work
+type identityEntry struct {
+	schema   *schema.Schema
+	value    reflect.Value // pointer of struct
+	state    identityState
+	snapshot map[string]interface{} // values of columns when loaded or flushed
+}
+
+// UnitOfWork returns unit of work session of db, check UnitOfWork for details
+func (db *DB) UnitOfWork() *UnitOfWork {
+	return &UnitOfWork{db: db.Session(&Session{}), identities: map[identityKey]*identityEntry{}}
+}
+
+// Load loads record of type T with primary key id through unit of work, check UnitOfWork.Find for details
+//    user, err := gorm.Load[User](uow, 1)
+func Load[T any](uow *UnitOfWork, id interface{}) (*T, error) {
+	var value *T
+	err := uow.Find(&value, id)
+	return value, err
+}
+
+// Find loads record with primary key id into dest, dest should be pointer of struct pointer, record already loaded or added
+// is returned without querying, returns ErrRecordNotFound if no record found or it is removed
+func (uow *UnitOfWork) Find(dest interface{}, id interface{}) error {
+	rv := reflect.ValueOf(dest)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Ptr || rv.Elem().Type().Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("%w: unit of work requires pointer of struct pointer, got %T", ErrInvalidData, dest)
+	}
+
+	s, err := schema.Parse(dest, uow.db.cacheStore, uow.db.NamingStrategy)
+	if err != nil {
+		return err
+	}
+
+	uow.mu.Lock()
+	defer uow.mu.Unlock()
+
+	if entry, ok := uow.identities[identityKey{table: s.Table, key: utils.ToStringKey(id)}]; ok {
+		if entry.state == identityRemoved {
+			return ErrRecordNotFound
+		}
+		rv.Elem().Set(entry.value)
+		return nil
+	}
+
+	value := reflect.New(s.ModelType)
+	if err := uow.db.Take(value.Interface(), id).Error; err != nil {
+		return err
+	}
+
+	entry := &identityEntry{schema: s, value: value, state: identityLoaded}
+	entry.snapshot = entry.columns()
+	uow.identities[entry.key()] = entry
+	uow.entries = append(uow.entries, entry)
+	rv.Elem().Set(value)
+	return nil
+}
+
+// Add adds new record value to be created by Commit, value should be struct pointer, record with primary key is returned
+// by Find without querying
+func (uow *UnitOfWork) Add(value interface{}) error {
+	entry, err := uow.entry(value)
+	if err != nil {
+		return err
+	}
+
+	uow.mu.Lock()
+	defer uow.mu.Unlock()
+	for _, e := range uow.entries {
+		if e.value.Pointer() == entry.value.Pointer() {
+			return nil
+		}
+	}
+
+	if entry.hasPrimaryKey() {
+		if _, ok := uow.identities[entry.key()]; ok {
+			return fmt.Errorf("%w: record %T with the same primary key is tracked by unit of work", ErrInvalidData, value)
+		}
+		uow.identities[entry.key()] = entry
+	}
+	uow.entries = append(uow.entries, entry)
+	return nil
+}
+
+// Remove marks record value loaded or added to be deleted by Commit
+func (uow *UnitOfWork) Remove(value interface{}) error {
+	uow.mu.Lock()
+	defer uow.mu.Unlock()
+
+	for idx, entry := range uow.entries {
+		if entry.value.Interface() == value {
+			if entry.state == identityNew {
+				if uow.identities[entry.key()] == entry {
+					delete(uow.identities, entry.key())
+				}
+				uow.entries = append(uow.entries[:idx], uow.entries[idx+1:]...)
+			} else {
+				entry.state = identityRemoved
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: record %T is not tracked by unit of work", ErrInvalidData, value)
+}
+
+// Dirty returns columns of record value changed since it is loaded or flushed, all columns of new records
+func (uow *UnitOfWork) Dirty(value interface{}) []string {
+	uow.mu.Lock()
+	defer uow.mu.Unlock()
+
+	for _, entry := range uow.entries {
+		if entry.value.Interface() == value {
+			return entry.dirty()
+		}
+	}
+	return nil
+}
+
+// Commit flushes changes in one transaction, records are created and updated in dependency order of their associations,
+// e.g: companies before users belong to them, users before pets they have, foreign keys are assigned from associated records,
+// only changed columns are updated, removed records are deleted in reverse order
+func (uow *UnitOfWork) Commit() error {
+	uow.mu.Lock()
+	defer uow.mu.Unlock()
+
+	entries := uow.sorted()
+	err := uow.db.Transaction(func(tx *DB) error {
+		tx = tx.Omit(clause.Associations).Session(&Session{})
+		for _, entry := range entries {
+			if entry.state == identityRemoved {
+				continue
+			}
+
+			entry.assignBelongsTo()
+			if entry.state == identityNew {
+				if err := tx.Create(entry.value.Interface()).Error; err != nil {
+					return err
+				}
+			} else if columns := entry.dirty(); len(columns) > 0 {
+				if err := tx.Model(entry.value.Interface()).Select(columns).Updates(entry.value.Interface()).Error; err != nil {
+					return err
+				}
+			}
+			entry.assignHasMany()
+		}
+
+		for idx := len(entries) - 1; idx >= 0; idx-- {
+			if entry := entries[idx]; entry.state == identityRemoved {
+				if err := tx.Delete(entry.value.Interface()).Error; err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	tracked := uow.entries[:0]
+	for _, entry := range uow.entries {
+		if entry.state == identityRemoved {
+			delete(uow.identities, entry.key())
+			continue
+		}
+
+		entry.state, entry.snapshot = identityLoaded, entry.columns()
+		uow.identities[entry.key()] = entry
+		tracked = append(tracked, entry)
+	}
+	uow.entries = tracked
+	return nil
+}
+
+// entry returns entry of new record value
+func (uow *UnitOfWork) entry(value interface{}) (*identityEntry, error) {
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w: unit of work requires struct pointer, got %T", ErrInvalidData, value)
+	}
+
+	s, err := schema.Parse(value, uow.db.cacheStore, uow.db.NamingStrategy)
+	if err != nil {
+		return nil, err
+	}
+	return &identityEntry{schema: s, value: rv, state: identityNew}, nil
+}
+
+// sorted returns entries sorted by dependency order of their schemas, entries of schemas in cycles keep their order
+func (uow *UnitOfWork) sorted() []*identityEntry {
+	var (
+		schemas []*schema.Schema
+		deps    = map[*schema.Schema]map[*schema.Schema]bool{}
+	)
+
+	for _, entry := range uow.entries {
+		if _, ok := deps[entry.schema]; !ok {
+			deps[entry.schema] = map[*schema.Schema]bool{}
+			schemas = append(schemas, entry.schema)
+		}
+	}
+
+	for _, s := range schemas {
+		for _, rel := range s.Relationships.BelongsTo {
+			if _, ok := deps[rel.FieldSchema]; ok && rel.FieldSchema != s {
+				deps[s][rel.FieldSchema] = true
+			}
+		}
+
+		for _, rel := range hasRelationships(s) {
+			if _, ok := deps[rel.FieldSchema]; ok && rel.FieldSchema != s {
+				deps[rel.FieldSchema][s] = true
+			}
+		}
+	}
+
+	var (
+		ordered = make([]*schema.Schema, 0, len(schemas))
+		done    = map[*schema.Schema]bool{}
+	)
+
+	for len(ordered) < len(schemas) {
+		progressed := false
+		for _, s := range schemas {
+			if done[s] {
+				continue
+			}
+
+			ready := true
+			for dep := range deps[s] {
+				ready = ready && done[dep]
+			}
+
+			if ready {
+				ordered, done[s], progressed = append(ordered, s), true, true
+			}
+		}
+
+		// schemas in cycles are flushed in order of registration
+		if !progressed {
+			for _, s := range schemas {
+				if !done[s] {
+					ordered, done[s] = append(ordered, s), true
+				}
+			}
+		}
+	}
+
+	entries := make([]*identityEntry, 0, len(uow.entries))
+	for _, s := range ordered {
+		for _, entry := range uow.entries {
+			if entry.schema == s {
+				entries = append(entries, entry)
+			}
+		}
+	}
+	return entries
+}
+
+// key returns identity key of the record
+func (entry *identityEntry) key() identityKey {
+	values := make([]interface{}, len(entry.schema.PrimaryFields))
+	for idx, field := range entry.schema.PrimaryFields {
+		values[idx], _ = field.ValueOf(entry.value.Elem())
+	}
+	return identityKey{table: entry.schema.Table, key: utils.ToStringKey(values...)}
+}
+
+// hasPrimaryKey returns true if primary key of the record is set
+func (entry *identityEntry) hasPrimaryKey() bool {
+	for _, field := range entry.schema.PrimaryFields {
+		if _, isZero := field.ValueOf(entry.value.Elem()); isZero {
+			return false
+		}
+	}
+	return len(entry.schema.PrimaryFields) > 0
+}
+
+// columns returns values of columns of the record
+func (entry *identityEntry) columns() map[string]interface{} {
+	return snapshotColumns(entry.schema, entry.value.Elem())
+}
+
+// dirty returns columns changed since snapshot
+func (entry *identityEntry) dirty() []string {
+	return changedColumns(entry.schema, entry.snapshot, entry.value.Elem())
+}
+
+// assignBelongsTo assigns foreign keys of the record from records it belongs to
+func (entry *identityEntry) assignBelongsTo() {
+	for _, rel := range entry.schema.Relationships.BelongsTo {
+		owner, isZero := rel.Field.ValueOf(entry.value.Elem())
+		if isZero {
+			continue
+		}
+
+		ownerValue := reflect.Indirect(reflect.ValueOf(owner))
+		for _, ref := range rel.References {
+			if !ref.OwnPrimaryKey && ref.PrimaryKey != nil {
+				if value, isZero := ref.PrimaryKey.ValueOf(ownerValue); !isZero {
+					ref.ForeignKey.Set(entry.value.Elem(), value)
+				}
+			}
+		}
+	}
+}
+
+// assignHasMany assigns foreign keys of records the record has
+func (entry *identityEntry) assignHasMany() {
+	for _, rel := range hasRelationships(entry.schema) {
+		associated, isZero := rel.Field.ValueOf(entry.value.Elem())
+		if isZero {
+			continue
+		}
+
+		var elems []reflect.Value
+		if rv := reflect.Indirect(reflect.ValueOf(associated)); rv.Kind() == reflect.Slice {
+			for i := 0; i < rv.Len(); i++ {
+				elems = append(elems, reflect.Indirect(rv.Index(i)))
+			}
+		} else if rv.Kind() == reflect.Struct && rv.CanAddr() {
+			elems = append(elems, rv)
+		}
+
+		for _, elem := range elems {
+			for _, ref := range rel.References {
+				if ref.PrimaryKey != nil {
+					value, _ := ref.PrimaryKey.ValueOf(entry.value.Elem())
+					ref.ForeignKey.Set(elem, value)
+				} else if ref.PrimaryValue != "" {
+					ref.ForeignKey.Set(elem, ref.PrimaryValue)
+				}
+			}
+		}
+	}
+}
+
+// hasRelationships returns has one and has many relationships of s
+func hasRelationships(s *schema.Schema) []*schema.Relationship {
+	relationships := make([]*schema.Relationship, 0, len(s.Relationships.HasOne)+len(s.Relationships.HasMany))
+	return append(append(relationships, s.Relationships.HasOne...), s.Relationships.HasMany...)
+}
@@ -0,0 +1,78 @@
+// Package snapshot reads rows changed by statements as maps keyed by column names, it is shared by plugins capturing
+// old and new values of rows, e.g: cdc, audit
+package snapshot
+
+import (
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+	"gorm.io/gorm/utils"
+)
+
+// Before reads rows going to be updated or deleted by statement of db, ok is false if the statement has no conditions
+// and global update is not allowed, it should run before `gorm:update` or `gorm:delete`
+func Before(db *gorm.DB) (rows []map[string]interface{}, ok bool, err error) {
+	var exprs []clause.Expression
+	if where, ok := db.Statement.Clauses["WHERE"].Expression.(clause.Where); ok {
+		exprs = append(exprs, where.Exprs...)
+	}
+
+	// primary keys of values are added to conditions when updating or deleting them
+	if _, values := schema.GetIdentityFieldValuesMap(db.Statement.ReflectValue, db.Statement.Schema.PrimaryFields); len(values) > 0 {
+		column, queryValues := schema.ToQueryValues(db.Statement.Table, db.Statement.Schema.PrimaryFieldDBNames, values)
+		exprs = append(exprs, clause.IN{Column: column, Values: queryValues})
+	}
+
+	if len(exprs) == 0 && !db.AllowGlobalUpdate {
+		return nil, false, nil
+	}
+
+	rows, err = Find(db, exprs)
+	return rows, true, err
+}
+
+// FindByPrimaryValues reads rows of model of statement with values of primary keys
+func FindByPrimaryValues(db *gorm.DB, values [][]interface{}) ([]map[string]interface{}, error) {
+	column, queryValues := schema.ToQueryValues(db.Statement.Table, db.Statement.Schema.PrimaryFieldDBNames, values)
+	return Find(db, []clause.Expression{clause.IN{Column: column, Values: queryValues}})
+}
+
+// Find reads rows of model of statement matching exprs with the connection of the statement
+func Find(db *gorm.DB, exprs []clause.Expression) ([]map[string]interface{}, error) {
+	var (
+		rows []map[string]interface{}
+		tx   = db.Session(&gorm.Session{NewDB: true}).Primary()
+	)
+
+	tx = tx.Model(reflect.New(db.Statement.Schema.ModelType).Interface()).Table(db.Statement.Table)
+	if db.Statement.Unscoped {
+		tx = tx.Unscoped()
+	}
+	tx.Statement.AddClause(clause.Where{Exprs: exprs})
+	return rows, tx.Find(&rows).Error
+}
+
+// PrimaryValues returns values of primary keys of row
+func PrimaryValues(row map[string]interface{}, primaries []string) []interface{} {
+	values := make([]interface{}, len(primaries))
+	for idx, name := range primaries {
+		values[idx] = row[name]
+	}
+	return values
+}
+
+// PrimaryKey returns primary keys of row
+func PrimaryKey(row map[string]interface{}, primaries []string) map[string]interface{} {
+	key := make(map[string]interface{}, len(primaries))
+	for _, name := range primaries {
+		key[name] = row[name]
+	}
+	return key
+}
+
+// Key returns string key of row of its primary keys, rows are matched with it
+func Key(row map[string]interface{}, primaries []string) string {
+	return utils.ToStringKey(PrimaryValues(row, primaries)...)
+}
@@ -0,0 +1,45 @@
+package gorm
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FieldError validation error of field, Rule is failed validation rule like `max=255`,
+// or empty if returned by model's Validate method
+type FieldError struct {
+	Field   string
+	Rule    string
+	Value   interface{}
+	Message string
+}
+
+func (err *FieldError) Error() string {
+	if err.Message != "" {
+		return fmt.Sprintf("%s: %s", err.Field, err.Message)
+	}
+	return fmt.Sprintf("%s: failed on validation %s", err.Field, err.Rule)
+}
+
+// ValidationErrors field errors aggregated when validating values before creating or updating,
+// values are validated with tag `validate` of fields and method `Validate(context.Context) error` of models
+//    var errs gorm.ValidationErrors
+//    if errors.As(db.Create(&user).Error, &errs) {
+//      for _, err := range errs {
+//        fmt.Println(err.Field, err.Rule)
+//      }
+//    }
+type ValidationErrors []*FieldError
+
+func (errs ValidationErrors) Error() string {
+	messages := make([]string, 0, len(errs))
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+	return fmt.Sprintf("%v: %s", ErrValidationFailed, strings.Join(messages, "; "))
+}
+
+// Unwrap makes errors.Is(err, ErrValidationFailed) works
+func (errs ValidationErrors) Unwrap() error {
+	return ErrValidationFailed
+}